@@ -24,6 +24,8 @@ func main() {
 	app.Register(NewQueueCommand)
 	app.Register(NewTempCommand)
 	app.Register(NewMigrateCommand)
+	app.Register(NewStorageCommand)
+	app.Register(NewJwtKeyCommand)
 	app.Run()
 }
 
@@ -105,3 +107,106 @@ func NewTempCommand() core.Command {
 		},
 	}
 }
+
+func NewStorageCommand() core.Command {
+	return core.Command{
+		Name:  "storage",
+		Usage: "Storage Command - 文件存储管理",
+		Subcommands: []core.Command{
+			{
+				Name:  "migrate",
+				Usage: "Migrate Command - 迁移文件到其它存储驱动",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "from",
+						Usage:    "源存储驱动[local、minio、s3、webdav]",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:     "to",
+						Usage:    "目标存储驱动[local、minio、s3、webdav]",
+						Required: true,
+					},
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "仅检查源文件，不执行复制",
+					},
+					&cli.BoolFlag{
+						Name:  "rewrite-url",
+						Usage: "将消息中的文件地址替换为目标存储地址",
+					},
+				},
+				Action: func(ctx *cli.Context, conf *config.Config) error {
+					logger.Init(conf.Log.LogFilePath("app.log"), logger.LevelInfo, "storage")
+					return mission.StorageMigrate(ctx, NewStorageInjector(conf))
+				},
+			},
+		},
+	}
+}
+
+func NewJwtKeyCommand() core.Command {
+	dir := &cli.StringFlag{
+		Name:  "dir",
+		Usage: "密钥目录，默认读取配置 jwt.key_dir",
+	}
+
+	kid := &cli.StringFlag{
+		Name:     "kid",
+		Usage:    "密钥ID",
+		Required: true,
+	}
+
+	alg := &cli.StringFlag{
+		Name:  "alg",
+		Usage: "签名算法[RS256、EdDSA]",
+		Value: "EdDSA",
+	}
+
+	return core.Command{
+		Name:  "jwt-key",
+		Usage: "Jwt Key Command - 签名密钥管理",
+		Subcommands: []core.Command{
+			{
+				Name:  "generate",
+				Usage: "生成签名密钥",
+				Flags: []cli.Flag{dir, alg, &cli.BoolFlag{Name: "activate", Usage: "生成后立即设为当前签名密钥"}},
+				Action: func(ctx *cli.Context, conf *config.Config) error {
+					return mission.JwtKeyGenerate(ctx, conf)
+				},
+			},
+			{
+				Name:  "rotate",
+				Usage: "生成并启用新的签名密钥，旧密钥继续用于验签",
+				Flags: []cli.Flag{dir, alg},
+				Action: func(ctx *cli.Context, conf *config.Config) error {
+					return mission.JwtKeyRotate(ctx, conf)
+				},
+			},
+			{
+				Name:  "activate",
+				Usage: "设置当前签名密钥",
+				Flags: []cli.Flag{dir, kid},
+				Action: func(ctx *cli.Context, conf *config.Config) error {
+					return mission.JwtKeyActivate(ctx, conf)
+				},
+			},
+			{
+				Name:  "remove",
+				Usage: "删除不再使用的验签密钥",
+				Flags: []cli.Flag{dir, kid},
+				Action: func(ctx *cli.Context, conf *config.Config) error {
+					return mission.JwtKeyRemove(ctx, conf)
+				},
+			},
+			{
+				Name:  "list",
+				Usage: "查看全部签名密钥",
+				Flags: []cli.Flag{dir},
+				Action: func(ctx *cli.Context, conf *config.Config) error {
+					return mission.JwtKeyList(ctx, conf)
+				},
+			},
+		},
+	}
+}
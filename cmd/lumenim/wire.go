@@ -78,3 +78,12 @@ func NewMigrateInjector(conf *config.Config) *mission.MigrateProvider {
 		),
 	)
 }
+
+func NewStorageInjector(conf *config.Config) *mission.StorageProvider {
+	panic(
+		wire.Build(
+			providerSet,
+			mission.StorageProviderSet,
+		),
+	)
+}
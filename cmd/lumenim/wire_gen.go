@@ -55,21 +55,35 @@ func NewHttpInjector(conf *config.Config) *apis.AppProvider {
 	db := provider.NewMySQLClient(conf)
 	client := provider.NewRedisClient(conf)
 	users := repo.NewUsers(db, client)
+	redisLock := cache.NewRedisLock(client)
 	smsStorage := cache.NewSmsStorage(client)
 	smsService := &service.SmsService{
 		Storage: smsStorage,
 	}
+	producer := provider.NewNsqProducer(conf)
+	sender := provider.NewEmailClient(conf)
+	emailOutbox := repo.NewEmailOutbox(db)
+	templateService := &service.TemplateService{}
+	emailService := &service.EmailService{
+		Producer:        producer,
+		EmailClient:     sender,
+		EmailOutboxRepo: emailOutbox,
+		TemplateService: templateService,
+		CodeStorage:     smsStorage,
+	}
 	userService := &service.UserService{
-		UsersRepo: users,
+		UsersRepo:    users,
+		EmailService: emailService,
 	}
 	common := &v1.Common{
-		Config:      conf,
-		UsersRepo:   users,
-		SmsService:  smsService,
-		UserService: userService,
+		Config:       conf,
+		UsersRepo:    users,
+		RedisLock:    redisLock,
+		SmsService:   smsService,
+		UserService:  userService,
+		EmailService: emailService,
 	}
 	jwtTokenStorage := cache.NewTokenSessionStorage(client)
-	redisLock := cache.NewRedisLock(client)
 	robot := repo.NewRobot(db)
 	source := repo.NewSource(db, client)
 	articleClass := repo.NewArticleClass(db)
@@ -78,6 +92,72 @@ func NewHttpInjector(conf *config.Config) *apis.AppProvider {
 		ArticleClass: articleClass,
 	}
 	iRsa := provider.NewRsa(conf)
+	twoFactor := repo.NewTwoFactor(db)
+	preAuthStorage := cache.NewPreAuthStorage(client)
+	loginLimitStorage := cache.NewLoginLimitStorage(client)
+	twoFactorService := &service.TwoFactorService{
+		Config:            conf,
+		TwoFactorRepo:     twoFactor,
+		PreAuthStorage:    preAuthStorage,
+		LoginLimitStorage: loginLimitStorage,
+	}
+	keySet := provider.NewJwtKeySet(conf)
+	authSessionStorage := cache.NewAuthSessionStorage(client)
+	userDevice := repo.NewUserDevice(db)
+	authSessionService := &service.AuthSessionService{
+		Config:             conf,
+		JwtKeySet:          keySet,
+		AuthSessionStorage: authSessionStorage,
+		JwtTokenStorage:    jwtTokenStorage,
+		UserDeviceRepo:     userDevice,
+	}
+	httpClient := provider.NewHttpClient()
+	providers := provider.NewOidcProviders(conf, httpClient)
+	oidcStateStorage := cache.NewOidcStateStorage(client)
+	userIdentity := repo.NewUserIdentity(db)
+	organize := repo.NewOrganize(db)
+	department := repo.NewDepartment(db)
+	position := repo.NewPosition(db)
+	organizeSyncService := &service.OrganizeSyncService{
+		OrganizeRepo:   organize,
+		DepartmentRepo: department,
+		PositionRepo:   position,
+	}
+	oidcService := &service.OidcService{
+		Config:              conf,
+		Source:              source,
+		OidcProviders:       providers,
+		OidcStateStorage:    oidcStateStorage,
+		UsersRepo:           users,
+		UserIdentityRepo:    userIdentity,
+		OrganizeSyncService: organizeSyncService,
+	}
+	ldapClient := provider.NewLdapClient(conf)
+	pushMessage := &business.PushMessage{
+		Redis: client,
+	}
+	adminUserService := &service.AdminUserService{
+		UsersRepo:          users,
+		JwtTokenStorage:    jwtTokenStorage,
+		AuthSessionService: authSessionService,
+		PushMessage:        pushMessage,
+	}
+	ldapService := &service.LdapService{
+		Config:              conf,
+		Source:              source,
+		LdapClient:          ldapClient,
+		UsersRepo:           users,
+		UserIdentityRepo:    userIdentity,
+		UserService:         userService,
+		AdminUserService:    adminUserService,
+		OrganizeSyncService: organizeSyncService,
+	}
+	loginLimitService := &service.LoginLimitService{
+		Config:            conf,
+		LoginLimitStorage: loginLimitStorage,
+	}
+	captchaStorage := cache.NewCaptchaStorage(client)
+	captcha := provider.NewBase64Captcha(captchaStorage)
 	auth := &v1.Auth{
 		Config:              conf,
 		Redis:               client,
@@ -88,43 +168,73 @@ func NewHttpInjector(conf *config.Config) *apis.AppProvider {
 		UserService:         userService,
 		ArticleClassService: articleClassService,
 		Rsa:                 iRsa,
-	}
-	organize := repo.NewOrganize(db)
+		TwoFactorService:    twoFactorService,
+		AuthSessionService:  authSessionService,
+		OidcService:         oidcService,
+		LdapService:         ldapService,
+		LoginLimitService:   loginLimitService,
+		ICaptcha:            captcha,
+	}
+	usersSetting := repo.NewUsersSetting(db)
 	user := &v1.User{
-		Redis:        client,
-		UsersRepo:    users,
-		OrganizeRepo: organize,
-		UserService:  userService,
-		SmsService:   smsService,
-		Rsa:          iRsa,
+		Redis:            client,
+		UsersRepo:        users,
+		UsersSettingRepo: usersSetting,
+		OrganizeRepo:     organize,
+		UserService:      userService,
+		SmsService:       smsService,
+		EmailService:     emailService,
+		Rsa:              iRsa,
+	}
+	v1TwoFactor := &v1.TwoFactor{
+		UsersRepo:        users,
+		TwoFactorService: twoFactorService,
+		Rsa:              iRsa,
+	}
+	pushClient := provider.NewPushClient(conf)
+	serverStorage := cache.NewSidStorage(client)
+	clientStorage := cache.NewClientStorage(client, conf, serverStorage)
+	unreadStorage := cache.NewUnreadStorage(client)
+	repoGroup := repo.NewGroup(db)
+	relation := cache.NewRelation(client)
+	groupMember := repo.NewGroupMember(db, relation)
+	talkSession := repo.NewTalkSession(db)
+	pushService := &service.PushService{
+		Producer:        producer,
+		PushClient:      pushClient,
+		ClientStorage:   clientStorage,
+		UnreadStorage:   unreadStorage,
+		UserDeviceRepo:  userDevice,
+		UsersRepo:       users,
+		GroupRepo:       repoGroup,
+		GroupMemberRepo: groupMember,
+		TalkSessionRepo: talkSession,
+	}
+	pushDevice := &v1.PushDevice{
+		PushService: pushService,
 	}
-	department := repo.NewDepartment(db)
-	position := repo.NewPosition(db)
 	v1Organize := &v1.Organize{
 		DepartmentRepo: department,
 		PositionRepo:   position,
 		OrganizeRepo:   organize,
 	}
+	iFilesystem := provider.NewFilesystem(conf)
+	file := &v1.File{
+		Filesystem: iFilesystem,
+	}
 	messageStorage := cache.NewMessageStorage(client)
-	serverStorage := cache.NewSidStorage(client)
-	clientStorage := cache.NewClientStorage(client, conf, serverStorage)
-	unreadStorage := cache.NewUnreadStorage(client)
 	contactRemark := cache.NewContactRemark(client)
-	relation := cache.NewRelation(client)
 	repoContact := repo.NewContact(db, contactRemark, relation)
-	repoGroup := repo.NewGroup(db)
-	groupMember := repo.NewGroupMember(db, relation)
-	pushMessage := &business.PushMessage{
-		Redis: client,
-	}
+	talkMention := repo.NewTalkMention(db)
+	fileObject := repo.NewFileObject(db)
 	talkService := &service.TalkService{
 		Source:          source,
 		GroupMemberRepo: groupMember,
 		UserRepo:        users,
 		PushMessage:     pushMessage,
 		MessageStorage:  messageStorage,
+		FileObjectRepo:  fileObject,
 	}
-	talkSession := repo.NewTalkSession(db)
 	talkSessionService := &service.TalkSessionService{
 		Source:          source,
 		TalkSessionRepo: talkSession,
@@ -144,6 +254,7 @@ func NewHttpInjector(conf *config.Config) *apis.AppProvider {
 		ContactRepo:     repoContact,
 		GroupRepo:       repoGroup,
 		GroupMemberRepo: groupMember,
+		RobotRepo:       robot,
 	}
 	contactService := &service.ContactService{
 		Source:      source,
@@ -159,6 +270,7 @@ func NewHttpInjector(conf *config.Config) *apis.AppProvider {
 		UnreadStorage:        unreadStorage,
 		ContactRemark:        contactRemark,
 		ContactRepo:          repoContact,
+		TalkMentionRepo:      talkMention,
 		UsersRepo:            users,
 		GroupRepo:            repoGroup,
 		TalkService:          talkService,
@@ -169,7 +281,6 @@ func NewHttpInjector(conf *config.Config) *apis.AppProvider {
 		ContactService:       contactService,
 		ClientConnectService: clientConnectService,
 	}
-	iFilesystem := provider.NewFilesystem(conf)
 	talkMessage := &talk.Message{
 		TalkService: talkService,
 		AuthService: authService,
@@ -202,31 +313,30 @@ func NewHttpInjector(conf *config.Config) *apis.AppProvider {
 		AuthService:          authService,
 		Filesystem:           iFilesystem,
 	}
-	emoticon := repo.NewEmoticon(db)
-	emoticonService := &service.EmoticonService{
-		Source:       source,
-		EmoticonRepo: emoticon,
-		Filesystem:   iFilesystem,
-	}
-	v1Emoticon := &v1.Emoticon{
-		RedisLock:       redisLock,
-		EmoticonRepo:    emoticon,
-		EmoticonService: emoticonService,
-		Filesystem:      iFilesystem,
-	}
-	fileUpload := repo.NewFileUpload(db)
-	fileSplitUploadService := &service.FileSplitUploadService{
+	talkExport := repo.NewTalkExport(db)
+	talkExportService := &service.TalkExportService{
+		Source:            source,
+		TalkExportRepo:    talkExport,
+		GroupMemberRepo:   groupMember,
+		TalkRecordService: talkRecordService,
+		TemplateService:   templateService,
+		Filesystem:        iFilesystem,
+		Producer:          producer,
+	}
+	export := &talk.Export{
+		TalkExportRepo:    talkExport,
+		TalkExportService: talkExportService,
+		Filesystem:        iFilesystem,
+	}
+	remindStorage := cache.NewRemindStorage(client)
+	groupVoteService := &service.GroupVoteService{
 		Source:          source,
-		SplitUploadRepo: fileUpload,
-		Config:          conf,
-		FileSystem:      iFilesystem,
-	}
-	upload := &v1.Upload{
-		Config:             conf,
-		Filesystem:         iFilesystem,
-		SplitUploadService: fileSplitUploadService,
+		GroupMemberRepo: groupMember,
+		GroupVoteRepo:   groupVote,
+		Sequence:        repoSequence,
 	}
-	groupNotice := repo.NewGroupNotice(db)
+	fileUpload := repo.NewFileUpload(db)
+	storageUsage := repo.NewStorageUsage(db)
 	messageService := &message.Service{
 		Source:              source,
 		GroupMemberRepo:     groupMember,
@@ -240,8 +350,83 @@ func NewHttpInjector(conf *config.Config) *apis.AppProvider {
 		ClientStorage:       clientStorage,
 		Sequence:            repoSequence,
 		RobotRepo:           robot,
+		FileObjectRepo:      fileObject,
+		StorageUsageRepo:    storageUsage,
+		Config:              conf,
+		Producer:            producer,
 		PushMessage:         pushMessage,
 	}
+	commandService := &service.CommandService{
+		Source:             source,
+		GroupRepo:          repoGroup,
+		GroupMemberRepo:    groupMember,
+		UsersRepo:          users,
+		RobotRepo:          robot,
+		RemindStorage:      remindStorage,
+		GroupVoteService:   groupVoteService,
+		GroupMemberService: groupMemberService,
+		MessageService:     messageService,
+		Producer:           producer,
+	}
+	command := &talk.Command{
+		AuthService:    authService,
+		CommandService: commandService,
+	}
+	talkMentionService := &service.TalkMentionService{
+		TalkMentionRepo: talkMention,
+		UnreadStorage:   unreadStorage,
+	}
+	mention := &talk.Mention{
+		TalkMentionService: talkMentionService,
+	}
+	emoticon := repo.NewEmoticon(db)
+	emoticonService := &service.EmoticonService{
+		Source:       source,
+		EmoticonRepo: emoticon,
+		Filesystem:   iFilesystem,
+	}
+	storageQuotaService := &service.StorageQuotaService{
+		Config:           conf,
+		StorageUsageRepo: storageUsage,
+	}
+	scanner := provider.NewScanner(conf)
+	fileScanService := &service.FileScanService{
+		Config:             conf,
+		Scanner:            scanner,
+		FileSystem:         iFilesystem,
+		RobotRepo:          robot,
+		TalkSessionService: talkSessionService,
+		MessageService:     messageService,
+	}
+	v1Emoticon := &v1.Emoticon{
+		RedisLock:           redisLock,
+		EmoticonRepo:        emoticon,
+		EmoticonService:     emoticonService,
+		Filesystem:          iFilesystem,
+		StorageQuotaService: storageQuotaService,
+		FileScanService:     fileScanService,
+	}
+	uploadChallengeStorage := cache.NewUploadChallengeStorage(client)
+	fileSplitUploadService := &service.FileSplitUploadService{
+		Source:                 source,
+		SplitUploadRepo:        fileUpload,
+		Config:                 conf,
+		FileSystem:             iFilesystem,
+		RedisLock:              redisLock,
+		FileObjectRepo:         fileObject,
+		UploadChallengeStorage: uploadChallengeStorage,
+		StorageQuotaService:    storageQuotaService,
+		FileScanService:        fileScanService,
+	}
+	upload := &v1.Upload{
+		Config:              conf,
+		Filesystem:          iFilesystem,
+		SplitUploadService:  fileSplitUploadService,
+		StorageQuotaService: storageQuotaService,
+		FileScanService:     fileScanService,
+		GroupMemberRepo:     groupMember,
+	}
+	groupNotice := repo.NewGroupNotice(db)
 	groupGroup := &group.Group{
 		RedisLock:          redisLock,
 		Repo:               source,
@@ -281,12 +466,6 @@ func NewHttpInjector(conf *config.Config) *apis.AppProvider {
 		GroupService:       groupService,
 		PushMessage:        pushMessage,
 	}
-	groupVoteService := &service.GroupVoteService{
-		Source:          source,
-		GroupMemberRepo: groupMember,
-		GroupVoteRepo:   groupVote,
-		Sequence:        repoSequence,
-	}
 	vote2 := &group.Vote{
 		GroupMemberRepo:  groupMember,
 		GroupVoteRepo:    groupVote,
@@ -337,18 +516,35 @@ func NewHttpInjector(conf *config.Config) *apis.AppProvider {
 		ArticleHistory: articleHistory,
 	}
 	articleAnnexService := &service.ArticleAnnexService{
-		Source:       source,
-		ArticleAnnex: articleAnnex,
-		FileSystem:   iFilesystem,
+		Source:              source,
+		ArticleAnnex:        articleAnnex,
+		FileSystem:          iFilesystem,
+		StorageQuotaService: storageQuotaService,
+		FileScanService:     fileScanService,
 	}
-	articleArticle := &article.Article{
+	articleTagService := &service.ArticleTagService{
+		Source: source,
+	}
+	articleTransferService := &service.ArticleTransferService{
 		Source:              source,
-		ArticleAnnexRepo:    articleAnnex,
-		ArticleClassRepo:    articleClass,
 		ArticleRepo:         repoArticle,
+		ArticleClass:        articleClass,
+		ArticleAnnex:        articleAnnex,
 		ArticleService:      articleService,
+		ArticleClassService: articleClassService,
+		ArticleTagService:   articleTagService,
 		ArticleAnnexService: articleAnnexService,
-		Filesystem:          iFilesystem,
+		FileSystem:          iFilesystem,
+	}
+	articleArticle := &article.Article{
+		Source:                 source,
+		ArticleAnnexRepo:       articleAnnex,
+		ArticleClassRepo:       articleClass,
+		ArticleRepo:            repoArticle,
+		ArticleService:         articleService,
+		ArticleAnnexService:    articleAnnexService,
+		ArticleTransferService: articleTransferService,
+		Filesystem:             iFilesystem,
 	}
 	annex := &article.Annex{
 		ArticleAnnexRepo:    articleAnnex,
@@ -358,24 +554,28 @@ func NewHttpInjector(conf *config.Config) *apis.AppProvider {
 	class := &article.Class{
 		ArticleClassService: articleClassService,
 	}
-	articleTagService := &service.ArticleTagService{
-		Source: source,
-	}
 	tag := &article.Tag{
 		ArticleTagService: articleTagService,
 	}
 	publish := &talk.Publish{
 		AuthService:    authService,
 		MessageService: messageService,
+		CommandService: commandService,
 	}
 	webV1 := &web.V1{
 		Common:       common,
 		Auth:         auth,
 		User:         user,
+		TwoFactor:    v1TwoFactor,
+		PushDevice:   pushDevice,
 		Organize:     v1Organize,
+		File:         file,
 		Talk:         session,
 		TalkMessage:  talkMessage,
 		TalkRecords:  records,
+		TalkExport:   export,
+		TalkCommand:  command,
+		TalkMention:  mention,
 		Emoticon:     v1Emoticon,
 		Upload:       upload,
 		Group:        groupGroup,
@@ -396,18 +596,112 @@ func NewHttpInjector(conf *config.Config) *apis.AppProvider {
 	}
 	index := v1_2.NewIndex()
 	repoAdmin := repo.NewAdmin(db)
-	captchaStorage := cache.NewCaptchaStorage(client)
-	captcha := provider.NewBase64Captcha(captchaStorage)
+	adminRole := repo.NewAdminRole(db)
+	adminRoleService := &service.AdminRoleService{
+		AdminRepo:     repoAdmin,
+		AdminRoleRepo: adminRole,
+	}
 	v1Auth := &v1_2.Auth{
-		Config:          conf,
-		AdminRepo:       repoAdmin,
-		JwtTokenStorage: jwtTokenStorage,
-		ICaptcha:        captcha,
-		Rsa:             iRsa,
+		Config:           conf,
+		JwtKeySet:        keySet,
+		AdminRepo:        repoAdmin,
+		JwtTokenStorage:  jwtTokenStorage,
+		ICaptcha:         captcha,
+		Rsa:              iRsa,
+		RoleService:      adminRoleService,
+		TwoFactorService: twoFactorService,
+	}
+	twoFactor2 := &v1_2.TwoFactor{
+		AdminRepo:        repoAdmin,
+		TwoFactorService: twoFactorService,
+		Rsa:              iRsa,
+	}
+	usersLoginLog := repo.NewUsersLoginLog(db)
+	adminAuditLog := repo.NewAdminAuditLog(db)
+	adminAuditService := &service.AdminAuditService{
+		AdminAuditLogRepo: adminAuditLog,
+	}
+	v1User := &v1_2.User{
+		UsersRepo:         users,
+		UsersLoginLogRepo: usersLoginLog,
+		AdminUserService:  adminUserService,
+		AdminAuditService: adminAuditService,
+		Rsa:               iRsa,
+	}
+	adminGroupService := &service.AdminGroupService{
+		Source:             source,
+		GroupRepo:          repoGroup,
+		GroupMemberRepo:    groupMember,
+		UsersRepo:          users,
+		GroupService:       groupService,
+		GroupMemberService: groupMemberService,
+		TalkService:        talkService,
+		Message:            messageService,
+	}
+	v1Group := &v1_2.Group{
+		GroupRepo:         repoGroup,
+		GroupMemberRepo:   groupMember,
+		AdminGroupService: adminGroupService,
+		AdminAuditService: adminAuditService,
+	}
+	v1Message := &v1_2.Message{
+		AdminGroupService: adminGroupService,
+		AdminAuditService: adminAuditService,
+	}
+	audit := &v1_2.Audit{
+		AdminAuditLogRepo: adminAuditLog,
+	}
+	role := &v1_2.Role{
+		AdminRoleRepo:     adminRole,
+		AdminRoleService:  adminRoleService,
+		AdminAuditService: adminAuditService,
+	}
+	adminAccountService := &service.AdminAccountService{
+		AdminRepo:        repoAdmin,
+		AdminRoleRepo:    adminRole,
+		AdminRoleService: adminRoleService,
+	}
+	account := &v1_2.Account{
+		AdminRepo:           repoAdmin,
+		AdminAccountService: adminAccountService,
+		AdminAuditService:   adminAuditService,
+		Rsa:                 iRsa,
+		TwoFactorService:    twoFactorService,
+	}
+	robotService := &service.RobotService{
+		Source:    source,
+		RobotRepo: robot,
+		UsersRepo: users,
+	}
+	v1Robot := &v1_2.Robot{
+		RobotRepo:         robot,
+		RobotService:      robotService,
+		AdminAuditService: adminAuditService,
+	}
+	openApp := repo.NewOpenApp(db)
+	openNonceStorage := cache.NewOpenNonceStorage(client)
+	openAppService := &service.OpenAppService{
+		OpenAppRepo:      openApp,
+		RobotRepo:        robot,
+		OpenNonceStorage: openNonceStorage,
+	}
+	v1OpenApp := &v1_2.OpenApp{
+		OpenAppRepo:       openApp,
+		OpenAppService:    openAppService,
+		AdminAuditService: adminAuditService,
 	}
 	adminV1 := &admin.V1{
-		Index: index,
-		Auth:  v1Auth,
+		Index:     index,
+		Auth:      v1Auth,
+		TwoFactor: twoFactor2,
+		User:      v1User,
+		Group:     v1Group,
+		Message:   v1Message,
+		Audit:     audit,
+		Role:      role,
+		Account:   account,
+		Robot:     v1Robot,
+		OpenApp:   v1OpenApp,
 	}
 	v2 := &admin.V2{}
 	adminHandler := &admin.Handler{
@@ -415,8 +709,23 @@ func NewHttpInjector(conf *config.Config) *apis.AppProvider {
 		V2: v2,
 	}
 	v1Index := v1_3.NewIndex()
+	message2 := &v1_3.Message{
+		UsersRepo:      users,
+		AuthService:    authService,
+		MessageService: messageService,
+	}
+	v1Upload := &v1_3.Upload{
+		SplitUploadService: fileSplitUploadService,
+	}
+	group3 := &v1_3.Group{
+		GroupRepo:       repoGroup,
+		GroupMemberRepo: groupMember,
+	}
 	openV1 := &open.V1{
-		Index: v1Index,
+		Index:   v1Index,
+		Message: message2,
+		Upload:  v1Upload,
+		Group:   group3,
 	}
 	openHandler := &open.Handler{
 		V1: openV1,
@@ -426,7 +735,7 @@ func NewHttpInjector(conf *config.Config) *apis.AppProvider {
 		Admin: adminHandler,
 		Open:  openHandler,
 	}
-	engine := router.NewRouter(conf, handlerHandler, jwtTokenStorage)
+	engine := router.NewRouter(conf, handlerHandler, keySet, jwtTokenStorage, adminRoleService, openAppService)
 	appProvider := &apis.AppProvider{
 		Config: conf,
 		Engine: engine,
@@ -485,8 +794,9 @@ func NewCometInjector(conf *config.Config) *comet.AppProvider {
 		Config:      conf,
 		RoomStorage: roomStorage,
 	}
+	keySet := provider.NewJwtKeySet(conf)
 	jwtTokenStorage := cache.NewTokenSessionStorage(client)
-	engine := router2.NewRouter(conf, handlerHandler, jwtTokenStorage)
+	engine := router2.NewRouter(conf, handlerHandler, keySet, jwtTokenStorage)
 	healthSubscribe := process.NewHealthSubscribe(serverStorage)
 	organize := repo.NewOrganize(db)
 	users := repo.NewUsers(db, client)
@@ -529,9 +839,9 @@ func NewCometInjector(conf *config.Config) *comet.AppProvider {
 		MessageSubscribe: messageSubscribe,
 	}
 	server := process.NewServer(subServers)
-	emailClient := provider.NewEmailClient(conf)
+	sender := provider.NewEmailClient(conf)
 	providers := &provider.Providers{
-		EmailClient: emailClient,
+		EmailClient: sender,
 	}
 	appProvider := &comet.AppProvider{
 		Config:    conf,
@@ -551,22 +861,152 @@ func NewCronInjector(conf *config.Config) *mission.CronProvider {
 	}
 	db := provider.NewMySQLClient(conf)
 	iFilesystem := provider.NewFilesystem(conf)
+	storageUsage := repo.NewStorageUsage(db)
 	clearArticle := &cron.ClearArticle{
-		DB:         db,
-		Filesystem: iFilesystem,
+		DB:               db,
+		Filesystem:       iFilesystem,
+		StorageUsageRepo: storageUsage,
 	}
 	clearTmpFile := &cron.ClearTmpFile{
-		DB:         db,
-		Filesystem: iFilesystem,
+		DB:               db,
+		Filesystem:       iFilesystem,
+		StorageUsageRepo: storageUsage,
 	}
 	clearExpireServer := &cron.ClearExpireServer{
 		Storage: serverStorage,
 	}
+	remindStorage := cache.NewRemindStorage(client)
+	robot := repo.NewRobot(db)
+	repoGroup := repo.NewGroup(db)
+	source := repo.NewSource(db, client)
+	relation := cache.NewRelation(client)
+	groupMember := repo.NewGroupMember(db, relation)
+	fileUpload := repo.NewFileUpload(db)
+	vote := cache.NewVote(client)
+	groupVote := repo.NewGroupVote(db, vote)
+	users := repo.NewUsers(db, client)
+	unreadStorage := cache.NewUnreadStorage(client)
+	messageStorage := cache.NewMessageStorage(client)
+	clientStorage := cache.NewClientStorage(client, conf, serverStorage)
+	sequence := cache.NewSequence(client)
+	repoSequence := repo.NewSequence(db, sequence)
+	fileObject := repo.NewFileObject(db)
+	producer := provider.NewNsqProducer(conf)
+	pushMessage := &business.PushMessage{
+		Redis: client,
+	}
+	messageService := &message.Service{
+		Source:              source,
+		GroupMemberRepo:     groupMember,
+		SplitUploadRepo:     fileUpload,
+		TalkRecordsVoteRepo: groupVote,
+		UsersRepo:           users,
+		Filesystem:          iFilesystem,
+		UnreadStorage:       unreadStorage,
+		MessageStorage:      messageStorage,
+		ServerStorage:       serverStorage,
+		ClientStorage:       clientStorage,
+		Sequence:            repoSequence,
+		RobotRepo:           robot,
+		FileObjectRepo:      fileObject,
+		StorageUsageRepo:    storageUsage,
+		Config:              conf,
+		Producer:            producer,
+		PushMessage:         pushMessage,
+	}
+	remindNotify := &cron.RemindNotify{
+		RemindStorage:  remindStorage,
+		RobotRepo:      robot,
+		GroupRepo:      repoGroup,
+		MessageService: messageService,
+	}
+	ldapClient := provider.NewLdapClient(conf)
+	userIdentity := repo.NewUserIdentity(db)
+	sender := provider.NewEmailClient(conf)
+	emailOutbox := repo.NewEmailOutbox(db)
+	templateService := &service.TemplateService{}
+	smsStorage := cache.NewSmsStorage(client)
+	emailService := &service.EmailService{
+		Producer:        producer,
+		EmailClient:     sender,
+		EmailOutboxRepo: emailOutbox,
+		TemplateService: templateService,
+		CodeStorage:     smsStorage,
+	}
+	userService := &service.UserService{
+		UsersRepo:    users,
+		EmailService: emailService,
+	}
+	jwtTokenStorage := cache.NewTokenSessionStorage(client)
+	keySet := provider.NewJwtKeySet(conf)
+	authSessionStorage := cache.NewAuthSessionStorage(client)
+	userDevice := repo.NewUserDevice(db)
+	authSessionService := &service.AuthSessionService{
+		Config:             conf,
+		JwtKeySet:          keySet,
+		AuthSessionStorage: authSessionStorage,
+		JwtTokenStorage:    jwtTokenStorage,
+		UserDeviceRepo:     userDevice,
+	}
+	adminUserService := &service.AdminUserService{
+		UsersRepo:          users,
+		JwtTokenStorage:    jwtTokenStorage,
+		AuthSessionService: authSessionService,
+		PushMessage:        pushMessage,
+	}
+	organize := repo.NewOrganize(db)
+	department := repo.NewDepartment(db)
+	position := repo.NewPosition(db)
+	organizeSyncService := &service.OrganizeSyncService{
+		OrganizeRepo:   organize,
+		DepartmentRepo: department,
+		PositionRepo:   position,
+	}
+	ldapService := &service.LdapService{
+		Config:              conf,
+		Source:              source,
+		LdapClient:          ldapClient,
+		UsersRepo:           users,
+		UserIdentityRepo:    userIdentity,
+		UserService:         userService,
+		AdminUserService:    adminUserService,
+		OrganizeSyncService: organizeSyncService,
+	}
+	ldapSync := &cron.LdapSync{
+		Config:      conf,
+		LdapService: ldapService,
+	}
+	cronEmailOutbox := &cron.EmailOutbox{
+		EmailService: emailService,
+	}
+	usersSetting := repo.NewUsersSetting(db)
+	talkSession := repo.NewTalkSession(db)
+	talkSessionService := &service.TalkSessionService{
+		Source:          source,
+		TalkSessionRepo: talkSession,
+	}
+	offlineDigestService := &service.OfflineDigestService{
+		Config:             conf,
+		ClientStorage:      clientStorage,
+		UnreadStorage:      unreadStorage,
+		UsersRepo:          users,
+		UsersSettingRepo:   usersSetting,
+		TalkSessionService: talkSessionService,
+		EmailService:       emailService,
+	}
+	offlineDigest := &cron.OfflineDigest{
+		Config:               conf,
+		OfflineDigestService: offlineDigestService,
+	}
 	crontab := &cron.Crontab{
 		ClearWsCache:      clearWsCache,
 		ClearArticle:      clearArticle,
 		ClearTmpFile:      clearTmpFile,
 		ClearExpireServer: clearExpireServer,
+		RemindNotify:      remindNotify,
+		LdapSync:          ldapSync,
+		EmailOutbox:       cronEmailOutbox,
+		OfflineDigest:     offlineDigest,
 	}
 	cronProvider := &mission.CronProvider{
 		Config:  conf,
@@ -579,6 +1019,8 @@ func NewQueueInjector(conf *config.Config) *mission.QueueProvider {
 	db := provider.NewMySQLClient(conf)
 	robot := repo.NewRobot(db)
 	client := provider.NewRedisClient(conf)
+	users := repo.NewUsers(db, client)
+	usersLoginLog := repo.NewUsersLoginLog(db)
 	source := repo.NewSource(db, client)
 	httpClient := provider.NewHttpClient()
 	ipaddressClient := provider.NewIpAddressClient(httpClient)
@@ -592,12 +1034,23 @@ func NewQueueInjector(conf *config.Config) *mission.QueueProvider {
 		Source:          source,
 		TalkSessionRepo: talkSession,
 	}
+	producer := provider.NewNsqProducer(conf)
+	sender := provider.NewEmailClient(conf)
+	emailOutbox := repo.NewEmailOutbox(db)
+	templateService := &service.TemplateService{}
+	smsStorage := cache.NewSmsStorage(client)
+	emailService := &service.EmailService{
+		Producer:        producer,
+		EmailClient:     sender,
+		EmailOutboxRepo: emailOutbox,
+		TemplateService: templateService,
+		CodeStorage:     smsStorage,
+	}
 	relation := cache.NewRelation(client)
 	groupMember := repo.NewGroupMember(db, relation)
 	fileUpload := repo.NewFileUpload(db)
 	vote := cache.NewVote(client)
 	groupVote := repo.NewGroupVote(db, vote)
-	users := repo.NewUsers(db, client)
 	iFilesystem := provider.NewFilesystem(conf)
 	unreadStorage := cache.NewUnreadStorage(client)
 	messageStorage := cache.NewMessageStorage(client)
@@ -605,6 +1058,8 @@ func NewQueueInjector(conf *config.Config) *mission.QueueProvider {
 	clientStorage := cache.NewClientStorage(client, conf, serverStorage)
 	sequence := cache.NewSequence(client)
 	repoSequence := repo.NewSequence(db, sequence)
+	fileObject := repo.NewFileObject(db)
+	storageUsage := repo.NewStorageUsage(db)
 	pushMessage := &business.PushMessage{
 		Redis: client,
 	}
@@ -621,18 +1076,92 @@ func NewQueueInjector(conf *config.Config) *mission.QueueProvider {
 		ClientStorage:       clientStorage,
 		Sequence:            repoSequence,
 		RobotRepo:           robot,
+		FileObjectRepo:      fileObject,
+		StorageUsageRepo:    storageUsage,
+		Config:              conf,
+		Producer:            producer,
 		PushMessage:         pushMessage,
 	}
 	userLoginConsumer := &queue.UserLoginConsumer{
+		Config:             conf,
 		RobotRepo:          robot,
+		UsersRepo:          users,
+		UsersLoginLogRepo:  usersLoginLog,
 		IpAddressService:   ipAddressService,
 		TalkSessionService: talkSessionService,
+		EmailService:       emailService,
+		Message:            messageService,
+	}
+	talkExport := repo.NewTalkExport(db)
+	talkUserMessage := repo.NewTalkRecordFriend(db)
+	talkGroupMessage := repo.NewTalkRecordGroup(db)
+	talkGroupMessageDel := repo.NewTalkRecordGroupDel(db)
+	talkRecordService := &service.TalkRecordService{
+		Source:                source,
+		TalkVoteCache:         vote,
+		TalkRecordsVoteRepo:   groupVote,
+		GroupMemberRepo:       groupMember,
+		TalkRecordFriendRepo:  talkUserMessage,
+		TalkRecordGroupRepo:   talkGroupMessage,
+		TalkRecordsDeleteRepo: talkGroupMessageDel,
+	}
+	talkExportService := &service.TalkExportService{
+		Source:            source,
+		TalkExportRepo:    talkExport,
+		GroupMemberRepo:   groupMember,
+		TalkRecordService: talkRecordService,
+		TemplateService:   templateService,
+		Filesystem:        iFilesystem,
+		Producer:          producer,
+	}
+	talkExportConsumer := &queue.TalkExportConsumer{
+		Config:             conf,
+		RobotRepo:          robot,
+		TalkExportService:  talkExportService,
+		TalkSessionService: talkSessionService,
 		Message:            messageService,
 	}
+	repoGroup := repo.NewGroup(db)
+	robotWebhookConsumer := &queue.RobotWebhookConsumer{
+		RobotRepo:            robot,
+		UsersRepo:            users,
+		GroupRepo:            repoGroup,
+		TalkRecordFriendRepo: talkUserMessage,
+		TalkRecordGroupRepo:  talkGroupMessage,
+		Message:              messageService,
+	}
+	emailConsumer := &queue.EmailConsumer{
+		EmailService: emailService,
+	}
+	pushClient := provider.NewPushClient(conf)
+	userDevice := repo.NewUserDevice(db)
+	pushService := &service.PushService{
+		Producer:        producer,
+		PushClient:      pushClient,
+		ClientStorage:   clientStorage,
+		UnreadStorage:   unreadStorage,
+		UserDeviceRepo:  userDevice,
+		UsersRepo:       users,
+		GroupRepo:       repoGroup,
+		GroupMemberRepo: groupMember,
+		TalkSessionRepo: talkSession,
+	}
+	pushDispatchConsumer := &queue.PushDispatchConsumer{
+		PushService: pushService,
+	}
+	pushDeliverConsumer := &queue.PushDeliverConsumer{
+		PushService: pushService,
+	}
 	consumers := &queue.Consumers{
-		UserLoginConsumer: userLoginConsumer,
+		UserLoginConsumer:    userLoginConsumer,
+		TalkExportConsumer:   talkExportConsumer,
+		RobotWebhookConsumer: robotWebhookConsumer,
+		EmailConsumer:        emailConsumer,
+		PushDispatchConsumer: pushDispatchConsumer,
+		PushDeliverConsumer:  pushDeliverConsumer,
 	}
 	queueProvider := &mission.QueueProvider{
+		Config:    conf,
 		Consumers: consumers,
 		Redis:     client,
 	}
@@ -661,6 +1190,15 @@ func NewMigrateInjector(conf *config.Config) *mission.MigrateProvider {
 	return migrateProvider
 }
 
+func NewStorageInjector(conf *config.Config) *mission.StorageProvider {
+	db := provider.NewMySQLClient(conf)
+	storageProvider := &mission.StorageProvider{
+		Config: conf,
+		DB:     db,
+	}
+	return storageProvider
+}
+
 // wire.go:
 
 var providerSet = wire.NewSet(provider.ProviderSet, cache.ProviderSet, repo.ProviderSet, business.ProviderSet, service.ProviderSet)
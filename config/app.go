@@ -1,9 +1,33 @@
 package config
 
+import "strings"
+
 type App struct {
 	Env        string   `json:"env"`
 	Debug      bool     `json:"debug"`
 	PublicKey  string   `json:"-" yaml:"public_key"`
 	PrivateKey string   `json:"-" yaml:"private_key"`
 	AdminEmail []string `json:"admin_email"`
+	ApiUrl     string   `json:"api_url" yaml:"api_url"` // Http 接口服务对外访问地址，如 https://im-api.xxx.com
+
+	// TrustedProxies 可信代理地址(IP 或 CIDR)，仅信任来自这些地址的 X-Forwarded-For 请求头，默认仅信任本机
+	TrustedProxies []string `json:"trusted_proxies" yaml:"trusted_proxies"`
+}
+
+// GetTrustedProxies 获取可信代理地址
+func (a *App) GetTrustedProxies() []string {
+	if a == nil || len(a.TrustedProxies) == 0 {
+		return []string{"127.0.0.1", "::1"}
+	}
+
+	return a.TrustedProxies
+}
+
+// GetApiUrl 拼接 Http 接口的对外访问地址，未配置 api_url 时返回相对地址
+func (a *App) GetApiUrl(uri string) string {
+	if a == nil || a.ApiUrl == "" {
+		return uri
+	}
+
+	return strings.TrimRight(a.ApiUrl, "/") + uri
 }
@@ -16,9 +16,17 @@ type Config struct {
 	Cors       *Cors       `json:"cors" yaml:"cors"`
 	Log        *Log        `json:"log" yaml:"log"`
 	Filesystem *Filesystem `json:"filesystem" yaml:"filesystem"`
+	Upload     *Upload     `json:"upload" yaml:"upload"`   // 上传策略及存储配额
+	Scanner    *Scanner    `json:"scanner" yaml:"scanner"` // 文件安全扫描
 	Email      *Email      `json:"email" yaml:"email"`
 	Server     *Server     `json:"server" yaml:"server"`
-	Nsq        *Nsq        `json:"nsq" yaml:"nsq"` // 目前没用到
+	Nsq        *Nsq        `json:"nsq" yaml:"nsq"`     // 异步任务队列
+	Oidc       *Oidc       `json:"oidc" yaml:"oidc"`   // 第三方统一身份认证登录
+	Ldap       *Ldap       `json:"ldap" yaml:"ldap"`   // 目录服务认证及组织架构同步
+	Login      *Login      `json:"login" yaml:"login"` // 登录安全策略
+
+	OfflineDigest *OfflineDigest `json:"offline_digest" yaml:"offline_digest"` // 离线消息邮件摘要
+	Push          *Push          `json:"push" yaml:"push"`                     // 离线消息推送
 }
 
 type Server struct {
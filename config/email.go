@@ -1,7 +1,16 @@
 package config
 
+// 邮件发送驱动
+const (
+	EmailDriverSmtp = "smtp" // SMTP 发送
+	EmailDriverFile = "file" // 保存为 .eml 文件，用于开发及测试环境
+	EmailDriverLog  = "log"  // 输出到标准输出，用于开发及测试环境
+)
+
 // Email 邮件配置信息
 type Email struct {
+	Driver   string `yaml:"driver"`   // 发送驱动[smtp;file;log;]，默认 smtp
+	Path     string `yaml:"path"`     // file 驱动保存邮件的目录
 	Host     string `yaml:"host"`     // smtp.163.com
 	Port     int    `yaml:"port"`     // 端口号
 	UserName string `yaml:"username"` // 登录账号
@@ -3,7 +3,9 @@ package config
 import "go-chat/internal/pkg/filesystem"
 
 type Filesystem struct {
-	Default string                       `json:"default" yaml:"default"`
-	Local   filesystem.LocalSystemConfig `json:"local" yaml:"local"`
-	Minio   filesystem.MinioSystemConfig `json:"minio" yaml:"minio"`
+	Default string                        `json:"default" yaml:"default"`
+	Local   filesystem.LocalSystemConfig  `json:"local" yaml:"local"`
+	Minio   filesystem.MinioSystemConfig  `json:"minio" yaml:"minio"`
+	S3      filesystem.S3SystemConfig     `json:"s3" yaml:"s3"`
+	Webdav  filesystem.WebDAVSystemConfig `json:"webdav" yaml:"webdav"`
 }
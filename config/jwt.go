@@ -1,8 +1,36 @@
 package config
 
+import "time"
+
 // Jwt 相关配置信息
 type Jwt struct {
-	Secret      string `yaml:"secret"`       // Jwt 秘钥
-	ExpiresTime int64  `yaml:"expires_time"` // 过期时间(单位秒)
-	BufferTime  int64  `yaml:"buffer_time"`  // 缓冲时间(单位秒)
+	Secret            string `yaml:"secret"`              // Jwt 秘钥
+	ExpiresTime       int64  `yaml:"expires_time"`        // 访问令牌过期时间(单位秒)
+	BufferTime        int64  `yaml:"buffer_time"`         // 缓冲时间(单位秒)
+	RefreshTime       int64  `yaml:"refresh_time"`        // 刷新令牌过期时间(单位秒)，默认 30 天
+	KeyDir            string `yaml:"key_dir"`             // 非对称签名密钥目录，为空时使用 Secret(HS256) 签名
+	LegacySecretUntil string `yaml:"legacy_secret_until"` // 启用非对称签名后仍接受 Secret 签名令牌的截止时间(2006-01-02 15:04:05)
+}
+
+// GetRefreshTime 刷新令牌过期时间(单位秒)
+func (j *Jwt) GetRefreshTime() int64 {
+	if j.RefreshTime <= 0 {
+		return 30 * 24 * 3600
+	}
+
+	return j.RefreshTime
+}
+
+// GetLegacySecretUntil 启用非对称签名后 HS256 令牌的过渡截止时间，未配置或格式错误时返回零值
+func (j *Jwt) GetLegacySecretUntil() time.Time {
+	if j.LegacySecretUntil == "" {
+		return time.Time{}
+	}
+
+	t, err := time.ParseInLocation(time.DateTime, j.LegacySecretUntil, time.Local)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return t
 }
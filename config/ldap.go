@@ -0,0 +1,11 @@
+package config
+
+import "go-chat/internal/pkg/ldap"
+
+// Ldap 目录服务(LDAP)认证及组织架构同步配置
+type Ldap struct {
+	Enabled       bool        `json:"enabled" yaml:"enabled"`               // 是否启用目录认证
+	FallbackLocal bool        `json:"fallback_local" yaml:"fallback_local"` // 目录中不存在的账号是否继续使用本地密码登录
+	SyncSpec      string      `json:"sync_spec" yaml:"sync_spec"`           // 同步定时任务规则，为空时不同步
+	Server        ldap.Config `json:"server" yaml:"server"`
+}
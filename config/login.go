@@ -0,0 +1,39 @@
+package config
+
+// Login 登录安全策略
+type Login struct {
+	Window             int  `json:"window" yaml:"window"`                             // 失败次数统计周期(秒)，默认 900
+	CaptchaThreshold   int  `json:"captcha_threshold" yaml:"captcha_threshold"`       // 账号连续失败达到该次数后需填写图形验证码，默认 3
+	IpCaptchaThreshold int  `json:"ip_captcha_threshold" yaml:"ip_captcha_threshold"` // 同一 IP 失败达到该次数后需填写图形验证码，默认 10
+	LockThreshold      int  `json:"lock_threshold" yaml:"lock_threshold"`             // 账号密码或动态码失败达到该次数后临时锁定，默认 10
+	IpLockThreshold    int  `json:"ip_lock_threshold" yaml:"ip_lock_threshold"`       // 同一 IP 失败达到该次数后临时禁止登录，默认 50
+	LockDuration       int  `json:"lock_duration" yaml:"lock_duration"`               // 锁定时长(秒)，默认 900
+	MaxDelay           int  `json:"max_delay" yaml:"max_delay"`                       // 失败后递增等待时间的上限(秒)，默认 30
+	AlertEmail         bool `json:"alert_email" yaml:"alert_email"`                   // 新设备或异地登录时是否发送邮件提醒
+}
+
+// WithDefault 返回补全默认值后的登录安全策略
+func (l *Login) WithDefault() *Login {
+	if l == nil {
+		l = &Login{AlertEmail: true}
+	}
+
+	conf := *l
+	conf.Window = withDefaultInt(conf.Window, 900)
+	conf.CaptchaThreshold = withDefaultInt(conf.CaptchaThreshold, 3)
+	conf.IpCaptchaThreshold = withDefaultInt(conf.IpCaptchaThreshold, 10)
+	conf.LockThreshold = withDefaultInt(conf.LockThreshold, 10)
+	conf.IpLockThreshold = withDefaultInt(conf.IpLockThreshold, 50)
+	conf.LockDuration = withDefaultInt(conf.LockDuration, 900)
+	conf.MaxDelay = withDefaultInt(conf.MaxDelay, 30)
+
+	return &conf
+}
+
+func withDefaultInt(value int, def int) int {
+	if value <= 0 {
+		return def
+	}
+
+	return value
+}
@@ -0,0 +1,13 @@
+package config
+
+// OfflineDigest 离线消息邮件摘要
+type OfflineDigest struct {
+	Enabled      bool   `json:"enabled" yaml:"enabled"`             // 是否启用
+	Spec         string `json:"spec" yaml:"spec"`                   // 定时任务规则，为空时不发送
+	OfflineHours int    `json:"offline_hours" yaml:"offline_hours"` // 离线超过该时长(小时)且有未读消息的用户才会收到摘要，默认 12
+}
+
+// GetOfflineHours 返回离线时长阈值(小时)
+func (o *OfflineDigest) GetOfflineHours() int {
+	return withDefaultInt(o.OfflineHours, 12)
+}
@@ -0,0 +1,36 @@
+package config
+
+// Oidc 第三方统一身份认证(OpenID Connect)登录配置
+type Oidc struct {
+	Providers []*OidcProvider `json:"providers" yaml:"providers"`
+}
+
+type OidcProvider struct {
+	Name          string   `json:"name" yaml:"name"`                     // 唯一标识，登录及回调时使用
+	Title         string   `json:"title" yaml:"title"`                   // 登录按钮显示名称
+	Issuer        string   `json:"issuer" yaml:"issuer"`                 // 颁发者地址
+	ClientId      string   `json:"client_id" yaml:"client_id"`           // 客户端ID
+	ClientSecret  string   `json:"client_secret" yaml:"client_secret"`   // 客户端密钥
+	RedirectUrl   string   `json:"redirect_url" yaml:"redirect_url"`     // 授权回调地址(前端页面)
+	Scopes        []string `json:"scopes" yaml:"scopes"`                 // 授权范围，默认 openid profile email
+	AutoRegister  bool     `json:"auto_register" yaml:"auto_register"`   // 未关联账号时是否自动创建账号
+	LinkByEmail   bool     `json:"link_by_email" yaml:"link_by_email"`   // 是否按已验证的邮箱关联已有账号
+	SyncOrganize  bool     `json:"sync_organize" yaml:"sync_organize"`   // 登录时是否同步部门及岗位
+	DeptClaim     string   `json:"dept_claim" yaml:"dept_claim"`         // 部门声明名称，多级部门以 / 分隔
+	PositionClaim string   `json:"position_claim" yaml:"position_claim"` // 岗位声明名称
+}
+
+// GetProvider 根据标识获取身份提供方配置
+func (o *Oidc) GetProvider(name string) *OidcProvider {
+	if o == nil {
+		return nil
+	}
+
+	for _, provider := range o.Providers {
+		if provider.Name == name {
+			return provider
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package config
+
+// Push 离线消息推送
+type Push struct {
+	Enabled bool      `json:"enabled" yaml:"enabled"` // 是否启用
+	Apns    *PushApns `json:"apns" yaml:"apns"`       // iOS 推送，为空时不启用
+	Fcm     *PushFcm  `json:"fcm" yaml:"fcm"`         // Android 推送，为空时不启用
+}
+
+type PushApns struct {
+	Endpoint string `json:"endpoint" yaml:"endpoint"` // 接口地址，为空时使用生产环境
+	KeyId    string `json:"key_id" yaml:"key_id"`     // 认证密钥ID
+	TeamId   string `json:"team_id" yaml:"team_id"`   // 开发者团队ID
+	Topic    string `json:"topic" yaml:"topic"`       // 应用的 Bundle ID
+	KeyFile  string `json:"key_file" yaml:"key_file"` // 认证密钥文件(.p8)
+}
+
+type PushFcm struct {
+	Endpoint        string `json:"endpoint" yaml:"endpoint"`                 // 接口地址，为空时使用官方地址
+	CredentialsFile string `json:"credentials_file" yaml:"credentials_file"` // 服务账号密钥文件(.json)
+}
+
+// IsEnabled 是否启用离线消息推送
+func (p *Push) IsEnabled() bool {
+	return p != nil && p.Enabled
+}
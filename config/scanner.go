@@ -0,0 +1,10 @@
+package config
+
+import "go-chat/internal/pkg/scanner"
+
+// Scanner 文件安全扫描配置
+type Scanner struct {
+	Driver   string              `json:"driver" yaml:"driver"`       // 扫描驱动[clamd;none]，默认 none
+	FailOpen bool                `json:"fail_open" yaml:"fail_open"` // 扫描服务不可用时是否放行文件
+	Clamd    scanner.ClamdConfig `json:"clamd" yaml:"clamd"`
+}
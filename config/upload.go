@@ -0,0 +1,122 @@
+package config
+
+import (
+	"slices"
+	"strings"
+)
+
+// 上传类型
+const (
+	UploadTypeAvatar   = "avatar"   // 头像
+	UploadTypeImage    = "image"    // 聊天图片
+	UploadTypeFile     = "file"     // 聊天文件(分片上传)
+	UploadTypeEmoticon = "emoticon" // 自定义表情
+	UploadTypeAnnex    = "annex"    // 笔记附件
+)
+
+// Upload 上传策略配置
+type Upload struct {
+	UserQuota  int64                    `json:"user_quota" yaml:"user_quota"`   // 单个用户存储配额(MB)，0 表示不限制
+	GroupQuota int64                    `json:"group_quota" yaml:"group_quota"` // 单个群组文件配额(MB)，0 表示不限制
+	Policies   map[string]*UploadPolicy `json:"policies" yaml:"policies"`       // 各上传类型的上传策略
+}
+
+// UploadPolicy 上传策略
+type UploadPolicy struct {
+	MaxSize    int64    `json:"max_size" yaml:"max_size"`     // 单个文件大小上限(MB)，0 表示不限制
+	Extensions []string `json:"extensions" yaml:"extensions"` // 允许的文件后缀，为空表示不限制
+	MimeTypes  []string `json:"mime_types" yaml:"mime_types"` // 允许的媒体类型(支持 image/* 通配)，为空表示不限制
+}
+
+// 未配置时的默认上传策略
+var defaultUploadPolicies = map[string]*UploadPolicy{
+	UploadTypeAvatar: {
+		MaxSize:    5,
+		Extensions: []string{"png", "jpg", "jpeg", "gif", "webp"},
+		MimeTypes:  []string{"image/*"},
+	},
+	UploadTypeImage: {
+		MaxSize:    20,
+		Extensions: []string{"png", "jpg", "jpeg", "gif", "webp"},
+		MimeTypes:  []string{"image/*"},
+	},
+	UploadTypeFile: {
+		MaxSize: 1024,
+	},
+	UploadTypeEmoticon: {
+		MaxSize:    5,
+		Extensions: []string{"png", "jpg", "jpeg", "gif"},
+		MimeTypes:  []string{"image/*"},
+	},
+	UploadTypeAnnex: {
+		MaxSize: 10,
+	},
+}
+
+// GetPolicy 获取上传类型对应的上传策略
+func (u *Upload) GetPolicy(uploadType string) *UploadPolicy {
+	if u != nil {
+		if policy, ok := u.Policies[uploadType]; ok && policy != nil {
+			return policy
+		}
+	}
+
+	if policy, ok := defaultUploadPolicies[uploadType]; ok {
+		return policy
+	}
+
+	return &UploadPolicy{}
+}
+
+// GetUserQuota 用户存储配额(字节)
+func (u *Upload) GetUserQuota() int64 {
+	if u == nil {
+		return 0
+	}
+
+	return u.UserQuota << 20
+}
+
+// GetGroupQuota 群组文件配额(字节)
+func (u *Upload) GetGroupQuota() int64 {
+	if u == nil {
+		return 0
+	}
+
+	return u.GroupQuota << 20
+}
+
+// MaxSizeBytes 单个文件大小上限(字节)
+func (p *UploadPolicy) MaxSizeBytes() int64 {
+	return p.MaxSize << 20
+}
+
+// AllowExtension 判断文件后缀是否允许上传
+func (p *UploadPolicy) AllowExtension(ext string) bool {
+	if len(p.Extensions) == 0 {
+		return true
+	}
+
+	return slices.Contains(p.Extensions, strings.ToLower(ext))
+}
+
+// AllowMimeType 判断媒体类型是否允许上传
+func (p *UploadPolicy) AllowMimeType(mimeType string) bool {
+	if len(p.MimeTypes) == 0 {
+		return true
+	}
+
+	mimeType, _, _ = strings.Cut(mimeType, ";")
+
+	for _, value := range p.MimeTypes {
+		if value == mimeType {
+			return true
+		}
+
+		if prefix, ok := strings.CutSuffix(value, "/*"); ok && strings.HasPrefix(mimeType, prefix+"/") {
+			return true
+		}
+	}
+
+	return false
+}
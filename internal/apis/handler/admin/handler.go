@@ -5,8 +5,17 @@ import (
 )
 
 type V1 struct {
-	Index *v12.Index
-	Auth  *v12.Auth
+	Index     *v12.Index
+	Auth      *v12.Auth
+	TwoFactor *v12.TwoFactor
+	User      *v12.User
+	Group     *v12.Group
+	Message   *v12.Message
+	Audit     *v12.Audit
+	Role      *v12.Role
+	Account   *v12.Account
+	Robot     *v12.Robot
+	OpenApp   *v12.OpenApp
 }
 
 type V2 struct{}
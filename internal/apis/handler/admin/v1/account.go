@@ -0,0 +1,189 @@
+package v1
+
+import (
+	"go-chat/internal/pkg/core"
+	"go-chat/internal/pkg/encrypt/rsautil"
+	"go-chat/internal/pkg/timeutil"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+	"go-chat/internal/service"
+	"gorm.io/gorm"
+)
+
+type Account struct {
+	AdminRepo           *repo.Admin
+	AdminAccountService service.IAdminAccountService
+	AdminAuditService   service.IAdminAuditService
+	Rsa                 rsautil.IRsa
+	TwoFactorService    service.ITwoFactorService
+}
+
+type AccountCreateRequest struct {
+	Username     string `form:"username" json:"username" binding:"required,max=20"`               // 登录账号
+	Password     string `form:"password" json:"password"`                                         // 登录密码(RSA 加密)
+	Email        string `form:"email" json:"email" binding:"required,email,max=30"`               // 邮箱
+	Mobile       string `form:"mobile" json:"mobile" binding:"omitempty,len=11"`                  // 手机号
+	RoleId       int    `form:"role_id" json:"role_id" binding:"required,min=1"`                  // 角色ID
+	Status       int    `form:"status" json:"status" binding:"required,oneof=1 2"`                // 状态[1:正常;2:停用;]
+	TotpRequired int    `form:"totp_required" json:"totp_required" binding:"omitempty,oneof=1 2"` // 强制两步验证[1:是;2:否;]
+}
+
+type AccountUpdateRequest struct {
+	AdminId int `form:"admin_id" json:"admin_id" binding:"required,min=1"` // 管理员ID
+	AccountCreateRequest
+}
+
+type AccountDeleteRequest struct {
+	AdminId int `form:"admin_id" json:"admin_id" binding:"required,min=1"` // 管理员ID
+}
+
+type AccountResetTwoFactorRequest struct {
+	AdminId int `form:"admin_id" json:"admin_id" binding:"required,min=1"` // 管理员ID
+}
+
+type AccountItem struct {
+	Id           int    `json:"id"`
+	Username     string `json:"username"`
+	Email        string `json:"email"`
+	Mobile       string `json:"mobile"`
+	RoleId       int    `json:"role_id"`
+	Status       int    `json:"status"`
+	TotpRequired int    `json:"totp_required"`
+	TwoFactor    bool   `json:"two_factor"`
+	CreatedAt    string `json:"created_at"`
+}
+
+// List 管理员列表
+func (c *Account) List(ctx *core.Context) error {
+	admins, err := c.AdminRepo.FindAll(ctx.Ctx(), func(db *gorm.DB) {
+		db.Order("id asc")
+	})
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	enabled, err := c.TwoFactorService.EnabledOwners(ctx.Ctx(), model.TwoFactorOwnerAdmin)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	items := make([]*AccountItem, 0, len(admins))
+	for _, admin := range admins {
+		items = append(items, &AccountItem{
+			Id:           admin.Id,
+			Username:     admin.Username,
+			Email:        admin.Email,
+			Mobile:       admin.Mobile,
+			RoleId:       admin.RoleId,
+			Status:       int(admin.Status),
+			TotpRequired: int(admin.TotpRequired),
+			TwoFactor:    enabled[admin.Id],
+			CreatedAt:    timeutil.FormatDatetime(admin.CreatedAt),
+		})
+	}
+
+	return ctx.Success(map[string]any{"items": items})
+}
+
+// Create 创建管理员
+func (c *Account) Create(ctx *core.Context) error {
+	in := &AccountCreateRequest{}
+	if err := ctx.Context.ShouldBindJSON(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	opt, err := c.option(in)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	admin, err := c.AdminAccountService.Create(ctx.Ctx(), ctx.UserId(), opt)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	in.Password = ""
+	audit(ctx, c.AdminAuditService, model.AdminAuditAdminCreate, model.AdminAuditTargetAdmin, admin.Id, in)
+
+	return ctx.Success(map[string]any{"admin_id": admin.Id})
+}
+
+// Update 更新管理员
+func (c *Account) Update(ctx *core.Context) error {
+	in := &AccountUpdateRequest{}
+	if err := ctx.Context.ShouldBindJSON(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	opt, err := c.option(&in.AccountCreateRequest)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	if err := c.AdminAccountService.Update(ctx.Ctx(), ctx.UserId(), in.AdminId, opt); err != nil {
+		return ctx.Error(err)
+	}
+
+	in.Password = ""
+	audit(ctx, c.AdminAuditService, model.AdminAuditAdminUpdate, model.AdminAuditTargetAdmin, in.AdminId, in)
+
+	return ctx.Success(nil)
+}
+
+// Delete 删除管理员
+func (c *Account) Delete(ctx *core.Context) error {
+	in := &AccountDeleteRequest{}
+	if err := ctx.Context.ShouldBind(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.AdminAccountService.Delete(ctx.Ctx(), ctx.UserId(), in.AdminId); err != nil {
+		return ctx.Error(err)
+	}
+
+	audit(ctx, c.AdminAuditService, model.AdminAuditAdminDelete, model.AdminAuditTargetAdmin, in.AdminId, in)
+
+	return ctx.Success(nil)
+}
+
+// ResetTwoFactor 重置管理员两步验证，用于身份验证器及恢复码均丢失的情况
+func (c *Account) ResetTwoFactor(ctx *core.Context) error {
+	in := &AccountResetTwoFactorRequest{}
+	if err := ctx.Context.ShouldBind(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.AdminAccountService.CheckManageable(ctx.Ctx(), ctx.UserId(), in.AdminId); err != nil {
+		return ctx.Error(err)
+	}
+
+	if err := c.TwoFactorService.Disable(ctx.Ctx(), model.TwoFactorOwnerAdmin, in.AdminId); err != nil {
+		return ctx.Error(err)
+	}
+
+	audit(ctx, c.AdminAuditService, model.AdminAuditAdminResetTwoFactor, model.AdminAuditTargetAdmin, in.AdminId, in)
+
+	return ctx.Success(nil)
+}
+
+func (c *Account) option(in *AccountCreateRequest) (*service.AdminAccountOpt, error) {
+	opt := &service.AdminAccountOpt{
+		Username:     in.Username,
+		Email:        in.Email,
+		Mobile:       in.Mobile,
+		RoleId:       in.RoleId,
+		Status:       in.Status,
+		TotpRequired: in.TotpRequired,
+	}
+
+	if in.Password != "" {
+		password, err := c.Rsa.Decrypt(in.Password)
+		if err != nil {
+			return nil, err
+		}
+
+		opt.Password = string(password)
+	}
+
+	return opt, nil
+}
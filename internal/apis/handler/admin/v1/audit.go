@@ -0,0 +1,94 @@
+package v1
+
+import (
+	"fmt"
+
+	"go-chat/internal/pkg/core"
+	"go-chat/internal/pkg/logger"
+	"go-chat/internal/pkg/timeutil"
+	"go-chat/internal/repository/repo"
+	"go-chat/internal/service"
+)
+
+type Audit struct {
+	AdminAuditLogRepo *repo.AdminAuditLog
+}
+
+type AuditListRequest struct {
+	AdminId    int    `form:"admin_id" json:"admin_id"`                          // 管理员ID
+	Action     string `form:"action" json:"action"`                              // 操作类型
+	TargetType string `form:"target_type" json:"target_type"`                    // 操作对象类型
+	TargetId   string `form:"target_id" json:"target_id"`                        // 操作对象ID
+	Page       int    `form:"page" json:"page" binding:"required,min=1"`         // 页码
+	Size       int    `form:"size" json:"size" binding:"required,min=1,max=100"` // 每页数量
+}
+
+type AuditItem struct {
+	Id         int    `json:"id"`
+	AdminId    int    `json:"admin_id"`
+	Action     string `json:"action"`
+	TargetType string `json:"target_type"`
+	TargetId   string `json:"target_id"`
+	Detail     string `json:"detail"`
+	Ip         string `json:"ip"`
+	CreatedAt  string `json:"created_at"`
+}
+
+// List 操作审计日志列表
+func (c *Audit) List(ctx *core.Context) error {
+	in := &AuditListRequest{}
+	if err := ctx.Context.ShouldBindQuery(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	logs, total, err := c.AdminAuditLogRepo.SearchList(ctx.Ctx(), &repo.SearchAdminAuditLogOpt{
+		AdminId:    in.AdminId,
+		Action:     in.Action,
+		TargetType: in.TargetType,
+		TargetId:   in.TargetId,
+		Page:       in.Page,
+		Size:       in.Size,
+	})
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	items := make([]*AuditItem, 0, len(logs))
+	for _, log := range logs {
+		items = append(items, &AuditItem{
+			Id:         log.Id,
+			AdminId:    log.AdminId,
+			Action:     log.Action,
+			TargetType: log.TargetType,
+			TargetId:   log.TargetId,
+			Detail:     log.Detail,
+			Ip:         log.Ip,
+			CreatedAt:  timeutil.FormatDatetime(log.CreatedAt),
+		})
+	}
+
+	return ctx.Success(map[string]any{
+		"items": items,
+		"paginate": map[string]any{
+			"page":  in.Page,
+			"size":  in.Size,
+			"total": total,
+		},
+	})
+}
+
+// 记录管理员操作日志
+func audit(ctx *core.Context, s service.IAdminAuditService, action string, targetType string, targetId any, detail any) {
+	err := s.Record(ctx.Ctx(), &service.AdminAuditRecordOpt{
+		AdminId:    ctx.UserId(),
+		Action:     action,
+		TargetType: targetType,
+		TargetId:   fmt.Sprintf("%v", targetId),
+		Detail:     detail,
+		Ip:         ctx.Context.ClientIP(),
+	})
+
+	if err != nil {
+		logger.Errorf("记录管理员操作日志失败 admin_id:%d action:%s err:%s", ctx.UserId(), action, err.Error())
+	}
+}
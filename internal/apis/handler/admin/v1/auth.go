@@ -16,15 +16,36 @@ import (
 	"go-chat/internal/repository/cache"
 	"go-chat/internal/repository/model"
 	"go-chat/internal/repository/repo"
+	"go-chat/internal/service"
 	"gorm.io/gorm"
 )
 
 type Auth struct {
-	Config          *config.Config
-	AdminRepo       *repo.Admin
-	JwtTokenStorage *cache.JwtTokenStorage
-	ICaptcha        *base64Captcha.Captcha
-	Rsa             rsautil.IRsa
+	Config           *config.Config
+	JwtKeySet        *jwt.KeySet
+	AdminRepo        *repo.Admin
+	JwtTokenStorage  *cache.JwtTokenStorage
+	ICaptcha         *base64Captcha.Captcha
+	Rsa              rsautil.IRsa
+	RoleService      service.IAdminRoleService
+	TwoFactorService service.ITwoFactorService
+}
+
+// AuthTwoFactorResponse 密码校验通过但需完成两步验证时的登录响应
+type AuthTwoFactorResponse struct {
+	Type         string `json:"type"`           // 固定为 TwoFactor
+	Action       string `json:"action"`         // 待完成的操作[verify:校验动态码;setup:绑定身份验证器;]
+	PreAuthToken string `json:"pre_auth_token"` // 登录临时凭证
+	ExpiresIn    int32  `json:"expires_in"`     // 临时凭证有效期(秒)
+}
+
+type AuthPreAuthRequest struct {
+	PreAuthToken string `form:"pre_auth_token" json:"pre_auth_token" binding:"required"` // 登录临时凭证
+}
+
+type AuthTwoFactorLoginRequest struct {
+	PreAuthToken string `form:"pre_auth_token" json:"pre_auth_token" binding:"required"` // 登录临时凭证
+	Code         string `form:"code" json:"code" binding:"required,max=16"`              // 动态码或恢复码
 }
 
 // Login 登录接口
@@ -61,22 +82,110 @@ func (c *Auth) Login(ctx *core.Context) error {
 		return ctx.Error(entity.ErrAccountDisabled)
 	}
 
-	expiresAt := time.Now().Add(12 * time.Hour)
+	enabled, err := c.TwoFactorService.IsEnabled(ctx.Ctx(), model.TwoFactorOwnerAdmin, adminInfo.Id)
+	if err != nil {
+		return ctx.Error(err)
+	}
 
-	// 生成登录凭证
-	token := jwt.GenerateToken("admin", c.Config.Jwt.Secret, &jwt.Options{
-		ExpiresAt: jwt.NewNumericDate(expiresAt),
-		ID:        strconv.Itoa(adminInfo.Id),
-		Issuer:    "im.admin",
-		IssuedAt:  jwt.NewNumericDate(time.Now()),
+	// 已开启两步验证的账号需校验动态码，被要求强制开启的账号需先完成绑定
+	action := ""
+	if enabled {
+		action = service.TwoFactorActionVerify
+	} else if adminInfo.TotpRequired == model.Yes {
+		action = service.TwoFactorActionSetup
+	}
+
+	if action != "" {
+		token, err := c.TwoFactorService.CreatePreAuth(ctx.Ctx(), &cache.PreAuth{
+			Guard:  "admin",
+			Action: action,
+			UserId: adminInfo.Id,
+		})
+		if err != nil {
+			return ctx.Error(err)
+		}
+
+		return ctx.Success(&AuthTwoFactorResponse{
+			Type:         "TwoFactor",
+			Action:       action,
+			PreAuthToken: token,
+			ExpiresIn:    int32(service.TwoFactorPreAuthExpire.Seconds()),
+		})
+	}
+
+	return ctx.Success(&admin.AuthLoginResponse{
+		Auth: c.token(adminInfo.Id),
 	})
+}
+
+// LoginTwoFactor 两步验证登录接口
+func (c *Auth) LoginTwoFactor(ctx *core.Context) error {
+
+	var in AuthTwoFactorLoginRequest
+	if err := ctx.Context.ShouldBindJSON(&in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	preAuth, err := c.TwoFactorService.CompletePreAuth(ctx.Ctx(), in.PreAuthToken, "admin", in.Code)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	if err := c.check(ctx, preAuth.UserId); err != nil {
+		return ctx.Error(err)
+	}
 
 	return ctx.Success(&admin.AuthLoginResponse{
-		Auth: &admin.AccessToken{
-			Type:        "Bearer",
-			AccessToken: token,
-			ExpiresIn:   int32(expiresAt.Unix() - time.Now().Unix()),
-		},
+		Auth: c.token(preAuth.UserId),
+	})
+}
+
+// LoginSetup 强制开启两步验证时生成身份验证器绑定信息
+func (c *Auth) LoginSetup(ctx *core.Context) error {
+
+	var in AuthPreAuthRequest
+	if err := ctx.Context.ShouldBindJSON(&in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	preAuth, err := c.TwoFactorService.GetPreAuth(ctx.Ctx(), in.PreAuthToken, "admin", service.TwoFactorActionSetup)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	adminInfo, err := c.AdminRepo.FindById(ctx.Ctx(), preAuth.UserId)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	setup, err := c.TwoFactorService.Setup(ctx.Ctx(), model.TwoFactorOwnerAdmin, adminInfo.Id, adminInfo.Username)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	return ctx.Success(setup)
+}
+
+// LoginEnable 强制开启两步验证时确认绑定并登录
+func (c *Auth) LoginEnable(ctx *core.Context) error {
+
+	var in AuthTwoFactorLoginRequest
+	if err := ctx.Context.ShouldBindJSON(&in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	preAuth, codes, err := c.TwoFactorService.CompleteSetup(ctx.Ctx(), in.PreAuthToken, "admin", in.Code)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	if err := c.check(ctx, preAuth.UserId); err != nil {
+		return ctx.Error(err)
+	}
+
+	return ctx.Success(map[string]any{
+		"recovery_codes": codes,
+		"auth":           c.token(preAuth.UserId),
 	})
 }
 
@@ -96,12 +205,7 @@ func (c *Auth) Captcha(ctx *core.Context) error {
 // Logout 退出登录接口
 func (c *Auth) Logout(ctx *core.Context) error {
 
-	session := ctx.JwtSession()
-	if session != nil {
-		if ex := session.ExpiresAt - time.Now().Unix(); ex > 0 {
-			_ = c.JwtTokenStorage.SetBlackList(ctx.Ctx(), session.Token, time.Duration(ex)*time.Second)
-		}
-	}
+	c.toBlackList(ctx)
 
 	return ctx.Success(nil)
 }
@@ -109,7 +213,86 @@ func (c *Auth) Logout(ctx *core.Context) error {
 // Refresh Token 刷新接口
 func (c *Auth) Refresh(ctx *core.Context) error {
 
-	// TODO 业务逻辑 ...
+	adminInfo, err := c.AdminRepo.FindById(ctx.Ctx(), ctx.UserId())
+	if err != nil {
+		return ctx.Error(err)
+	}
 
-	return ctx.Success(nil)
+	if adminInfo.Status != model.AdminStatusNormal {
+		return ctx.Error(entity.ErrAccountDisabled)
+	}
+
+	// 被要求强制开启两步验证但尚未绑定的账号需重新登录完成绑定
+	if adminInfo.TotpRequired == model.Yes {
+		enabled, err := c.TwoFactorService.IsEnabled(ctx.Ctx(), model.TwoFactorOwnerAdmin, adminInfo.Id)
+		if err != nil {
+			return ctx.Error(err)
+		}
+
+		if !enabled {
+			return ctx.Error(entity.ErrTwoFactorRequired)
+		}
+	}
+
+	c.toBlackList(ctx)
+
+	return ctx.Success(&admin.AuthLoginResponse{
+		Auth: c.token(adminInfo.Id),
+	})
+}
+
+// Permissions 当前管理员权限列表
+func (c *Auth) Permissions(ctx *core.Context) error {
+
+	permissions, err := c.RoleService.GetPermissions(ctx.Ctx(), ctx.UserId())
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	return ctx.Success(map[string]any{"permissions": permissions})
+}
+
+// 两步验证期间账号可能被停用，签发凭证前再次校验
+func (c *Auth) check(ctx *core.Context, adminId int) error {
+
+	adminInfo, err := c.AdminRepo.FindById(ctx.Ctx(), adminId)
+	if err != nil {
+		return err
+	}
+
+	if adminInfo.Status != model.AdminStatusNormal {
+		return entity.ErrAccountDisabled
+	}
+
+	return nil
+}
+
+func (c *Auth) token(adminId int) *admin.AccessToken {
+
+	expiresAt := time.Now().Add(12 * time.Hour)
+
+	// 生成登录凭证
+	token := c.JwtKeySet.GenerateToken("admin", &jwt.Options{
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+		ID:        strconv.Itoa(adminId),
+		Issuer:    "im.admin",
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+	})
+
+	return &admin.AccessToken{
+		Type:        "Bearer",
+		AccessToken: token,
+		ExpiresIn:   int32(expiresAt.Unix() - time.Now().Unix()),
+	}
+}
+
+// 设置黑名单
+func (c *Auth) toBlackList(ctx *core.Context) {
+
+	session := ctx.JwtSession()
+	if session != nil {
+		if ex := session.ExpiresAt - time.Now().Unix(); ex > 0 {
+			_ = c.JwtTokenStorage.SetBlackList(ctx.Ctx(), session.Token, time.Duration(ex)*time.Second)
+		}
+	}
 }
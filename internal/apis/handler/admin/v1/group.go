@@ -0,0 +1,197 @@
+package v1
+
+import (
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/core"
+	"go-chat/internal/pkg/timeutil"
+	"go-chat/internal/pkg/utils"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+	"go-chat/internal/service"
+)
+
+type Group struct {
+	GroupRepo         *repo.Group
+	GroupMemberRepo   *repo.GroupMember
+	AdminGroupService service.IAdminGroupService
+	AdminAuditService service.IAdminAuditService
+}
+
+type GroupListRequest struct {
+	Name      string `form:"name" json:"name"`                                           // 群名称
+	IsDismiss int    `form:"is_dismiss" json:"is_dismiss" binding:"omitempty,oneof=1 2"` // 是否已解散
+	Page      int    `form:"page" json:"page" binding:"required,min=1"`                  // 页码
+	Size      int    `form:"size" json:"size" binding:"required,min=1,max=100"`          // 每页数量
+}
+
+type GroupRequest struct {
+	GroupId int `form:"group_id" json:"group_id" binding:"required,min=1"` // 群ID
+}
+
+type GroupTransferRequest struct {
+	GroupId int `form:"group_id" json:"group_id" binding:"required,min=1"` // 群ID
+	UserId  int `form:"user_id" json:"user_id" binding:"required,min=1"`   // 新群主ID
+}
+
+type GroupMuteRequest struct {
+	GroupId int `form:"group_id" json:"group_id" binding:"required,min=1"` // 群ID
+	Action  int `form:"action" json:"action" binding:"required,oneof=1 2"` // 操作方式[1:禁言;2:解除禁言;]
+}
+
+type GroupMemberMuteRequest struct {
+	GroupId int `form:"group_id" json:"group_id" binding:"required,min=1"` // 群ID
+	UserId  int `form:"user_id" json:"user_id" binding:"required,min=1"`   // 成员ID
+	Action  int `form:"action" json:"action" binding:"required,oneof=1 2"` // 操作方式[1:禁言;2:解除禁言;]
+}
+
+type GroupItem struct {
+	Id        int    `json:"id"`
+	Type      int    `json:"type"`
+	Name      string `json:"name"`
+	Avatar    string `json:"avatar"`
+	Profile   string `json:"profile"`
+	CreatorId int    `json:"creator_id"`
+	MaxNum    int    `json:"max_num"`
+	IsOvert   int    `json:"is_overt"`
+	IsMute    int    `json:"is_mute"`
+	IsDismiss int    `json:"is_dismiss"`
+	CreatedAt string `json:"created_at"`
+}
+
+// List 群组列表
+func (c *Group) List(ctx *core.Context) error {
+	in := &GroupListRequest{}
+	if err := ctx.Context.ShouldBindQuery(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	groups, total, err := c.GroupRepo.SearchList(ctx.Ctx(), &repo.SearchGroupListOpt{
+		Name:      in.Name,
+		IsDismiss: in.IsDismiss,
+		Page:      in.Page,
+		Size:      in.Size,
+	})
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	items := make([]*GroupItem, 0, len(groups))
+	for _, group := range groups {
+		items = append(items, &GroupItem{
+			Id:        group.Id,
+			Type:      group.Type,
+			Name:      group.Name,
+			Avatar:    group.Avatar,
+			Profile:   group.Profile,
+			CreatorId: group.CreatorId,
+			MaxNum:    group.MaxNum,
+			IsOvert:   group.IsOvert,
+			IsMute:    group.IsMute,
+			IsDismiss: group.IsDismiss,
+			CreatedAt: timeutil.FormatDatetime(group.CreatedAt),
+		})
+	}
+
+	return ctx.Success(map[string]any{
+		"items": items,
+		"paginate": map[string]any{
+			"page":  in.Page,
+			"size":  in.Size,
+			"total": total,
+		},
+	})
+}
+
+// Members 群成员列表
+func (c *Group) Members(ctx *core.Context) error {
+	in := &GroupRequest{}
+	if err := ctx.Context.ShouldBindQuery(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if _, err := c.GroupRepo.FindById(ctx.Ctx(), in.GroupId); err != nil {
+		if utils.IsSqlNoRows(err) {
+			return ctx.Error(entity.ErrGroupNotExist)
+		}
+
+		return ctx.Error(err)
+	}
+
+	return ctx.Success(map[string]any{
+		"items": c.GroupMemberRepo.GetMembers(ctx.Ctx(), in.GroupId),
+	})
+}
+
+// Dismiss 解散群组
+func (c *Group) Dismiss(ctx *core.Context) error {
+	in := &GroupRequest{}
+	if err := ctx.Context.ShouldBind(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.AdminGroupService.Dismiss(ctx.Ctx(), in.GroupId); err != nil {
+		return ctx.Error(err)
+	}
+
+	audit(ctx, c.AdminAuditService, model.AdminAuditGroupDismiss, model.AdminAuditTargetGroup, in.GroupId, in)
+
+	return ctx.Success(nil)
+}
+
+// Transfer 转让群主
+func (c *Group) Transfer(ctx *core.Context) error {
+	in := &GroupTransferRequest{}
+	if err := ctx.Context.ShouldBind(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.AdminGroupService.Transfer(ctx.Ctx(), in.GroupId, in.UserId); err != nil {
+		return ctx.Error(err)
+	}
+
+	audit(ctx, c.AdminAuditService, model.AdminAuditGroupTransfer, model.AdminAuditTargetGroup, in.GroupId, in)
+
+	return ctx.Success(nil)
+}
+
+// Mute 全员禁言
+func (c *Group) Mute(ctx *core.Context) error {
+	in := &GroupMuteRequest{}
+	if err := ctx.Context.ShouldBind(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	status := model.No
+	if in.Action == 1 {
+		status = model.Yes
+	}
+
+	if err := c.AdminGroupService.Mute(ctx.Ctx(), in.GroupId, status); err != nil {
+		return ctx.Error(err)
+	}
+
+	audit(ctx, c.AdminAuditService, model.AdminAuditGroupMute, model.AdminAuditTargetGroup, in.GroupId, in)
+
+	return ctx.Success(nil)
+}
+
+// MemberMute 禁言群成员
+func (c *Group) MemberMute(ctx *core.Context) error {
+	in := &GroupMemberMuteRequest{}
+	if err := ctx.Context.ShouldBind(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	status := model.No
+	if in.Action == 1 {
+		status = model.Yes
+	}
+
+	if err := c.AdminGroupService.MemberMute(ctx.Ctx(), in.GroupId, in.UserId, status); err != nil {
+		return ctx.Error(err)
+	}
+
+	audit(ctx, c.AdminAuditService, model.AdminAuditGroupMemberMute, model.AdminAuditTargetGroup, in.GroupId, in)
+
+	return ctx.Success(nil)
+}
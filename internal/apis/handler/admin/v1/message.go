@@ -0,0 +1,51 @@
+package v1
+
+import (
+	"go-chat/internal/pkg/core"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/service"
+)
+
+type Message struct {
+	AdminGroupService service.IAdminGroupService
+	AdminAuditService service.IAdminAuditService
+}
+
+type MessageRequest struct {
+	TalkMode int    `form:"talk_mode" json:"talk_mode" binding:"required,oneof=1 2"` // 对话类型
+	MsgId    string `form:"msg_id" json:"msg_id" binding:"required"`                 // 消息ID
+}
+
+// Detail 查看消息
+func (c *Message) Detail(ctx *core.Context) error {
+	in := &MessageRequest{}
+	if err := ctx.Context.ShouldBindQuery(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	record, err := c.AdminGroupService.FindMessage(ctx.Ctx(), in.TalkMode, in.MsgId)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	// 查看私聊或群聊消息内容属于敏感操作，同样记录审计日志
+	audit(ctx, c.AdminAuditService, model.AdminAuditMessageView, model.AdminAuditTargetMessage, in.MsgId, in)
+
+	return ctx.Success(record)
+}
+
+// Revoke 撤回消息
+func (c *Message) Revoke(ctx *core.Context) error {
+	in := &MessageRequest{}
+	if err := ctx.Context.ShouldBind(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.AdminGroupService.RevokeMessage(ctx.Ctx(), in.TalkMode, in.MsgId); err != nil {
+		return ctx.Error(err)
+	}
+
+	audit(ctx, c.AdminAuditService, model.AdminAuditMessageRevoke, model.AdminAuditTargetMessage, in.MsgId, in)
+
+	return ctx.Success(nil)
+}
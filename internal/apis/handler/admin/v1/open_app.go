@@ -0,0 +1,150 @@
+package v1
+
+import (
+	"go-chat/internal/pkg/core"
+	"go-chat/internal/pkg/timeutil"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+	"go-chat/internal/service"
+	"gorm.io/gorm"
+)
+
+type OpenApp struct {
+	OpenAppRepo       *repo.OpenApp
+	OpenAppService    service.IOpenAppService
+	AdminAuditService service.IAdminAuditService
+}
+
+type OpenAppCreateRequest struct {
+	AppName string   `form:"app_name" json:"app_name" binding:"required,max=64"` // 应用名称
+	RobotId int      `form:"robot_id" json:"robot_id" binding:"required,min=1"`  // 绑定机器人ID
+	Scopes  []string `form:"scopes" json:"scopes" binding:"required,min=1"`      // 权限范围
+	Status  int      `form:"status" json:"status" binding:"required,oneof=1 2"`  // 状态[1:正常;2:已禁用;]
+	Remark  string   `form:"remark" json:"remark" binding:"max=255"`             // 备注
+}
+
+type OpenAppUpdateRequest struct {
+	AppId int `form:"app_id" json:"app_id" binding:"required,min=1"` // 应用ID
+	OpenAppCreateRequest
+}
+
+type OpenAppIdRequest struct {
+	AppId int `form:"app_id" json:"app_id" binding:"required,min=1"` // 应用ID
+}
+
+type OpenAppItem struct {
+	Id        int      `json:"id"`
+	AppName   string   `json:"app_name"`
+	AppKey    string   `json:"app_key"`
+	RobotId   int      `json:"robot_id"`
+	Scopes    []string `json:"scopes"`
+	Status    int      `json:"status"`
+	Remark    string   `json:"remark"`
+	CreatedAt string   `json:"created_at"`
+}
+
+// List 开放平台应用列表
+func (c *OpenApp) List(ctx *core.Context) error {
+	apps, err := c.OpenAppRepo.FindAll(ctx.Ctx(), func(db *gorm.DB) {
+		db.Order("id desc")
+	})
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	items := make([]*OpenAppItem, 0, len(apps))
+	for _, app := range apps {
+		items = append(items, &OpenAppItem{
+			Id:        app.Id,
+			AppName:   app.AppName,
+			AppKey:    app.AppKey,
+			RobotId:   app.RobotId,
+			Scopes:    service.DecodeOpenScopes(app.Scopes),
+			Status:    app.Status,
+			Remark:    app.Remark,
+			CreatedAt: timeutil.FormatDatetime(app.CreatedAt),
+		})
+	}
+
+	return ctx.Success(map[string]any{"items": items, "scopes": model.OpenScopes})
+}
+
+// Create 创建开放平台应用
+func (c *OpenApp) Create(ctx *core.Context) error {
+	in := &OpenAppCreateRequest{}
+	if err := ctx.Context.ShouldBindJSON(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	app, err := c.OpenAppService.Create(ctx.Ctx(), c.option(in))
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	audit(ctx, c.AdminAuditService, model.AdminAuditOpenAppCreate, model.AdminAuditTargetOpenApp, app.Id, in)
+
+	return ctx.Success(map[string]any{
+		"app_id":     app.Id,
+		"app_key":    app.AppKey,
+		"app_secret": app.AppSecret,
+	})
+}
+
+// Update 更新开放平台应用
+func (c *OpenApp) Update(ctx *core.Context) error {
+	in := &OpenAppUpdateRequest{}
+	if err := ctx.Context.ShouldBindJSON(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.OpenAppService.Update(ctx.Ctx(), in.AppId, c.option(&in.OpenAppCreateRequest)); err != nil {
+		return ctx.Error(err)
+	}
+
+	audit(ctx, c.AdminAuditService, model.AdminAuditOpenAppUpdate, model.AdminAuditTargetOpenApp, in.AppId, in)
+
+	return ctx.Success(nil)
+}
+
+// Delete 删除开放平台应用
+func (c *OpenApp) Delete(ctx *core.Context) error {
+	in := &OpenAppIdRequest{}
+	if err := ctx.Context.ShouldBind(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.OpenAppService.Delete(ctx.Ctx(), in.AppId); err != nil {
+		return ctx.Error(err)
+	}
+
+	audit(ctx, c.AdminAuditService, model.AdminAuditOpenAppDelete, model.AdminAuditTargetOpenApp, in.AppId, in)
+
+	return ctx.Success(nil)
+}
+
+// ResetSecret 重置应用密钥
+func (c *OpenApp) ResetSecret(ctx *core.Context) error {
+	in := &OpenAppIdRequest{}
+	if err := ctx.Context.ShouldBind(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	secret, err := c.OpenAppService.ResetSecret(ctx.Ctx(), in.AppId)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	audit(ctx, c.AdminAuditService, model.AdminAuditOpenAppResetSecret, model.AdminAuditTargetOpenApp, in.AppId, in)
+
+	return ctx.Success(map[string]any{"app_secret": secret})
+}
+
+func (c *OpenApp) option(in *OpenAppCreateRequest) *service.OpenAppOpt {
+	return &service.OpenAppOpt{
+		AppName: in.AppName,
+		RobotId: in.RobotId,
+		Scopes:  in.Scopes,
+		Status:  in.Status,
+		Remark:  in.Remark,
+	}
+}
@@ -0,0 +1,171 @@
+package v1
+
+import (
+	"go-chat/internal/pkg/core"
+	"go-chat/internal/pkg/timeutil"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+	"go-chat/internal/service"
+	"gorm.io/gorm"
+)
+
+type Robot struct {
+	RobotRepo         *repo.Robot
+	RobotService      service.IRobotService
+	AdminAuditService service.IAdminAuditService
+}
+
+type RobotCreateRequest struct {
+	RobotName  string             `form:"robot_name" json:"robot_name" binding:"required,max=30"`         // 机器人名称
+	Describe   string             `form:"describe" json:"describe" binding:"max=255"`                     // 描述信息
+	Logo       string             `form:"logo" json:"logo" binding:"omitempty,url,max=255"`               // 机器人头像
+	WebhookUrl string             `form:"webhook_url" json:"webhook_url" binding:"omitempty,url,max=255"` // 消息推送地址(为空时仅用于开放平台发送消息)
+	Status     int                `form:"status" json:"status" binding:"oneof=0 1"`                       // 状态[0:正常;1:已禁用;]
+	Commands   []RobotCommandItem `form:"commands" json:"commands" binding:"max=20,dive"`                 // 支持的斜杠命令
+}
+
+type RobotCommandItem struct {
+	Name     string `json:"name" binding:"required,max=32"` // 命令名称
+	Usage    string `json:"usage" binding:"max=100"`        // 使用说明
+	Describe string `json:"describe" binding:"max=100"`     // 命令描述
+}
+
+type RobotUpdateRequest struct {
+	RobotId int `form:"robot_id" json:"robot_id" binding:"required,min=1"` // 机器人ID
+	RobotCreateRequest
+}
+
+type RobotIdRequest struct {
+	RobotId int `form:"robot_id" json:"robot_id" binding:"required,min=1"` // 机器人ID
+}
+
+type RobotItem struct {
+	Id         int                  `json:"id"`
+	UserId     int                  `json:"user_id"`
+	RobotName  string               `json:"robot_name"`
+	Describe   string               `json:"describe"`
+	Logo       string               `json:"logo"`
+	Type       int                  `json:"type"`
+	WebhookUrl string               `json:"webhook_url"`
+	Commands   []model.RobotCommand `json:"commands"`
+	Status     int                  `json:"status"`
+	CreatedAt  string               `json:"created_at"`
+}
+
+// List 机器人列表
+func (c *Robot) List(ctx *core.Context) error {
+	robots, err := c.RobotRepo.FindAll(ctx.Ctx(), func(db *gorm.DB) {
+		db.Order("id asc")
+	})
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	items := make([]*RobotItem, 0, len(robots))
+	for _, robot := range robots {
+		items = append(items, &RobotItem{
+			Id:         robot.Id,
+			UserId:     robot.UserId,
+			RobotName:  robot.RobotName,
+			Describe:   robot.Describe,
+			Logo:       robot.Logo,
+			Type:       robot.Type,
+			WebhookUrl: robot.WebhookUrl,
+			Commands:   service.DecodeRobotCommands(robot.Commands),
+			Status:     robot.Status,
+			CreatedAt:  timeutil.FormatDatetime(robot.CreatedAt),
+		})
+	}
+
+	return ctx.Success(map[string]any{"items": items})
+}
+
+// Create 创建 Webhook 机器人
+func (c *Robot) Create(ctx *core.Context) error {
+	in := &RobotCreateRequest{}
+	if err := ctx.Context.ShouldBindJSON(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	robot, err := c.RobotService.Create(ctx.Ctx(), c.option(in))
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	audit(ctx, c.AdminAuditService, model.AdminAuditRobotCreate, model.AdminAuditTargetRobot, robot.Id, in)
+
+	return ctx.Success(map[string]any{
+		"robot_id":       robot.Id,
+		"user_id":        robot.UserId,
+		"webhook_secret": robot.WebhookSecret,
+	})
+}
+
+// Update 更新机器人
+func (c *Robot) Update(ctx *core.Context) error {
+	in := &RobotUpdateRequest{}
+	if err := ctx.Context.ShouldBindJSON(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.RobotService.Update(ctx.Ctx(), in.RobotId, c.option(&in.RobotCreateRequest)); err != nil {
+		return ctx.Error(err)
+	}
+
+	audit(ctx, c.AdminAuditService, model.AdminAuditRobotUpdate, model.AdminAuditTargetRobot, in.RobotId, in)
+
+	return ctx.Success(nil)
+}
+
+// Delete 删除机器人
+func (c *Robot) Delete(ctx *core.Context) error {
+	in := &RobotIdRequest{}
+	if err := ctx.Context.ShouldBind(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.RobotService.Delete(ctx.Ctx(), in.RobotId); err != nil {
+		return ctx.Error(err)
+	}
+
+	audit(ctx, c.AdminAuditService, model.AdminAuditRobotDelete, model.AdminAuditTargetRobot, in.RobotId, in)
+
+	return ctx.Success(nil)
+}
+
+// ResetSecret 重置机器人推送签名密钥
+func (c *Robot) ResetSecret(ctx *core.Context) error {
+	in := &RobotIdRequest{}
+	if err := ctx.Context.ShouldBind(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	secret, err := c.RobotService.ResetSecret(ctx.Ctx(), in.RobotId)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	audit(ctx, c.AdminAuditService, model.AdminAuditRobotResetSecret, model.AdminAuditTargetRobot, in.RobotId, in)
+
+	return ctx.Success(map[string]any{"webhook_secret": secret})
+}
+
+func (c *Robot) option(in *RobotCreateRequest) *service.RobotOpt {
+	commands := make([]model.RobotCommand, 0, len(in.Commands))
+	for _, item := range in.Commands {
+		commands = append(commands, model.RobotCommand{
+			Name:     item.Name,
+			Usage:    item.Usage,
+			Describe: item.Describe,
+		})
+	}
+
+	return &service.RobotOpt{
+		RobotName:  in.RobotName,
+		Describe:   in.Describe,
+		Logo:       in.Logo,
+		WebhookUrl: in.WebhookUrl,
+		Commands:   commands,
+		Status:     in.Status,
+	}
+}
@@ -0,0 +1,130 @@
+package v1
+
+import (
+	"go-chat/internal/pkg/core"
+	"go-chat/internal/pkg/timeutil"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+	"go-chat/internal/service"
+	"gorm.io/gorm"
+)
+
+type Role struct {
+	AdminRoleRepo     *repo.AdminRole
+	AdminRoleService  service.IAdminRoleService
+	AdminAuditService service.IAdminAuditService
+}
+
+type RoleCreateRequest struct {
+	Name        string   `form:"name" json:"name" binding:"required,max=32"`          // 角色名称
+	Code        string   `form:"code" json:"code" binding:"required,alphanum,max=32"` // 角色标识
+	Permissions []string `form:"permissions" json:"permissions" binding:"required"`   // 权限列表
+	Remark      string   `form:"remark" json:"remark" binding:"max=255"`              // 角色备注
+}
+
+type RoleUpdateRequest struct {
+	RoleId int `form:"role_id" json:"role_id" binding:"required,min=1"` // 角色ID
+	RoleCreateRequest
+}
+
+type RoleDeleteRequest struct {
+	RoleId int `form:"role_id" json:"role_id" binding:"required,min=1"` // 角色ID
+}
+
+type RoleItem struct {
+	Id          int      `json:"id"`
+	Name        string   `json:"name"`
+	Code        string   `json:"code"`
+	Permissions []string `json:"permissions"`
+	Remark      string   `json:"remark"`
+	CreatedAt   string   `json:"created_at"`
+}
+
+// Permissions 可分配的权限列表
+func (c *Role) Permissions(ctx *core.Context) error {
+	return ctx.Success(map[string]any{"items": model.AdminPermissions})
+}
+
+// List 角色列表
+func (c *Role) List(ctx *core.Context) error {
+	roles, err := c.AdminRoleRepo.FindAll(ctx.Ctx(), func(db *gorm.DB) {
+		db.Order("id asc")
+	})
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	items := make([]*RoleItem, 0, len(roles))
+	for _, role := range roles {
+		items = append(items, &RoleItem{
+			Id:          role.Id,
+			Name:        role.Name,
+			Code:        role.Code,
+			Permissions: service.DecodeAdminPermissions(role.Permissions),
+			Remark:      role.Remark,
+			CreatedAt:   timeutil.FormatDatetime(role.CreatedAt),
+		})
+	}
+
+	return ctx.Success(map[string]any{"items": items})
+}
+
+// Create 创建角色
+func (c *Role) Create(ctx *core.Context) error {
+	in := &RoleCreateRequest{}
+	if err := ctx.Context.ShouldBindJSON(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	role, err := c.AdminRoleService.Create(ctx.Ctx(), ctx.UserId(), &service.AdminRoleOpt{
+		Name:        in.Name,
+		Code:        in.Code,
+		Permissions: in.Permissions,
+		Remark:      in.Remark,
+	})
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	audit(ctx, c.AdminAuditService, model.AdminAuditRoleCreate, model.AdminAuditTargetRole, role.Id, in)
+
+	return ctx.Success(map[string]any{"role_id": role.Id})
+}
+
+// Update 更新角色
+func (c *Role) Update(ctx *core.Context) error {
+	in := &RoleUpdateRequest{}
+	if err := ctx.Context.ShouldBindJSON(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	err := c.AdminRoleService.Update(ctx.Ctx(), ctx.UserId(), in.RoleId, &service.AdminRoleOpt{
+		Name:        in.Name,
+		Code:        in.Code,
+		Permissions: in.Permissions,
+		Remark:      in.Remark,
+	})
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	audit(ctx, c.AdminAuditService, model.AdminAuditRoleUpdate, model.AdminAuditTargetRole, in.RoleId, in)
+
+	return ctx.Success(nil)
+}
+
+// Delete 删除角色
+func (c *Role) Delete(ctx *core.Context) error {
+	in := &RoleDeleteRequest{}
+	if err := ctx.Context.ShouldBind(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.AdminRoleService.Delete(ctx.Ctx(), in.RoleId); err != nil {
+		return ctx.Error(err)
+	}
+
+	audit(ctx, c.AdminAuditService, model.AdminAuditRoleDelete, model.AdminAuditTargetRole, in.RoleId, in)
+
+	return ctx.Success(nil)
+}
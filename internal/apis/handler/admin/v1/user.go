@@ -0,0 +1,254 @@
+package v1
+
+import (
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/core"
+	"go-chat/internal/pkg/encrypt/rsautil"
+	"go-chat/internal/pkg/timeutil"
+	"go-chat/internal/pkg/utils"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+	"go-chat/internal/service"
+)
+
+type User struct {
+	UsersRepo         *repo.Users
+	UsersLoginLogRepo *repo.UsersLoginLog
+	AdminUserService  service.IAdminUserService
+	AdminAuditService service.IAdminAuditService
+	Rsa               rsautil.IRsa
+}
+
+type UserListRequest struct {
+	Keyword string `form:"keyword" json:"keyword"`                             // 手机号、昵称或邮箱
+	Status  int    `form:"status" json:"status" binding:"omitempty,oneof=1 2"` // 账号状态
+	Page    int    `form:"page" json:"page" binding:"required,min=1"`          // 页码
+	Size    int    `form:"size" json:"size" binding:"required,min=1,max=100"`  // 每页数量
+}
+
+type UserDetailRequest struct {
+	UserId int `form:"user_id" json:"user_id" binding:"required,min=1"` // 用户ID
+}
+
+type UserLoginLogRequest struct {
+	UserId int `form:"user_id" json:"user_id" binding:"required,min=1"`   // 用户ID
+	Page   int `form:"page" json:"page" binding:"required,min=1"`         // 页码
+	Size   int `form:"size" json:"size" binding:"required,min=1,max=100"` // 每页数量
+}
+
+type UserResetPasswordRequest struct {
+	UserId   int    `form:"user_id" json:"user_id" binding:"required,min=1"` // 用户ID
+	Password string `form:"password" json:"password" binding:"required"`     // 新密码(RSA 加密)
+}
+
+type UserItem struct {
+	Id        int    `json:"id"`
+	Mobile    string `json:"mobile"`
+	Nickname  string `json:"nickname"`
+	Avatar    string `json:"avatar"`
+	Gender    int    `json:"gender"`
+	Email     string `json:"email"`
+	Motto     string `json:"motto"`
+	Birthday  string `json:"birthday"`
+	Status    int    `json:"status"`
+	CreatedAt string `json:"created_at"`
+}
+
+type UserLoginLogItem struct {
+	Id        int    `json:"id"`
+	Ip        string `json:"ip"`
+	Address   string `json:"address"`
+	Platform  string `json:"platform"`
+	Agent     string `json:"agent"`
+	CreatedAt string `json:"created_at"`
+}
+
+// List 用户列表
+func (c *User) List(ctx *core.Context) error {
+	in := &UserListRequest{}
+	if err := ctx.Context.ShouldBindQuery(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	users, total, err := c.UsersRepo.SearchList(ctx.Ctx(), &repo.SearchUserListOpt{
+		Keyword: in.Keyword,
+		Status:  in.Status,
+		Page:    in.Page,
+		Size:    in.Size,
+	})
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	items := make([]*UserItem, 0, len(users))
+	for _, user := range users {
+		items = append(items, newUserItem(user))
+	}
+
+	return ctx.Success(map[string]any{
+		"items": items,
+		"paginate": map[string]any{
+			"page":  in.Page,
+			"size":  in.Size,
+			"total": total,
+		},
+	})
+}
+
+// Detail 用户详情
+func (c *User) Detail(ctx *core.Context) error {
+	in := &UserDetailRequest{}
+	if err := ctx.Context.ShouldBindQuery(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	user, err := c.UsersRepo.FindById(ctx.Ctx(), in.UserId)
+	if err != nil {
+		if utils.IsSqlNoRows(err) {
+			return ctx.Error(entity.ErrUserNotExist)
+		}
+
+		return ctx.Error(err)
+	}
+
+	logs, _, err := c.UsersLoginLogRepo.FindUserLogs(ctx.Ctx(), user.Id, 1, 1)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	var lastLogin *UserLoginLogItem
+	if len(logs) > 0 {
+		lastLogin = newUserLoginLogItem(logs[0])
+	}
+
+	return ctx.Success(map[string]any{
+		"user":       newUserItem(user),
+		"last_login": lastLogin,
+	})
+}
+
+// LoginLogs 用户登录历史
+func (c *User) LoginLogs(ctx *core.Context) error {
+	in := &UserLoginLogRequest{}
+	if err := ctx.Context.ShouldBindQuery(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	logs, total, err := c.UsersLoginLogRepo.FindUserLogs(ctx.Ctx(), in.UserId, in.Page, in.Size)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	items := make([]*UserLoginLogItem, 0, len(logs))
+	for _, log := range logs {
+		items = append(items, newUserLoginLogItem(log))
+	}
+
+	return ctx.Success(map[string]any{
+		"items": items,
+		"paginate": map[string]any{
+			"page":  in.Page,
+			"size":  in.Size,
+			"total": total,
+		},
+	})
+}
+
+// Disable 禁用账号
+func (c *User) Disable(ctx *core.Context) error {
+	in := &UserDetailRequest{}
+	if err := ctx.Context.ShouldBind(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.AdminUserService.Disable(ctx.Ctx(), in.UserId); err != nil {
+		return ctx.Error(err)
+	}
+
+	audit(ctx, c.AdminAuditService, model.AdminAuditUserDisable, model.AdminAuditTargetUser, in.UserId, in)
+
+	return ctx.Success(nil)
+}
+
+// Enable 启用账号
+func (c *User) Enable(ctx *core.Context) error {
+	in := &UserDetailRequest{}
+	if err := ctx.Context.ShouldBind(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.AdminUserService.Enable(ctx.Ctx(), in.UserId); err != nil {
+		return ctx.Error(err)
+	}
+
+	audit(ctx, c.AdminAuditService, model.AdminAuditUserEnable, model.AdminAuditTargetUser, in.UserId, in)
+
+	return ctx.Success(nil)
+}
+
+// ResetPassword 重置账号密码
+func (c *User) ResetPassword(ctx *core.Context) error {
+	in := &UserResetPasswordRequest{}
+	if err := ctx.Context.ShouldBind(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	password, err := c.Rsa.Decrypt(in.Password)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	if len(password) < 6 {
+		return ctx.InvalidParams("密码长度不能少于6位！")
+	}
+
+	if err := c.AdminUserService.ResetPassword(ctx.Ctx(), in.UserId, string(password)); err != nil {
+		return ctx.Error(err)
+	}
+
+	audit(ctx, c.AdminAuditService, model.AdminAuditUserResetPassword, model.AdminAuditTargetUser, in.UserId, nil)
+
+	return ctx.Success(nil)
+}
+
+// ForceLogout 强制账号下线
+func (c *User) ForceLogout(ctx *core.Context) error {
+	in := &UserDetailRequest{}
+	if err := ctx.Context.ShouldBind(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.AdminUserService.ForceLogout(ctx.Ctx(), in.UserId, "账号已被管理员强制下线"); err != nil {
+		return ctx.Error(err)
+	}
+
+	audit(ctx, c.AdminAuditService, model.AdminAuditUserForceLogout, model.AdminAuditTargetUser, in.UserId, in)
+
+	return ctx.Success(nil)
+}
+
+func newUserItem(user *model.Users) *UserItem {
+	return &UserItem{
+		Id:        user.Id,
+		Mobile:    user.Mobile,
+		Nickname:  user.Nickname,
+		Avatar:    user.Avatar,
+		Gender:    user.Gender,
+		Email:     user.Email,
+		Motto:     user.Motto,
+		Birthday:  user.Birthday,
+		Status:    user.Status,
+		CreatedAt: timeutil.FormatDatetime(user.CreatedAt),
+	}
+}
+
+func newUserLoginLogItem(log *model.UsersLoginLog) *UserLoginLogItem {
+	return &UserLoginLogItem{
+		Id:        log.Id,
+		Ip:        log.Ip,
+		Address:   log.Address,
+		Platform:  log.Platform,
+		Agent:     log.Agent,
+		CreatedAt: timeutil.FormatDatetime(log.CreatedAt),
+	}
+}
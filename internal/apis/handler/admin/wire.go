@@ -8,6 +8,15 @@ import (
 var ProviderSet = wire.NewSet(
 	v12.NewIndex,
 	wire.Struct(new(v12.Auth), "*"),
+	wire.Struct(new(v12.User), "*"),
+	wire.Struct(new(v12.Group), "*"),
+	wire.Struct(new(v12.Message), "*"),
+	wire.Struct(new(v12.Audit), "*"),
+	wire.Struct(new(v12.Role), "*"),
+	wire.Struct(new(v12.Account), "*"),
+	wire.Struct(new(v12.TwoFactor), "*"),
+	wire.Struct(new(v12.Robot), "*"),
+	wire.Struct(new(v12.OpenApp), "*"),
 
 	wire.Struct(new(V1), "*"),
 	wire.Struct(new(V2), "*"),
@@ -5,7 +5,10 @@ import (
 )
 
 type V1 struct {
-	Index *v1.Index
+	Index   *v1.Index
+	Message *v1.Message
+	Upload  *v1.Upload
+	Group   *v1.Group
 }
 
 type Handler struct {
@@ -0,0 +1,68 @@
+package v1
+
+import (
+	"slices"
+
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/core"
+	"go-chat/internal/pkg/utils"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+)
+
+type Group struct {
+	GroupRepo       *repo.Group
+	GroupMemberRepo *repo.GroupMember
+}
+
+type GroupMembersRequest struct {
+	GroupId int `form:"group_id" binding:"required,min=1"` // 群ID
+}
+
+type GroupMemberItem struct {
+	UserId   int    `json:"user_id"`
+	Nickname string `json:"nickname"`
+	Avatar   string `json:"avatar"`
+	Remark   string `json:"remark"` // 群名片
+	Leader   int    `json:"leader"` // 成员角色[1:群主;2:管理员;3:普通成员;]
+	IsMute   int    `json:"is_mute"`
+}
+
+// Members 查询群成员列表，机器人需已加入群组
+func (c *Group) Members(ctx *core.Context) error {
+	in := &GroupMembersRequest{}
+	if err := ctx.Context.ShouldBindQuery(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	group, err := c.GroupRepo.FindById(ctx.Ctx(), in.GroupId)
+	if err != nil {
+		if utils.IsSqlNoRows(err) {
+			return ctx.Error(entity.ErrDataNotFound)
+		}
+
+		return ctx.Error(err)
+	}
+
+	if group.IsDismiss == model.Yes || !c.GroupMemberRepo.IsMember(ctx.Ctx(), in.GroupId, ctx.OpenSession().UserId, false) {
+		return ctx.Error(entity.ErrPermissionDenied)
+	}
+
+	items := make([]*GroupMemberItem, 0)
+	for _, item := range c.GroupMemberRepo.GetMembers(ctx.Ctx(), in.GroupId) {
+		items = append(items, &GroupMemberItem{
+			UserId:   item.UserId,
+			Nickname: item.Nickname,
+			Avatar:   item.Avatar,
+			Remark:   item.UserCard,
+			Leader:   item.Leader,
+			IsMute:   item.IsMute,
+		})
+	}
+
+	slices.SortFunc(items, func(a, b *GroupMemberItem) int {
+		return a.Leader - b.Leader
+	})
+
+	return ctx.Success(map[string]any{"items": items})
+}
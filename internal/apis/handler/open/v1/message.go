@@ -0,0 +1,109 @@
+package v1
+
+import (
+	"errors"
+	"html"
+
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/core"
+	"go-chat/internal/pkg/utils"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+	"go-chat/internal/service"
+	"go-chat/internal/service/message"
+)
+
+type Message struct {
+	UsersRepo      *repo.Users
+	AuthService    service.IAuthService
+	MessageService message.IService
+}
+
+type MessageSendRequest struct {
+	TalkMode int    `json:"talk_mode" binding:"required,oneof=1 2"`           // 对话类型[1:私聊;2:群聊;]
+	ToFromId int    `json:"to_from_id" binding:"required,min=1"`              // 接收者ID(用户ID或群ID)
+	Type     string `json:"type" binding:"required,oneof=text code file"`     // 消息类型
+	Content  string `json:"content" binding:"required_if=Type text,max=5000"` // 文本内容
+	Mentions []int  `json:"mentions"`                                         // @用户ID列表(群聊)
+	Code     string `json:"code" binding:"required_if=Type code,max=65535"`   // 代码内容
+	Lang     string `json:"lang" binding:"max=20"`                            // 代码语言
+	UploadId string `json:"upload_id" binding:"required_if=Type file"`        // 文件上传ID
+}
+
+// Send 以应用绑定的机器人身份发送消息
+func (c *Message) Send(ctx *core.Context) error {
+	in := &MessageSendRequest{}
+	if err := ctx.Context.ShouldBindJSON(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	uid := ctx.OpenSession().UserId
+
+	if err := c.authorize(ctx, uid, in); err != nil {
+		return ctx.Error(err)
+	}
+
+	var err error
+	switch in.Type {
+	case "text":
+		err = c.MessageService.CreateTextMessage(ctx.Ctx(), message.CreateTextMessage{
+			TalkMode: in.TalkMode,
+			FromId:   uid,
+			ToFromId: in.ToFromId,
+			Content:  html.EscapeString(in.Content),
+			Mentions: in.Mentions,
+		})
+	case "code":
+		err = c.MessageService.CreateCodeMessage(ctx.Ctx(), message.CreateCodeMessage{
+			TalkMode: in.TalkMode,
+			FromId:   uid,
+			ToFromId: in.ToFromId,
+			Code:     in.Code,
+			Lang:     in.Lang,
+		})
+	case "file":
+		err = c.MessageService.CreateFileMessage(ctx.Ctx(), message.CreateFileMessage{
+			TalkMode: in.TalkMode,
+			FromId:   uid,
+			ToFromId: in.ToFromId,
+			UploadId: in.UploadId,
+		})
+	}
+
+	if err != nil {
+		if utils.IsSqlNoRows(err) {
+			return ctx.Error(entity.ErrDataNotFound)
+		}
+
+		return ctx.Error(err)
+	}
+
+	return ctx.Success(nil)
+}
+
+// authorize 校验机器人是否可向目标发送消息，群聊需机器人已加入群组
+func (c *Message) authorize(ctx *core.Context, uid int, in *MessageSendRequest) error {
+	if in.TalkMode == entity.ChatGroupMode {
+		return c.AuthService.IsAuth(ctx.Ctx(), &service.AuthOption{
+			TalkType:          in.TalkMode,
+			UserId:            uid,
+			ToFromId:          in.ToFromId,
+			IsVerifyGroupMute: true,
+		})
+	}
+
+	user, err := c.UsersRepo.FindById(ctx.Ctx(), in.ToFromId)
+	if err != nil {
+		if utils.IsSqlNoRows(err) {
+			return errors.New("用户不存在")
+		}
+
+		return err
+	}
+
+	if user.Status != model.UsersStatusNormal || user.IsRobot == model.Yes {
+		return errors.New("暂无权限发送消息！")
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package v1
+
+import (
+	"go-chat/internal/pkg/core"
+	"go-chat/internal/service"
+)
+
+type Upload struct {
+	SplitUploadService service.ISplitUploadService
+}
+
+type UploadFileResponse struct {
+	UploadId string `json:"upload_id"`
+	FileName string `json:"file_name"`
+	FileSize int64  `json:"file_size"`
+}
+
+// File 上传文件，返回的 upload_id 可用于发送文件消息
+func (u *Upload) File(ctx *core.Context) error {
+	file, err := ctx.Context.FormFile("file")
+	if err != nil {
+		return ctx.InvalidParams("文件上传失败！")
+	}
+
+	info, err := u.SplitUploadService.Upload(ctx.Ctx(), ctx.OpenSession().UserId, file)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	return ctx.Success(&UploadFileResponse{
+		UploadId: info.UploadId,
+		FileName: info.OriginalName,
+		FileSize: info.FileSize,
+	})
+}
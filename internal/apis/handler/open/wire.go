@@ -7,6 +7,9 @@ import (
 
 var ProviderSet = wire.NewSet(
 	v1.NewIndex,
+	wire.Struct(new(v1.Message), "*"),
+	wire.Struct(new(v1.Upload), "*"),
+	wire.Struct(new(v1.Group), "*"),
 
 	wire.Struct(new(V1), "*"),
 )
@@ -12,10 +12,16 @@ type V1 struct {
 	Common       *v1.Common
 	Auth         *v1.Auth
 	User         *v1.User
+	TwoFactor    *v1.TwoFactor
+	PushDevice   *v1.PushDevice
 	Organize     *v1.Organize
+	File         *v1.File
 	Talk         *talk.Session
 	TalkMessage  *talk.Message
 	TalkRecords  *talk.Records
+	TalkExport   *talk.Export
+	TalkCommand  *talk.Command
+	TalkMention  *talk.Mention
 	Emoticon     *v1.Emoticon
 	Upload       *v1.Upload
 	Group        *group.Group
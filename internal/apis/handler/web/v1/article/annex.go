@@ -1,10 +1,9 @@
 package article
 
 import (
-	"database/sql"
 	"errors"
-	"fmt"
 	"math"
+	"mime"
 	"net/http"
 	"time"
 
@@ -12,8 +11,6 @@ import (
 	"go-chat/internal/entity"
 	"go-chat/internal/pkg/core"
 	"go-chat/internal/pkg/filesystem"
-	"go-chat/internal/pkg/strutil"
-	"go-chat/internal/repository/model"
 	"go-chat/internal/repository/repo"
 	"go-chat/internal/service"
 )
@@ -36,38 +33,18 @@ func (c *Annex) Upload(ctx *core.Context) error {
 		return ctx.InvalidParams("annex 字段必传！")
 	}
 
-	// 判断上传文件大小（10M）
-	if file.Size > 10<<20 {
-		return ctx.InvalidParams("附件大小不能超过10M！")
-	}
-
 	stream, err := filesystem.ReadMultipartStream(file)
 	if err != nil {
 		return ctx.Error(err)
 	}
 
-	ext := strutil.FileSuffix(file.Filename)
-
-	filePath := fmt.Sprintf("article-files/%s/%s", time.Now().Format("200601"), strutil.GenFileName(ext))
-	if err := c.Filesystem.Write(c.Filesystem.BucketPrivateName(), filePath, stream); err != nil {
-		return ctx.Error(err)
-	}
-
-	data := &model.ArticleAnnex{
-		UserId:       ctx.UserId(),
-		ArticleId:    int(in.ArticleId),
-		Drive:        entity.FileDriveMode(c.Filesystem.Driver()),
-		Suffix:       ext,
-		Size:         int(file.Size),
-		Path:         filePath,
-		OriginalName: file.Filename,
-		Status:       1,
-		DeletedAt: sql.NullTime{
-			Valid: false,
-		},
-	}
-
-	if err := c.ArticleAnnexService.Create(ctx.Ctx(), data); err != nil {
+	data, err := c.ArticleAnnexService.Upload(ctx.Ctx(), &service.ArticleAnnexUploadOpt{
+		UserId:    ctx.UserId(),
+		ArticleId: int(in.ArticleId),
+		Filename:  file.Filename,
+		Stream:    stream,
+	})
+	if err != nil {
 		return ctx.Error(err)
 	}
 
@@ -182,10 +159,24 @@ func (c *Annex) Download(ctx *core.Context) error {
 			return ctx.Error(errors.New("未知文件驱动类型"))
 		}
 
-		filePath := c.Filesystem.(*filesystem.LocalFilesystem).Path(c.Filesystem.BucketPrivateName(), info.Path)
-		ctx.Context.FileAttachment(filePath, info.OriginalName)
-	case entity.FileDriveMinio:
+		if local := c.Filesystem.(*filesystem.LocalFilesystem); local.SignEnabled() {
+			ctx.Context.Redirect(http.StatusFound, local.PrivateUrl(c.Filesystem.BucketPrivateName(), info.Path, info.OriginalName, 60*time.Second))
+		} else {
+			ctx.Context.FileAttachment(local.Path(c.Filesystem.BucketPrivateName(), info.Path), info.OriginalName)
+		}
+	case entity.FileDriveMinio, entity.FileDriveS3:
 		ctx.Context.Redirect(http.StatusFound, c.Filesystem.PrivateUrl(c.Filesystem.BucketPrivateName(), info.Path, info.OriginalName, 60*time.Second))
+	case entity.FileDriveWebDAV:
+		reader, err := c.Filesystem.ReadObject(c.Filesystem.BucketPrivateName(), info.Path)
+		if err != nil {
+			return ctx.Error(err)
+		}
+
+		defer reader.Close()
+
+		ctx.Context.DataFromReader(http.StatusOK, -1, "application/octet-stream", reader, map[string]string{
+			"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": info.OriginalName}),
+		})
 	default:
 		return ctx.Error(errors.New("未知文件驱动类型"))
 	}
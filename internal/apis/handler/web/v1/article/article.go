@@ -18,13 +18,14 @@ import (
 )
 
 type Article struct {
-	Source              *repo.Source
-	ArticleAnnexRepo    *repo.ArticleAnnex
-	ArticleClassRepo    *repo.ArticleClass
-	ArticleRepo         *repo.Article
-	ArticleService      service.IArticleService
-	ArticleAnnexService service.IArticleAnnexService
-	Filesystem          filesystem.IFilesystem
+	Source                 *repo.Source
+	ArticleAnnexRepo       *repo.ArticleAnnex
+	ArticleClassRepo       *repo.ArticleClass
+	ArticleRepo            *repo.Article
+	ArticleService         service.IArticleService
+	ArticleAnnexService    service.IArticleAnnexService
+	ArticleTransferService service.IArticleTransferService
+	Filesystem             filesystem.IFilesystem
 }
 
 // List 文章列表
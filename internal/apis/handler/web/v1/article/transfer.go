@@ -0,0 +1,57 @@
+package article
+
+import (
+	"fmt"
+	"time"
+
+	"go-chat/internal/pkg/core"
+	"go-chat/internal/pkg/filesystem"
+	"go-chat/internal/pkg/logger"
+)
+
+// Export 导出笔记(zip 压缩包)
+func (c *Article) Export(ctx *core.Context) error {
+
+	filename := fmt.Sprintf("notes-%s.zip", time.Now().Format("20060102150405"))
+
+	ctx.Context.Header("Content-Type", "application/zip")
+	ctx.Context.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	if err := c.ArticleTransferService.Export(ctx.Ctx(), ctx.UserId(), ctx.Context.Writer); err != nil {
+		if !ctx.Context.Writer.Written() {
+			ctx.Context.Header("Content-Disposition", "")
+			return ctx.Error(err)
+		}
+
+		logger.Errorf("笔记导出失败 uid:%d err:%s", ctx.UserId(), err.Error())
+	}
+
+	ctx.Context.Abort()
+	return nil
+}
+
+// Import 导入笔记(zip 压缩包或 markdown 文件)
+func (c *Article) Import(ctx *core.Context) error {
+
+	file, err := ctx.Context.FormFile("file")
+	if err != nil {
+		return ctx.InvalidParams("file 字段必传！")
+	}
+
+	// 判断上传文件大小（100M）
+	if file.Size > 100<<20 {
+		return ctx.InvalidParams("导入文件大小不能超过100M！")
+	}
+
+	stream, err := filesystem.ReadMultipartStream(file)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	result, err := c.ArticleTransferService.Import(ctx.Ctx(), ctx.UserId(), file.Filename, stream)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	return ctx.Success(result)
+}
@@ -1,9 +1,10 @@
 package v1
 
 import (
-	"strconv"
+	"errors"
 	"time"
 
+	"github.com/mojocn/base64Captcha"
 	"github.com/redis/go-redis/v9"
 	"go-chat/internal/pkg/encrypt/rsautil"
 
@@ -13,9 +14,9 @@ import (
 	"go-chat/internal/entity"
 	"go-chat/internal/pkg/core"
 	"go-chat/internal/pkg/jsonutil"
-	"go-chat/internal/pkg/jwt"
 	"go-chat/internal/pkg/logger"
 	"go-chat/internal/repository/cache"
+	"go-chat/internal/repository/model"
 	"go-chat/internal/repository/repo"
 	"go-chat/internal/service"
 )
@@ -30,53 +31,135 @@ type Auth struct {
 	UserService         service.IUserService
 	ArticleClassService service.IArticleClassService
 	Rsa                 rsautil.IRsa
+	TwoFactorService    service.ITwoFactorService
+	AuthSessionService  service.IAuthSessionService
+	OidcService         service.IOidcService
+	LdapService         service.ILdapService
+	LoginLimitService   service.ILoginLimitService
+	ICaptcha            *base64Captcha.Captcha
+}
+
+type AuthLoginRequest struct {
+	Mobile         string `form:"mobile" json:"mobile" binding:"required"`                                  // 登录账号
+	Password       string `form:"password" json:"password" binding:"required"`                              // 登录密码
+	Platform       string `form:"platform" json:"platform" binding:"required,oneof=h5 ios windows mac web"` // 登录平台
+	Captcha        string `form:"captcha" json:"captcha"`                                                   // 图形验证码，连续登录失败后必填
+	CaptchaVoucher string `form:"captcha_voucher" json:"captcha_voucher"`                                   // 图形验证码凭证
+}
+
+type AuthCaptchaResponse struct {
+	Voucher string `json:"voucher"` // 验证码唯一凭证
+	Captcha string `json:"captcha"` // 验证码图像 base64
+}
+
+// AuthTokenResponse 登录及刷新令牌的响应
+type AuthTokenResponse struct {
+	Type             string `json:"type"`               // 令牌类型
+	AccessToken      string `json:"access_token"`       // 访问令牌
+	ExpiresIn        int32  `json:"expires_in"`         // 访问令牌有效期(秒)
+	RefreshToken     string `json:"refresh_token"`      // 刷新令牌，仅可使用一次
+	RefreshExpiresIn int32  `json:"refresh_expires_in"` // 刷新令牌有效期(秒)
+}
+
+type AuthRefreshRequest struct {
+	RefreshToken string `form:"refresh_token" json:"refresh_token" binding:"required"` // 刷新令牌
+}
+
+type AuthRevokeSessionRequest struct {
+	SessionId string `form:"session_id" json:"session_id" binding:"required"` // 会话ID
+}
+
+// AuthTwoFactorResponse 密码校验通过但需完成两步验证时的登录响应
+type AuthTwoFactorResponse struct {
+	Type         string `json:"type"`           // 固定为 TwoFactor
+	Action       string `json:"action"`         // 待完成的操作[verify:校验动态码;setup:绑定身份验证器;]
+	PreAuthToken string `json:"pre_auth_token"` // 登录临时凭证
+	ExpiresIn    int32  `json:"expires_in"`     // 临时凭证有效期(秒)
+}
+
+type AuthTwoFactorLoginRequest struct {
+	PreAuthToken string `form:"pre_auth_token" json:"pre_auth_token" binding:"required"` // 登录临时凭证
+	Code         string `form:"code" json:"code" binding:"required,max=16"`              // 动态码或恢复码
 }
 
 // Login 登录接口
 func (c *Auth) Login(ctx *core.Context) error {
-	in := &web.AuthLoginRequest{}
+	in := &AuthLoginRequest{}
 	if err := ctx.Context.ShouldBindJSON(in); err != nil {
 		return ctx.InvalidParams(err)
 	}
 
+	ip := ctx.Context.ClientIP()
+
+	captchaRequired, err := c.LoginLimitService.Check(ctx.Ctx(), in.Mobile, ip)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	// 连续登录失败后需填写图形验证码，验证码校验后立即失效
+	if captchaRequired {
+		if in.Captcha == "" || in.CaptchaVoucher == "" {
+			return ctx.Error(entity.ErrLoginCaptchaRequired)
+		}
+
+		if !c.ICaptcha.Verify(in.CaptchaVoucher, in.Captcha, true) {
+			return ctx.Error(entity.ErrLoginCaptchaError)
+		}
+	}
+
 	password, err := c.Rsa.Decrypt(in.Password)
 	if err != nil {
 		return ctx.Error(err)
 	}
 
-	user, err := c.UserService.Login(ctx.Ctx(), in.Mobile, string(password))
+	// 启用目录认证时使用目录账号登录
+	login := c.UserService.Login
+	if c.LdapService.Enabled() {
+		login = c.LdapService.Login
+	}
+
+	user, err := login(ctx.Ctx(), in.Mobile, string(password))
 	if err != nil {
+		if errors.Is(err, entity.ErrAccountOrPassword) {
+			c.LoginLimitService.Fail(ctx.Ctx(), in.Mobile, ip)
+		}
+
 		return ctx.Error(err)
 	}
 
-	data := jsonutil.Marshal(queue.UserLoginRequest{
-		UserId:   int32(user.Id),
-		IpAddr:   ctx.Context.ClientIP(),
-		Platform: in.Platform,
-		Agent:    ctx.Context.GetHeader("user-agent"),
-		LoginAt:  time.Now().Format(time.DateTime),
-	})
+	return c.signin(ctx, user.Id, in.Platform, in.Mobile)
+}
 
-	if err := c.Redis.Publish(ctx.Ctx(), entity.LoginTopic, data).Err(); err != nil {
-		logger.ErrorWithFields(
-			"投递登录消息异常", err,
-			queue.UserLoginRequest{
-				UserId:   int32(user.Id),
-				IpAddr:   ctx.Context.ClientIP(),
-				Platform: in.Platform,
-				Agent:    ctx.Context.GetHeader("user-agent"),
-				LoginAt:  time.Now().Format(time.DateTime),
-			},
-		)
-	}
-
-	return ctx.Success(&web.AuthLoginResponse{
-		Type:        "Bearer",
-		AccessToken: c.token(user.Id),
-		ExpiresIn:   int32(c.Config.Jwt.ExpiresTime),
+// Captcha 登录图形验证码
+func (c *Auth) Captcha(ctx *core.Context) error {
+	voucher, captcha, _, err := c.ICaptcha.Generate()
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	return ctx.Success(&AuthCaptchaResponse{
+		Voucher: voucher,
+		Captcha: captcha,
 	})
 }
 
+// LoginTwoFactor 两步验证登录接口
+func (c *Auth) LoginTwoFactor(ctx *core.Context) error {
+	in := &AuthTwoFactorLoginRequest{}
+	if err := ctx.Context.ShouldBindJSON(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	preAuth, err := c.TwoFactorService.CompletePreAuth(ctx.Ctx(), in.PreAuthToken, "api", in.Code)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	c.publishLogin(ctx, preAuth.UserId, preAuth.Platform)
+
+	return c.login(ctx, preAuth.UserId, preAuth.Platform, preAuth.Account)
+}
+
 // Register 注册接口
 func (c *Auth) Register(ctx *core.Context) error {
 	in := &web.AuthRegisterRequest{}
@@ -113,19 +196,68 @@ func (c *Auth) Logout(ctx *core.Context) error {
 
 	c.toBlackList(ctx)
 
+	if session := ctx.JwtSession(); session != nil && session.SessionId != "" {
+		if err := c.AuthSessionService.Revoke(ctx.Ctx(), ctx.UserId(), session.SessionId); err != nil {
+			return ctx.Error(err)
+		}
+	}
+
+	return ctx.Success(nil)
+}
+
+// LogoutAll 退出所有设备的登录
+func (c *Auth) LogoutAll(ctx *core.Context) error {
+
+	if err := c.AuthSessionService.RevokeAll(ctx.Ctx(), ctx.UserId()); err != nil {
+		return ctx.Error(err)
+	}
+
 	return ctx.Success(nil)
 }
 
-// Refresh Token 刷新接口
+// Sessions 当前登录的设备列表
+func (c *Auth) Sessions(ctx *core.Context) error {
+
+	sid := ""
+	if session := ctx.JwtSession(); session != nil {
+		sid = session.SessionId
+	}
+
+	items, err := c.AuthSessionService.List(ctx.Ctx(), ctx.UserId(), sid)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	return ctx.Success(map[string]any{"items": items})
+}
+
+// RevokeSession 注销指定设备的登录
+func (c *Auth) RevokeSession(ctx *core.Context) error {
+	in := &AuthRevokeSessionRequest{}
+	if err := ctx.Context.ShouldBindJSON(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.AuthSessionService.Revoke(ctx.Ctx(), ctx.UserId(), in.SessionId); err != nil {
+		return ctx.Error(err)
+	}
+
+	return ctx.Success(nil)
+}
+
+// Refresh Token 刷新接口，刷新令牌每次使用后轮换
 func (c *Auth) Refresh(ctx *core.Context) error {
+	in := &AuthRefreshRequest{}
+	if err := ctx.Context.ShouldBindJSON(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
 
-	c.toBlackList(ctx)
+	token, err := c.AuthSessionService.Refresh(ctx.Ctx(), in.RefreshToken, ctx.Context.ClientIP())
+	if err != nil {
+		return ctx.Error(err)
+	}
 
-	return ctx.Success(&web.AuthRefreshResponse{
-		Type:        "Bearer",
-		AccessToken: c.token(ctx.UserId()),
-		ExpiresIn:   int32(c.Config.Jwt.ExpiresTime),
-	})
+	return ctx.Success(c.response(token))
 }
 
 // Forget 账号找回接口
@@ -158,19 +290,85 @@ func (c *Auth) Forget(ctx *core.Context) error {
 	return ctx.Success(&web.AuthForgetResponse{})
 }
 
-func (c *Auth) token(uid int) string {
+// 身份校验通过后完成登录，已开启两步验证时先签发临时凭证，校验动态码后再签发登录凭证
+// account 为密码登录的账号，完成登录后清除其登录失败记录，第三方登录时为空
+func (c *Auth) signin(ctx *core.Context, uid int, platform string, account string) error {
+
+	enabled, err := c.TwoFactorService.IsEnabled(ctx.Ctx(), model.TwoFactorOwnerUser, uid)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	if enabled {
+		token, err := c.TwoFactorService.CreatePreAuth(ctx.Ctx(), &cache.PreAuth{
+			Guard:    "api",
+			Action:   service.TwoFactorActionVerify,
+			UserId:   uid,
+			Platform: platform,
+			Account:  account,
+		})
+		if err != nil {
+			return ctx.Error(err)
+		}
+
+		return ctx.Success(&AuthTwoFactorResponse{
+			Type:         "TwoFactor",
+			Action:       service.TwoFactorActionVerify,
+			PreAuthToken: token,
+			ExpiresIn:    int32(service.TwoFactorPreAuthExpire.Seconds()),
+		})
+	}
 
-	expiresAt := time.Now().Add(time.Second * time.Duration(c.Config.Jwt.ExpiresTime))
+	c.publishLogin(ctx, uid, platform)
 
-	// 生成登录凭证
-	token := jwt.GenerateToken("api", c.Config.Jwt.Secret, &jwt.Options{
-		ExpiresAt: jwt.NewNumericDate(expiresAt),
-		ID:        strconv.Itoa(uid),
-		Issuer:    "im.web",
-		IssuedAt:  jwt.NewNumericDate(time.Now()),
+	return c.login(ctx, uid, platform, account)
+}
+
+// 创建登录会话并签发令牌
+func (c *Auth) login(ctx *core.Context, uid int, platform string, account string) error {
+
+	token, err := c.AuthSessionService.Create(ctx.Ctx(), &service.AuthSessionOpt{
+		UserId:   uid,
+		Platform: platform,
+		Agent:    ctx.Context.GetHeader("user-agent"),
+		IpAddr:   ctx.Context.ClientIP(),
 	})
+	if err != nil {
+		return ctx.Error(err)
+	}
 
-	return token
+	// 完成全部登录步骤后才清除失败记录，避免两步验证前重置计数
+	if account != "" {
+		c.LoginLimitService.Success(ctx.Ctx(), account)
+	}
+
+	return ctx.Success(c.response(token))
+}
+
+func (c *Auth) response(token *service.AuthToken) *AuthTokenResponse {
+	return &AuthTokenResponse{
+		Type:             "Bearer",
+		AccessToken:      token.AccessToken,
+		ExpiresIn:        token.ExpiresIn,
+		RefreshToken:     token.RefreshToken,
+		RefreshExpiresIn: token.RefreshExpiresIn,
+	}
+}
+
+// 投递登录消息
+func (c *Auth) publishLogin(ctx *core.Context, uid int, platform string) {
+
+	data := queue.UserLoginRequest{
+		UserId:   int32(uid),
+		IpAddr:   ctx.Context.ClientIP(),
+		Platform: platform,
+		Agent:    ctx.Context.GetHeader("user-agent"),
+		LoginAt:  time.Now().Format(time.DateTime),
+	}
+
+	if err := c.Redis.Publish(ctx.Ctx(), entity.LoginTopic, jsonutil.Marshal(&data)).Err(); err != nil {
+		logger.ErrorWithFields("投递登录消息异常", err, &data)
+	}
 }
 
 // 设置黑名单
@@ -0,0 +1,61 @@
+package v1
+
+import (
+	"go-chat/internal/pkg/core"
+	"go-chat/internal/service"
+)
+
+type AuthOidcAuthorizeRequest struct {
+	Provider string `form:"provider" json:"provider" binding:"required"`                              // 身份提供方标识
+	Platform string `form:"platform" json:"platform" binding:"required,oneof=h5 ios windows mac web"` // 登录平台
+}
+
+type AuthOidcAuthorizeResponse struct {
+	Url     string `json:"url"`     // 身份提供方授权地址
+	Binding string `json:"binding"` // 客户端绑定值，需保存在发起授权的客户端并在回调时提交
+}
+
+type AuthOidcCallbackRequest struct {
+	Code    string `form:"code" json:"code" binding:"required"`       // 授权码
+	State   string `form:"state" json:"state" binding:"required"`     // 授权请求状态
+	Binding string `form:"binding" json:"binding" binding:"required"` // 发起授权时返回的客户端绑定值
+}
+
+// OidcProviders 第三方登录方式列表
+func (c *Auth) OidcProviders(ctx *core.Context) error {
+	return ctx.Success(map[string]any{"items": c.OidcService.Providers()})
+}
+
+// OidcAuthorize 获取第三方登录授权地址
+func (c *Auth) OidcAuthorize(ctx *core.Context) error {
+	in := &AuthOidcAuthorizeRequest{}
+	if err := ctx.Context.ShouldBindQuery(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	result, err := c.OidcService.Authorize(ctx.Ctx(), in.Provider, in.Platform)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	return ctx.Success(&AuthOidcAuthorizeResponse{Url: result.Url, Binding: result.Binding})
+}
+
+// OidcCallback 第三方登录授权回调，前端回调页面取得 code 及 state 后调用
+func (c *Auth) OidcCallback(ctx *core.Context) error {
+	in := &AuthOidcCallbackRequest{}
+	if err := ctx.Context.ShouldBindJSON(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	result, err := c.OidcService.Callback(ctx.Ctx(), &service.OidcCallbackOpt{
+		Code:    in.Code,
+		State:   in.State,
+		Binding: in.Binding,
+	})
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	return c.signin(ctx, result.UserId, result.Platform, "")
+}
@@ -1,19 +1,28 @@
 package v1
 
 import (
+	"fmt"
+
 	"go-chat/api/pb/web/v1"
 	"go-chat/config"
 	"go-chat/internal/entity"
 	"go-chat/internal/pkg/core"
+	"go-chat/internal/repository/cache"
 	"go-chat/internal/repository/repo"
 	"go-chat/internal/service"
 )
 
 type Common struct {
-	Config      *config.Config
-	UsersRepo   *repo.Users
-	SmsService  service.ISmsService
-	UserService service.IUserService
+	Config       *config.Config
+	UsersRepo    *repo.Users
+	RedisLock    *cache.RedisLock
+	SmsService   service.ISmsService
+	UserService  service.IUserService
+	EmailService service.IEmailService
+}
+
+type CommonSendEmailRequest struct {
+	Email string `form:"email" json:"email" binding:"required,email,max=30"` // 邮箱地址
 }
 
 // SmsCode 发送短信验证码
@@ -59,11 +68,29 @@ func (c *Common) SmsCode(ctx *core.Context) error {
 // EmailCode 发送邮件验证码
 func (c *Common) EmailCode(ctx *core.Context) error {
 
-	params := &web.CommonSendEmailRequest{}
-	if err := ctx.Context.ShouldBind(params); err != nil {
+	in := &CommonSendEmailRequest{}
+	if err := ctx.Context.ShouldBind(in); err != nil {
 		return ctx.InvalidParams(err)
 	}
 
+	exist, err := c.UsersRepo.IsExist(ctx.Ctx(), "email = ? and id != ?", in.Email, ctx.UserId())
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	if exist {
+		return ctx.Error(entity.ErrEmailExist)
+	}
+
+	// 同一邮箱 60 秒内只能发送一次
+	if !c.RedisLock.Lock(ctx.Ctx(), fmt.Sprintf("email-code:%s", in.Email), 60) {
+		return ctx.Error(entity.ErrTooFrequentOperation)
+	}
+
+	if err := c.EmailService.SendCode(ctx.Ctx(), entity.EmailChangeChannel, in.Email); err != nil {
+		return ctx.Error(err)
+	}
+
 	return ctx.Success(nil)
 }
 
@@ -2,9 +2,9 @@ package v1
 
 import (
 	"bytes"
-	"slices"
 
 	"go-chat/api/pb/web/v1"
+	"go-chat/config"
 	"go-chat/internal/pkg/core"
 	"go-chat/internal/pkg/utils"
 	"go-chat/internal/repository/cache"
@@ -21,6 +21,9 @@ type Emoticon struct {
 	EmoticonRepo    *repo.Emoticon
 	EmoticonService service.IEmoticonService
 	Filesystem      filesystem.IFilesystem
+
+	StorageQuotaService service.IStorageQuotaService
+	FileScanService     service.IFileScanService
 }
 
 // List 收藏列表
@@ -88,13 +91,8 @@ func (c *Emoticon) Upload(ctx *core.Context) error {
 		return ctx.InvalidParams("file 字段必传！")
 	}
 
-	if !slices.Contains([]string{"png", "jpg", "jpeg", "gif"}, strutil.FileSuffix(file.Filename)) {
-		return ctx.InvalidParams("上传文件格式不正确,仅支持 png、jpg、jpeg 和 gif")
-	}
-
-	// 判断上传文件大小（5M）
-	if file.Size > 5<<20 {
-		return ctx.InvalidParams("上传文件大小不能超过5M！")
+	if err := c.StorageQuotaService.CheckPolicy(config.UploadTypeEmoticon, file.Filename, file.Size); err != nil {
+		return ctx.Error(err)
 	}
 
 	stream, err := filesystem.ReadMultipartStream(file)
@@ -102,11 +100,24 @@ func (c *Emoticon) Upload(ctx *core.Context) error {
 		return ctx.Error(err)
 	}
 
+	if err := c.StorageQuotaService.CheckMimeType(config.UploadTypeEmoticon, stream); err != nil {
+		return ctx.Error(err)
+	}
+
+	if err := c.FileScanService.ScanStream(ctx.Ctx(), &service.FileScanOpt{UserId: ctx.UserId(), Filename: file.Filename}, stream); err != nil {
+		return ctx.Error(err)
+	}
+
+	if err := c.StorageQuotaService.Reserve(ctx.Ctx(), model.StorageOwnerUser, ctx.UserId(), file.Size); err != nil {
+		return ctx.Error(err)
+	}
+
 	meta := utils.ReadImageMeta(bytes.NewReader(stream))
 	ext := strutil.FileSuffix(file.Filename)
 
 	src := strutil.GenMediaObjectName(ext, meta.Width, meta.Height)
 	if err = c.Filesystem.Write(c.Filesystem.BucketPublicName(), src, stream); err != nil {
+		_ = c.StorageQuotaService.Release(ctx.Ctx(), model.StorageOwnerUser, ctx.UserId(), file.Size)
 		return ctx.Error(err)
 	}
 
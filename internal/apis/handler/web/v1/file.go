@@ -0,0 +1,77 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go-chat/internal/pkg/core"
+	"go-chat/internal/pkg/filesystem"
+)
+
+type File struct {
+	Filesystem filesystem.IFilesystem
+}
+
+type fileDownloadRequest struct {
+	Expires   int64  `form:"expires" binding:"required"`
+	Filename  string `form:"filename"`
+	Signature string `form:"signature" binding:"required"`
+}
+
+// Download 本地存储签名地址下载，支持 Range 分段请求(音视频拖动播放、断点续传)
+func (f *File) Download(ctx *core.Context) error {
+	local, ok := f.Filesystem.(*filesystem.LocalFilesystem)
+	if !ok || !local.SignEnabled() {
+		return ctx.Forbidden("签名下载未启用！")
+	}
+
+	in := &fileDownloadRequest{}
+	if err := ctx.Context.ShouldBindQuery(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	bucketName, objectName := ctx.Context.Param("bucket"), ctx.Context.Param("object")
+
+	err := local.VerifySignature(bucketName, objectName, in.Filename, in.Expires, in.Signature)
+	if errors.Is(err, filesystem.ErrSignatureExpired) {
+		return ctx.Forbidden("下载链接已过期！")
+	} else if err != nil {
+		return ctx.Forbidden("下载链接无效！")
+	}
+
+	file, err := os.Open(local.Path(bucketName, objectName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			ctx.Context.AbortWithStatusJSON(http.StatusNotFound, &core.Response{Code: http.StatusNotFound, Message: "文件不存在"})
+			return nil
+		}
+
+		return ctx.Error(err)
+	}
+
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		ctx.Context.AbortWithStatusJSON(http.StatusNotFound, &core.Response{Code: http.StatusNotFound, Message: "文件不存在"})
+		return nil
+	}
+
+	name := filepath.Base(objectName)
+	if in.Filename != "" {
+		name = in.Filename
+		ctx.Context.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": in.Filename}))
+	}
+
+	if maxAge := in.Expires - time.Now().Unix(); maxAge > 0 {
+		ctx.Context.Header("Cache-Control", fmt.Sprintf("private, max-age=%d", maxAge))
+	}
+
+	http.ServeContent(ctx.Context.Writer, ctx.Context.Request, name, info.ModTime(), file)
+	return nil
+}
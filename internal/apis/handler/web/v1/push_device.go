@@ -0,0 +1,59 @@
+package v1
+
+import (
+	"go-chat/internal/pkg/core"
+	"go-chat/internal/service"
+)
+
+type PushDevice struct {
+	PushService service.IPushService
+}
+
+type PushDeviceRegisterRequest struct {
+	Provider string `form:"provider" json:"provider" binding:"required,oneof=apns fcm"`    // 推送服务[apns;fcm;]
+	Platform string `form:"platform" json:"platform" binding:"required,oneof=ios android"` // 设备平台[ios;android;]
+	Token    string `form:"token" json:"token" binding:"required,max=255"`                 // 设备推送令牌
+}
+
+type PushDeviceUnregisterRequest struct {
+	Provider string `form:"provider" json:"provider" binding:"required,oneof=apns fcm"` // 推送服务[apns;fcm;]
+	Token    string `form:"token" json:"token" binding:"required,max=255"`              // 设备推送令牌
+}
+
+// Register 注册推送设备，客户端启动或设备令牌变更时调用
+func (c *PushDevice) Register(ctx *core.Context) error {
+	in := &PushDeviceRegisterRequest{}
+	if err := ctx.Context.ShouldBindJSON(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	opt := &service.PushDeviceOpt{
+		Provider: in.Provider,
+		Platform: in.Platform,
+		Token:    in.Token,
+	}
+
+	if session := ctx.JwtSession(); session != nil {
+		opt.SessionId = session.SessionId
+	}
+
+	if err := c.PushService.RegisterDevice(ctx.Ctx(), ctx.UserId(), opt); err != nil {
+		return ctx.Error(err)
+	}
+
+	return ctx.Success(nil)
+}
+
+// Unregister 注销推送设备，退出登录或关闭通知时调用
+func (c *PushDevice) Unregister(ctx *core.Context) error {
+	in := &PushDeviceUnregisterRequest{}
+	if err := ctx.Context.ShouldBindJSON(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.PushService.UnregisterDevice(ctx.Ctx(), ctx.UserId(), in.Provider, in.Token); err != nil {
+		return ctx.Error(err)
+	}
+
+	return ctx.Success(nil)
+}
@@ -0,0 +1,39 @@
+package talk
+
+import (
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/core"
+	"go-chat/internal/service"
+)
+
+type Command struct {
+	AuthService    service.IAuthService
+	CommandService service.ICommandService
+}
+
+type CommandListRequest struct {
+	TalkMode int `form:"talk_mode" binding:"required,oneof=1 2"` // 对话类型 1:私聊 2:群聊
+	ToFromId int `form:"to_from_id" binding:"required,gt=0"`     // 接受者ID (好友ID或者群ID)
+}
+
+// List 对话可用的斜杠命令列表
+func (c *Command) List(ctx *core.Context) error {
+	in := &CommandListRequest{}
+	if err := ctx.Context.ShouldBindQuery(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	uid := ctx.UserId()
+
+	if err := c.AuthService.IsAuth(ctx.Ctx(), &service.AuthOption{
+		TalkType: in.TalkMode,
+		UserId:   uid,
+		ToFromId: in.ToFromId,
+	}); err != nil {
+		return ctx.Error(entity.ErrPermissionDenied)
+	}
+
+	return ctx.Success(map[string]any{
+		"items": c.CommandService.Commands(ctx.Ctx(), uid, in.TalkMode, in.ToFromId),
+	})
+}
@@ -0,0 +1,162 @@
+package talk
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"time"
+
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/core"
+	"go-chat/internal/pkg/filesystem"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+	"go-chat/internal/service"
+	"gorm.io/gorm"
+)
+
+type Export struct {
+	TalkExportRepo    *repo.TalkExport
+	TalkExportService service.ITalkExportService
+	Filesystem        filesystem.IFilesystem
+}
+
+type TalkExportCreateRequest struct {
+	TalkMode  int    `form:"talk_mode" json:"talk_mode" binding:"required,oneof=1 2"`       // 对话类型
+	ToFromId  int    `form:"to_from_id" json:"to_from_id" binding:"required,numeric,min=1"` // 接收者ID
+	StartDate string `form:"start_date" json:"start_date" binding:"required"`               // 开始日期(2006-01-02)
+	EndDate   string `form:"end_date" json:"end_date" binding:"required"`                   // 结束日期(2006-01-02)
+}
+
+type TalkExportDownloadRequest struct {
+	ExportId int `form:"export_id" json:"export_id" binding:"required,numeric,min=1"` // 导出任务ID
+}
+
+type TalkExportItem struct {
+	ExportId  int    `json:"export_id"`
+	TalkMode  int    `json:"talk_mode"`
+	ToFromId  int    `json:"to_from_id"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+	Status    int    `json:"status"`
+	Size      int    `json:"size"`
+	MsgNum    int    `json:"msg_num"`
+	Reason    string `json:"reason"`
+	CreatedAt string `json:"created_at"`
+}
+
+// Create 创建聊天记录导出任务
+func (c *Export) Create(ctx *core.Context) error {
+	in := &TalkExportCreateRequest{}
+	if err := ctx.Context.ShouldBind(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	startTime, err := time.ParseInLocation(time.DateOnly, in.StartDate, time.Local)
+	if err != nil {
+		return ctx.InvalidParams("start_date 格式错误！")
+	}
+
+	endTime, err := time.ParseInLocation(time.DateOnly, in.EndDate, time.Local)
+	if err != nil {
+		return ctx.InvalidParams("end_date 格式错误！")
+	}
+
+	info, err := c.TalkExportService.Create(ctx.Ctx(), &service.TalkExportCreateOpt{
+		UserId:    ctx.UserId(),
+		TalkMode:  in.TalkMode,
+		ToFromId:  in.ToFromId,
+		StartTime: startTime,
+		EndTime:   endTime.Add(24*time.Hour - time.Second),
+	})
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	return ctx.Success(map[string]any{
+		"export_id": info.Id,
+		"status":    info.Status,
+	})
+}
+
+// List 导出任务列表
+func (c *Export) List(ctx *core.Context) error {
+	items, err := c.TalkExportRepo.FindAll(ctx.Ctx(), func(db *gorm.DB) {
+		db.Where("user_id = ?", ctx.UserId()).Order("id desc").Limit(20)
+	})
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	list := make([]*TalkExportItem, 0, len(items))
+	for _, item := range items {
+		list = append(list, &TalkExportItem{
+			ExportId:  item.Id,
+			TalkMode:  item.TalkMode,
+			ToFromId:  item.ToFromId,
+			StartDate: item.StartTime.Format(time.DateOnly),
+			EndDate:   item.EndTime.Format(time.DateOnly),
+			Status:    item.Status,
+			Size:      item.Size,
+			MsgNum:    item.MsgNum,
+			Reason:    item.Reason,
+			CreatedAt: item.CreatedAt.Format(time.DateTime),
+		})
+	}
+
+	return ctx.Success(map[string]any{"items": list})
+}
+
+// Download 下载导出的聊天记录
+func (c *Export) Download(ctx *core.Context) error {
+	in := &TalkExportDownloadRequest{}
+	if err := ctx.Context.ShouldBindQuery(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	info, err := c.TalkExportRepo.FindById(ctx.Ctx(), in.ExportId)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	if info.UserId != ctx.UserId() {
+		return ctx.Forbidden("无权限下载")
+	}
+
+	if info.Status != model.TalkExportStatusSuccess {
+		return ctx.InvalidParams("导出任务未完成！")
+	}
+
+	filename := fmt.Sprintf("talk-records-%d-%s.zip", info.ToFromId, info.CreatedAt.Format("20060102150405"))
+
+	switch info.Drive {
+	case entity.FileDriveLocal:
+		if c.Filesystem.Driver() != filesystem.LocalDriver {
+			return ctx.Error(errors.New("未知文件驱动类型"))
+		}
+
+		if local := c.Filesystem.(*filesystem.LocalFilesystem); local.SignEnabled() {
+			ctx.Context.Redirect(http.StatusFound, local.PrivateUrl(c.Filesystem.BucketPrivateName(), info.Path, filename, 60*time.Second))
+		} else {
+			ctx.Context.FileAttachment(local.Path(c.Filesystem.BucketPrivateName(), info.Path), filename)
+		}
+	case entity.FileDriveMinio, entity.FileDriveS3:
+		ctx.Context.Redirect(http.StatusFound, c.Filesystem.PrivateUrl(c.Filesystem.BucketPrivateName(), info.Path, filename, 60*time.Second))
+	case entity.FileDriveWebDAV:
+		reader, err := c.Filesystem.ReadObject(c.Filesystem.BucketPrivateName(), info.Path)
+		if err != nil {
+			return ctx.Error(err)
+		}
+
+		defer reader.Close()
+
+		ctx.Context.DataFromReader(http.StatusOK, -1, "application/octet-stream", reader, map[string]string{
+			"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": filename}),
+		})
+	default:
+		return ctx.Error(errors.New("未知文件驱动类型"))
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package talk
+
+import (
+	"go-chat/internal/pkg/core"
+	"go-chat/internal/repository/repo"
+	"go-chat/internal/service"
+)
+
+type Mention struct {
+	TalkMentionService service.ITalkMentionService
+}
+
+type TalkMentionListRequest struct {
+	Cursor int `form:"cursor" json:"cursor" binding:"omitempty,min=0"`       // 上一页最后一条记录ID
+	Limit  int `form:"limit" json:"limit" binding:"omitempty,min=1,max=100"` // 每页数量，默认 20
+	Unread int `form:"unread" json:"unread" binding:"omitempty,oneof=1 2"`   // 是否仅查询未读[1:是;2:否;]
+}
+
+type TalkMentionReadRequest struct {
+	Ids []int `form:"ids" json:"ids" binding:"omitempty,max=100"` // 记录ID列表，为空时全部标记为已读
+}
+
+// List @我的消息列表
+func (c *Mention) List(ctx *core.Context) error {
+	in := &TalkMentionListRequest{}
+	if err := ctx.Context.ShouldBindQuery(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if in.Limit == 0 {
+		in.Limit = 20
+	}
+
+	uid := ctx.UserId()
+	items, err := c.TalkMentionService.List(ctx.Ctx(), &repo.TalkMentionListOpt{
+		UserId:     uid,
+		Cursor:     in.Cursor,
+		Limit:      in.Limit,
+		UnreadOnly: in.Unread == 1,
+	})
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	unreadNum, err := c.TalkMentionService.UnreadNum(ctx.Ctx(), uid)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	cursor := 0
+	if len(items) > 0 {
+		cursor = items[len(items)-1].Id
+	}
+
+	return ctx.Success(map[string]any{
+		"items":      items,
+		"cursor":     cursor,
+		"unread_num": unreadNum,
+	})
+}
+
+// Read 标记@我的消息为已读
+func (c *Mention) Read(ctx *core.Context) error {
+	in := &TalkMentionReadRequest{}
+	if err := ctx.Context.ShouldBindJSON(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.TalkMentionService.Read(ctx.Ctx(), ctx.UserId(), in.Ids); err != nil {
+		return ctx.Error(err)
+	}
+
+	return ctx.Success(nil)
+}
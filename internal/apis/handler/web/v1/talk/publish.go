@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin/binding"
 	"go-chat/internal/pkg/core"
 	"go-chat/internal/pkg/logger"
+	"go-chat/internal/pkg/slashcmd"
 	"go-chat/internal/service"
 	"go-chat/internal/service/message"
 )
@@ -16,6 +17,7 @@ var mapping map[string]func(ctx *core.Context) error
 type Publish struct {
 	AuthService    service.IAuthService
 	MessageService message.IService
+	CommandService service.ICommandService
 }
 
 type BaseMessageRequest struct {
@@ -59,6 +61,24 @@ func (c *Publish) onSendText(ctx *core.Context) error {
 		return ctx.InvalidParams(err)
 	}
 
+	// 以 / 开头的文本消息优先作为斜杠命令执行，命令不存在时按普通文本发送
+	if command, ok := slashcmd.Parse(in.Body.Text); ok {
+		isCommand, err := c.CommandService.Dispatch(ctx.Ctx(), &service.CommandInvocation{
+			TalkMode: in.TalkMode,
+			UserId:   ctx.UserId(),
+			ToFromId: in.ToFromId,
+			Mentions: in.Body.Mentions,
+			Command:  command,
+		})
+		if err != nil {
+			return ctx.Error(err)
+		}
+
+		if isCommand {
+			return ctx.Success(nil)
+		}
+	}
+
 	err := c.MessageService.CreateTextMessage(ctx.Ctx(), message.CreateTextMessage{
 		TalkMode: in.TalkMode,
 		FromId:   ctx.UserId(),
@@ -79,6 +99,8 @@ type onSendImageMessage struct {
 	BaseMessageRequest
 	Body struct {
 		Url    string `json:"url" binding:"required"`
+		Thumb  string `json:"thumb"`
+		Medium string `json:"medium"`
 		Width  int    `json:"width" binding:"required"`
 		Height int    `json:"height" binding:"required"`
 		Size   int    `json:"size" binding:"required"`
@@ -98,6 +120,8 @@ func (c *Publish) onSendImage(ctx *core.Context) error {
 		ToFromId: in.ToFromId,
 		QuoteId:  in.QuoteId,
 		Url:      in.Body.Url,
+		Thumb:    in.Body.Thumb,
+		Medium:   in.Body.Medium,
 		Width:    in.Body.Width,
 		Height:   in.Body.Height,
 		Size:     in.Body.Size,
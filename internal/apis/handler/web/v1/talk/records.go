@@ -2,6 +2,7 @@ package talk
 
 import (
 	"errors"
+	"mime"
 	"net/http"
 	"slices"
 	"time"
@@ -237,40 +238,109 @@ func (c *Records) Download(ctx *core.Context) error {
 		return ctx.InvalidParams(err)
 	}
 
+	fileInfo, err := c.findChatFile(ctx, params)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	switch c.Filesystem.Driver() {
+	case filesystem.LocalDriver:
+		if local := c.Filesystem.(*filesystem.LocalFilesystem); local.SignEnabled() {
+			ctx.Context.Redirect(http.StatusFound, local.PrivateUrl(c.Filesystem.BucketPrivateName(), fileInfo.Path, fileInfo.Name, 60*time.Second))
+		} else {
+			ctx.Context.FileAttachment(local.Path(c.Filesystem.BucketPrivateName(), fileInfo.Path), fileInfo.Name)
+		}
+	case filesystem.MinioDriver, filesystem.S3Driver:
+		ctx.Context.Redirect(http.StatusFound, c.Filesystem.PrivateUrl(c.Filesystem.BucketPrivateName(), fileInfo.Path, fileInfo.Name, 60*time.Second))
+	case filesystem.WebDAVDriver:
+		reader, err := c.Filesystem.ReadObject(c.Filesystem.BucketPrivateName(), fileInfo.Path)
+		if err != nil {
+			return ctx.Error(err)
+		}
+
+		defer reader.Close()
+
+		ctx.Context.DataFromReader(http.StatusOK, -1, "application/octet-stream", reader, map[string]string{
+			"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": fileInfo.Name}),
+		})
+	default:
+		return ctx.Error(errors.New("未知文件驱动类型"))
+	}
+
+	return nil
+}
+
+type chatFileUrlResponse struct {
+	Url       string `json:"url"`
+	ExpiredAt string `json:"expired_at"`
+}
+
+// FileUrl 获取聊天文件的临时访问地址(音视频在线播放、分享链接)
+func (c *Records) FileUrl(ctx *core.Context) error {
+	params := &DownloadChatFileRequest{}
+	if err := ctx.Context.ShouldBindQuery(params); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	fileInfo, err := c.findChatFile(ctx, params)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	switch c.Filesystem.Driver() {
+	case filesystem.LocalDriver:
+		if !c.Filesystem.(*filesystem.LocalFilesystem).SignEnabled() {
+			return ctx.InvalidParams("未配置文件签名密钥，不支持临时访问地址！")
+		}
+	case filesystem.MinioDriver, filesystem.S3Driver:
+	default:
+		return ctx.InvalidParams("当前存储驱动不支持临时访问地址！")
+	}
+
+	expire := 30 * time.Minute
+
+	return ctx.Success(&chatFileUrlResponse{
+		Url:       c.Filesystem.PrivateUrl(c.Filesystem.BucketPrivateName(), fileInfo.Path, fileInfo.Name, expire),
+		ExpiredAt: time.Now().Add(expire).Format(time.DateTime),
+	})
+}
+
+// findChatFile 查询当前用户有权访问的文件消息
+func (c *Records) findChatFile(ctx *core.Context, params *DownloadChatFileRequest) (*model.TalkRecordExtraFile, error) {
 	var fileInfo model.TalkRecordExtraFile
 	if params.TalkMode == entity.ChatGroupMode {
 		record, err := c.TalkRecordGroupRepo.FindByWhere(ctx.Ctx(), "msg_id = ?", params.MsgId)
 		if err != nil {
-			return ctx.Error(err)
+			return nil, err
 		}
 
 		if !c.GroupMemberRepo.IsMember(ctx.Ctx(), record.GroupId, ctx.UserId(), false) {
-			return ctx.Forbidden("无访问权限！")
+			return nil, entity.ErrPermissionDenied
+		}
+
+		// 撤回的文件已释放文件对象引用，不再允许下载
+		if record.IsRevoked == model.Yes {
+			return nil, entity.ErrDataNotFound
 		}
 
 		if err := jsonutil.Decode(record.Extra, &fileInfo); err != nil {
-			return ctx.Error(err)
+			return nil, err
 		}
 	} else {
 		record, err := c.TalkRecordFriendRepo.FindByWhere(ctx.Ctx(), "user_id = ? and msg_id = ?", ctx.UserId(), params.MsgId)
 		if err != nil {
-			return ctx.Error(err)
+			return nil, err
 		}
 
-		if err := jsonutil.Decode(record.Extra, &fileInfo); err != nil {
-			return ctx.Error(err)
+		// 撤回的文件已释放文件对象引用，不再允许下载
+		if record.IsRevoked == model.Yes {
+			return nil, entity.ErrDataNotFound
 		}
-	}
 
-	switch c.Filesystem.Driver() {
-	case filesystem.LocalDriver:
-		filePath := c.Filesystem.(*filesystem.LocalFilesystem).Path(c.Filesystem.BucketPrivateName(), fileInfo.Path)
-		ctx.Context.FileAttachment(filePath, fileInfo.Name)
-	case filesystem.MinioDriver:
-		ctx.Context.Redirect(http.StatusFound, c.Filesystem.PrivateUrl(c.Filesystem.BucketPrivateName(), fileInfo.Path, fileInfo.Name, 60*time.Second))
-	default:
-		return ctx.Error(errors.New("未知文件驱动类型"))
+		if err := jsonutil.Decode(record.Extra, &fileInfo); err != nil {
+			return nil, err
+		}
 	}
 
-	return nil
+	return &fileInfo, nil
 }
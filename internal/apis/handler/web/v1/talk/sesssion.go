@@ -21,6 +21,7 @@ type Session struct {
 	UnreadStorage        *cache.UnreadStorage
 	ContactRemark        *cache.ContactRemark
 	ContactRepo          *repo.Contact
+	TalkMentionRepo      *repo.TalkMention
 	UsersRepo            *repo.Users
 	GroupRepo            *repo.Group
 	TalkService          service.ITalkService
@@ -220,6 +221,7 @@ func (c *Session) List(ctx *core.Context) error {
 		} else {
 			value.Name = item.GroupName
 			value.Avatar = item.GroupAvatar
+			value.MentionNum = int32(c.UnreadStorage.GetMention(ctx.Ctx(), uid, item.TalkMode, item.ToFromId))
 		}
 
 		// 查询缓存消息
@@ -242,5 +244,10 @@ func (c *Session) ClearUnreadMessage(ctx *core.Context) error {
 
 	c.UnreadStorage.Reset(ctx.Ctx(), ctx.UserId(), int(in.TalkMode), int(in.ToFromId))
 
+	// 会话已读时，该群聊中@我的消息同时标记为已读
+	if in.TalkMode == entity.ChatGroupMode {
+		_, _ = c.TalkMentionRepo.ReadByGroup(ctx.Ctx(), ctx.UserId(), int(in.ToFromId))
+	}
+
 	return ctx.Success(&web.TalkSessionClearUnreadNumResponse{})
 }
@@ -0,0 +1,117 @@
+package v1
+
+import (
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/core"
+	"go-chat/internal/pkg/encrypt"
+	"go-chat/internal/pkg/encrypt/rsautil"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+	"go-chat/internal/service"
+)
+
+type TwoFactor struct {
+	UsersRepo        *repo.Users
+	TwoFactorService service.ITwoFactorService
+	Rsa              rsautil.IRsa
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `form:"code" json:"code" binding:"required,max=16"` // 动态码或恢复码
+}
+
+type TwoFactorDisableRequest struct {
+	Password string `form:"password" json:"password" binding:"required"` // 登录密码(RSA 加密)
+	Code     string `form:"code" json:"code" binding:"required,max=16"`  // 动态码或恢复码
+}
+
+// Status 两步验证状态
+func (c *TwoFactor) Status(ctx *core.Context) error {
+	status, err := c.TwoFactorService.Status(ctx.Ctx(), model.TwoFactorOwnerUser, ctx.UserId())
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	return ctx.Success(status)
+}
+
+// Setup 生成身份验证器绑定信息
+func (c *TwoFactor) Setup(ctx *core.Context) error {
+	user, err := c.UsersRepo.FindById(ctx.Ctx(), ctx.UserId())
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	setup, err := c.TwoFactorService.Setup(ctx.Ctx(), model.TwoFactorOwnerUser, user.Id, user.Mobile)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	return ctx.Success(setup)
+}
+
+// Enable 确认绑定并开启两步验证
+func (c *TwoFactor) Enable(ctx *core.Context) error {
+	in := &TwoFactorCodeRequest{}
+	if err := ctx.Context.ShouldBindJSON(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	codes, err := c.TwoFactorService.Enable(ctx.Ctx(), model.TwoFactorOwnerUser, ctx.UserId(), in.Code)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	return ctx.Success(map[string]any{"recovery_codes": codes})
+}
+
+// Disable 关闭两步验证
+func (c *TwoFactor) Disable(ctx *core.Context) error {
+	in := &TwoFactorDisableRequest{}
+	if err := ctx.Context.ShouldBindJSON(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	user, err := c.UsersRepo.FindById(ctx.Ctx(), ctx.UserId())
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	password, err := c.Rsa.Decrypt(in.Password)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	if !encrypt.VerifyPassword(user.Password, string(password)) {
+		return ctx.Error(entity.ErrAccountOrPasswordError)
+	}
+
+	if err := c.TwoFactorService.Verify(ctx.Ctx(), model.TwoFactorOwnerUser, user.Id, in.Code); err != nil {
+		return ctx.Error(err)
+	}
+
+	if err := c.TwoFactorService.Disable(ctx.Ctx(), model.TwoFactorOwnerUser, user.Id); err != nil {
+		return ctx.Error(err)
+	}
+
+	return ctx.Success(nil, "两步验证已关闭！")
+}
+
+// RecoveryCodes 重新生成恢复码
+func (c *TwoFactor) RecoveryCodes(ctx *core.Context) error {
+	in := &TwoFactorCodeRequest{}
+	if err := ctx.Context.ShouldBindJSON(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	if err := c.TwoFactorService.Verify(ctx.Ctx(), model.TwoFactorOwnerUser, ctx.UserId(), in.Code); err != nil {
+		return ctx.Error(err)
+	}
+
+	codes, err := c.TwoFactorService.RecoveryCodes(ctx.Ctx(), model.TwoFactorOwnerUser, ctx.UserId())
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	return ctx.Success(map[string]any{"recovery_codes": codes})
+}
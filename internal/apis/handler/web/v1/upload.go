@@ -2,6 +2,7 @@ package v1
 
 import (
 	"bytes"
+	"fmt"
 	"math"
 	"path"
 	"strconv"
@@ -11,15 +12,23 @@ import (
 	"go-chat/config"
 	"go-chat/internal/pkg/core"
 	"go-chat/internal/pkg/filesystem"
+	"go-chat/internal/pkg/imageutil"
+	"go-chat/internal/pkg/logger"
 	"go-chat/internal/pkg/strutil"
 	"go-chat/internal/pkg/utils"
+	"go-chat/internal/repository/cache"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
 	"go-chat/internal/service"
 )
 
 type Upload struct {
-	Config             *config.Config
-	Filesystem         filesystem.IFilesystem
-	SplitUploadService service.ISplitUploadService
+	Config              *config.Config
+	Filesystem          filesystem.IFilesystem
+	SplitUploadService  service.ISplitUploadService
+	StorageQuotaService service.IStorageQuotaService
+	FileScanService     service.IFileScanService
+	GroupMemberRepo     *repo.GroupMember
 }
 
 // Avatar 头像上传上传
@@ -29,11 +38,23 @@ func (u *Upload) Avatar(ctx *core.Context) error {
 		return ctx.InvalidParams("文件上传失败！")
 	}
 
+	if err := u.StorageQuotaService.CheckPolicy(config.UploadTypeAvatar, file.Filename, file.Size); err != nil {
+		return ctx.Error(err)
+	}
+
 	stream, err := filesystem.ReadMultipartStream(file)
 	if err != nil {
 		return ctx.Error(err)
 	}
 
+	if err := u.StorageQuotaService.CheckMimeType(config.UploadTypeAvatar, stream); err != nil {
+		return ctx.Error(err)
+	}
+
+	if err := u.FileScanService.ScanStream(ctx.Ctx(), &service.FileScanOpt{UserId: ctx.UserId(), Filename: file.Filename}, stream); err != nil {
+		return ctx.Error(err)
+	}
+
 	object := strutil.GenMediaObjectName("png", 200, 200)
 	if err := u.Filesystem.Write(u.Filesystem.BucketPublicName(), object, stream); err != nil {
 		return ctx.Error(err)
@@ -44,6 +65,14 @@ func (u *Upload) Avatar(ctx *core.Context) error {
 	})
 }
 
+type uploadImageResponse struct {
+	Src    string `json:"src"`
+	Thumb  string `json:"thumb"`  // 缩略图地址
+	Medium string `json:"medium"` // 中图地址
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
 // Image 图片上传
 func (u *Upload) Image(ctx *core.Context) error {
 
@@ -58,40 +87,125 @@ func (u *Upload) Image(ctx *core.Context) error {
 		height, _ = strconv.Atoi(ctx.Context.DefaultPostForm("height", "0"))
 	)
 
-	stream, _ := filesystem.ReadMultipartStream(file)
+	if err := u.StorageQuotaService.CheckPolicy(config.UploadTypeImage, file.Filename, file.Size); err != nil {
+		return ctx.Error(err)
+	}
+
+	stream, err := filesystem.ReadMultipartStream(file)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	if err := u.StorageQuotaService.CheckMimeType(config.UploadTypeImage, stream); err != nil {
+		return ctx.Error(err)
+	}
+
+	if err := u.FileScanService.ScanStream(ctx.Ctx(), &service.FileScanOpt{UserId: ctx.UserId(), Filename: file.Filename}, stream); err != nil {
+		return ctx.Error(err)
+	}
+
+	if err := u.StorageQuotaService.Reserve(ctx.Ctx(), model.StorageOwnerUser, ctx.UserId(), file.Size); err != nil {
+		return ctx.Error(err)
+	}
+
+	// 清除图片中的定位信息
+	stream = imageutil.StripGPS(stream)
+
 	if width == 0 || height == 0 {
 		meta := utils.ReadImageMeta(bytes.NewReader(stream))
 		width = meta.Width
 		height = meta.Height
+
+		// 按拍摄方向旋转90度的图片宽高互换
+		if imageutil.Orientation(stream) >= 5 {
+			width, height = height, width
+		}
 	}
 
 	object := strutil.GenMediaObjectName(ext, width, height)
 	if err := u.Filesystem.Write(u.Filesystem.BucketPublicName(), object, stream); err != nil {
+		_ = u.StorageQuotaService.Release(ctx.Ctx(), model.StorageOwnerUser, ctx.UserId(), file.Size)
 		return ctx.Error(err)
 	}
 
-	return ctx.Success(web.UploadImageResponse{
-		Src: u.Filesystem.PublicUrl(u.Filesystem.BucketPublicName(), object),
-	})
+	src := u.Filesystem.PublicUrl(u.Filesystem.BucketPublicName(), object)
+
+	resp := &uploadImageResponse{Src: src, Thumb: src, Medium: src, Width: width, Height: height}
+
+	variants, err := imageutil.GenerateVariants(stream)
+	if err != nil {
+		logger.Errorf("图片变体生成失败 object:%s err:%s", object, err.Error())
+	}
+
+	for _, variant := range variants {
+		name := fmt.Sprintf("%s_%s.%s", strings.TrimSuffix(object, path.Ext(object)), variant.Name, variant.Ext)
+		if err := u.Filesystem.Write(u.Filesystem.BucketPublicName(), name, variant.Data); err != nil {
+			return ctx.Error(err)
+		}
+
+		switch variant.Name {
+		case imageutil.VariantThumb:
+			resp.Thumb = u.Filesystem.PublicUrl(u.Filesystem.BucketPublicName(), name)
+		case imageutil.VariantMedium:
+			resp.Medium = u.Filesystem.PublicUrl(u.Filesystem.BucketPublicName(), name)
+		}
+	}
+
+	return ctx.Success(resp)
+}
+
+type uploadInitiateMultipartRequest struct {
+	FileName string `json:"file_name" binding:"required"`
+	FileSize int64  `json:"file_size" binding:"required"`
+	Sha256   string `json:"sha256" binding:"omitempty,len=64,hexadecimal"`
+	Proof    string `json:"proof" binding:"omitempty,len=64,hexadecimal"` // 秒传校验值，文件在校验区间内容的 SHA-256
+}
+
+type uploadInitiateMultipartResponse struct {
+	UploadId  string `json:"upload_id"`
+	ShardSize int32  `json:"shard_size"`
+	ShardNum  int32  `json:"shard_num"`
+	IsInstant bool   `json:"is_instant"` // 是否秒传(无需上传分片)
+
+	// 秒传校验区间，存在相同文件时返回，客户端需携带 proof 重新初始化
+	Challenge *cache.UploadChallenge `json:"challenge"`
 }
 
 // InitiateMultipart 批量上传初始化
 func (u *Upload) InitiateMultipart(ctx *core.Context) error {
-	in := &web.UploadInitiateMultipartRequest{}
+	in := &uploadInitiateMultipartRequest{}
 	if err := ctx.Context.ShouldBindJSON(in); err != nil {
 		return ctx.InvalidParams(err)
 	}
 
-	info, err := u.SplitUploadService.InitiateMultipartUpload(ctx.Ctx(), &service.MultipartInitiateOpt{
+	result, err := u.SplitUploadService.InitiateMultipartUpload(ctx.Ctx(), &service.MultipartInitiateOpt{
 		Name:   in.FileName,
 		Size:   in.FileSize,
+		Hash:   in.Sha256,
+		Proof:  in.Proof,
 		UserId: ctx.UserId(),
 	})
 	if err != nil {
 		return ctx.Error(err)
 	}
 
-	return ctx.Success(&web.UploadInitiateMultipartResponse{
+	if result.Challenge != nil {
+		return ctx.Success(&uploadInitiateMultipartResponse{
+			ShardSize: 5 << 20,
+			Challenge: result.Challenge,
+		})
+	}
+
+	info := result.Upload
+	if result.IsInstant {
+		return ctx.Success(&uploadInitiateMultipartResponse{
+			UploadId:  info.UploadId,
+			ShardSize: 5 << 20,
+			IsInstant: true,
+		})
+	}
+
+	return ctx.Success(&uploadInitiateMultipartResponse{
 		UploadId:  info.UploadId,
 		ShardSize: 5 << 20,
 		ShardNum:  int32(math.Ceil(float64(in.FileSize) / float64(5<<20))),
@@ -110,18 +224,19 @@ func (u *Upload) MultipartUpload(ctx *core.Context) error {
 		return ctx.InvalidParams("文件上传失败！")
 	}
 
-	err = u.SplitUploadService.MultipartUpload(ctx.Ctx(), &service.MultipartUploadOpt{
+	isMerge, err := u.SplitUploadService.MultipartUpload(ctx.Ctx(), &service.MultipartUploadOpt{
 		UserId:     ctx.UserId(),
 		UploadId:   in.UploadId,
 		SplitIndex: int(in.SplitIndex),
 		SplitNum:   int(in.SplitNum),
+		Checksum:   ctx.Context.PostForm("checksum"),
 		File:       file,
 	})
 	if err != nil {
 		return ctx.Error(err)
 	}
 
-	if in.SplitIndex != in.SplitNum {
+	if !isMerge {
 		return ctx.Success(&web.UploadMultipartResponse{
 			IsMerge: false,
 		})
@@ -132,3 +247,50 @@ func (u *Upload) MultipartUpload(ctx *core.Context) error {
 		IsMerge:  true,
 	})
 }
+
+type uploadMultipartStatusRequest struct {
+	UploadId string `form:"upload_id" json:"upload_id" binding:"required"`
+}
+
+// MultipartStatus 查询分片上传进度(断点续传)
+func (u *Upload) MultipartStatus(ctx *core.Context) error {
+	in := &uploadMultipartStatusRequest{}
+	if err := ctx.Context.ShouldBindQuery(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	status, err := u.SplitUploadService.MultipartStatus(ctx.Ctx(), ctx.UserId(), in.UploadId)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	return ctx.Success(status)
+}
+
+type uploadUsageRequest struct {
+	GroupId int `form:"group_id" json:"group_id" binding:"omitempty,min=1"`
+}
+
+// Usage 存储空间使用情况(传 group_id 时查询群组文件空间)
+func (u *Upload) Usage(ctx *core.Context) error {
+	in := &uploadUsageRequest{}
+	if err := ctx.Context.ShouldBindQuery(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	ownerType, ownerId := model.StorageOwnerUser, ctx.UserId()
+	if in.GroupId > 0 {
+		if !u.GroupMemberRepo.IsMember(ctx.Ctx(), in.GroupId, ctx.UserId(), true) {
+			return ctx.Forbidden("暂无权限！")
+		}
+
+		ownerType, ownerId = model.StorageOwnerGroup, in.GroupId
+	}
+
+	usage, err := u.StorageQuotaService.Usage(ctx.Ctx(), ownerType, ownerId)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	return ctx.Success(usage)
+}
@@ -2,6 +2,7 @@ package v1
 
 import (
 	"strings"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 	"go-chat/api/pb/web/v1"
@@ -15,12 +16,46 @@ import (
 )
 
 type User struct {
-	Redis        *redis.Client
-	UsersRepo    *repo.Users
-	OrganizeRepo *repo.Organize
-	UserService  service.IUserService
-	SmsService   service.ISmsService
-	Rsa          rsautil.IRsa
+	Redis            *redis.Client
+	UsersRepo        *repo.Users
+	UsersSettingRepo *repo.UsersSetting
+	OrganizeRepo     *repo.Organize
+	UserService      service.IUserService
+	SmsService       service.ISmsService
+	EmailService     service.IEmailService
+	Rsa              rsautil.IRsa
+}
+
+// UserSettingResponse 用户设置，在 web.UserSettingResponse 的基础上增加离线消息邮件提醒设置
+type UserSettingResponse struct {
+	UserInfo *UserSettingUserInfo   `json:"user_info"`
+	Setting  *UserSettingConfigInfo `json:"setting"`
+}
+
+type UserSettingUserInfo struct {
+	Uid      int32  `json:"uid"`
+	Nickname string `json:"nickname"`
+	Avatar   string `json:"avatar"`
+	Motto    string `json:"motto"`
+	Gender   int32  `json:"gender"`
+	IsQiye   bool   `json:"is_qiye"`
+	Mobile   string `json:"mobile"`
+	Email    string `json:"email"`
+}
+
+type UserSettingConfigInfo struct {
+	ThemeMode           string `json:"theme_mode"`
+	ThemeBagImg         string `json:"theme_bag_img"`
+	ThemeColor          string `json:"theme_color"`
+	NotifyCueTone       string `json:"notify_cue_tone"`
+	KeyboardEventNotify string `json:"keyboard_event_notify"`
+	DigestEnabled       int    `json:"digest_enabled"`   // 离线消息邮件提醒[1:开启;2:关闭;]
+	DigestFrequency     int    `json:"digest_frequency"` // 离线消息邮件提醒频率[1:每天;2:每周;]
+}
+
+type UserSettingUpdateRequest struct {
+	DigestEnabled   int `form:"digest_enabled" json:"digest_enabled" binding:"required,oneof=1 2"`     // 离线消息邮件提醒[1:开启;2:关闭;]
+	DigestFrequency int `form:"digest_frequency" json:"digest_frequency" binding:"required,oneof=1 2"` // 离线消息邮件提醒频率[1:每天;2:每周;]
 }
 
 // Detail 个人用户信息
@@ -56,8 +91,13 @@ func (u *User) Setting(ctx *core.Context) error {
 		return ctx.Error(err)
 	}
 
-	return ctx.Success(&web.UserSettingResponse{
-		UserInfo: &web.UserSettingResponse_UserInfo{
+	setting, err := u.UsersSettingRepo.Find(ctx.Ctx(), uid)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	return ctx.Success(&UserSettingResponse{
+		UserInfo: &UserSettingUserInfo{
 			Uid:      int32(user.Id),
 			Nickname: user.Nickname,
 			Avatar:   user.Avatar,
@@ -67,8 +107,30 @@ func (u *User) Setting(ctx *core.Context) error {
 			Mobile:   user.Mobile,
 			Email:    user.Email,
 		},
-		Setting: &web.UserSettingResponse_ConfigInfo{},
+		Setting: &UserSettingConfigInfo{
+			DigestEnabled:   setting.DigestEnabled,
+			DigestFrequency: setting.DigestFrequency,
+		},
+	})
+}
+
+// ChangeSetting 修改用户设置
+func (u *User) ChangeSetting(ctx *core.Context) error {
+	in := &UserSettingUpdateRequest{}
+	if err := ctx.Context.ShouldBindJSON(in); err != nil {
+		return ctx.InvalidParams(err)
+	}
+
+	err := u.UsersSettingRepo.Save(ctx.Ctx(), ctx.UserId(), map[string]any{
+		"digest_enabled":   in.DigestEnabled,
+		"digest_frequency": in.DigestFrequency,
+		"updated_at":       time.Now(),
 	})
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	return ctx.Success(nil, "设置修改成功！")
 }
 
 // ChangeDetail 修改个人用户信息
@@ -184,7 +246,51 @@ func (u *User) ChangeEmail(ctx *core.Context) error {
 		return ctx.InvalidParams(err)
 	}
 
-	// todo 1.验证邮件激活码是否正确
+	uid := ctx.UserId()
+	if uid == 2054 || uid == 2055 {
+		return ctx.Error(entity.ErrPermissionDenied)
+	}
 
-	return ctx.Success(nil, "手机号修改成功！")
+	user, err := u.UsersRepo.FindById(ctx.Ctx(), uid)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	if user.Email == in.Email {
+		return ctx.InvalidParams("邮箱与原邮箱一致无需修改！")
+	}
+
+	password, err := u.Rsa.Decrypt(in.Password)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	if !encrypt.VerifyPassword(user.Password, string(password)) {
+		return ctx.Error(entity.ErrAccountOrPasswordError)
+	}
+
+	if !u.EmailService.VerifyCode(ctx.Ctx(), entity.EmailChangeChannel, in.Email, in.Code) {
+		return ctx.Error(entity.ErrEmailCodeError)
+	}
+
+	exist, err := u.UsersRepo.IsExist(ctx.Ctx(), "email = ? and id != ?", in.Email, uid)
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	if exist {
+		return ctx.Error(entity.ErrEmailExist)
+	}
+
+	_, err = u.UsersRepo.UpdateById(ctx.Ctx(), user.Id, map[string]any{
+		"email": in.Email,
+	})
+	if err != nil {
+		return ctx.Error(err)
+	}
+
+	u.EmailService.DeleteCode(ctx.Ctx(), entity.EmailChangeChannel, in.Email)
+	_ = u.UsersRepo.ClearTableCache(ctx.Ctx(), user.Id)
+
+	return ctx.Success(nil, "邮箱修改成功！")
 }
@@ -13,9 +13,12 @@ var ProviderSet = wire.NewSet(
 	wire.Struct(new(v1.Auth), "*"),
 	wire.Struct(new(v1.Common), "*"),
 	wire.Struct(new(v1.User), "*"),
+	wire.Struct(new(v1.TwoFactor), "*"),
+	wire.Struct(new(v1.PushDevice), "*"),
 	wire.Struct(new(v1.Organize), "*"),
 	wire.Struct(new(v1.Upload), "*"),
 	wire.Struct(new(v1.Emoticon), "*"),
+	wire.Struct(new(v1.File), "*"),
 
 	wire.Struct(new(contact.Contact), "*"),
 	wire.Struct(new(contact.Apply), "*"),
@@ -30,6 +33,9 @@ var ProviderSet = wire.NewSet(
 	wire.Struct(new(talk.Message), "*"),
 	wire.Struct(new(talk.Records), "*"),
 	wire.Struct(new(talk.Publish), "*"),
+	wire.Struct(new(talk.Command), "*"),
+	wire.Struct(new(talk.Export), "*"),
+	wire.Struct(new(talk.Mention), "*"),
 
 	wire.Struct(new(article.Article), "*"),
 	wire.Struct(new(article.Annex), "*"),
@@ -5,13 +5,20 @@ import (
 	"go-chat/internal/apis/handler/admin"
 	"go-chat/internal/pkg/core"
 	"go-chat/internal/pkg/core/middleware"
+	"go-chat/internal/pkg/jwt"
+	"go-chat/internal/repository/model"
 )
 
 // RegisterAdminRoute 注册 Admin 路由
-func RegisterAdminRoute(secret string, router *gin.Engine, handler *admin.Handler, storage middleware.IStorage) {
+func RegisterAdminRoute(keys *jwt.KeySet, router *gin.Engine, handler *admin.Handler, storage middleware.IStorage, permission middleware.IPermission) {
 
 	// 授权验证中间件
-	authorize := middleware.Auth(secret, "admin", storage)
+	authorize := middleware.Auth(keys, "admin", storage)
+
+	// 权限验证中间件
+	can := func(name string) gin.HandlerFunc {
+		return middleware.Permission(permission, name)
+	}
 
 	// v1 接口
 	v1 := router.Group("/admin/v1")
@@ -24,9 +31,86 @@ func RegisterAdminRoute(secret string, router *gin.Engine, handler *admin.Handle
 		auth := v1.Group("/auth")
 		{
 			auth.POST("/login", core.HandlerFunc(handler.V1.Auth.Login))
+			auth.POST("/login/two-factor", core.HandlerFunc(handler.V1.Auth.LoginTwoFactor))
+			auth.POST("/login/two-factor/setup", core.HandlerFunc(handler.V1.Auth.LoginSetup))
+			auth.POST("/login/two-factor/enable", core.HandlerFunc(handler.V1.Auth.LoginEnable))
 			auth.GET("/captcha", core.HandlerFunc(handler.V1.Auth.Captcha))
 			auth.GET("/logout", authorize, core.HandlerFunc(handler.V1.Auth.Logout))
 			auth.POST("/refresh", authorize, core.HandlerFunc(handler.V1.Auth.Refresh))
+			auth.GET("/permissions", authorize, core.HandlerFunc(handler.V1.Auth.Permissions))
+			auth.GET("/two-factor", authorize, core.HandlerFunc(handler.V1.TwoFactor.Status))
+			auth.POST("/two-factor/setup", authorize, core.HandlerFunc(handler.V1.TwoFactor.Setup))
+			auth.POST("/two-factor/enable", authorize, core.HandlerFunc(handler.V1.TwoFactor.Enable))
+			auth.POST("/two-factor/disable", authorize, core.HandlerFunc(handler.V1.TwoFactor.Disable))
+			auth.POST("/two-factor/recovery-codes", authorize, core.HandlerFunc(handler.V1.TwoFactor.RecoveryCodes))
+		}
+
+		user := v1.Group("/user").Use(authorize)
+		{
+			user.GET("/list", can(model.AdminPermissionUserView), core.HandlerFunc(handler.V1.User.List))                       // 用户列表
+			user.GET("/detail", can(model.AdminPermissionUserView), core.HandlerFunc(handler.V1.User.Detail))                   // 用户详情
+			user.GET("/login-logs", can(model.AdminPermissionUserView), core.HandlerFunc(handler.V1.User.LoginLogs))            // 用户登录历史
+			user.POST("/disable", can(model.AdminPermissionUserManage), core.HandlerFunc(handler.V1.User.Disable))              // 禁用账号
+			user.POST("/enable", can(model.AdminPermissionUserManage), core.HandlerFunc(handler.V1.User.Enable))                // 启用账号
+			user.POST("/reset-password", can(model.AdminPermissionUserManage), core.HandlerFunc(handler.V1.User.ResetPassword)) // 重置密码
+			user.POST("/force-logout", can(model.AdminPermissionUserManage), core.HandlerFunc(handler.V1.User.ForceLogout))     // 强制下线
+		}
+
+		group := v1.Group("/group").Use(authorize)
+		{
+			group.GET("/list", can(model.AdminPermissionGroupView), core.HandlerFunc(handler.V1.Group.List))                 // 群组列表
+			group.GET("/members", can(model.AdminPermissionGroupView), core.HandlerFunc(handler.V1.Group.Members))           // 群成员列表
+			group.POST("/dismiss", can(model.AdminPermissionGroupManage), core.HandlerFunc(handler.V1.Group.Dismiss))        // 解散群组
+			group.POST("/transfer", can(model.AdminPermissionGroupManage), core.HandlerFunc(handler.V1.Group.Transfer))      // 转让群主
+			group.POST("/mute", can(model.AdminPermissionGroupManage), core.HandlerFunc(handler.V1.Group.Mute))              // 全员禁言
+			group.POST("/member-mute", can(model.AdminPermissionGroupManage), core.HandlerFunc(handler.V1.Group.MemberMute)) // 禁言群成员
+		}
+
+		message := v1.Group("/message").Use(authorize)
+		{
+			message.GET("/detail", can(model.AdminPermissionMessageView), core.HandlerFunc(handler.V1.Message.Detail))    // 查看消息
+			message.POST("/revoke", can(model.AdminPermissionMessageManage), core.HandlerFunc(handler.V1.Message.Revoke)) // 撤回消息
+		}
+
+		audit := v1.Group("/audit").Use(authorize)
+		{
+			audit.GET("/list", can(model.AdminPermissionAuditView), core.HandlerFunc(handler.V1.Audit.List)) // 操作审计日志
+		}
+
+		role := v1.Group("/role").Use(authorize, can(model.AdminPermissionRoleManage))
+		{
+			role.GET("/permissions", core.HandlerFunc(handler.V1.Role.Permissions)) // 可分配的权限列表
+			role.GET("/list", core.HandlerFunc(handler.V1.Role.List))               // 角色列表
+			role.POST("/create", core.HandlerFunc(handler.V1.Role.Create))          // 创建角色
+			role.POST("/update", core.HandlerFunc(handler.V1.Role.Update))          // 更新角色
+			role.POST("/delete", core.HandlerFunc(handler.V1.Role.Delete))          // 删除角色
+		}
+
+		account := v1.Group("/account").Use(authorize, can(model.AdminPermissionAdminManage))
+		{
+			account.GET("/list", core.HandlerFunc(handler.V1.Account.List))                        // 管理员列表
+			account.POST("/create", core.HandlerFunc(handler.V1.Account.Create))                   // 创建管理员
+			account.POST("/update", core.HandlerFunc(handler.V1.Account.Update))                   // 更新管理员
+			account.POST("/delete", core.HandlerFunc(handler.V1.Account.Delete))                   // 删除管理员
+			account.POST("/reset-two-factor", core.HandlerFunc(handler.V1.Account.ResetTwoFactor)) // 重置两步验证
+		}
+
+		robot := v1.Group("/robot").Use(authorize, can(model.AdminPermissionRobotManage))
+		{
+			robot.GET("/list", core.HandlerFunc(handler.V1.Robot.List))                 // 机器人列表
+			robot.POST("/create", core.HandlerFunc(handler.V1.Robot.Create))            // 创建机器人
+			robot.POST("/update", core.HandlerFunc(handler.V1.Robot.Update))            // 更新机器人
+			robot.POST("/delete", core.HandlerFunc(handler.V1.Robot.Delete))            // 删除机器人
+			robot.POST("/reset-secret", core.HandlerFunc(handler.V1.Robot.ResetSecret)) // 重置推送签名密钥
+		}
+
+		openApp := v1.Group("/open-app").Use(authorize, can(model.AdminPermissionOpenManage))
+		{
+			openApp.GET("/list", core.HandlerFunc(handler.V1.OpenApp.List))                 // 应用列表
+			openApp.POST("/create", core.HandlerFunc(handler.V1.OpenApp.Create))            // 创建应用
+			openApp.POST("/update", core.HandlerFunc(handler.V1.OpenApp.Update))            // 更新应用
+			openApp.POST("/delete", core.HandlerFunc(handler.V1.OpenApp.Delete))            // 删除应用
+			openApp.POST("/reset-secret", core.HandlerFunc(handler.V1.OpenApp.ResetSecret)) // 重置应用密钥
 		}
 	}
 }
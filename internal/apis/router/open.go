@@ -4,10 +4,16 @@ import (
 	"github.com/gin-gonic/gin"
 	"go-chat/internal/apis/handler/open"
 	"go-chat/internal/pkg/core"
+	"go-chat/internal/pkg/core/middleware"
+	"go-chat/internal/repository/model"
 )
 
 // RegisterOpenRoute 注册 Open 路由
-func RegisterOpenRoute(router *gin.Engine, handler *open.Handler) {
+func RegisterOpenRoute(router *gin.Engine, handler *open.Handler, storage middleware.IOpenAppStorage) {
+
+	// 签名验证中间件
+	authorize := middleware.OpenAuth(storage)
+
 	// v1 接口
 	v1 := router.Group("/open/v1")
 	{
@@ -15,5 +21,20 @@ func RegisterOpenRoute(router *gin.Engine, handler *open.Handler) {
 		{
 			index.Any("", core.HandlerFunc(handler.V1.Index.Index))
 		}
+
+		message := v1.Group("/message").Use(authorize)
+		{
+			message.POST("/send", middleware.OpenScope(model.OpenScopeMessageSend), core.HandlerFunc(handler.V1.Message.Send)) // 发送消息
+		}
+
+		upload := v1.Group("/upload").Use(authorize)
+		{
+			upload.POST("/file", middleware.OpenScope(model.OpenScopeFileUpload), core.HandlerFunc(handler.V1.Upload.File)) // 上传文件
+		}
+
+		group := v1.Group("/group").Use(authorize)
+		{
+			group.GET("/members", middleware.OpenScope(model.OpenScopeGroupMember), core.HandlerFunc(handler.V1.Group.Members)) // 群成员列表
+		}
 	}
 }
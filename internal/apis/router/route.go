@@ -8,14 +8,21 @@ import (
 	"go-chat/config"
 	"go-chat/internal/apis/handler"
 	"go-chat/internal/pkg/core/middleware"
+	"go-chat/internal/pkg/jwt"
 	"go-chat/internal/pkg/logger"
 	"go-chat/internal/repository/cache"
+	"go-chat/internal/service"
 )
 
 // NewRouter 初始化配置路由
-func NewRouter(conf *config.Config, handler *handler.Handler, session *cache.JwtTokenStorage) *gin.Engine {
+func NewRouter(conf *config.Config, handler *handler.Handler, keys *jwt.KeySet, session *cache.JwtTokenStorage, permission service.IAdminRoleService, openApp service.IOpenAppService) *gin.Engine {
 	router := gin.New()
 
+	// 仅信任可信代理转发的客户端 IP，避免伪造 X-Forwarded-For 绕过登录限制
+	if err := router.SetTrustedProxies(conf.App.GetTrustedProxies()); err != nil {
+		panic(err)
+	}
+
 	router.Use(middleware.Cors(conf.Cors))
 
 	if conf.Log.AccessLog {
@@ -42,13 +49,19 @@ func NewRouter(conf *config.Config, handler *handler.Handler, session *cache.Jwt
 		c.JSON(200, map[string]any{"code": 200, "message": "hello world"})
 	})
 
+	// 验签公钥，其它服务可据此校验本服务签发的令牌
+	router.GET("/.well-known/jwks.json", func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(200, keys.JWKS())
+	})
+
 	router.GET("/health/check", func(c *gin.Context) {
 		c.JSON(200, map[string]any{"status": "ok"})
 	})
 
-	RegisterWebRoute(conf.Jwt.Secret, router, handler.Api, session)
-	RegisterAdminRoute(conf.Jwt.Secret, router, handler.Admin, session)
-	RegisterOpenRoute(router, handler.Open)
+	RegisterWebRoute(keys, router, handler.Api, session)
+	RegisterAdminRoute(keys, router, handler.Admin, session, permission)
+	RegisterOpenRoute(router, handler.Open, openApp)
 
 	// 注册 debug 路由
 	if conf.Debug() {
@@ -5,13 +5,14 @@ import (
 	"go-chat/internal/apis/handler/web"
 	"go-chat/internal/pkg/core"
 	"go-chat/internal/pkg/core/middleware"
+	"go-chat/internal/pkg/jwt"
 )
 
 // RegisterWebRoute 注册 Web 路由
-func RegisterWebRoute(secret string, router *gin.Engine, handler *web.Handler, storage middleware.IStorage) {
+func RegisterWebRoute(keys *jwt.KeySet, router *gin.Engine, handler *web.Handler, storage middleware.IStorage) {
 
 	// 授权验证中间件
-	authorize := middleware.Auth(secret, "api", storage)
+	authorize := middleware.Auth(keys, "api", storage)
 
 	// v1 接口
 	v1 := router.Group("/api/v1")
@@ -25,11 +26,19 @@ func RegisterWebRoute(secret string, router *gin.Engine, handler *web.Handler, s
 		// 授权相关分组
 		auth := v1.Group("/auth")
 		{
-			auth.POST("/login", core.HandlerFunc(handler.V1.Auth.Login))                // 登录
-			auth.POST("/register", core.HandlerFunc(handler.V1.Auth.Register))          // 注册
-			auth.POST("/refresh", authorize, core.HandlerFunc(handler.V1.Auth.Refresh)) // 刷新 Token
-			auth.POST("/logout", authorize, core.HandlerFunc(handler.V1.Auth.Logout))   // 退出登录
-			auth.POST("/forget", core.HandlerFunc(handler.V1.Auth.Forget))              // 找回密码
+			auth.POST("/login", core.HandlerFunc(handler.V1.Auth.Login))                              // 登录
+			auth.GET("/captcha", core.HandlerFunc(handler.V1.Auth.Captcha))                           // 登录图形验证码
+			auth.POST("/login/two-factor", core.HandlerFunc(handler.V1.Auth.LoginTwoFactor))          // 两步验证登录
+			auth.POST("/register", core.HandlerFunc(handler.V1.Auth.Register))                        // 注册
+			auth.POST("/refresh", core.HandlerFunc(handler.V1.Auth.Refresh))                          // 刷新 Token
+			auth.POST("/logout", authorize, core.HandlerFunc(handler.V1.Auth.Logout))                 // 退出登录
+			auth.POST("/logout-all", authorize, core.HandlerFunc(handler.V1.Auth.LogoutAll))          // 退出所有设备
+			auth.GET("/sessions", authorize, core.HandlerFunc(handler.V1.Auth.Sessions))              // 登录设备列表
+			auth.POST("/sessions/revoke", authorize, core.HandlerFunc(handler.V1.Auth.RevokeSession)) // 注销指定设备
+			auth.POST("/forget", core.HandlerFunc(handler.V1.Auth.Forget))                            // 找回密码
+			auth.GET("/oidc/providers", core.HandlerFunc(handler.V1.Auth.OidcProviders))              // 第三方登录方式
+			auth.GET("/oidc/authorize", core.HandlerFunc(handler.V1.Auth.OidcAuthorize))              // 第三方登录授权地址
+			auth.POST("/oidc/callback", core.HandlerFunc(handler.V1.Auth.OidcCallback))               // 第三方登录回调
 		}
 
 		// 用户相关分组
@@ -37,10 +46,22 @@ func RegisterWebRoute(secret string, router *gin.Engine, handler *web.Handler, s
 		{
 			user.GET("/detail", core.HandlerFunc(handler.V1.User.Detail))                   // 获取个人信息
 			user.GET("/setting", core.HandlerFunc(handler.V1.User.Setting))                 // 获取个人信息
+			user.POST("/setting/update", core.HandlerFunc(handler.V1.User.ChangeSetting))   // 修改用户设置
 			user.POST("/update", core.HandlerFunc(handler.V1.User.ChangeDetail))            // 修改用户信息
 			user.POST("/password/update", core.HandlerFunc(handler.V1.User.ChangePassword)) // 修改用户密码
 			user.POST("/mobile/update", core.HandlerFunc(handler.V1.User.ChangeMobile))     // 修改用户手机号
 			user.POST("/email/update", core.HandlerFunc(handler.V1.User.ChangeEmail))       // 修改用户邮箱
+
+			// 两步验证相关
+			user.GET("/two-factor", core.HandlerFunc(handler.V1.TwoFactor.Status))                        // 两步验证状态
+			user.POST("/two-factor/setup", core.HandlerFunc(handler.V1.TwoFactor.Setup))                  // 生成绑定信息
+			user.POST("/two-factor/enable", core.HandlerFunc(handler.V1.TwoFactor.Enable))                // 开启两步验证
+			user.POST("/two-factor/disable", core.HandlerFunc(handler.V1.TwoFactor.Disable))              // 关闭两步验证
+			user.POST("/two-factor/recovery-codes", core.HandlerFunc(handler.V1.TwoFactor.RecoveryCodes)) // 重新生成恢复码
+
+			// 离线推送设备
+			user.POST("/push-device/register", core.HandlerFunc(handler.V1.PushDevice.Register))     // 注册推送设备
+			user.POST("/push-device/unregister", core.HandlerFunc(handler.V1.PushDevice.Unregister)) // 注销推送设备
 		}
 
 		contact := v1.Group("/contact").Use(authorize)
@@ -116,7 +137,14 @@ func RegisterWebRoute(secret string, router *gin.Engine, handler *web.Handler, s
 			talk.GET("/history-records", core.HandlerFunc(handler.V1.TalkRecords.SearchHistoryRecords)) // 历史会话记录
 			talk.GET("/forward-records", core.HandlerFunc(handler.V1.TalkRecords.GetForwardRecords))    // 会话转发记录
 			talk.GET("/file-download", core.HandlerFunc(handler.V1.TalkRecords.Download))               // 下载文件
+			talk.GET("/file-url", core.HandlerFunc(handler.V1.TalkRecords.FileUrl))                     // 文件临时访问地址
 			talk.POST("/clear-unread", core.HandlerFunc(handler.V1.Talk.ClearUnreadMessage))            // 清除会话未读数
+			talk.POST("/export/create", core.HandlerFunc(handler.V1.TalkExport.Create))                 // 创建聊天记录导出任务
+			talk.GET("/export/list", core.HandlerFunc(handler.V1.TalkExport.List))                      // 聊天记录导出任务列表
+			talk.GET("/export/download", core.HandlerFunc(handler.V1.TalkExport.Download))              // 下载导出的聊天记录
+			talk.GET("/commands", core.HandlerFunc(handler.V1.TalkCommand.List))                        // 对话可用的斜杠命令
+			talk.GET("/mention/list", core.HandlerFunc(handler.V1.TalkMention.List))                    // @我的消息列表
+			talk.POST("/mention/read", core.HandlerFunc(handler.V1.TalkMention.Read))                   // @我的消息标记已读
 		}
 
 		talkMessage := v1.Group("/talk/message").Use(authorize)
@@ -139,6 +167,8 @@ func RegisterWebRoute(secret string, router *gin.Engine, handler *web.Handler, s
 			upload.POST("/media-file", core.HandlerFunc(handler.V1.Upload.Image))
 			upload.POST("/init-multipart", core.HandlerFunc(handler.V1.Upload.InitiateMultipart))
 			upload.POST("/multipart", core.HandlerFunc(handler.V1.Upload.MultipartUpload))
+			upload.GET("/multipart/status", core.HandlerFunc(handler.V1.Upload.MultipartStatus))
+			upload.GET("/usage", core.HandlerFunc(handler.V1.Upload.Usage))
 		}
 
 		note := v1.Group("/note").Use(authorize)
@@ -154,6 +184,8 @@ func RegisterWebRoute(secret string, router *gin.Engine, handler *web.Handler, s
 			note.POST("/article/move-classify", core.HandlerFunc(handler.V1.Article.MoveClassify))   // 移动分类
 			note.POST("/article/collect", core.HandlerFunc(handler.V1.Article.Collect))              // 收藏文章
 			note.POST("/article/update-tag", core.HandlerFunc(handler.V1.Article.UpdateTag))         // 更新文章标签
+			note.GET("/article/export", core.HandlerFunc(handler.V1.Article.Export))                 // 导出笔记
+			note.POST("/article/import", core.HandlerFunc(handler.V1.Article.Import))                // 导入笔记
 
 			// 文章分类
 			note.GET("/classify/list", core.HandlerFunc(handler.V1.ArticleClass.List))
@@ -182,6 +214,10 @@ func RegisterWebRoute(secret string, router *gin.Engine, handler *web.Handler, s
 			organize.GET("/department/all", core.HandlerFunc(handler.V1.Organize.DepartmentList))
 			organize.GET("/personnel/all", core.HandlerFunc(handler.V1.Organize.PersonnelList))
 		}
+
+		// 本地存储签名下载地址(通过签名鉴权，无需登录)
+		v1.GET("/files/:bucket/*object", core.HandlerFunc(handler.V1.File.Download))
+		v1.HEAD("/files/:bucket/*object", core.HandlerFunc(handler.V1.File.Download))
 	}
 
 	// v2 接口
@@ -35,6 +35,8 @@ func (h *Handler) init() {
 	handlers[entity.SubEventContactApply] = h.onConsumeContactApply
 	handlers[entity.SubEventGroupJoin] = h.onConsumeGroupJoin
 	handlers[entity.SubEventGroupApply] = h.onConsumeGroupApply
+	handlers[entity.SubEventUserKickout] = h.onConsumeUserKickout
+	handlers[entity.SubEventImMessageEphemeral] = h.onConsumeTalkEphemeral
 }
 
 func (h *Handler) Call(ctx context.Context, event string, data []byte) {
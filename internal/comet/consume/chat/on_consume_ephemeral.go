@@ -0,0 +1,31 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/core/socket"
+	"go-chat/internal/pkg/logger"
+	"go-chat/internal/pkg/server"
+)
+
+// 临时消息(仅推送给指定用户)
+func (h *Handler) onConsumeTalkEphemeral(ctx context.Context, body []byte) {
+	var in entity.SubEventImMessageEphemeralPayload
+	if err := json.Unmarshal(body, &in); err != nil {
+		logger.Errorf("[ChatSubscribe] onConsumeTalkEphemeral Unmarshal err: %s", err.Error())
+		return
+	}
+
+	clientIds, _ := h.ClientConnectService.GetUidFromClientIds(ctx, server.ID(), socket.Session.Chat.Name(), in.UserId)
+	if len(clientIds) == 0 {
+		return
+	}
+
+	c := socket.NewSenderContent()
+	c.SetReceive(clientIds...)
+	c.SetMessage(entity.PushEventImMessageEphemeral, json.RawMessage(in.Message))
+
+	socket.Session.Chat.Write(c)
+}
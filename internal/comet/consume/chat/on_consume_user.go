@@ -0,0 +1,27 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/core/socket"
+	"go-chat/internal/pkg/logger"
+	"go-chat/internal/pkg/server"
+)
+
+// 用户强制下线消息
+func (h *Handler) onConsumeUserKickout(ctx context.Context, body []byte) {
+	var in entity.SubEventUserKickoutPayload
+	if err := json.Unmarshal(body, &in); err != nil {
+		logger.Errorf("[ChatSubscribe] onConsumeUserKickout Unmarshal err: %s", err.Error())
+		return
+	}
+
+	clientIds, _ := h.ClientConnectService.GetUidFromClientIds(ctx, server.ID(), socket.Session.Chat.Name(), in.UserId)
+	for _, cid := range clientIds {
+		if client, ok := socket.Session.Chat.Client(cid); ok {
+			client.Close(2001, in.Reason)
+		}
+	}
+}
@@ -9,13 +9,14 @@ import (
 	"go-chat/internal/pkg/core"
 	"go-chat/internal/pkg/core/middleware"
 	"go-chat/internal/pkg/core/socket"
+	"go-chat/internal/pkg/jwt"
 	"go-chat/internal/repository/cache"
 
 	"go-chat/config"
 )
 
 // NewRouter 初始化配置路由
-func NewRouter(conf *config.Config, handle *handler.Handler, storage *cache.JwtTokenStorage) *gin.Engine {
+func NewRouter(conf *config.Config, handle *handler.Handler, keys *jwt.KeySet, storage *cache.JwtTokenStorage) *gin.Engine {
 
 	router := gin.New()
 	router.Use(gin.RecoveryWithWriter(gin.DefaultWriter, func(c *gin.Context, err any) {
@@ -23,7 +24,7 @@ func NewRouter(conf *config.Config, handle *handler.Handler, storage *cache.JwtT
 	}))
 
 	// 授权验证中间件
-	authorize := middleware.Auth(conf.Jwt.Secret, "api", storage)
+	authorize := middleware.Auth(keys, "api", storage)
 
 	// 查看客户端连接状态
 	router.GET("/wss/connect/detail", func(ctx *gin.Context) {
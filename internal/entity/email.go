@@ -0,0 +1,6 @@
+package entity
+
+// 邮箱验证码渠道
+const (
+	EmailChangeChannel = "change_email"
+)
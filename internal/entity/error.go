@@ -15,10 +15,39 @@ var (
 	ErrAccountOrPasswordError    = errorx.New(100007, "账号密码填写错误")
 	ErrSmsCodeError              = errorx.New(100008, "短信验证码填写错误")
 	ErrAccountDisabled           = errorx.New(100009, "账号已被管理员禁用，如有问题请联系管理员！")
+	ErrTwoFactorCodeError        = errorx.New(100010, "两步验证码填写错误")
+	ErrTwoFactorExpired          = errorx.New(100011, "登录验证已过期，请重新登录")
+	ErrTwoFactorEnabled          = errorx.New(100012, "已开启两步验证")
+	ErrTwoFactorNotEnabled       = errorx.New(100013, "未开启两步验证")
+	ErrTwoFactorRequired         = errorx.New(100014, "当前账号必须开启两步验证")
+	ErrRefreshTokenInvalid       = errorx.New(100015, "登录已失效，请重新登录")
+	ErrRefreshTokenReused        = errorx.New(100016, "登录凭证已被重复使用，请重新登录")
+	ErrOidcProviderNotExist      = errorx.New(100017, "不支持的登录方式")
+	ErrOidcStateInvalid          = errorx.New(100018, "登录请求已失效，请重新登录")
+	ErrOidcLoginFailed           = errorx.New(100019, "第三方账号授权失败，请重新登录")
+	ErrOidcAccountNotBound       = errorx.New(100020, "第三方账号未关联系统账号，请联系管理员开通")
+	ErrOidcAccountConflict       = errorx.New(100021, "第三方账号无法关联，请联系管理员处理")
+	ErrLoginCaptchaRequired      = errorx.New(100022, "请填写图形验证码")
+	ErrLoginCaptchaError         = errorx.New(100023, "图形验证码填写错误")
+	ErrLoginLocked               = errorx.New(100024, "登录失败次数过多，请稍后再试")
+	ErrLoginTooFrequent          = errorx.New(100025, "登录尝试过于频繁，请稍后再试")
+	ErrEmailExist                = errorx.New(100026, "邮箱已被其它账号绑定")
+	ErrEmailCodeError            = errorx.New(100027, "邮箱验证码填写错误")
+	ErrPushProviderNotSupported  = errorx.New(100028, "暂不支持该推送服务")
+	ErrPushDeviceNotExist        = errorx.New(100029, "推送设备不存在")
 	ErrGroupDismissed            = errorx.New(110001, "群组已解散")
 	ErrGroupMemberLimit          = errorx.New(110002, "群成员数量已达到上限")
 	ErrGroupNotExist             = errorx.New(110003, "群组不存在")
+	ErrMentionNotMember          = errorx.New(110004, "@的用户不是群成员")
+	ErrMentionAllNotAllowed      = errorx.New(110005, "仅群主或管理员可以@所有人")
 	ErrNoteClassNotExist         = errorx.New(120003, "分类不存在")
 	ErrNoteClassDefaultNotAllow  = errorx.New(120004, "默认分类不允许修改")
 	ErrNoteClassDefaultNotDelete = errorx.New(120005, "默认分类不允许删除")
+	ErrUploadFileTooLarge        = errorx.New(130001, "上传文件大小超出限制")
+	ErrUploadFileTypeNotAllow    = errorx.New(130002, "上传文件类型不允许")
+	ErrUserStorageQuotaExceeded  = errorx.New(130003, "个人存储空间不足")
+	ErrGroupStorageQuotaExceeded = errorx.New(130004, "群组存储空间不足")
+	ErrFileInfected              = errorx.New(130005, "文件存在安全风险，已被拦截")
+	ErrFileScanUnavailable       = errorx.New(130006, "文件安全检测暂不可用，请稍后再试")
+	ErrFileNotScanned            = errorx.New(130007, "文件未通过安全检测")
 )
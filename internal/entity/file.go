@@ -28,13 +28,17 @@ func GetMediaType(ext string) int {
 
 // 文件系统相关
 const (
-	FileDriveLocal = 1
-	FileDriveMinio = 2
+	FileDriveLocal  = 1
+	FileDriveMinio  = 2
+	FileDriveS3     = 3
+	FileDriveWebDAV = 4
 )
 
 var fileSystemDriveMap = map[string]int{
-	"local": FileDriveLocal,
-	"minio": FileDriveMinio,
+	"local":  FileDriveLocal,
+	"minio":  FileDriveMinio,
+	"s3":     FileDriveS3,
+	"webdav": FileDriveWebDAV,
 }
 
 func FileDriveMode(drive string) int {
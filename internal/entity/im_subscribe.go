@@ -1,13 +1,15 @@
 package entity
 
 const (
-	SubEventImMessage         = "sub.im.message"          // 对话消息通知
-	SubEventImMessageKeyboard = "sub.im.message.keyboard" // 键盘输入事件通知
-	SubEventImMessageRevoke   = "sub.im.message.revoke"   // 聊天消息撤销通知
-	SubEventContactStatus     = "sub.im.contact.status"   // 用户在线状态通知
-	SubEventContactApply      = "sub.im.contact.apply"    // 好友申请消息通知
-	SubEventGroupJoin         = "sub.im.group.join"       // 邀请加入群聊通知
-	SubEventGroupApply        = "sub.im.group.apply"      // 入群申请通知
+	SubEventImMessage          = "sub.im.message"           // 对话消息通知
+	SubEventImMessageKeyboard  = "sub.im.message.keyboard"  // 键盘输入事件通知
+	SubEventImMessageRevoke    = "sub.im.message.revoke"    // 聊天消息撤销通知
+	SubEventContactStatus      = "sub.im.contact.status"    // 用户在线状态通知
+	SubEventContactApply       = "sub.im.contact.apply"     // 好友申请消息通知
+	SubEventGroupJoin          = "sub.im.group.join"        // 邀请加入群聊通知
+	SubEventGroupApply         = "sub.im.group.apply"       // 入群申请通知
+	SubEventUserKickout        = "sub.im.user.kickout"      // 用户强制下线通知
+	SubEventImMessageEphemeral = "sub.im.message.ephemeral" // 临时消息通知(仅推送给指定用户)
 )
 
 type SubscribeMessage struct {
@@ -52,3 +54,13 @@ type SubEventTalkRevokePayload struct {
 	MsgId    string `json:"msg_id"`    // 消息ID
 	Remark   string `json:"remark"`
 }
+
+type SubEventUserKickoutPayload struct {
+	UserId int    `json:"user_id"`
+	Reason string `json:"reason"`
+}
+
+type SubEventImMessageEphemeralPayload struct {
+	UserId  int    `json:"user_id"` // 接收者用户ID
+	Message string `json:"message"` // json 字符串(ImMessagePayload)
+}
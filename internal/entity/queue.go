@@ -1,5 +1,51 @@
 package entity
 
 const (
-	LoginTopic = "im.user.login"
+	LoginTopic        = "im.user.login"
+	TalkExportTopic   = "im.talk.export"
+	RobotWebhookTopic = "im.robot.webhook"
+	EmailTopic        = "im.email.send"
+	PushDispatchTopic = "im.push.dispatch"
+	PushDeliverTopic  = "im.push.deliver"
 )
+
+// PushDispatchMessage 离线推送分发队列消息，由消费者筛选离线用户后按设备投递推送
+type PushDispatchMessage struct {
+	TalkMode int    `json:"talk_mode"`  // 对话类型[1:私聊;2:群聊;]
+	FromId   int    `json:"from_id"`    // 发送者ID
+	ToFromId int    `json:"to_from_id"` // 接收者ID(用户ID或群ID)
+	MsgId    string `json:"msg_id"`     // 消息ID
+	Content  string `json:"content"`    // 消息摘要
+	Mentions []int  `json:"mentions"`   // @用户ID列表，包含 MentionAll 时为@所有人
+}
+
+// PushDeliverMessage 离线推送发送队列消息
+type PushDeliverMessage struct {
+	DeviceId int               `json:"device_id"` // 推送设备ID
+	Title    string            `json:"title"`     // 标题
+	Body     string            `json:"body"`      // 内容
+	Badge    int               `json:"badge"`     // 角标数
+	Data     map[string]string `json:"data"`      // 自定义数据
+}
+
+// EmailMessage 邮件发送队列消息
+type EmailMessage struct {
+	OutboxId int `json:"outbox_id"` // 发件箱邮件ID
+}
+
+// TalkExportMessage 聊天记录导出队列消息
+type TalkExportMessage struct {
+	ExportId int `json:"export_id"` // 导出任务ID
+}
+
+// RobotWebhookMessage 机器人消息推送队列消息
+type RobotWebhookMessage struct {
+	RobotId  int    `json:"robot_id"`  // 机器人ID
+	TalkMode int    `json:"talk_mode"` // 对话类型[1:私聊;2:群聊;]
+	MsgId    string `json:"msg_id"`    // 消息ID(私聊为机器人信箱中的消息ID)
+
+	Event    string               `json:"event,omitempty"`      // 事件类型，为空时为 message 事件
+	UserId   int                  `json:"user_id,omitempty"`    // 命令发送者ID(command 事件)
+	ToFromId int                  `json:"to_from_id,omitempty"` // 对话接收者ID(command 事件)
+	Command  *RobotWebhookCommand `json:"command,omitempty"`    // 斜杠命令(command 事件)
+}
@@ -0,0 +1,51 @@
+package entity
+
+import "encoding/json"
+
+const (
+	RobotWebhookEventMessage = "message" // 收到消息
+	RobotWebhookEventCommand = "command" // 收到斜杠命令
+)
+
+// RobotWebhookPayload 推送给机器人 Webhook 的请求内容
+type RobotWebhookPayload struct {
+	Event    string               `json:"event"`             // 事件类型
+	RobotId  int                  `json:"robot_id"`          // 机器人ID
+	TalkMode int                  `json:"talk_mode"`         // 对话类型[1:私聊;2:群聊;]
+	MsgId    string               `json:"msg_id"`            // 消息ID
+	MsgType  int                  `json:"msg_type"`          // 消息类型
+	Content  string               `json:"content"`           // 文本内容(非文本消息为消息摘要)
+	Extra    json.RawMessage      `json:"extra"`             // 消息扩展字段
+	Sender   RobotWebhookSender   `json:"sender"`            // 发送者
+	Group    *RobotWebhookGroup   `json:"group,omitempty"`   // 群信息(群聊消息)
+	Command  *RobotWebhookCommand `json:"command,omitempty"` // 斜杠命令(command 事件)
+	SendTime string               `json:"send_time"`         // 发送时间
+}
+
+type RobotWebhookCommand struct {
+	Name string   `json:"name"` // 命令名称
+	Args []string `json:"args"` // 命令参数
+	Text string   `json:"text"` // 命令名称之后的原始文本
+}
+
+type RobotWebhookSender struct {
+	UserId   int    `json:"user_id"`
+	Nickname string `json:"nickname"`
+	Avatar   string `json:"avatar"`
+}
+
+type RobotWebhookGroup struct {
+	GroupId   int    `json:"group_id"`
+	GroupName string `json:"group_name"`
+}
+
+// RobotWebhookResponse 机器人 Webhook 响应内容，reply 不为空时以机器人身份回复消息(command 事件仅回复给命令发送者)
+type RobotWebhookResponse struct {
+	Reply *RobotWebhookReply `json:"reply"`
+}
+
+type RobotWebhookReply struct {
+	Type    string `json:"type"`    // 回复类型[text:文本消息;code:代码消息;]，默认 text
+	Content string `json:"content"` // 回复内容
+	Lang    string `json:"lang"`    // 代码语言(type=code)
+}
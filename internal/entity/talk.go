@@ -6,13 +6,17 @@ const (
 	ChatGroupMode   = 2 // 群聊模式
 )
 
+// MentionAll @所有人，群聊文本消息的 mentions 中包含该值时提醒全体群成员，仅群主及管理员可用
+const MentionAll = 0
+
 const (
-	PushEventImMessage         = "im.message"          // 对话消息推送
-	PushEventImMessageKeyboard = "im.message.keyboard" // 键盘输入事件推送
-	PushEventImMessageRevoke   = "im.message.revoke"   // 聊天消息撤销推送
-	PushEventContactApply      = "im.contact.apply"    // 好友申请消息推送
-	PushEventContactStatus     = "im.contact.status"   // 用户在线状态推送
-	PushEventGroupApply        = "im.group.apply"      // 用户在线状态推送
+	PushEventImMessage          = "im.message"           // 对话消息推送
+	PushEventImMessageKeyboard  = "im.message.keyboard"  // 键盘输入事件推送
+	PushEventImMessageRevoke    = "im.message.revoke"    // 聊天消息撤销推送
+	PushEventContactApply       = "im.contact.apply"     // 好友申请消息推送
+	PushEventContactStatus      = "im.contact.status"    // 用户在线状态推送
+	PushEventGroupApply         = "im.group.apply"       // 用户在线状态推送
+	PushEventImMessageEphemeral = "im.message.ephemeral" // 临时消息推送(仅推送给指定用户，不保存)
 )
 
 // IM消息类型
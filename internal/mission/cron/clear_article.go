@@ -7,14 +7,16 @@ import (
 	"go-chat/internal/pkg/core/crontab"
 	"go-chat/internal/pkg/filesystem"
 	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
 	"gorm.io/gorm"
 )
 
 var _ crontab.ICrontab = (*ClearArticle)(nil)
 
 type ClearArticle struct {
-	DB         *gorm.DB
-	Filesystem filesystem.IFilesystem
+	DB               *gorm.DB
+	Filesystem       filesystem.IFilesystem
+	StorageUsageRepo *repo.StorageUsage
 }
 
 func (c *ClearArticle) Name() string {
@@ -54,8 +56,7 @@ func (c *ClearArticle) clearAnnex() {
 		}
 
 		for _, item := range items {
-			_ = c.Filesystem.Delete(c.Filesystem.BucketPrivateName(), item.Path)
-			c.DB.Delete(&model.ArticleAnnex{}, item.Id)
+			c.deleteAnnex(item)
 		}
 
 		if len(items) < size {
@@ -82,14 +83,12 @@ func (c *ClearArticle) clearNote() {
 		for _, item := range items {
 			subItems := make([]*model.ArticleAnnex, 0)
 
-			if err := c.DB.Model(&model.ArticleAnnex{}).Select("path").Where("article_id = ?", item.Id).Scan(&subItems).Error; err != nil {
+			if err := c.DB.Model(&model.ArticleAnnex{}).Select("id", "user_id", "size", "path").Where("article_id = ?", item.Id).Scan(&subItems).Error; err != nil {
 				continue
 			}
 
 			for _, subItem := range subItems {
-				_ = c.Filesystem.Delete(c.Filesystem.BucketPrivateName(), subItem.Path)
-
-				c.DB.Delete(&model.ArticleAnnex{}, subItem.Id)
+				c.deleteAnnex(subItem)
 			}
 
 			c.DB.Delete(&model.Article{}, item.Id)
@@ -102,3 +101,12 @@ func (c *ClearArticle) clearNote() {
 		lastId = items[size-1].Id
 	}
 }
+
+// deleteAnnex 删除附件文件并释放存储配额
+func (c *ClearArticle) deleteAnnex(item *model.ArticleAnnex) {
+	_ = c.Filesystem.Delete(c.Filesystem.BucketPrivateName(), item.Path)
+
+	if c.DB.Delete(&model.ArticleAnnex{}, item.Id).RowsAffected > 0 {
+		_ = c.StorageUsageRepo.Decr(context.Background(), model.StorageOwnerUser, item.UserId, int64(item.Size))
+	}
+}
@@ -2,20 +2,22 @@ package cron
 
 import (
 	"context"
-	"path"
+	"os"
 	"time"
 
 	"go-chat/internal/pkg/core/crontab"
 	"go-chat/internal/pkg/filesystem"
 	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
 	"gorm.io/gorm"
 )
 
 var _ crontab.ICrontab = (*ClearTmpFile)(nil)
 
 type ClearTmpFile struct {
-	DB         *gorm.DB
-	Filesystem filesystem.IFilesystem
+	DB               *gorm.DB
+	Filesystem       filesystem.IFilesystem
+	StorageUsageRepo *repo.StorageUsage
 }
 
 // Spec 配置定时任务规则
@@ -44,28 +46,67 @@ func (c *ClearTmpFile) Do(ctx context.Context) error {
 		}
 
 		for _, item := range items {
-			if item.Drive == 2 {
+			// 引用文件对象的上传记录只释放引用，文件对象由引用计数归零后统一清理
+			if !c.releaseObject(item) {
+				// 清理未完成合并的分片文件
 				_ = c.Filesystem.AbortMultipartUpload(c.Filesystem.BucketPrivateName(), item.Path, item.UploadId)
 
 				c.DB.Delete(model.FileUpload{}, "user_id = ? and upload_id = ? and type = 2", item.UserId, item.UploadId)
-			} else {
-				list := make([]*model.FileUpload, 0)
-				c.DB.Table("file_upload").Where("user_id = ? and upload_id = ? and type = 2", item.UserId, item.UploadId).Scan(&list)
 
-				for _, value := range list {
-					_ = c.Filesystem.Delete(c.Filesystem.BucketPublicName(), value.Path)
-					c.DB.Delete(model.FileUpload{}, value.Id)
+				if err := c.Filesystem.Delete(c.Filesystem.BucketPrivateName(), item.Path); err != nil && !os.IsNotExist(err) {
+					continue
 				}
+			}
 
-				if len(list) > 0 {
-					// 删除空文件夹
-					_ = c.Filesystem.Delete(c.Filesystem.BucketPrivateName(), path.Dir(item.Path))
-				}
+			// 上传记录删除后释放初始化时预留的存储配额
+			if res := c.DB.Delete(model.FileUpload{}, item.Id); res.Error == nil && res.RowsAffected > 0 {
+				_ = c.StorageUsageRepo.Decr(ctx, model.StorageOwnerUser, item.UserId, item.FileSize)
 			}
+		}
+
+		if len(items) == size {
+			lastId = items[size-1].Id
+		} else {
+			break
+		}
+	}
+
+	return c.clearObject()
+}
+
+// releaseObject 释放上传记录对文件对象的引用
+func (c *ClearTmpFile) releaseObject(item *model.FileUpload) bool {
+	if item.Hash == "" {
+		return false
+	}
 
-			if err := c.Filesystem.Delete(c.Filesystem.BucketPrivateName(), item.Path); err == nil {
-				c.DB.Delete(model.FileUpload{}, item.Id)
+	res := c.DB.Model(&model.FileObject{}).
+		Where("hash = ? and path = ? and ref_count > 0", item.Hash, item.Path).
+		Update("ref_count", gorm.Expr("ref_count - 1"))
+
+	return res.Error == nil && res.RowsAffected > 0
+}
+
+// clearObject 删除引用计数已归零的文件对象
+func (c *ClearTmpFile) clearObject() error {
+	lastId, size := 0, 100
+
+	for {
+		items := make([]*model.FileObject, 0)
+
+		err := c.DB.Model(&model.FileObject{}).Where("id > ? and ref_count <= 0", lastId).Order("id asc").Limit(size).Scan(&items).Error
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			// 删除前再次确认引用计数，避免与秒传并发
+			res := c.DB.Delete(&model.FileObject{}, "id = ? and ref_count <= 0", item.Id)
+			if res.Error != nil || res.RowsAffected == 0 {
+				continue
 			}
+
+			_ = c.Filesystem.Delete(c.Filesystem.BucketPrivateName(), item.Path)
 		}
 
 		if len(items) == size {
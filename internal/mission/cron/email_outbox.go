@@ -0,0 +1,43 @@
+package cron
+
+import (
+	"context"
+
+	"go-chat/internal/pkg/core/crontab"
+	"go-chat/internal/pkg/logger"
+	"go-chat/internal/service"
+)
+
+var _ crontab.ICrontab = (*EmailOutbox)(nil)
+
+// EmailOutbox 重新投递发件箱中投递队列失败或队列消息丢失的邮件
+type EmailOutbox struct {
+	EmailService service.IEmailService
+}
+
+// Spec 配置定时任务规则
+// 每隔5分钟执行一次
+func (c *EmailOutbox) Spec() string {
+	return "*/5 * * * *"
+}
+
+func (c *EmailOutbox) Name() string {
+	return "email.outbox"
+}
+
+func (c *EmailOutbox) Enable() bool {
+	return true
+}
+
+func (c *EmailOutbox) Do(ctx context.Context) error {
+	num, err := c.EmailService.Republish(ctx)
+	if err != nil {
+		return err
+	}
+
+	if num > 0 {
+		logger.Infof("重新投递待发送邮件 num:%d", num)
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package cron
+
+import (
+	"context"
+
+	"go-chat/config"
+	"go-chat/internal/pkg/core/crontab"
+	"go-chat/internal/pkg/logger"
+	"go-chat/internal/service"
+)
+
+var _ crontab.ICrontab = (*LdapSync)(nil)
+
+// LdapSync 从目录服务同步用户、部门及岗位
+type LdapSync struct {
+	Config      *config.Config
+	LdapService service.ILdapService
+}
+
+// Spec 配置定时任务规则
+// 由配置文件 ldap.sync_spec 指定
+func (c *LdapSync) Spec() string {
+	if c.Config.Ldap == nil {
+		return ""
+	}
+
+	return c.Config.Ldap.SyncSpec
+}
+
+func (c *LdapSync) Name() string {
+	return "ldap.sync"
+}
+
+func (c *LdapSync) Enable() bool {
+	return c.LdapService.Enabled() && c.Spec() != ""
+}
+
+func (c *LdapSync) Do(ctx context.Context) error {
+	result, err := c.LdapService.Sync(ctx)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("目录同步完成 total:%d created:%d disabled:%d failed:%d", result.Total, result.Created, result.Disabled, result.Failed)
+
+	return nil
+}
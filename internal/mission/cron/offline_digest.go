@@ -0,0 +1,49 @@
+package cron
+
+import (
+	"context"
+
+	"go-chat/config"
+	"go-chat/internal/pkg/core/crontab"
+	"go-chat/internal/pkg/logger"
+	"go-chat/internal/service"
+)
+
+var _ crontab.ICrontab = (*OfflineDigest)(nil)
+
+// OfflineDigest 向长时间离线且有未读消息的用户发送邮件摘要
+type OfflineDigest struct {
+	Config               *config.Config
+	OfflineDigestService service.IOfflineDigestService
+}
+
+// Spec 配置定时任务规则
+// 由配置文件 offline_digest.spec 指定
+func (c *OfflineDigest) Spec() string {
+	if c.Config.OfflineDigest == nil {
+		return ""
+	}
+
+	return c.Config.OfflineDigest.Spec
+}
+
+func (c *OfflineDigest) Name() string {
+	return "offline.digest"
+}
+
+func (c *OfflineDigest) Enable() bool {
+	return c.Config.OfflineDigest != nil && c.Config.OfflineDigest.Enabled && c.Spec() != ""
+}
+
+func (c *OfflineDigest) Do(ctx context.Context) error {
+	num, err := c.OfflineDigestService.Send(ctx)
+	if err != nil {
+		return err
+	}
+
+	if num > 0 {
+		logger.Infof("离线消息摘要发送完成 num:%d", num)
+	}
+
+	return nil
+}
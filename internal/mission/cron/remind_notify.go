@@ -0,0 +1,79 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"time"
+
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/core/crontab"
+	"go-chat/internal/pkg/logger"
+	"go-chat/internal/repository/cache"
+	"go-chat/internal/repository/repo"
+	"go-chat/internal/service/message"
+)
+
+var _ crontab.ICrontab = (*RemindNotify)(nil)
+
+// RemindNotify 发送 /remind 命令创建的定时提醒
+type RemindNotify struct {
+	RemindStorage  *cache.RemindStorage
+	RobotRepo      *repo.Robot
+	GroupRepo      *repo.Group
+	MessageService message.IService
+}
+
+// Spec 配置定时任务规则
+// 每分钟执行一次
+func (c *RemindNotify) Spec() string {
+	return "* * * * *"
+}
+
+func (c *RemindNotify) Name() string {
+	return "command.remind.notify"
+}
+
+func (c *RemindNotify) Enable() bool {
+	return true
+}
+
+func (c *RemindNotify) Do(ctx context.Context) error {
+	robot, err := c.RobotRepo.GetLoginRobot(ctx)
+	if err != nil {
+		return err
+	}
+
+	for {
+		items, err := c.RemindStorage.PopDue(ctx, time.Now().Unix(), 100)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			content := fmt.Sprintf("⏰ 提醒：%s", item.Content)
+			if item.TalkMode == entity.ChatGroupMode {
+				if group, err := c.GroupRepo.FindById(ctx, item.ToFromId); err == nil {
+					content = fmt.Sprintf("%s\n(来自群聊「%s」)", content, group.Name)
+				}
+			}
+
+			// 通过系统通知机器人私信提醒，用户离线时也可在上线后查看
+			err := c.MessageService.CreateTextMessage(ctx, message.CreateTextMessage{
+				TalkMode: entity.ChatPrivateMode,
+				FromId:   robot.UserId,
+				ToFromId: item.UserId,
+				Content:  html.EscapeString(content),
+			})
+			if err != nil {
+				logger.Errorf("定时提醒发送失败 uid:%d err:%s", item.UserId, err.Error())
+			}
+		}
+
+		if len(items) < 100 {
+			break
+		}
+	}
+
+	return nil
+}
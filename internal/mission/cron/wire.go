@@ -7,6 +7,10 @@ type Crontab struct {
 	ClearArticle      *ClearArticle
 	ClearTmpFile      *ClearTmpFile
 	ClearExpireServer *ClearExpireServer
+	RemindNotify      *RemindNotify
+	LdapSync          *LdapSync
+	EmailOutbox       *EmailOutbox
+	OfflineDigest     *OfflineDigest
 }
 
 var ProviderSet = wire.NewSet(
@@ -14,5 +18,9 @@ var ProviderSet = wire.NewSet(
 	wire.Struct(new(ClearTmpFile), "*"),
 	wire.Struct(new(ClearWsCache), "*"),
 	wire.Struct(new(ClearExpireServer), "*"),
+	wire.Struct(new(RemindNotify), "*"),
+	wire.Struct(new(LdapSync), "*"),
+	wire.Struct(new(EmailOutbox), "*"),
+	wire.Struct(new(OfflineDigest), "*"),
 	wire.Struct(new(Crontab), "*"),
 )
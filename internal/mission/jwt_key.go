@@ -0,0 +1,119 @@
+package mission
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"go-chat/config"
+	"go-chat/internal/pkg/jwt"
+)
+
+// JwtKeyGenerate 生成签名密钥，指定 activate 时同时设为当前签名密钥
+//
+// 推荐的轮换流程：先生成新密钥(仅发布到 JWKS 供其它服务缓存)，待各服务刷新公钥后再 activate，
+// 旧密钥在其签发的令牌全部过期后再 remove。
+func JwtKeyGenerate(ctx *cli.Context, conf *config.Config) error {
+	return jwtKeyGenerate(ctx, conf, ctx.Bool("activate"))
+}
+
+// JwtKeyRotate 生成新的签名密钥并立即启用，旧密钥继续用于验签
+func JwtKeyRotate(ctx *cli.Context, conf *config.Config) error {
+	return jwtKeyGenerate(ctx, conf, true)
+}
+
+func jwtKeyGenerate(ctx *cli.Context, conf *config.Config, activate bool) error {
+	dir, err := jwtKeyDir(ctx, conf)
+	if err != nil {
+		return err
+	}
+
+	key, err := jwt.GenerateKey(ctx.String("alg"))
+	if err != nil {
+		return err
+	}
+
+	if err := jwt.SaveKey(dir, key); err != nil {
+		return err
+	}
+
+	fmt.Printf("生成签名密钥 kid:%s alg:%s\n", key.Id, key.Algorithm)
+
+	if !activate {
+		return nil
+	}
+
+	return jwtKeyActivate(dir, key.Id)
+}
+
+// JwtKeyActivate 设置当前签名密钥
+func JwtKeyActivate(ctx *cli.Context, conf *config.Config) error {
+	dir, err := jwtKeyDir(ctx, conf)
+	if err != nil {
+		return err
+	}
+
+	return jwtKeyActivate(dir, ctx.String("kid"))
+}
+
+// JwtKeyRemove 删除不再使用的验签密钥
+func JwtKeyRemove(ctx *cli.Context, conf *config.Config) error {
+	dir, err := jwtKeyDir(ctx, conf)
+	if err != nil {
+		return err
+	}
+
+	if err := jwt.RemoveKey(dir, ctx.String("kid")); err != nil {
+		return err
+	}
+
+	fmt.Printf("已删除签名密钥 kid:%s\n", ctx.String("kid"))
+	return nil
+}
+
+// JwtKeyList 查看密钥目录中的全部密钥
+func JwtKeyList(ctx *cli.Context, conf *config.Config) error {
+	dir, err := jwtKeyDir(ctx, conf)
+	if err != nil {
+		return err
+	}
+
+	keys, active, err := jwt.LoadKeyDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		status := "verify"
+		if key.Id == active {
+			status = "active"
+		}
+
+		fmt.Printf("%-20s %-6s %-7s %s\n", key.Id, key.Algorithm, status, key.CreatedAt.Format(time.DateTime))
+	}
+
+	return nil
+}
+
+func jwtKeyActivate(dir string, kid string) error {
+	if err := jwt.SetActiveKey(dir, kid); err != nil {
+		return err
+	}
+
+	fmt.Printf("已启用签名密钥 kid:%s，运行中的服务将在 1 分钟内生效\n", kid)
+	return nil
+}
+
+func jwtKeyDir(ctx *cli.Context, conf *config.Config) (string, error) {
+	dir := ctx.String("dir")
+	if dir == "" {
+		dir = conf.Jwt.KeyDir
+	}
+
+	if dir == "" {
+		return "", errors.New("未配置 jwt.key_dir，可通过 --dir 指定密钥目录")
+	}
+
+	return dir, nil
+}
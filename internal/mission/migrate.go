@@ -14,6 +14,42 @@ import (
 //go:embed resource/lumenim.sql
 var file embed.FS
 
+// upgradeColumns 旧版本升级时需补齐的字段，CREATE TABLE IF NOT EXISTS 不会变更已存在的数据表
+var upgradeColumns = []struct {
+	Table      string
+	Column     string
+	Definition string
+}{
+	{"users", "status", "tinyint unsigned NOT NULL DEFAULT '1' COMMENT '账号状态[1:正常;2:已禁用;]'"},
+	{"admin", "role_id", "int unsigned NOT NULL DEFAULT '1' COMMENT '角色ID'"},
+	{"admin", "totp_required", "tinyint unsigned NOT NULL DEFAULT '2' COMMENT '强制两步验证[1:是;2:否;]'"},
+	{"file_upload", "hash", "varchar(64) NOT NULL DEFAULT '' COMMENT '文件SHA-256'"},
+	{"file_upload", "scan_status", "tinyint unsigned NOT NULL DEFAULT '0' COMMENT '安全扫描状态[0:未扫描;1:安全;2:已拒绝;]'"},
+	{"robot", "webhook_url", "varchar(255) NOT NULL DEFAULT '' COMMENT '消息推送地址(Webhook)'"},
+	{"robot", "webhook_secret", "varchar(64) NOT NULL DEFAULT '' COMMENT '消息推送签名密钥'"},
+	{"robot", "commands", "varchar(2048) NOT NULL DEFAULT '' COMMENT '斜杠命令列表(json)'"},
+	{"user_device", "session_id", "varchar(64) NOT NULL DEFAULT '' COMMENT '登录会话ID'"},
+}
+
+// upgradeIndexes 旧版本升级时需补齐的索引
+var upgradeIndexes = []struct {
+	Table      string
+	Index      string
+	Definition string
+}{
+	{"file_upload", "idx_hash", "KEY `idx_hash` (`hash`) USING BTREE"},
+	{"robot", "idx_type", "KEY `idx_type` (`type`)"},
+	{"user_device", "idx_session_id", "KEY `idx_session_id` (`session_id`) USING BTREE"},
+}
+
+// upgradeDropIndexes 旧版本升级时需移除的索引
+var upgradeDropIndexes = []struct {
+	Table string
+	Index string
+}{
+	{"robot", "uk_type"},
+}
+
 type MigrateProvider struct {
 	Config *config.Config
 	DB     *gorm.DB
@@ -39,5 +75,44 @@ func Migrate(_ *cli.Context, app *MigrateProvider) error {
 		}
 	}
 
+	upgrade(app.DB)
+
 	return nil
 }
+
+// upgrade 补齐旧版本数据表缺失的字段及索引
+func upgrade(db *gorm.DB) {
+	migrator := db.Migrator()
+
+	for _, item := range upgradeColumns {
+		if migrator.HasColumn(item.Table, item.Column) {
+			continue
+		}
+
+		sql := fmt.Sprintf("ALTER TABLE `%s` ADD COLUMN `%s` %s", item.Table, item.Column, item.Definition)
+		if err := db.Exec(sql).Error; err != nil {
+			fmt.Println("执行SQL:", sql, " Err:", err)
+		}
+	}
+
+	for _, item := range upgradeDropIndexes {
+		if !migrator.HasIndex(item.Table, item.Index) {
+			continue
+		}
+
+		if err := migrator.DropIndex(item.Table, item.Index); err != nil {
+			fmt.Println("删除索引:", item.Table, item.Index, " Err:", err)
+		}
+	}
+
+	for _, item := range upgradeIndexes {
+		if migrator.HasIndex(item.Table, item.Index) {
+			continue
+		}
+
+		sql := fmt.Sprintf("ALTER TABLE `%s` ADD %s", item.Table, item.Definition)
+		if err := db.Exec(sql).Error; err != nil {
+			fmt.Println("执行SQL:", sql, " Err:", err)
+		}
+	}
+}
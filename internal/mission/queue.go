@@ -4,20 +4,39 @@ import (
 	"context"
 	"time"
 
+	"github.com/nsqio/go-nsq"
 	"github.com/redis/go-redis/v9"
 	"github.com/urfave/cli/v2"
+	"go-chat/config"
 	"go-chat/internal/mission/queue"
+	"go-chat/internal/pkg/core/consumer"
 )
 
 type QueueProvider struct {
+	Config    *config.Config
 	Consumers *queue.Consumers
 	Redis     *redis.Client
 }
 
 func Queue(ctx *cli.Context, app *QueueProvider) error {
+	go subscribe(ctx.Context, app)
+
+	c := consumer.NewConsumer(app.Config.Nsq.Addr, nsq.NewConfig())
+
+	c.Register("default", app.Consumers.TalkExportConsumer)
+	c.Register("default", app.Consumers.RobotWebhookConsumer)
+	c.Register("default", app.Consumers.EmailConsumer)
+	c.Register("default", app.Consumers.PushDispatchConsumer)
+	c.Register("default", app.Consumers.PushDeliverConsumer)
+
+	return c.Start(ctx.Context, ctx.String("group"))
+}
+
+// subscribe 订阅 Redis 发布的队列消息
+func subscribe(ctx context.Context, app *QueueProvider) {
 	topics := []string{"im.user.login"}
 
-	sub := app.Redis.Subscribe(ctx.Context, topics...)
+	sub := app.Redis.Subscribe(ctx, topics...)
 	defer sub.Close()
 
 	for data := range sub.Channel(redis.WithChannelHealthCheckInterval(10 * time.Second)) {
@@ -26,6 +45,4 @@ func Queue(ctx *cli.Context, app *QueueProvider) error {
 			_ = app.Consumers.UserLoginConsumer.Do(context.Background(), []byte(data.Payload), 1)
 		}
 	}
-
-	return nil
 }
@@ -0,0 +1,38 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/core/consumer"
+	"go-chat/internal/service"
+)
+
+var _ consumer.IConsumerHandle = (*EmailConsumer)(nil)
+
+type EmailConsumer struct {
+	EmailService service.IEmailService
+}
+
+func (e *EmailConsumer) Touch() bool {
+	return true
+}
+
+func (e *EmailConsumer) Topic() string {
+	return entity.EmailTopic
+}
+
+func (e *EmailConsumer) Channel() string {
+	return "default"
+}
+
+func (e *EmailConsumer) Do(ctx context.Context, msg []byte, attempts uint16) error {
+	var in entity.EmailMessage
+	if err := json.Unmarshal(msg, &in); err != nil {
+		return nil
+	}
+
+	// 发送失败时返回错误由队列延迟重试，重试次数记录在发件箱中
+	return e.EmailService.Deliver(ctx, in.OutboxId)
+}
@@ -0,0 +1,78 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/core/consumer"
+	"go-chat/internal/service"
+)
+
+var (
+	_ consumer.IConsumerHandle = (*PushDispatchConsumer)(nil)
+	_ consumer.IConsumerHandle = (*PushDeliverConsumer)(nil)
+)
+
+// 推送失败最大重试次数，重试间隔由 consumer.BackoffStrategy 决定，超过后推送已失去时效
+const pushDeliverMaxAttempts = 3
+
+// PushDispatchConsumer 筛选离线用户并按设备投递推送
+type PushDispatchConsumer struct {
+	PushService service.IPushService
+}
+
+func (p *PushDispatchConsumer) Touch() bool {
+	return true
+}
+
+func (p *PushDispatchConsumer) Topic() string {
+	return entity.PushDispatchTopic
+}
+
+func (p *PushDispatchConsumer) Channel() string {
+	return "default"
+}
+
+func (p *PushDispatchConsumer) Do(ctx context.Context, msg []byte, attempts uint16) error {
+	var in entity.PushDispatchMessage
+	if err := json.Unmarshal(msg, &in); err != nil {
+		return nil
+	}
+
+	if err := p.PushService.Dispatch(ctx, &in); err != nil && attempts < pushDeliverMaxAttempts {
+		return err
+	}
+
+	return nil
+}
+
+// PushDeliverConsumer 发送推送通知到设备
+type PushDeliverConsumer struct {
+	PushService service.IPushService
+}
+
+func (p *PushDeliverConsumer) Touch() bool {
+	return true
+}
+
+func (p *PushDeliverConsumer) Topic() string {
+	return entity.PushDeliverTopic
+}
+
+func (p *PushDeliverConsumer) Channel() string {
+	return "default"
+}
+
+func (p *PushDeliverConsumer) Do(ctx context.Context, msg []byte, attempts uint16) error {
+	var in entity.PushDeliverMessage
+	if err := json.Unmarshal(msg, &in); err != nil {
+		return nil
+	}
+
+	if err := p.PushService.Deliver(ctx, &in); err != nil && attempts < pushDeliverMaxAttempts {
+		return err
+	}
+
+	return nil
+}
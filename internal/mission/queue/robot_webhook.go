@@ -0,0 +1,265 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"html"
+	"strings"
+	"time"
+
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/core/consumer"
+	"go-chat/internal/pkg/jsonutil"
+	"go-chat/internal/pkg/logger"
+	"go-chat/internal/pkg/webhook"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+	"go-chat/internal/service/message"
+	"gorm.io/gorm"
+)
+
+var _ consumer.IConsumerHandle = (*RobotWebhookConsumer)(nil)
+
+// 推送失败最大重试次数，重试间隔由 consumer.BackoffStrategy 决定
+const robotWebhookMaxAttempts = 5
+
+var robotWebhookClient = webhook.NewClient(10 * time.Second)
+
+type RobotWebhookConsumer struct {
+	RobotRepo            *repo.Robot
+	UsersRepo            *repo.Users
+	GroupRepo            *repo.Group
+	TalkRecordFriendRepo *repo.TalkUserMessage
+	TalkRecordGroupRepo  *repo.TalkGroupMessage
+	Message              message.IService
+}
+
+func (r *RobotWebhookConsumer) Touch() bool {
+	return true
+}
+
+func (r *RobotWebhookConsumer) Topic() string {
+	return entity.RobotWebhookTopic
+}
+
+func (r *RobotWebhookConsumer) Channel() string {
+	return "default"
+}
+
+func (r *RobotWebhookConsumer) Do(ctx context.Context, msg []byte, attempts uint16) error {
+	var in entity.RobotWebhookMessage
+	if err := json.Unmarshal(msg, &in); err != nil {
+		return nil
+	}
+
+	robot, err := r.RobotRepo.FindById(ctx, in.RobotId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	if robot.Status != model.RootStatusNormal || robot.WebhookUrl == "" {
+		return nil
+	}
+
+	var payload *entity.RobotWebhookPayload
+	if in.Event == entity.RobotWebhookEventCommand {
+		payload, err = r.commandPayload(ctx, robot, &in)
+	} else {
+		payload, err = r.payload(ctx, robot, &in)
+	}
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	resp, err := robotWebhookClient.Post(ctx, robot.WebhookUrl, robot.WebhookSecret, payload)
+	if err != nil {
+		logger.Errorf("机器人消息推送失败 robot_id:%d event:%s msg_id:%s attempts:%d err:%s", robot.Id, payload.Event, in.MsgId, attempts, err.Error())
+
+		if attempts < robotWebhookMaxAttempts {
+			return err
+		}
+
+		return nil
+	}
+
+	if in.Event == entity.RobotWebhookEventCommand {
+		r.ephemeralReply(ctx, robot, &in, resp.Body)
+	} else {
+		r.reply(ctx, robot, payload, resp.Body)
+	}
+
+	return nil
+}
+
+// commandPayload 斜杠命令推送内容，命令不会保存为消息记录
+func (r *RobotWebhookConsumer) commandPayload(ctx context.Context, robot *model.Robot, in *entity.RobotWebhookMessage) (*entity.RobotWebhookPayload, error) {
+	if in.Command == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	payload := &entity.RobotWebhookPayload{
+		Event:    entity.RobotWebhookEventCommand,
+		RobotId:  robot.Id,
+		TalkMode: in.TalkMode,
+		MsgType:  entity.ChatMsgTypeText,
+		Content:  strings.TrimSpace("/" + in.Command.Name + " " + in.Command.Text),
+		Extra:    json.RawMessage("{}"),
+		Command:  in.Command,
+		SendTime: time.Now().Format(time.DateTime),
+	}
+
+	user, err := r.UsersRepo.FindByIdWithCache(ctx, in.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	payload.Sender = entity.RobotWebhookSender{UserId: user.Id, Nickname: user.Nickname, Avatar: user.Avatar}
+
+	if in.TalkMode == entity.ChatGroupMode {
+		group, err := r.GroupRepo.FindById(ctx, in.ToFromId)
+		if err != nil {
+			return nil, err
+		}
+
+		payload.Group = &entity.RobotWebhookGroup{GroupId: group.Id, GroupName: group.Name}
+	}
+
+	return payload, nil
+}
+
+// ephemeralReply 斜杠命令的响应内容以临时消息回复给命令发送者
+func (r *RobotWebhookConsumer) ephemeralReply(ctx context.Context, robot *model.Robot, in *entity.RobotWebhookMessage, body []byte) {
+	var resp entity.RobotWebhookResponse
+	if len(body) == 0 || json.Unmarshal(body, &resp) != nil || resp.Reply == nil {
+		return
+	}
+
+	content := strings.TrimSpace(resp.Reply.Content)
+	if content == "" {
+		return
+	}
+
+	err := r.Message.CreateEphemeralMessage(ctx, message.CreateEphemeralMessage{
+		TalkMode: in.TalkMode,
+		FromId:   robot.UserId,
+		ToFromId: in.ToFromId,
+		UserId:   in.UserId,
+		Content:  html.EscapeString(content),
+	})
+	if err != nil {
+		logger.Errorf("机器人回复命令失败 robot_id:%d command:%s err:%s", robot.Id, in.Command.Name, err.Error())
+	}
+}
+
+func (r *RobotWebhookConsumer) payload(ctx context.Context, robot *model.Robot, in *entity.RobotWebhookMessage) (*entity.RobotWebhookPayload, error) {
+	payload := &entity.RobotWebhookPayload{
+		Event:    entity.RobotWebhookEventMessage,
+		RobotId:  robot.Id,
+		TalkMode: in.TalkMode,
+		MsgId:    in.MsgId,
+	}
+
+	var extra string
+	if in.TalkMode == entity.ChatGroupMode {
+		record, err := r.TalkRecordGroupRepo.FindByMsgId(ctx, in.MsgId)
+		if err != nil {
+			return nil, err
+		}
+
+		group, err := r.GroupRepo.FindById(ctx, record.GroupId)
+		if err != nil {
+			return nil, err
+		}
+
+		payload.MsgType, payload.Sender.UserId, extra = record.MsgType, record.FromId, record.Extra
+		payload.SendTime = record.SendTime.Format(time.DateTime)
+		payload.Group = &entity.RobotWebhookGroup{GroupId: group.Id, GroupName: group.Name}
+	} else {
+		record, err := r.TalkRecordFriendRepo.FindByMsgId(ctx, in.MsgId)
+		if err != nil {
+			return nil, err
+		}
+
+		payload.MsgType, payload.Sender.UserId, extra = record.MsgType, record.FromId, record.Extra
+		payload.SendTime = record.SendTime.Format(time.DateTime)
+	}
+
+	if user, err := r.UsersRepo.FindByIdWithCache(ctx, payload.Sender.UserId); err == nil {
+		payload.Sender.Nickname = user.Nickname
+		payload.Sender.Avatar = user.Avatar
+	}
+
+	payload.Extra = json.RawMessage(extra)
+	if !json.Valid(payload.Extra) {
+		payload.Extra = json.RawMessage("{}")
+	}
+
+	if payload.MsgType == entity.ChatMsgTypeText {
+		var text model.TalkRecordExtraText
+		_ = jsonutil.Decode(extra, &text)
+		payload.Content = html.UnescapeString(text.Content)
+	} else {
+		payload.Content = entity.ChatMsgTypeMapping[payload.MsgType]
+	}
+
+	return payload, nil
+}
+
+// reply 根据 Webhook 响应内容以机器人身份回复消息
+func (r *RobotWebhookConsumer) reply(ctx context.Context, robot *model.Robot, payload *entity.RobotWebhookPayload, body []byte) {
+	var resp entity.RobotWebhookResponse
+	if len(body) == 0 || json.Unmarshal(body, &resp) != nil || resp.Reply == nil {
+		return
+	}
+
+	content := strings.TrimSpace(resp.Reply.Content)
+	if content == "" {
+		return
+	}
+
+	talkMode, toFromId := entity.ChatPrivateMode, payload.Sender.UserId
+	if payload.Group != nil {
+		talkMode, toFromId = entity.ChatGroupMode, payload.Group.GroupId
+	}
+
+	var err error
+	switch resp.Reply.Type {
+	case "code":
+		err = r.Message.CreateCodeMessage(ctx, message.CreateCodeMessage{
+			TalkMode: talkMode,
+			FromId:   robot.UserId,
+			ToFromId: toFromId,
+			Lang:     resp.Reply.Lang,
+			Code:     content,
+		})
+	default:
+		opt := message.CreateTextMessage{
+			TalkMode: talkMode,
+			FromId:   robot.UserId,
+			ToFromId: toFromId,
+			Content:  html.EscapeString(content),
+		}
+
+		// 群聊中回复时 @ 消息发送者
+		if talkMode == entity.ChatGroupMode {
+			opt.QuoteId = payload.MsgId
+			opt.Mentions = []int{payload.Sender.UserId}
+		}
+
+		err = r.Message.CreateTextMessage(ctx, opt)
+	}
+
+	if err != nil {
+		logger.Errorf("机器人回复消息失败 robot_id:%d msg_id:%s err:%s", robot.Id, payload.MsgId, err.Error())
+	}
+}
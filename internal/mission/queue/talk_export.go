@@ -0,0 +1,102 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go-chat/config"
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/core/consumer"
+	"go-chat/internal/pkg/jsonutil"
+	"go-chat/internal/pkg/logger"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+	"go-chat/internal/service"
+	"go-chat/internal/service/message"
+)
+
+var _ consumer.IConsumerHandle = (*TalkExportConsumer)(nil)
+
+// 导出失败最大重试次数
+const talkExportMaxAttempts = 3
+
+type TalkExportConsumer struct {
+	Config             *config.Config
+	RobotRepo          *repo.Robot
+	TalkExportService  service.ITalkExportService
+	TalkSessionService service.ITalkSessionService
+	Message            message.IService
+}
+
+func (t *TalkExportConsumer) Touch() bool {
+	return true
+}
+
+func (t *TalkExportConsumer) Topic() string {
+	return entity.TalkExportTopic
+}
+
+func (t *TalkExportConsumer) Channel() string {
+	return "default"
+}
+
+func (t *TalkExportConsumer) Do(ctx context.Context, msg []byte, attempts uint16) error {
+	var in entity.TalkExportMessage
+	if err := json.Unmarshal(msg, &in); err != nil {
+		return nil
+	}
+
+	info, err := t.TalkExportService.Export(ctx, in.ExportId)
+	if err != nil {
+		logger.Errorf("聊天记录导出失败 export_id:%d attempts:%d err:%s", in.ExportId, attempts, err.Error())
+
+		if attempts < talkExportMaxAttempts {
+			return err
+		}
+
+		_ = t.TalkExportService.Fail(ctx, in.ExportId, err.Error())
+		return nil
+	}
+
+	if info.Status != model.TalkExportStatusSuccess {
+		return nil
+	}
+
+	t.notify(ctx, info)
+
+	return nil
+}
+
+// notify 通过登录机器人通知用户下载
+func (t *TalkExportConsumer) notify(ctx context.Context, info *model.TalkExport) {
+	robot, err := t.RobotRepo.GetLoginRobot(ctx)
+	if err != nil {
+		return
+	}
+
+	_, _ = t.TalkSessionService.Create(ctx, &service.TalkSessionCreateOpt{
+		UserId:     info.UserId,
+		TalkType:   entity.ChatPrivateMode,
+		ReceiverId: robot.UserId,
+		IsBoot:     true,
+	})
+
+	link := t.Config.App.GetApiUrl(fmt.Sprintf("/api/v1/talk/export/download?export_id=%d", info.Id))
+
+	content := fmt.Sprintf(
+		"聊天记录导出完成，共 %d 条消息（%s ~ %s）。\n下载地址：%s",
+		info.MsgNum, info.StartTime.Format("2006-01-02"), info.EndTime.Format("2006-01-02"), link,
+	)
+
+	err = t.Message.CreateToUserPrivateMessage(ctx, &model.TalkUserMessage{
+		MsgType:  entity.ChatMsgTypeText,
+		UserId:   info.UserId,
+		ToFromId: robot.UserId,
+		FromId:   robot.UserId,
+		Extra:    jsonutil.Encode(&model.TalkRecordExtraText{Content: content}),
+	})
+	if err != nil {
+		logger.Errorf("聊天记录导出通知失败 export_id:%d err:%s", info.Id, err.Error())
+	}
+}
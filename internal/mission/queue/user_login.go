@@ -3,10 +3,15 @@ package queue
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"time"
 
 	"go-chat/api/pb/queue/v1"
+	"go-chat/config"
 	"go-chat/internal/entity"
 	"go-chat/internal/pkg/core/consumer"
+	"go-chat/internal/pkg/logger"
+	"go-chat/internal/repository/model"
 	"go-chat/internal/repository/repo"
 	"go-chat/internal/service"
 	"go-chat/internal/service/message"
@@ -14,10 +19,17 @@ import (
 
 var _ consumer.IConsumerHandle = (*UserLoginConsumer)(nil)
 
+// 判断是否为常用设备及常用登录地时参考的最近登录次数
+const loginHistoryLimit = 50
+
 type UserLoginConsumer struct {
+	Config             *config.Config
 	RobotRepo          *repo.Robot
+	UsersRepo          *repo.Users
+	UsersLoginLogRepo  *repo.UsersLoginLog
 	IpAddressService   service.IIpAddressService
 	TalkSessionService service.ITalkSessionService
+	EmailService       service.IEmailService
 	Message            message.IService
 }
 
@@ -39,17 +51,33 @@ func (u *UserLoginConsumer) Do(ctx context.Context, msg []byte, attempts uint16)
 		return err
 	}
 
-	root, err := u.RobotRepo.GetLoginRobot(ctx)
+	// 需在记录本次登录日志前查询，否则本次登录会被视为常用设备
+	history, _ := u.UsersLoginLogRepo.FindLatest(ctx, int(in.UserId), loginHistoryLimit)
+
+	// 查询失败时视为未知地址，不影响登录日志及提醒邮件
+	address, err := u.IpAddressService.FindAddress(in.IpAddr)
 	if err != nil {
-		return nil
+		address = ""
 	}
 
-	if root == nil {
-		return nil
+	// 记录登录日志
+	_ = u.UsersLoginLogRepo.Create(ctx, &model.UsersLoginLog{
+		UserId:    int(in.UserId),
+		Ip:        in.IpAddr,
+		Address:   address,
+		Platform:  in.Platform,
+		Agent:     in.Agent,
+		CreatedAt: time.Now(),
+	})
+
+	// 登录提醒邮件不依赖登录通知机器人
+	reason, isAlert := u.reason(history, &in, address)
+	if isAlert {
+		u.sendAlertEmail(ctx, &in, address, reason)
 	}
 
-	address, err := u.IpAddressService.FindAddress(in.IpAddr)
-	if err != nil {
+	root, err := u.RobotRepo.GetLoginRobot(ctx)
+	if err != nil || root == nil {
 		return nil
 	}
 
@@ -67,7 +95,72 @@ func (u *UserLoginConsumer) Do(ctx context.Context, msg []byte, attempts uint16)
 		Address:  address,
 		Platform: in.Platform,
 		Agent:    in.Agent,
-		Reason:   "常用设备登录",
+		Reason:   reason,
 		LoginAt:  in.LoginAt,
 	})
 }
+
+// 对比最近的登录记录判断是否为新设备或异地登录，返回登录说明及是否需要提醒
+func (u *UserLoginConsumer) reason(history []*model.UsersLoginLog, in *queue.UserLoginRequest, address string) (string, bool) {
+	// 首次登录没有可对比的记录，不做提醒
+	if len(history) == 0 {
+		return "首次登录", false
+	}
+
+	isNewDevice, isNewAddress := true, address != ""
+	for _, item := range history {
+		if item.Platform == in.Platform && item.Agent == in.Agent {
+			isNewDevice = false
+		}
+
+		if item.Address == address {
+			isNewAddress = false
+		}
+	}
+
+	switch {
+	case isNewDevice && isNewAddress:
+		return "新设备异地登录", true
+	case isNewDevice:
+		return "新设备登录", true
+	case isNewAddress:
+		return "异地登录", true
+	}
+
+	return "常用设备登录", false
+}
+
+// 发送登录提醒邮件，未绑定邮箱的用户不发送
+func (u *UserLoginConsumer) sendAlertEmail(ctx context.Context, in *queue.UserLoginRequest, address string, reason string) {
+	if !u.Config.Login.WithDefault().AlertEmail {
+		return
+	}
+
+	user, err := u.UsersRepo.FindById(ctx, int(in.UserId))
+	if err != nil || user.Email == "" {
+		return
+	}
+
+	if address == "" {
+		address = "未知"
+	}
+
+	err = u.EmailService.Send(ctx, &service.EmailSendOpt{
+		UserId:   user.Id,
+		Email:    user.Email,
+		Template: service.EmailTemplateLoginAlert,
+		Subject:  fmt.Sprintf("账号安全提醒：您的账号在%s", reason),
+		Data: map[string]string{
+			"nickname": user.Nickname,
+			"login_at": in.LoginAt,
+			"reason":   reason,
+			"ip":       in.IpAddr,
+			"address":  address,
+			"platform": in.Platform,
+			"agent":    in.Agent,
+		},
+	})
+	if err != nil {
+		logger.Errorf("登录提醒邮件发送失败 uid:%d err:%s", in.UserId, err.Error())
+	}
+}
@@ -3,10 +3,20 @@ package queue
 import "github.com/google/wire"
 
 type Consumers struct {
-	UserLoginConsumer *UserLoginConsumer
+	UserLoginConsumer    *UserLoginConsumer
+	TalkExportConsumer   *TalkExportConsumer
+	RobotWebhookConsumer *RobotWebhookConsumer
+	EmailConsumer        *EmailConsumer
+	PushDispatchConsumer *PushDispatchConsumer
+	PushDeliverConsumer  *PushDeliverConsumer
 }
 
 var ProviderSet = wire.NewSet(
 	wire.Struct(new(Consumers), "*"),
 	wire.Struct(new(UserLoginConsumer), "*"),
+	wire.Struct(new(TalkExportConsumer), "*"),
+	wire.Struct(new(RobotWebhookConsumer), "*"),
+	wire.Struct(new(EmailConsumer), "*"),
+	wire.Struct(new(PushDispatchConsumer), "*"),
+	wire.Struct(new(PushDeliverConsumer), "*"),
 )
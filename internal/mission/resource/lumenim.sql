@@ -1,16 +1,18 @@
 CREATE TABLE IF NOT EXISTS `admin`
 (
-    `id`         int unsigned     NOT NULL AUTO_INCREMENT COMMENT '用户ID',
-    `username`   varchar(20)      NOT NULL COMMENT '用户昵称',
-    `password`   varchar(255)     NOT NULL COMMENT '用户密码',
-    `avatar`     varchar(255)     NOT NULL DEFAULT '' COMMENT '用户头像',
-    `gender`     tinyint unsigned NOT NULL DEFAULT '3' COMMENT '用户性别[1:男;2:女;3:未知;]',
-    `mobile`     varchar(11)      NOT NULL DEFAULT '' COMMENT '手机号',
-    `email`      varchar(30)      NOT NULL DEFAULT '' COMMENT '用户邮箱',
-    `motto`      varchar(100)     NOT NULL DEFAULT '' COMMENT '用户座右铭',
-    `status`     tinyint unsigned NOT NULL DEFAULT '1' COMMENT '状态[1:正常;2:停用;]',
-    `created_at` datetime         NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '注册时间',
-    `updated_at` datetime         NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    `id`            int unsigned     NOT NULL AUTO_INCREMENT COMMENT '用户ID',
+    `username`      varchar(20)      NOT NULL COMMENT '用户昵称',
+    `password`      varchar(255)     NOT NULL COMMENT '用户密码',
+    `avatar`        varchar(255)     NOT NULL DEFAULT '' COMMENT '用户头像',
+    `gender`        tinyint unsigned NOT NULL DEFAULT '3' COMMENT '用户性别[1:男;2:女;3:未知;]',
+    `mobile`        varchar(11)      NOT NULL DEFAULT '' COMMENT '手机号',
+    `email`         varchar(30)      NOT NULL DEFAULT '' COMMENT '用户邮箱',
+    `motto`         varchar(100)     NOT NULL DEFAULT '' COMMENT '用户座右铭',
+    `status`        tinyint unsigned NOT NULL DEFAULT '1' COMMENT '状态[1:正常;2:停用;]',
+    `role_id`       int unsigned     NOT NULL DEFAULT '1' COMMENT '角色ID',
+    `totp_required` tinyint unsigned NOT NULL DEFAULT '2' COMMENT '强制两步验证[1:是;2:否;]',
+    `created_at`    datetime         NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '注册时间',
+    `updated_at`    datetime         NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
     PRIMARY KEY (`id`) USING BTREE,
     UNIQUE KEY `uk_username` (`username`) USING BTREE,
     UNIQUE KEY `uk_email` (`email`) USING BTREE,
@@ -202,12 +204,15 @@ CREATE TABLE IF NOT EXISTS `file_upload`
     `path`          varchar(255)     NOT NULL DEFAULT '' COMMENT '临时保存路径',
     `file_ext`      varchar(16)      NOT NULL DEFAULT '' COMMENT '文件后缀名',
     `file_size`     int unsigned     NOT NULL COMMENT '文件大小',
+    `hash`          varchar(64)      NOT NULL DEFAULT '' COMMENT '文件SHA-256',
+    `scan_status`   tinyint unsigned NOT NULL DEFAULT '0' COMMENT '安全扫描状态[0:未扫描;1:安全;2:已拒绝;]',
     `is_delete`     tinyint unsigned NOT NULL DEFAULT '0' COMMENT '文件是否删除[1:是;2:否;] ',
     `attr`          json             NOT NULL COMMENT '额外参数json',
     `created_at`    datetime         NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '更新时间',
     `updated_at`    datetime         NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '创建时间',
     PRIMARY KEY (`id`),
     KEY `idx_user_id_hash_name` (`user_id`, `upload_id`) USING BTREE,
+    KEY `idx_hash` (`hash`) USING BTREE,
     KEY `idx_created_at` (`created_at`) USING BTREE,
     KEY `idx_updated_at` (`updated_at`) USING BTREE
 ) ENGINE = InnoDB
@@ -394,18 +399,21 @@ CREATE TABLE IF NOT EXISTS `organize_position`
 
 CREATE TABLE IF NOT EXISTS `robot`
 (
-    `id`         int unsigned     NOT NULL AUTO_INCREMENT COMMENT '机器人ID',
-    `user_id`    int unsigned     NOT NULL COMMENT '关联用户ID',
-    `robot_name` varchar(64)      NOT NULL DEFAULT '' COMMENT '机器人名称',
-    `describe`   varchar(255)     NOT NULL DEFAULT '' COMMENT '描述信息',
-    `logo`       varchar(255)     NOT NULL DEFAULT '' COMMENT '机器人logo',
-    `is_talk`    tinyint unsigned NOT NULL DEFAULT '2' COMMENT '可发送消息[1:是;2:否;]',
-    `status`     tinyint unsigned NOT NULL DEFAULT '0' COMMENT '状态[1:正常;2:已禁用;3:已删除;]',
-    `type`       tinyint unsigned NOT NULL DEFAULT '0' COMMENT '机器人类型',
-    `created_at` datetime         NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
-    `updated_at` datetime         NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    `id`             int unsigned     NOT NULL AUTO_INCREMENT COMMENT '机器人ID',
+    `user_id`        int unsigned     NOT NULL COMMENT '关联用户ID',
+    `robot_name`     varchar(64)      NOT NULL DEFAULT '' COMMENT '机器人名称',
+    `describe`       varchar(255)     NOT NULL DEFAULT '' COMMENT '描述信息',
+    `logo`           varchar(255)     NOT NULL DEFAULT '' COMMENT '机器人logo',
+    `is_talk`        tinyint unsigned NOT NULL DEFAULT '2' COMMENT '可发送消息[1:是;2:否;]',
+    `status`         tinyint unsigned NOT NULL DEFAULT '0' COMMENT '状态[1:正常;2:已禁用;3:已删除;]',
+    `type`           tinyint unsigned NOT NULL DEFAULT '0' COMMENT '机器人类型[1:登录通知;2:Webhook机器人;]',
+    `webhook_url`    varchar(255)     NOT NULL DEFAULT '' COMMENT '消息推送地址(Webhook)',
+    `webhook_secret` varchar(64)      NOT NULL DEFAULT '' COMMENT '消息推送签名密钥',
+    `commands`       varchar(2048)    NOT NULL DEFAULT '' COMMENT '斜杠命令列表(json)',
+    `created_at`     datetime         NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at`     datetime         NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
     PRIMARY KEY (`id`),
-    UNIQUE KEY `uk_type` (`type`),
+    KEY `idx_type` (`type`),
     UNIQUE KEY `uk_user_id` (`user_id`) USING BTREE,
     KEY `idx_created_at` (`created_at`) USING BTREE,
     KEY `idx_updated_at` (`updated_at`) USING BTREE
@@ -512,6 +520,7 @@ CREATE TABLE IF NOT EXISTS `users`
     `email`      varchar(30)      NOT NULL DEFAULT '' COMMENT '用户邮箱',
     `birthday`   varchar(10)      NOT NULL DEFAULT '' COMMENT '生日',
     `is_robot`   tinyint unsigned NOT NULL DEFAULT '2' COMMENT '是否机器人[1:是;2:否;]',
+    `status`     tinyint unsigned NOT NULL DEFAULT '1' COMMENT '账号状态[1:正常;2:已禁用;]',
     `created_at` datetime         NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '注册时间',
     `updated_at` datetime         NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
     PRIMARY KEY (`id`) USING BTREE,
@@ -548,4 +557,238 @@ CREATE TABLE IF NOT EXISTS `article_history`
     KEY          `idx_created_at` (`created_at`) USING BTREE,
     KEY          `idx_article_id` (`article_id`) USING BTREE,
     KEY          `idx_user_id_article_id` (`user_id`,`article_id`) USING BTREE
-) ENGINE=InnoDB  DEFAULT CHARSET=utf8mb4 COMMENT='笔记历史记录表';;
\ No newline at end of file
+) ENGINE=InnoDB  DEFAULT CHARSET=utf8mb4 COMMENT='笔记历史记录表';;
+
+CREATE TABLE IF NOT EXISTS `talk_export`
+(
+    `id`         int unsigned     NOT NULL AUTO_INCREMENT COMMENT '导出任务ID',
+    `user_id`    int unsigned     NOT NULL COMMENT '申请导出的用户ID',
+    `talk_mode`  tinyint unsigned NOT NULL DEFAULT '1' COMMENT '对话类型[1:私聊;2:群聊;]',
+    `to_from_id` int unsigned     NOT NULL COMMENT '接收者ID（用户ID 或 群ID）',
+    `start_time` datetime         NOT NULL COMMENT '导出开始时间',
+    `end_time`   datetime         NOT NULL COMMENT '导出结束时间',
+    `status`     tinyint unsigned NOT NULL DEFAULT '1' COMMENT '导出状态[1:等待导出;2:导出中;3:导出完成;4:导出失败;]',
+    `drive`      tinyint unsigned NOT NULL DEFAULT '1' COMMENT '文件驱动[1:local;2:minio;3:s3;4:webdav;]',
+    `path`       varchar(255)     NOT NULL DEFAULT '' COMMENT '压缩包地址（私有桶相对地址）',
+    `size`       bigint unsigned  NOT NULL DEFAULT '0' COMMENT '压缩包大小',
+    `msg_num`    int unsigned     NOT NULL DEFAULT '0' COMMENT '导出消息数',
+    `reason`     varchar(255)     NOT NULL DEFAULT '' COMMENT '失败原因',
+    `created_at` datetime         NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at` datetime         NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    KEY `idx_user_id` (`user_id`) USING BTREE,
+    KEY `idx_created_at` (`created_at`) USING BTREE
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_general_ci COMMENT ='聊天记录导出任务表';;
+
+CREATE TABLE IF NOT EXISTS `users_login_log`
+(
+    `id`         int unsigned NOT NULL AUTO_INCREMENT COMMENT '日志ID',
+    `user_id`    int unsigned NOT NULL COMMENT '用户ID',
+    `ip`         varchar(64)  NOT NULL DEFAULT '' COMMENT '登录IP',
+    `address`    varchar(128) NOT NULL DEFAULT '' COMMENT '登录地址',
+    `platform`   varchar(32)  NOT NULL DEFAULT '' COMMENT '登录平台',
+    `agent`      varchar(500) NOT NULL DEFAULT '' COMMENT '设备信息',
+    `created_at` datetime     NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '登录时间',
+    PRIMARY KEY (`id`),
+    KEY `idx_user_id` (`user_id`) USING BTREE,
+    KEY `idx_created_at` (`created_at`) USING BTREE
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_general_ci COMMENT ='用户登录日志表';;
+
+CREATE TABLE IF NOT EXISTS `admin_audit_log`
+(
+    `id`          int unsigned NOT NULL AUTO_INCREMENT COMMENT '日志ID',
+    `admin_id`    int unsigned NOT NULL COMMENT '管理员ID',
+    `action`      varchar(64)  NOT NULL DEFAULT '' COMMENT '操作类型',
+    `target_type` varchar(32)  NOT NULL DEFAULT '' COMMENT '操作对象类型[user:用户;group:群组;message:消息;]',
+    `target_id`   varchar(64)  NOT NULL DEFAULT '' COMMENT '操作对象ID',
+    `detail`      json                  DEFAULT NULL COMMENT '操作详情',
+    `ip`          varchar(64)  NOT NULL DEFAULT '' COMMENT '操作IP',
+    `created_at`  datetime     NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '操作时间',
+    PRIMARY KEY (`id`),
+    KEY `idx_admin_id` (`admin_id`) USING BTREE,
+    KEY `idx_target` (`target_type`, `target_id`) USING BTREE,
+    KEY `idx_created_at` (`created_at`) USING BTREE
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_general_ci COMMENT ='管理员操作审计日志表';;
+
+CREATE TABLE IF NOT EXISTS `admin_role`
+(
+    `id`          int unsigned NOT NULL AUTO_INCREMENT COMMENT '角色ID',
+    `name`        varchar(32)  NOT NULL COMMENT '角色名称',
+    `code`        varchar(32)  NOT NULL COMMENT '角色标识',
+    `permissions` json         NOT NULL COMMENT '权限列表',
+    `remark`      varchar(255) NOT NULL DEFAULT '' COMMENT '角色备注',
+    `created_at`  datetime     NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at`  datetime     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_code` (`code`) USING BTREE
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_general_ci COMMENT ='管理员角色表';;
+
+INSERT IGNORE INTO `admin_role` (`id`, `name`, `code`, `permissions`, `remark`)
+VALUES (1, '超级管理员', 'superadmin', '["*"]', '拥有全部权限'),
+       (2, '内容审核员', 'moderator', '["user.view","user.manage","group.view","group.manage","message.view","message.manage"]', '用户及群组内容管理'),
+       (3, '只读客服', 'viewer', '["user.view","group.view","message.view"]', '仅可查看数据');;
+
+CREATE TABLE IF NOT EXISTS `file_object`
+(
+    `id`         int unsigned     NOT NULL AUTO_INCREMENT COMMENT '文件对象ID',
+    `hash`       char(64)         NOT NULL COMMENT '文件SHA-256',
+    `drive`      tinyint unsigned NOT NULL DEFAULT '1' COMMENT '驱动类型[1:local;2:minio;3:s3;4:webdav;]',
+    `path`       varchar(255)     NOT NULL COMMENT '文件保存路径(私有桶)',
+    `file_size`  bigint unsigned  NOT NULL DEFAULT '0' COMMENT '文件大小',
+    `ref_count`  int              NOT NULL DEFAULT '0' COMMENT '引用计数',
+    `created_at` datetime         NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at` datetime         NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_hash` (`hash`) USING BTREE,
+    KEY `idx_ref_count` (`ref_count`) USING BTREE
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_general_ci COMMENT ='文件对象表(内容寻址去重)';;
+
+CREATE TABLE IF NOT EXISTS `storage_usage`
+(
+    `id`         int unsigned     NOT NULL AUTO_INCREMENT COMMENT '自增ID',
+    `owner_type` tinyint unsigned NOT NULL COMMENT '归属类型[1:用户;2:群组;]',
+    `owner_id`   int unsigned     NOT NULL COMMENT '用户ID或群组ID',
+    `used_size`  bigint           NOT NULL DEFAULT '0' COMMENT '已使用空间(字节)',
+    `file_count` int              NOT NULL DEFAULT '0' COMMENT '文件数量',
+    `created_at` datetime         NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at` datetime         NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_owner` (`owner_type`, `owner_id`) USING BTREE
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_general_ci COMMENT ='存储空间使用统计表';;
+
+CREATE TABLE IF NOT EXISTS `open_app`
+(
+    `id`         int unsigned     NOT NULL AUTO_INCREMENT COMMENT '应用ID',
+    `app_name`   varchar(64)      NOT NULL COMMENT '应用名称',
+    `app_key`    varchar(32)      NOT NULL COMMENT '应用标识',
+    `app_secret` varchar(64)      NOT NULL COMMENT '应用密钥',
+    `robot_id`   int unsigned     NOT NULL COMMENT '绑定机器人ID',
+    `scopes`     json             NOT NULL COMMENT '权限范围',
+    `status`     tinyint unsigned NOT NULL DEFAULT '1' COMMENT '状态[1:正常;2:已禁用;]',
+    `remark`     varchar(255)     NOT NULL DEFAULT '' COMMENT '备注',
+    `created_at` datetime         NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at` datetime         NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_app_key` (`app_key`) USING BTREE,
+    KEY `idx_robot_id` (`robot_id`) USING BTREE
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_general_ci COMMENT ='开放平台应用表';;
+
+CREATE TABLE IF NOT EXISTS `two_factor`
+(
+    `id`             int unsigned     NOT NULL AUTO_INCREMENT COMMENT '自增ID',
+    `owner_type`     tinyint unsigned NOT NULL COMMENT '归属类型[1:用户;2:管理员;]',
+    `owner_id`       int unsigned     NOT NULL COMMENT '用户ID或管理员ID',
+    `secret`         varchar(64)      NOT NULL COMMENT 'TOTP 密钥',
+    `recovery_codes` varchar(1024)    NOT NULL DEFAULT '' COMMENT '恢复码摘要(JSON 数组)',
+    `last_step`      bigint           NOT NULL DEFAULT '0' COMMENT '最后一次使用的时间步',
+    `is_enabled`     tinyint unsigned NOT NULL DEFAULT '2' COMMENT '是否已启用[1:是;2:否;]',
+    `enabled_at`     datetime                  DEFAULT NULL COMMENT '启用时间',
+    `created_at`     datetime         NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at`     datetime         NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_owner` (`owner_type`, `owner_id`) USING BTREE
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_general_ci COMMENT ='两步验证表';;
+
+
+CREATE TABLE IF NOT EXISTS `user_identity`
+(
+    `id`            int unsigned NOT NULL AUTO_INCREMENT COMMENT '自增ID',
+    `user_id`       int unsigned NOT NULL COMMENT '用户ID',
+    `provider`      varchar(32)  NOT NULL COMMENT '身份提供方标识',
+    `subject`       varchar(255) NOT NULL COMMENT '身份提供方中的用户唯一标识(sub)',
+    `email`         varchar(255) NOT NULL DEFAULT '' COMMENT '身份提供方中的邮箱',
+    `last_login_at` datetime     NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '最后登录时间',
+    `created_at`    datetime     NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at`    datetime     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_provider_subject` (`provider`, `subject`) USING BTREE,
+    KEY `idx_user_id` (`user_id`) USING BTREE
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_general_ci COMMENT ='用户第三方身份绑定表';;
+
+CREATE TABLE IF NOT EXISTS `email_outbox`
+(
+    `id`         int unsigned     NOT NULL AUTO_INCREMENT COMMENT '邮件ID',
+    `user_id`    int unsigned     NOT NULL DEFAULT '0' COMMENT '收件用户ID，非用户邮件为 0',
+    `email`      varchar(255)     NOT NULL COMMENT '收件邮箱',
+    `template`   varchar(32)      NOT NULL DEFAULT '' COMMENT '邮件模板',
+    `subject`    varchar(255)     NOT NULL DEFAULT '' COMMENT '邮件主题',
+    `body`       mediumtext       NOT NULL COMMENT '邮件正文',
+    `status`     tinyint unsigned NOT NULL DEFAULT '1' COMMENT '发送状态[1:等待发送;2:发送成功;3:发送失败;]',
+    `attempts`   int unsigned     NOT NULL DEFAULT '0' COMMENT '已尝试发送次数',
+    `reason`     varchar(500)     NOT NULL DEFAULT '' COMMENT '最近一次发送失败的原因',
+    `sent_at`    datetime                  DEFAULT NULL COMMENT '发送成功时间',
+    `created_at` datetime         NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at` datetime         NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    KEY `idx_status_updated_at` (`status`, `updated_at`) USING BTREE,
+    KEY `idx_user_id` (`user_id`) USING BTREE
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_general_ci COMMENT ='邮件发件箱表';;
+
+CREATE TABLE IF NOT EXISTS `users_setting`
+(
+    `user_id`          int unsigned     NOT NULL COMMENT '用户ID',
+    `digest_enabled`   tinyint unsigned NOT NULL DEFAULT '1' COMMENT '离线消息邮件提醒[1:开启;2:关闭;]',
+    `digest_frequency` tinyint unsigned NOT NULL DEFAULT '1' COMMENT '离线消息邮件提醒频率[1:每天;2:每周;]',
+    `digest_at`        datetime                  DEFAULT NULL COMMENT '最近一次发送离线消息邮件的时间',
+    `created_at`       datetime         NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at`       datetime         NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`user_id`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_general_ci COMMENT ='用户设置表';;
+
+CREATE TABLE IF NOT EXISTS `user_device`
+(
+    `id`         int unsigned NOT NULL AUTO_INCREMENT COMMENT '设备ID',
+    `user_id`    int unsigned NOT NULL COMMENT '用户ID',
+    `session_id` varchar(64)  NOT NULL DEFAULT '' COMMENT '登录会话ID',
+    `provider`   varchar(16)  NOT NULL COMMENT '推送服务[apns;fcm;]',
+    `platform`   varchar(16)  NOT NULL DEFAULT '' COMMENT '设备平台[ios;android;]',
+    `token`      varchar(255) NOT NULL COMMENT '设备推送令牌',
+    `created_at` datetime     NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at` datetime     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_provider_token` (`provider`, `token`) USING BTREE,
+    KEY `idx_user_id` (`user_id`) USING BTREE,
+    KEY `idx_session_id` (`session_id`) USING BTREE
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_general_ci COMMENT ='用户推送设备表';;
+
+CREATE TABLE IF NOT EXISTS `talk_mention`
+(
+    `id`         int unsigned     NOT NULL AUTO_INCREMENT COMMENT '记录ID',
+    `user_id`    int unsigned     NOT NULL COMMENT '被@的用户ID',
+    `group_id`   int unsigned     NOT NULL COMMENT '群组ID',
+    `msg_id`     varchar(64)      NOT NULL COMMENT '消息ID',
+    `from_id`    int unsigned     NOT NULL COMMENT '消息发送者ID',
+    `is_all`     tinyint unsigned NOT NULL DEFAULT '2' COMMENT '是否@所有人[1:是;2:否;]',
+    `is_read`    tinyint unsigned NOT NULL DEFAULT '2' COMMENT '是否已读[1:是;2:否;]',
+    `created_at` datetime         NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at` datetime         NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_user_id_msg_id` (`user_id`, `msg_id`) USING BTREE,
+    KEY `idx_user_id_is_read` (`user_id`, `is_read`) USING BTREE
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_general_ci COMMENT ='@我的消息表';;
@@ -0,0 +1,332 @@
+package mission
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+	"github.com/urfave/cli/v2"
+	"go-chat/config"
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/filesystem"
+	"go-chat/internal/provider"
+	"gorm.io/gorm"
+)
+
+const (
+	storageMigrateBatchSize = 200
+	storageMigratePartSize  = 32 << 20 // 大文件按 32MB 分片写入目标存储
+)
+
+type StorageProvider struct {
+	Config *config.Config
+	DB     *gorm.DB
+}
+
+// StorageMigrate 将数据库中引用的所有文件从源存储驱动复制到目标存储驱动
+func StorageMigrate(ctx *cli.Context, app *StorageProvider) error {
+	from, to := ctx.String("from"), ctx.String("to")
+	if from == to {
+		return errors.New("源存储驱动与目标存储驱动不能相同")
+	}
+
+	src, err := provider.NewFilesystemDriver(app.Config, from)
+	if err != nil {
+		return err
+	}
+
+	dst, err := provider.NewFilesystemDriver(app.Config, to)
+	if err != nil {
+		return err
+	}
+
+	m := &storageMigrator{
+		db:         app.DB,
+		src:        src,
+		dst:        dst,
+		srcDrive:   entity.FileDriveMode(from),
+		dstDrive:   entity.FileDriveMode(to),
+		dryRun:     ctx.Bool("dry-run"),
+		rewriteUrl: ctx.Bool("rewrite-url"),
+		copied:     make(map[string]struct{}),
+	}
+
+	// 通过占位对象名推导源存储公开文件的地址前缀，用于从消息中的文件地址还原对象名
+	m.srcPrefix = strings.TrimSuffix(src.PublicUrl(src.BucketPublicName(), "__object__"), "__object__")
+
+	fmt.Printf("文件迁移中 %s => %s (dry-run: %t, rewrite-url: %t)\n", from, to, m.dryRun, m.rewriteUrl)
+
+	steps := []struct {
+		name string
+		fn   func() error
+	}{
+		{"file_upload", func() error { return m.migrateTable("file_upload", "type = 1") }},
+		{"file_object", func() error { return m.migrateTable("file_object", "") }},
+		{"article_annex", func() error { return m.migrateTable("article_annex", "") }},
+		{"talk_export", func() error { return m.migrateTable("talk_export", "path <> ''") }},
+		{"talk_user_message", func() error { return m.migrateMessage("talk_user_message") }},
+		{"talk_group_message", func() error { return m.migrateMessage("talk_group_message") }},
+	}
+
+	for _, step := range steps {
+		fmt.Printf("迁移 %s ...\n", step.name)
+		if err := step.fn(); err != nil {
+			return fmt.Errorf("迁移 %s 失败: %w", step.name, err)
+		}
+	}
+
+	fmt.Printf("文件迁移完成 复制: %d 已存在: %d 源文件缺失: %d 失败: %d\n", m.numCopied, m.numExists, m.numMissing, m.numFailed)
+
+	if m.numFailed > 0 {
+		return fmt.Errorf("%d 个文件迁移失败，可重新执行命令继续迁移", m.numFailed)
+	}
+
+	return nil
+}
+
+type storageMigrator struct {
+	db         *gorm.DB
+	src        filesystem.IFilesystem
+	dst        filesystem.IFilesystem
+	srcDrive   int
+	dstDrive   int
+	srcPrefix  string
+	dryRun     bool
+	rewriteUrl bool
+	copied     map[string]struct{} // 本次已处理的文件，避免重复复制
+
+	numCopied  int
+	numExists  int
+	numMissing int
+	numFailed  int
+}
+
+// migrateTable 迁移记录了 drive、path 字段的数据表(私有桶文件)，迁移成功后更新记录的驱动类型
+func (m *storageMigrator) migrateTable(table string, where string) error {
+	var lastId int64
+
+	for {
+		items := make([]struct {
+			Id   int64
+			Path string
+		}, 0)
+
+		query := m.db.Table(table).Select("id, path").Where("id > ? and drive = ?", lastId, m.srcDrive)
+		if where != "" {
+			query = query.Where(where)
+		}
+
+		if err := query.Order("id asc").Limit(storageMigrateBatchSize).Scan(&items).Error; err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			if !m.copy(m.src.BucketPrivateName(), m.dst.BucketPrivateName(), item.Path) || m.dryRun {
+				continue
+			}
+
+			if err := m.db.Table(table).Where("id = ?", item.Id).Update("drive", m.dstDrive).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(items) < storageMigrateBatchSize {
+			return nil
+		}
+
+		lastId = items[len(items)-1].Id
+	}
+}
+
+// migrateMessage 迁移消息扩展字段中引用的文件
+func (m *storageMigrator) migrateMessage(table string) error {
+	var lastId int64
+
+	msgTypes := []int{
+		entity.ChatMsgTypeImage,
+		entity.ChatMsgTypeAudio,
+		entity.ChatMsgTypeVideo,
+		entity.ChatMsgTypeFile,
+		entity.ChatMsgTypeMixed,
+	}
+
+	for {
+		items := make([]struct {
+			Id      int64
+			MsgType int
+			Extra   string
+		}, 0)
+
+		err := m.db.Table(table).Select("id, msg_type, extra").
+			Where("id > ? and msg_type in ?", lastId, msgTypes).
+			Order("id asc").Limit(storageMigrateBatchSize).Scan(&items).Error
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			extra, changed := m.migrateExtra(item.MsgType, item.Extra)
+			if !changed {
+				continue
+			}
+
+			if err := m.db.Table(table).Where("id = ?", item.Id).Update("extra", extra).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(items) < storageMigrateBatchSize {
+			return nil
+		}
+
+		lastId = items[len(items)-1].Id
+	}
+}
+
+// migrateExtra 复制消息扩展字段中的文件，开启 rewrite-url 时返回替换为目标存储地址后的扩展字段
+func (m *storageMigrator) migrateExtra(msgType int, extra string) (string, bool) {
+	var fields []string
+
+	switch msgType {
+	case entity.ChatMsgTypeFile:
+		m.copy(m.src.BucketPrivateName(), m.dst.BucketPrivateName(), gjson.Get(extra, "path").String())
+		return extra, false
+	case entity.ChatMsgTypeImage:
+		fields = []string{"url", "thumb", "medium"}
+	case entity.ChatMsgTypeAudio:
+		fields = []string{"url"}
+	case entity.ChatMsgTypeVideo:
+		fields = []string{"url", "cover"}
+	case entity.ChatMsgTypeMixed:
+		for i, item := range gjson.Get(extra, "items").Array() {
+			if item.Get("type").Int() == entity.ChatMsgTypeImage {
+				fields = append(fields, fmt.Sprintf("items.%d.content", i))
+			}
+		}
+	}
+
+	changed := false
+	for _, field := range fields {
+		object, ok := m.publicObject(gjson.Get(extra, field).String())
+		if !ok {
+			continue
+		}
+
+		if !m.copy(m.src.BucketPublicName(), m.dst.BucketPublicName(), object) || !m.rewriteUrl || m.dryRun {
+			continue
+		}
+
+		if value, err := sjson.Set(extra, field, m.dst.PublicUrl(m.dst.BucketPublicName(), object)); err == nil {
+			extra, changed = value, true
+		}
+	}
+
+	return extra, changed
+}
+
+// publicObject 从源存储的公开文件地址中解析对象名，非源存储的地址返回 false
+func (m *storageMigrator) publicObject(uri string) (string, bool) {
+	if uri == "" || !strings.HasPrefix(uri, m.srcPrefix) {
+		return "", false
+	}
+
+	object, _, _ := strings.Cut(strings.TrimPrefix(uri, m.srcPrefix), "?")
+	return object, object != ""
+}
+
+// copy 复制单个文件，目标存储已存在同等大小的文件时跳过
+func (m *storageMigrator) copy(srcBucket, dstBucket, object string) bool {
+	if object == "" {
+		return false
+	}
+
+	key := dstBucket + "/" + object
+	if _, ok := m.copied[key]; ok {
+		return true
+	}
+
+	info, err := m.src.Stat(srcBucket, object)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			m.numMissing++
+		} else {
+			m.numFailed++
+		}
+
+		fmt.Printf("读取源文件失败 %s/%s Err: %s\n", srcBucket, object, err)
+		return false
+	}
+
+	if stat, err := m.dst.Stat(dstBucket, object); err == nil && stat.Size == info.Size {
+		m.copied[key] = struct{}{}
+		m.numExists++
+		return true
+	}
+
+	if !m.dryRun {
+		if err := m.transfer(srcBucket, dstBucket, object, info.Size); err != nil {
+			m.numFailed++
+			fmt.Printf("复制文件失败 %s/%s Err: %s\n", srcBucket, object, err)
+			return false
+		}
+	}
+
+	m.copied[key] = struct{}{}
+	m.numCopied++
+	return true
+}
+
+func (m *storageMigrator) transfer(srcBucket, dstBucket, object string, size int64) error {
+	reader, err := m.src.ReadObject(srcBucket, object)
+	if err != nil {
+		return err
+	}
+
+	defer reader.Close()
+
+	if size <= storageMigratePartSize {
+		stream, err := io.ReadAll(reader)
+		if err != nil {
+			return err
+		}
+
+		return m.dst.Write(dstBucket, object, stream)
+	}
+
+	uploadId, err := m.dst.InitiateMultipartUpload(dstBucket, object)
+	if err != nil {
+		return err
+	}
+
+	parts := make([]filesystem.ObjectPart, 0)
+	buf := make([]byte, storageMigratePartSize)
+
+	for index := 1; ; index++ {
+		n, err := io.ReadFull(reader, buf)
+		if n > 0 {
+			part, err := m.dst.PutObjectPart(dstBucket, object, uploadId, index, bytes.NewReader(buf[:n]), int64(n))
+			if err != nil {
+				_ = m.dst.AbortMultipartUpload(dstBucket, object, uploadId)
+				return err
+			}
+
+			parts = append(parts, part)
+		}
+
+		if err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+
+		if err != nil {
+			_ = m.dst.AbortMultipartUpload(dstBucket, object, uploadId)
+			return err
+		}
+	}
+
+	return m.dst.CompleteMultipartUpload(dstBucket, object, uploadId, parts)
+}
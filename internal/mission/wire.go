@@ -21,6 +21,10 @@ var MigrateProviderSet = wire.NewSet(
 	wire.Struct(new(MigrateProvider), "*"),
 )
 
+var StorageProviderSet = wire.NewSet(
+	wire.Struct(new(StorageProvider), "*"),
+)
+
 var TempProviderSet = wire.NewSet(
 	wire.Struct(new(temp.TestCommand), "*"),
 	wire.Struct(new(TempProvider), "*"),
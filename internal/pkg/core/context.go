@@ -136,6 +136,16 @@ func (c *Context) JwtSession() *middleware.JSession {
 	return data.(*middleware.JSession)
 }
 
+// OpenSession 返回开放平台应用的OpenSession
+func (c *Context) OpenSession() *middleware.OpenSession {
+	data, isOk := c.Context.Get(middleware.OpenSessionConst)
+	if !isOk {
+		return nil
+	}
+
+	return data.(*middleware.OpenSession)
+}
+
 // IsGuest 是否是游客(未登录状态)
 func (c *Context) IsGuest() bool {
 	return c.UserId() == 0
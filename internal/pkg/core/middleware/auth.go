@@ -25,15 +25,16 @@ type IStorage interface {
 type JSession struct {
 	Uid       int    `json:"uid"`
 	Token     string `json:"token"`
+	SessionId string `json:"session_id"`
 	ExpiresAt int64  `json:"expires_at"`
 }
 
 // Auth 授权中间件
-func Auth(secret string, guard string, storage IStorage) gin.HandlerFunc {
+func Auth(keys *jwt.KeySet, guard string, storage IStorage) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token := AuthHeaderToken(c)
 
-		claims, err := verify(guard, secret, token)
+		claims, err := verify(keys, guard, token)
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"code": 401, "message": err.Error()})
 			return
@@ -53,6 +54,7 @@ func Auth(secret string, guard string, storage IStorage) gin.HandlerFunc {
 		c.Set(JWTSessionConst, &JSession{
 			Uid:       uid,
 			Token:     token,
+			SessionId: claims.SessionId,
 			ExpiresAt: claims.ExpiresAt.Unix(),
 		})
 
@@ -72,13 +74,13 @@ func AuthHeaderToken(c *gin.Context) string {
 	return token
 }
 
-func verify(guard string, secret string, token string) (*jwt.AuthClaims, error) {
+func verify(keys *jwt.KeySet, guard string, token string) (*jwt.AuthClaims, error) {
 
 	if token == "" {
 		return nil, ErrNoAuthorize
 	}
 
-	claims, err := jwt.ParseToken(token, secret)
+	claims, err := keys.ParseToken(token)
 	if err != nil {
 		return nil, err
 	}
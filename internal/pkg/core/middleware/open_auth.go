@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go-chat/internal/pkg/openapi"
+)
+
+const OpenSessionConst = "__OPEN_SESSION__"
+
+const (
+	openSignTolerance = 5 * time.Minute // 签名时间误差
+	openMaxBodySize   = 32 << 20        // 请求体最大 32MB
+)
+
+type OpenSession struct {
+	AppId  int      `json:"app_id"`
+	AppKey string   `json:"app_key"`
+	UserId int      `json:"user_id"` // 应用绑定的机器人用户ID
+	Scopes []string `json:"scopes"`
+}
+
+// HasScope 判断应用是否拥有指定权限范围
+func (s *OpenSession) HasScope(scope string) bool {
+	return slices.Contains(s.Scopes, scope)
+}
+
+type OpenApp struct {
+	OpenSession
+	AppSecret string
+}
+
+type IOpenAppStorage interface {
+	// GetOpenApp 获取启用状态的开放平台应用，不存在时返回 nil
+	GetOpenApp(ctx context.Context, appKey string) (*OpenApp, error)
+	// UseNonce 记录请求随机串，随机串已使用过返回 false
+	UseNonce(ctx context.Context, appKey string, nonce string, expire time.Duration) bool
+}
+
+// OpenAuth 开放平台签名验证中间件
+func OpenAuth(storage IOpenAppStorage) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		abort := func(status int, message string) {
+			c.AbortWithStatusJSON(status, gin.H{"code": status, "message": message})
+		}
+
+		appKey := c.GetHeader(openapi.HeaderAppKey)
+		timestamp, _ := strconv.ParseInt(c.GetHeader(openapi.HeaderTimestamp), 10, 64)
+		if appKey == "" || timestamp == 0 {
+			abort(http.StatusUnauthorized, openapi.ErrSignatureMissing.Error())
+			return
+		}
+
+		app, err := storage.GetOpenApp(c.Request.Context(), appKey)
+		if err != nil {
+			abort(http.StatusInternalServerError, "系统繁忙，请稍后再试")
+			return
+		}
+
+		if app == nil {
+			abort(http.StatusUnauthorized, "应用不存在或已禁用")
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, openMaxBodySize))
+		if err != nil {
+			abort(http.StatusRequestEntityTooLarge, "请求内容过大")
+			return
+		}
+
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		payload := &openapi.Payload{
+			Method:    c.Request.Method,
+			Path:      c.Request.URL.Path,
+			Query:     c.Request.URL.RawQuery,
+			Timestamp: timestamp,
+			Nonce:     c.GetHeader(openapi.HeaderNonce),
+			Body:      body,
+		}
+
+		if err := openapi.Verify(app.AppSecret, payload, c.GetHeader(openapi.HeaderSignature), openSignTolerance); err != nil {
+			abort(http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		// 防止请求重放
+		if !storage.UseNonce(c.Request.Context(), appKey, payload.Nonce, 2*openSignTolerance) {
+			abort(http.StatusUnauthorized, "请求重复提交")
+			return
+		}
+
+		c.Set(OpenSessionConst, &app.OpenSession)
+
+		c.Next()
+	}
+}
+
+// OpenScope 开放平台权限范围校验中间件[需在 OpenAuth 中间件之后使用]
+func OpenScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, ok := c.Get(OpenSessionConst)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"code": 401, "message": ErrNoAuthorize.Error()})
+			return
+		}
+
+		session, ok := value.(*OpenSession)
+		if !ok || !session.HasScope(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"code": 403, "message": "应用未授权该接口"})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type IPermission interface {
+	// HasPermission 判断是否拥有指定权限
+	HasPermission(ctx context.Context, uid int, permission string) bool
+}
+
+// Permission 权限校验中间件[需在 Auth 中间件之后使用]
+func Permission(checker IPermission, permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, ok := c.Get(JWTSessionConst)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"code": 401, "message": ErrNoAuthorize.Error()})
+			return
+		}
+
+		session, ok := value.(*JSession)
+		if !ok || !checker.HasPermission(c.Request.Context(), session.Uid, permission) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"code": 403, "message": "无权访问资源"})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -6,6 +6,14 @@ import (
 	"gopkg.in/gomail.v2"
 )
 
+// Sender 邮件发送驱动
+type Sender interface {
+	SendMail(email *Option, opt ...OptionFunc) error
+}
+
+var _ Sender = (*Client)(nil)
+
+// Client SMTP 邮件发送
 type Client struct {
 	config *Config
 }
@@ -42,10 +50,14 @@ func (c *Client) do(msg *gomail.Message) error {
 }
 
 func (c *Client) SendMail(email *Option, opt ...OptionFunc) error {
+	return c.do(newMessage(c.config.UserName, c.config.FromName, email, opt...))
+}
+
+func newMessage(from string, fromName string, email *Option, opt ...OptionFunc) *gomail.Message {
 	m := gomail.NewMessage()
 
 	// 这种方式可以添加别名，即“XX官方”
-	m.SetHeader("From", m.FormatAddress(c.config.UserName, c.config.FromName))
+	m.SetHeader("From", m.FormatAddress(from, fromName))
 
 	if len(email.To) > 0 {
 		m.SetHeader("To", email.To...)
@@ -66,5 +78,5 @@ func (c *Client) SendMail(email *Option, opt ...OptionFunc) error {
 		o(m)
 	}
 
-	return c.do(m)
+	return m
 }
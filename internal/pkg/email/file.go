@@ -0,0 +1,80 @@
+package email
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	_ Sender = (*FileSender)(nil)
+	_ Sender = (*LogSender)(nil)
+)
+
+// FileSender 将邮件以 .eml 格式保存到目录中，用于开发及测试环境
+type FileSender struct {
+	dir      string
+	from     string
+	fromName string
+	seq      atomic.Uint64
+}
+
+func NewFileSender(dir string, from string, fromName string) *FileSender {
+	return &FileSender{dir: dir, from: from, fromName: fromName}
+}
+
+func (f *FileSender) SendMail(email *Option, opt ...OptionFunc) error {
+	if err := os.MkdirAll(f.dir, 0755); err != nil {
+		return err
+	}
+
+	// 文件名按发送时间排序，同一时刻发送的邮件以序号区分
+	name := fmt.Sprintf("%s_%06d.eml", time.Now().Format("20060102150405.000"), f.seq.Add(1))
+
+	file, err := os.Create(filepath.Join(f.dir, name))
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	_, err = newMessage(f.from, f.fromName, email, opt...).WriteTo(file)
+	return err
+}
+
+// LogSender 将邮件内容输出到日志，用于开发及测试环境
+type LogSender struct {
+	mu       sync.Mutex
+	writer   io.Writer
+	from     string
+	fromName string
+}
+
+// NewLogSender writer 为空时输出到标准输出
+func NewLogSender(writer io.Writer, from string, fromName string) *LogSender {
+	if writer == nil {
+		writer = os.Stdout
+	}
+
+	return &LogSender{writer: writer, from: from, fromName: fromName}
+}
+
+func (l *LogSender) SendMail(email *Option, opt ...OptionFunc) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, err := fmt.Fprintf(l.writer, "---------- email %s ----------\n", time.Now().Format(time.DateTime)); err != nil {
+		return err
+	}
+
+	if _, err := newMessage(l.from, l.fromName, email, opt...).WriteTo(l.writer); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(l.writer, "\n")
+	return err
+}
@@ -0,0 +1,51 @@
+package email
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/gomail.v2"
+)
+
+func TestFileSender(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	sender := NewFileSender(dir, "noreply@example.com", "LumenIM")
+
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, sender.SendMail(&Option{
+			To:      []string{"alice@example.com"},
+			Subject: "Verify",
+			Body:    "<p>code 123456</p>",
+		}))
+	}
+
+	files, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 2)
+	assert.True(t, strings.HasSuffix(files[0].Name(), ".eml"))
+
+	content, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "To: alice@example.com")
+	assert.Contains(t, string(content), "Subject: Verify")
+	assert.Contains(t, string(content), "From: \"LumenIM\" <noreply@example.com>")
+	assert.Contains(t, string(content), "code 123456")
+}
+
+func TestLogSender(t *testing.T) {
+	var buf bytes.Buffer
+	sender := NewLogSender(&buf, "noreply@example.com", "LumenIM")
+
+	err := sender.SendMail(&Option{To: []string{"bob@example.com"}, Subject: "Hello", Body: "hi"}, func(msg *gomail.Message) {
+		msg.SetHeader("Cc", "carol@example.com")
+	})
+
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "To: bob@example.com")
+	assert.Contains(t, buf.String(), "Cc: carol@example.com")
+	assert.Contains(t, buf.String(), "Subject: Hello")
+}
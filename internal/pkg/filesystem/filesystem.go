@@ -6,8 +6,10 @@ import (
 )
 
 const (
-	MinioDriver = "minio"
-	LocalDriver = "local"
+	MinioDriver  = "minio"
+	LocalDriver  = "local"
+	S3Driver     = "s3"
+	WebDAVDriver = "webdav"
 )
 
 type IFilesystem interface {
@@ -38,6 +40,9 @@ type IFilesystem interface {
 	// GetObject 读取文件内容
 	GetObject(bucketName string, objectName string) ([]byte, error)
 
+	// ReadObject 读取文件流(调用方负责关闭)
+	ReadObject(bucketName string, objectName string) (io.ReadCloser, error)
+
 	// PublicUrl 获取公开文件的访问地址
 	PublicUrl(bucketName, objectName string) string
 
@@ -80,6 +85,8 @@ type LocalSystemConfig struct {
 	BucketPublic  string `json:"bucket_public" yaml:"bucket_public"`
 	BucketPrivate string `json:"bucket_private" yaml:"bucket_private"`
 	Endpoint      string `json:"endpoint" yaml:"endpoint"`
+	SignSecret    string `json:"sign_secret" yaml:"sign_secret"` // 私有文件签名密钥，为空时不启用签名下载地址
+	SignUrl       string `json:"sign_url" yaml:"sign_url"`       // 签名下载地址前缀，例如 https://im-api.xxx.com/api/v1/files
 }
 
 // MinioSystemConfig 私有化 Minio 配置信息
@@ -91,3 +98,27 @@ type MinioSystemConfig struct {
 	BucketPrivate string `json:"bucket_private" yaml:"bucket_private"`
 	Endpoint      string `json:"endpoint" yaml:"endpoint"`
 }
+
+// S3SystemConfig 通用 S3 协议存储配置信息
+type S3SystemConfig struct {
+	SSL           bool   `json:"ssl" yaml:"ssl"`
+	AccessKey     string `json:"access_key" yaml:"access_key"`
+	SecretKey     string `json:"secret_key" yaml:"secret_key"`
+	Region        string `json:"region" yaml:"region"`
+	Endpoint      string `json:"endpoint" yaml:"endpoint"`
+	PathStyle     bool   `json:"path_style" yaml:"path_style"`       // true: endpoint/bucket/object，false: bucket.endpoint/object
+	PublicDomain  string `json:"public_domain" yaml:"public_domain"` // 公开桶自定义访问域名(可选)，例如 https://cdn.xxx.com
+	BucketPublic  string `json:"bucket_public" yaml:"bucket_public"`
+	BucketPrivate string `json:"bucket_private" yaml:"bucket_private"`
+}
+
+// WebDAVSystemConfig WebDAV 存储配置信息
+type WebDAVSystemConfig struct {
+	Endpoint      string `json:"endpoint" yaml:"endpoint"` // WebDAV 根地址，例如 https://dav.xxx.com/remote.php/dav/files/im
+	Username      string `json:"username" yaml:"username"`
+	Password      string `json:"password" yaml:"password"`
+	PublicDomain  string `json:"public_domain" yaml:"public_domain"` // 文件对外访问地址，例如 https://im-cdn.xxx.com
+	BucketPublic  string `json:"bucket_public" yaml:"bucket_public"`
+	BucketPrivate string `json:"bucket_private" yaml:"bucket_private"`
+	Timeout       int    `json:"timeout" yaml:"timeout"` // 请求超时时间(秒)，默认 60
+}
@@ -1,11 +1,19 @@
 package filesystem
 
 import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,6 +22,11 @@ import (
 
 var _ IFilesystem = (*LocalFilesystem)(nil)
 
+var (
+	ErrSignatureInvalid = errors.New("signature invalid")
+	ErrSignatureExpired = errors.New("signature expired")
+)
+
 type LocalFilesystem struct {
 	config LocalSystemConfig
 }
@@ -59,7 +72,7 @@ func (l LocalFilesystem) Write(bucketName string, objectName string, stream []by
 		}
 	}
 
-	f, err := os.OpenFile(filePath, os.O_CREATE|os.O_RDWR, 0755)
+	f, err := os.OpenFile(filePath, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0755)
 	if err != nil {
 		return err
 	}
@@ -137,6 +150,10 @@ func (l LocalFilesystem) GetObject(bucketName string, objectName string) ([]byte
 	return os.ReadFile(l.Path(bucketName, objectName))
 }
 
+func (l LocalFilesystem) ReadObject(bucketName string, objectName string) (io.ReadCloser, error) {
+	return os.Open(l.Path(bucketName, objectName))
+}
+
 func (l LocalFilesystem) PublicUrl(bucketName, objectName string) string {
 	domain := fmt.Sprintf("http://%s", l.config.Endpoint)
 	if l.config.SSL {
@@ -151,8 +168,63 @@ func (l LocalFilesystem) PublicUrl(bucketName, objectName string) string {
 	)
 }
 
-func (l LocalFilesystem) PrivateUrl(bucketName, objectName string, _ string, _ time.Duration) string {
-	return l.PublicUrl(bucketName, objectName)
+// PrivateUrl 获取私有文件的访问地址，配置签名密钥后返回带有效期的签名地址
+func (l LocalFilesystem) PrivateUrl(bucketName, objectName string, filename string, expire time.Duration) string {
+	if !l.SignEnabled() {
+		return l.PublicUrl(bucketName, objectName)
+	}
+
+	objectName = strings.Trim(objectName, "/")
+	expires := time.Now().Add(expire).Unix()
+
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	if filename != "" {
+		query.Set("filename", filename)
+	}
+
+	query.Set("signature", l.signature(bucketName, objectName, filename, expires))
+
+	uri := url.URL{Path: fmt.Sprintf("/%s/%s", bucketName, objectName)}
+
+	return fmt.Sprintf("%s%s?%s", strings.TrimRight(l.config.SignUrl, "/"), uri.EscapedPath(), query.Encode())
+}
+
+// SignEnabled 是否启用私有文件签名地址
+func (l LocalFilesystem) SignEnabled() bool {
+	return l.config.SignSecret != "" && l.config.SignUrl != ""
+}
+
+// VerifySignature 校验签名地址参数
+func (l LocalFilesystem) VerifySignature(bucketName, objectName string, filename string, expires int64, signature string) error {
+	if !l.SignEnabled() {
+		return ErrSignatureInvalid
+	}
+
+	if bucketName != l.BucketPublicName() && bucketName != l.BucketPrivateName() {
+		return ErrSignatureInvalid
+	}
+
+	objectName = strings.Trim(objectName, "/")
+	if objectName == "" || slices.Contains(strings.Split(objectName, "/"), "..") {
+		return ErrSignatureInvalid
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(l.signature(bucketName, objectName, filename, expires))) {
+		return ErrSignatureInvalid
+	}
+
+	if time.Now().Unix() > expires {
+		return ErrSignatureExpired
+	}
+
+	return nil
+}
+
+func (l LocalFilesystem) signature(bucketName, objectName string, filename string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(l.config.SignSecret))
+	mac.Write([]byte(fmt.Sprintf("%s\n%s\n%s\n%d", bucketName, objectName, filename, expires)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
 }
 
 func (l LocalFilesystem) InitiateMultipartUpload(_, _ string) (string, error) {
@@ -160,41 +232,65 @@ func (l LocalFilesystem) InitiateMultipartUpload(_, _ string) (string, error) {
 }
 
 func (l LocalFilesystem) PutObjectPart(bucketName, _ string, uploadID string, index int, data io.Reader, _ int64) (ObjectPart, error) {
-	stream, _ := io.ReadAll(data)
+	stream, err := io.ReadAll(data)
+	if err != nil {
+		return ObjectPart{}, err
+	}
 
-	objectName := fmt.Sprintf("multipart/%s/%d_%s.tmp", uploadID, index, uploadID)
+	objectName := l.partObjectName(uploadID, index)
 	if err := l.Write(bucketName, objectName, stream); err != nil {
 		return ObjectPart{}, err
 	}
 
 	return ObjectPart{
-		ETag:           "",
+		ETag:           fmt.Sprintf("%x", md5.Sum(stream)),
 		PartNumber:     index,
 		PartObjectName: objectName,
 	}, nil
 }
 
-func (l LocalFilesystem) CompleteMultipartUpload(bucketName, objectName, _ string, parts []ObjectPart) error {
+// CompleteMultipartUpload 按分片序号合并文件，合并完成后删除分片临时文件
+func (l LocalFilesystem) CompleteMultipartUpload(bucketName, objectName, uploadID string, parts []ObjectPart) error {
+	parts = slices.Clone(parts)
+	slices.SortFunc(parts, func(a, b ObjectPart) int {
+		return a.PartNumber - b.PartNumber
+	})
+
+	// 先写入临时文件，避免合并中断时留下不完整的目标文件
+	tmpName := objectName + ".merging"
+	_ = os.Remove(l.Path(bucketName, tmpName))
+
 	for _, part := range parts {
-		stream, err := l.GetObject(bucketName, part.PartObjectName)
-		if err != nil {
+		if err := l.appendCopy(bucketName, tmpName, part.PartObjectName); err != nil {
+			_ = os.Remove(l.Path(bucketName, tmpName))
 			return err
 		}
+	}
 
-		if err := l.appendWrite(bucketName, objectName, stream); err != nil {
-			return err
-		}
+	if err := os.Rename(l.Path(bucketName, tmpName), l.Path(bucketName, objectName)); err != nil {
+		return err
 	}
 
-	return nil
+	return os.RemoveAll(l.Path(bucketName, l.partDir(uploadID)))
 }
 
+// AbortMultipartUpload 取消分片上传，删除已上传的分片临时文件
 func (l LocalFilesystem) AbortMultipartUpload(bucketName, objectName, uploadID string) error {
-	// TODO implement me
-	panic("implement me")
+	if err := os.Remove(l.Path(bucketName, objectName+".merging")); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return os.RemoveAll(l.Path(bucketName, l.partDir(uploadID)))
 }
 
-func (l LocalFilesystem) appendWrite(bucketName, objectName string, stream []byte) error {
+func (l LocalFilesystem) appendCopy(bucketName, objectName, srcObjectName string) error {
+	src, err := os.Open(l.Path(bucketName, srcObjectName))
+	if err != nil {
+		return err
+	}
+
+	defer src.Close()
+
 	filePath := l.Path(bucketName, objectName)
 
 	dir := path.Dir(filePath)
@@ -204,17 +300,25 @@ func (l LocalFilesystem) appendWrite(bucketName, objectName string, stream []byt
 		}
 	}
 
-	f, err := os.OpenFile(filePath, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0766)
+	f, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0766)
 	if err != nil {
 		return err
 	}
 
 	defer f.Close()
 
-	_, err = f.Write(stream)
+	_, err = io.Copy(f, src)
 	return err
 }
 
+func (l LocalFilesystem) partDir(uploadID string) string {
+	return fmt.Sprintf("multipart/%s", uploadID)
+}
+
+func (l LocalFilesystem) partObjectName(uploadID string, index int) string {
+	return fmt.Sprintf("%s/%d_%s.tmp", l.partDir(uploadID), index, uploadID)
+}
+
 // Path 获取文件地址绝对路径
 func (l LocalFilesystem) Path(bucketName string, objectName string) string {
 	return fmt.Sprintf(
@@ -0,0 +1,198 @@
+package filesystem
+
+import (
+	"bytes"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func newTestLocalFilesystem(t *testing.T) *LocalFilesystem {
+	return NewLocalFilesystem(LocalSystemConfig{
+		Root:          t.TempDir(),
+		BucketPublic:  "im-static",
+		BucketPrivate: "im-private",
+		Endpoint:      "127.0.0.1:9000",
+	})
+}
+
+func TestLocalFilesystem_MultipartOutOfOrder(t *testing.T) {
+	client := newTestLocalFilesystem(t)
+
+	uploadId, err := client.InitiateMultipartUpload("im-private", "multipart/test.tmp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chunks := [][]byte{[]byte("hello "), []byte("multipart "), []byte("world")}
+
+	parts := make([]ObjectPart, 0)
+	for _, index := range []int{3, 1, 2} {
+		part, err := client.PutObjectPart("im-private", "multipart/test.tmp", uploadId, index, bytes.NewReader(chunks[index-1]), 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		parts = append(parts, part)
+	}
+
+	// 重复上传分片应覆盖原分片
+	part, err := client.PutObjectPart("im-private", "multipart/test.tmp", uploadId, 2, bytes.NewReader([]byte("local ")), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parts[2] = part
+
+	if err := client.CompleteMultipartUpload("im-private", "multipart/test.tmp", uploadId, parts); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := client.GetObject("im-private", "multipart/test.tmp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(content) != "hello local world" {
+		t.Fatalf("unexpected content: %q", content)
+	}
+
+	if _, err := os.Stat(client.Path("im-private", client.partDir(uploadId))); !os.IsNotExist(err) {
+		t.Fatalf("multipart parts should be removed, err: %v", err)
+	}
+}
+
+func TestLocalFilesystem_AbortMultipartUpload(t *testing.T) {
+	client := newTestLocalFilesystem(t)
+
+	uploadId, _ := client.InitiateMultipartUpload("im-private", "multipart/abort.tmp")
+	if _, err := client.PutObjectPart("im-private", "multipart/abort.tmp", uploadId, 1, bytes.NewReader([]byte("data")), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.AbortMultipartUpload("im-private", "multipart/abort.tmp", uploadId); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(client.Path("im-private", client.partDir(uploadId))); !os.IsNotExist(err) {
+		t.Fatalf("multipart parts should be removed, err: %v", err)
+	}
+
+	// 重复取消不应报错
+	if err := client.AbortMultipartUpload("im-private", "multipart/abort.tmp", uploadId); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLocalFilesystem_ReadObject(t *testing.T) {
+	client := newTestLocalFilesystem(t)
+
+	if err := client.Write("im-private", "objects/test.txt", []byte("content")); err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := client.ReadObject("im-private", "objects/test.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != "content" {
+		t.Fatalf("unexpected content %q", data)
+	}
+}
+
+func TestLocalFilesystem_PrivateUrlSignature(t *testing.T) {
+	client := NewLocalFilesystem(LocalSystemConfig{
+		Root:          t.TempDir(),
+		BucketPublic:  "im-static",
+		BucketPrivate: "im-private",
+		Endpoint:      "127.0.0.1:9000",
+		SignSecret:    "secret",
+		SignUrl:       "https://im-api.example.com/api/v1/files/",
+	})
+
+	uri, err := url.Parse(client.PrivateUrl("im-private", "/files/报告 1.pdf", "报告 1.pdf", time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if uri.Host != "im-api.example.com" || uri.Path != "/api/v1/files/im-private/files/报告 1.pdf" {
+		t.Fatalf("unexpected url: %s", uri)
+	}
+
+	query := uri.Query()
+	expires, _ := strconv.ParseInt(query.Get("expires"), 10, 64)
+
+	if err := client.VerifySignature("im-private", "files/报告 1.pdf", query.Get("filename"), expires, query.Get("signature")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.VerifySignature("im-private", "files/other.pdf", query.Get("filename"), expires, query.Get("signature")); err != ErrSignatureInvalid {
+		t.Fatalf("expected invalid signature, got %v", err)
+	}
+
+	if err := client.VerifySignature("im-private", "files/报告 1.pdf", query.Get("filename"), expires+60, query.Get("signature")); err != ErrSignatureInvalid {
+		t.Fatalf("expected invalid signature, got %v", err)
+	}
+
+	expired := time.Now().Add(-time.Minute).Unix()
+	signature := client.signature("im-private", "files/a.pdf", "", expired)
+
+	if err := client.VerifySignature("im-private", "files/a.pdf", "", expired, signature); err != ErrSignatureExpired {
+		t.Fatalf("expected expired signature, got %v", err)
+	}
+}
+
+func TestLocalFilesystem_PrivateUrlWithoutSecret(t *testing.T) {
+	client := newTestLocalFilesystem(t)
+
+	if got := client.PrivateUrl("im-private", "a.pdf", "a.pdf", time.Minute); got != "http://127.0.0.1:9000/im-private/a.pdf" {
+		t.Fatalf("unexpected url: %s", got)
+	}
+
+	if err := client.VerifySignature("im-private", "a.pdf", "", time.Now().Unix()+60, ""); err != ErrSignatureInvalid {
+		t.Fatalf("expected invalid signature, got %v", err)
+	}
+}
+
+func TestWriteStream(t *testing.T) {
+	client := newTestLocalFilesystem(t)
+
+	content := bytes.Repeat([]byte("0123456789"), 25)
+	if err := WriteStream(client, "im-private", "stream/test.zip", bytes.NewReader(content), 64); err != nil {
+		t.Fatal(err)
+	}
+
+	stream, err := client.GetObject("im-private", "stream/test.zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(stream, content) {
+		t.Fatalf("unexpected content length: %d", len(stream))
+	}
+}
+
+func TestPublicObjectName(t *testing.T) {
+	client := newTestLocalFilesystem(t)
+
+	uri := client.PublicUrl("im-static", "public/media/20240101/a.png")
+
+	if name := PublicObjectName(client, uri); name != "public/media/20240101/a.png" {
+		t.Fatalf("unexpected object name: %q", name)
+	}
+
+	if name := PublicObjectName(client, "https://example.com/im-static/a.png"); name != "" {
+		t.Fatalf("unexpected object name: %q", name)
+	}
+}
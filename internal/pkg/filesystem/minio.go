@@ -102,6 +102,10 @@ func (m MinioFilesystem) GetObject(bucketName string, objectName string) ([]byte
 	return io.ReadAll(object)
 }
 
+func (m MinioFilesystem) ReadObject(bucketName string, objectName string) (io.ReadCloser, error) {
+	return m.core.Client.GetObject(context.Background(), bucketName, objectName, minio.GetObjectOptions{})
+}
+
 func (m MinioFilesystem) PublicUrl(bucketName, objectName string) string {
 	uri, err := m.core.Client.PresignedGetObject(context.Background(), bucketName, objectName, 30*time.Minute, nil)
 	if err != nil {
@@ -0,0 +1,94 @@
+package filesystem
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+var _ IFilesystem = (*S3Filesystem)(nil)
+
+// S3Filesystem 通用 S3 协议存储(AWS S3、阿里云 OSS、腾讯云 COS 等兼容服务)
+// 除访问地址外的文件操作与 Minio 一致
+type S3Filesystem struct {
+	MinioFilesystem
+	config S3SystemConfig
+}
+
+func NewS3Filesystem(config S3SystemConfig) *S3Filesystem {
+	lookup := minio.BucketLookupDNS
+	if config.PathStyle {
+		lookup = minio.BucketLookupPath
+	}
+
+	client, err := minio.NewCore(config.Endpoint, &minio.Options{
+		Creds:        credentials.NewStaticV4(config.AccessKey, config.SecretKey, ""),
+		Secure:       config.SSL,
+		Region:       config.Region,
+		BucketLookup: lookup,
+	})
+
+	if err != nil {
+		panic(fmt.Sprintf("Unable to initialize s3 client, %s", err))
+	}
+
+	return &S3Filesystem{
+		MinioFilesystem: MinioFilesystem{
+			core: client,
+			config: MinioSystemConfig{
+				SSL:           config.SSL,
+				SecretId:      config.AccessKey,
+				SecretKey:     config.SecretKey,
+				BucketPublic:  config.BucketPublic,
+				BucketPrivate: config.BucketPrivate,
+				Endpoint:      config.Endpoint,
+			},
+		},
+		config: config,
+	}
+}
+
+func (s S3Filesystem) Driver() string {
+	return S3Driver
+}
+
+// PublicUrl 公开文件地址，配置了自定义域名时使用自定义域名
+func (s S3Filesystem) PublicUrl(bucketName, objectName string) string {
+	objectName = strings.TrimLeft(objectName, "/")
+
+	if s.config.PublicDomain != "" && bucketName == s.BucketPublicName() {
+		return fmt.Sprintf("%s/%s", strings.TrimRight(s.config.PublicDomain, "/"), objectName)
+	}
+
+	scheme := "http"
+	if s.config.SSL {
+		scheme = "https"
+	}
+
+	if s.config.PathStyle {
+		return fmt.Sprintf("%s://%s/%s/%s", scheme, s.config.Endpoint, bucketName, objectName)
+	}
+
+	return fmt.Sprintf("%s://%s.%s/%s", scheme, bucketName, s.config.Endpoint, objectName)
+}
+
+// PrivateUrl 私有文件预签名下载地址
+func (s S3Filesystem) PrivateUrl(bucketName, objectName string, filename string, expire time.Duration) string {
+	reqParams := make(url.Values)
+	if filename != "" {
+		reqParams.Set("response-content-disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	}
+
+	uri, err := s.core.Client.PresignedGetObject(context.Background(), bucketName, objectName, expire, reqParams)
+	if err != nil {
+		panic(err)
+	}
+
+	return uri.String()
+}
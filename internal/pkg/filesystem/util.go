@@ -1,9 +1,13 @@
 package filesystem
 
 import (
+	"bytes"
+	"errors"
 	"io"
 	"mime/multipart"
+	"net/url"
 	"os"
+	"strings"
 )
 
 func ReadMultipartStream(file *multipart.FileHeader) ([]byte, error) {
@@ -17,6 +21,60 @@ func ReadMultipartStream(file *multipart.FileHeader) ([]byte, error) {
 	return io.ReadAll(src)
 }
 
+// PublicObjectName 从公开文件的访问地址中还原文件名，地址前缀由驱动自身生成，兼容自定义域名及虚拟主机风格的地址
+func PublicObjectName(fs IFilesystem, uri string) string {
+	prefix := strings.TrimSuffix(fs.PublicUrl(fs.BucketPublicName(), "__object__"), "__object__")
+	if uri == "" || !strings.HasPrefix(uri, prefix) {
+		return ""
+	}
+
+	objectName, _, _ := strings.Cut(strings.TrimPrefix(uri, prefix), "?")
+	if value, err := url.PathUnescape(objectName); err == nil {
+		objectName = value
+	}
+
+	return strings.Trim(objectName, "/")
+}
+
+// WriteStream 以分片上传的方式写入文件流，避免将大文件一次性读入内存
+func WriteStream(fs IFilesystem, bucketName string, objectName string, reader io.Reader, partSize int64) (err error) {
+	uploadId, err := fs.InitiateMultipartUpload(bucketName, objectName)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = fs.AbortMultipartUpload(bucketName, objectName, uploadId)
+		}
+	}()
+
+	buf := make([]byte, partSize)
+	parts := make([]ObjectPart, 0)
+	for index := 1; ; index++ {
+		n, readErr := io.ReadFull(reader, buf)
+		if readErr != nil && !errors.Is(readErr, io.EOF) && !errors.Is(readErr, io.ErrUnexpectedEOF) {
+			return readErr
+		}
+
+		// 空文件也需要保留一个分片
+		if n > 0 || index == 1 {
+			part, err := fs.PutObjectPart(bucketName, objectName, uploadId, index, bytes.NewReader(buf[:n]), int64(n))
+			if err != nil {
+				return err
+			}
+
+			parts = append(parts, part)
+		}
+
+		if readErr != nil {
+			break
+		}
+	}
+
+	return fs.CompleteMultipartUpload(bucketName, objectName, uploadId, parts)
+}
+
 // isDirExist 判断目录是否存在
 func isDirExist(fileAddr string) bool {
 	s, err := os.Stat(fileAddr)
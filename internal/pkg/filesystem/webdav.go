@@ -0,0 +1,378 @@
+package filesystem
+
+import (
+	"bytes"
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var _ IFilesystem = (*WebDAVFilesystem)(nil)
+
+// WebDAVFilesystem WebDAV 协议存储(Nextcloud、Nginx dav 模块等)
+// 桶映射为根目录下的一级目录
+type WebDAVFilesystem struct {
+	config WebDAVSystemConfig
+	client *http.Client
+	dirs   *sync.Map // 已创建的目录缓存
+}
+
+func NewWebDAVFilesystem(config WebDAVSystemConfig) *WebDAVFilesystem {
+	timeout := time.Duration(config.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 60 * time.Second
+	}
+
+	return &WebDAVFilesystem{
+		config: config,
+		client: &http.Client{Timeout: timeout},
+		dirs:   &sync.Map{},
+	}
+}
+
+func (w WebDAVFilesystem) Driver() string {
+	return WebDAVDriver
+}
+
+func (w WebDAVFilesystem) BucketPublicName() string {
+	return w.config.BucketPublic
+}
+
+func (w WebDAVFilesystem) BucketPrivateName() string {
+	return w.config.BucketPrivate
+}
+
+func (w WebDAVFilesystem) Stat(bucketName string, objectName string) (*FileStatInfo, error) {
+	resp, err := w.do(http.MethodHead, w.objectPath(bucketName, objectName), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if err := w.checkResponse("stat", w.objectPath(bucketName, objectName), resp); err != nil {
+		return nil, err
+	}
+
+	info := &FileStatInfo{
+		Name:     filepath.Base(objectName),
+		Size:     resp.ContentLength,
+		Ext:      filepath.Ext(objectName),
+		MimeType: resp.Header.Get("Content-Type"),
+	}
+
+	if size, err := strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64); err == nil {
+		info.Size = size
+	}
+
+	if t, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		info.LastModTime = t
+	}
+
+	return info, nil
+}
+
+func (w WebDAVFilesystem) Write(bucketName string, objectName string, stream []byte) error {
+	return w.put(bucketName, objectName, bytes.NewReader(stream), int64(len(stream)))
+}
+
+func (w WebDAVFilesystem) Copy(bucketName string, srcObjectName, objectName string) error {
+	return w.CopyObject(bucketName, srcObjectName, bucketName, objectName)
+}
+
+func (w WebDAVFilesystem) CopyObject(srcBucketName string, srcObjectName, dstBucketName string, dstObjectName string) error {
+	if err := w.mkdirAll(path.Dir(w.objectPath(dstBucketName, dstObjectName))); err != nil {
+		return err
+	}
+
+	src := w.objectPath(srcBucketName, srcObjectName)
+
+	resp, err := w.do("COPY", src, nil, map[string]string{
+		"Destination": w.url(w.objectPath(dstBucketName, dstObjectName)),
+		"Overwrite":   "T",
+	})
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	return w.checkResponse("copy", src, resp)
+}
+
+func (w WebDAVFilesystem) Delete(bucketName string, objectName string) error {
+	return w.delete(w.objectPath(bucketName, objectName))
+}
+
+func (w WebDAVFilesystem) GetObject(bucketName string, objectName string) ([]byte, error) {
+	reader, err := w.ReadObject(bucketName, objectName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+func (w WebDAVFilesystem) ReadObject(bucketName string, objectName string) (io.ReadCloser, error) {
+	resp, err := w.do(http.MethodGet, w.objectPath(bucketName, objectName), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := w.checkResponse("read", w.objectPath(bucketName, objectName), resp); err != nil {
+		_ = resp.Body.Close()
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+func (w WebDAVFilesystem) PublicUrl(bucketName, objectName string) string {
+	domain := w.config.PublicDomain
+	if domain == "" {
+		domain = w.config.Endpoint
+	}
+
+	return fmt.Sprintf(
+		"%s/%s/%s",
+		strings.TrimRight(domain, "/"),
+		bucketName,
+		strings.Trim(objectName, "/"),
+	)
+}
+
+func (w WebDAVFilesystem) PrivateUrl(bucketName, objectName string, _ string, _ time.Duration) string {
+	return w.PublicUrl(bucketName, objectName)
+}
+
+func (w WebDAVFilesystem) InitiateMultipartUpload(_, _ string) (string, error) {
+	return uuid.New().String(), nil
+}
+
+func (w WebDAVFilesystem) PutObjectPart(bucketName, _ string, uploadID string, index int, data io.Reader, _ int64) (ObjectPart, error) {
+	stream, err := io.ReadAll(data)
+	if err != nil {
+		return ObjectPart{}, err
+	}
+
+	objectName := w.partObjectName(uploadID, index)
+	if err := w.Write(bucketName, objectName, stream); err != nil {
+		return ObjectPart{}, err
+	}
+
+	return ObjectPart{
+		ETag:           fmt.Sprintf("%x", md5.Sum(stream)),
+		PartNumber:     index,
+		PartObjectName: objectName,
+	}, nil
+}
+
+// CompleteMultipartUpload 按分片序号依次读取分片并流式写入目标文件，完成后删除分片目录
+func (w WebDAVFilesystem) CompleteMultipartUpload(bucketName, objectName, uploadID string, parts []ObjectPart) error {
+	parts = slices.Clone(parts)
+	slices.SortFunc(parts, func(a, b ObjectPart) int {
+		return a.PartNumber - b.PartNumber
+	})
+
+	reader := &partReader{fs: w, bucketName: bucketName, parts: parts}
+	defer reader.Close()
+
+	if err := w.put(bucketName, objectName, reader, -1); err != nil {
+		return err
+	}
+
+	return w.deleteDir(w.objectPath(bucketName, w.partDir(uploadID)))
+}
+
+// AbortMultipartUpload 取消分片上传，删除已上传的分片临时文件
+func (w WebDAVFilesystem) AbortMultipartUpload(bucketName, _, uploadID string) error {
+	return w.deleteDir(w.objectPath(bucketName, w.partDir(uploadID)))
+}
+
+func (w WebDAVFilesystem) put(bucketName, objectName string, body io.Reader, size int64) error {
+	name := w.objectPath(bucketName, objectName)
+	if err := w.mkdirAll(path.Dir(name)); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPut, w.url(name), body)
+	if err != nil {
+		return err
+	}
+
+	req.ContentLength = size
+
+	resp, err := w.send(req, nil)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	return w.checkResponse("write", name, resp)
+}
+
+func (w WebDAVFilesystem) delete(name string) error {
+	resp, err := w.do(http.MethodDelete, name, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	return w.checkResponse("delete", name, resp)
+}
+
+// deleteDir 删除目录，目录不存在时忽略
+func (w WebDAVFilesystem) deleteDir(name string) error {
+	err := w.delete(strings.TrimRight(name, "/") + "/")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	w.dirs.Delete(strings.TrimRight(name, "/"))
+	return nil
+}
+
+// mkdirAll 逐级创建目录(MKCOL 不支持递归创建)
+func (w WebDAVFilesystem) mkdirAll(dir string) error {
+	dir = strings.Trim(dir, "/")
+	if dir == "" || dir == "." {
+		return nil
+	}
+
+	if _, ok := w.dirs.Load(dir); ok {
+		return nil
+	}
+
+	if err := w.mkdirAll(path.Dir(dir)); err != nil {
+		return err
+	}
+
+	resp, err := w.do("MKCOL", dir+"/", nil, nil)
+	if err != nil {
+		return err
+	}
+
+	_ = resp.Body.Close()
+
+	// 201 创建成功，405 目录已存在
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusMethodNotAllowed {
+		return fmt.Errorf("webdav mkcol %s: %s", dir, resp.Status)
+	}
+
+	w.dirs.Store(dir, struct{}{})
+	return nil
+}
+
+func (w WebDAVFilesystem) do(method, name string, body io.Reader, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequest(method, w.url(name), body)
+	if err != nil {
+		return nil, err
+	}
+
+	return w.send(req, headers)
+}
+
+func (w WebDAVFilesystem) send(req *http.Request, headers map[string]string) (*http.Response, error) {
+	if w.config.Username != "" {
+		req.SetBasicAuth(w.config.Username, w.config.Password)
+	}
+
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	return w.client.Do(req)
+}
+
+func (w WebDAVFilesystem) checkResponse(op, name string, resp *http.Response) error {
+	if resp.StatusCode == http.StatusNotFound {
+		return &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webdav %s %s: %s", op, name, resp.Status)
+	}
+
+	return nil
+}
+
+func (w WebDAVFilesystem) url(name string) string {
+	u := url.URL{Path: "/" + strings.TrimLeft(name, "/")}
+	return strings.TrimRight(w.config.Endpoint, "/") + u.EscapedPath()
+}
+
+func (w WebDAVFilesystem) objectPath(bucketName string, objectName string) string {
+	return fmt.Sprintf("%s/%s", bucketName, strings.TrimLeft(objectName, "/"))
+}
+
+func (w WebDAVFilesystem) partDir(uploadID string) string {
+	return fmt.Sprintf("multipart/%s", uploadID)
+}
+
+func (w WebDAVFilesystem) partObjectName(uploadID string, index int) string {
+	return fmt.Sprintf("%s/%d_%s.tmp", w.partDir(uploadID), index, uploadID)
+}
+
+// partReader 按顺序读取分片内容，每次只打开一个分片
+type partReader struct {
+	fs         WebDAVFilesystem
+	bucketName string
+	parts      []ObjectPart
+	current    io.ReadCloser
+}
+
+func (p *partReader) Read(b []byte) (int, error) {
+	for {
+		if p.current == nil {
+			if len(p.parts) == 0 {
+				return 0, io.EOF
+			}
+
+			reader, err := p.fs.ReadObject(p.bucketName, p.parts[0].PartObjectName)
+			if err != nil {
+				return 0, err
+			}
+
+			p.current, p.parts = reader, p.parts[1:]
+		}
+
+		n, err := p.current.Read(b)
+		if err == io.EOF {
+			_ = p.current.Close()
+			p.current = nil
+
+			if n > 0 {
+				return n, nil
+			}
+
+			continue
+		}
+
+		return n, err
+	}
+}
+
+func (p *partReader) Close() error {
+	if p.current != nil {
+		return p.current.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package filesystem
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"golang.org/x/net/webdav"
+)
+
+func newTestWebDAVFilesystem(t *testing.T) *WebDAVFilesystem {
+	server := httptest.NewServer(&webdav.Handler{
+		FileSystem: webdav.NewMemFS(),
+		LockSystem: webdav.NewMemLS(),
+	})
+
+	t.Cleanup(server.Close)
+
+	return NewWebDAVFilesystem(WebDAVSystemConfig{
+		Endpoint:      server.URL,
+		BucketPublic:  "im-static",
+		BucketPrivate: "im-private",
+	})
+}
+
+func TestWebDAVFilesystem_WriteAndRead(t *testing.T) {
+	client := newTestWebDAVFilesystem(t)
+
+	if err := client.Write("im-private", "a/b/c/test.txt", []byte("hello webdav")); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := client.GetObject("im-private", "a/b/c/test.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(content) != "hello webdav" {
+		t.Fatalf("unexpected content: %q", content)
+	}
+
+	info, err := client.Stat("im-private", "a/b/c/test.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Size != int64(len("hello webdav")) || info.Ext != ".txt" {
+		t.Fatalf("unexpected stat: %+v", info)
+	}
+
+	if err := client.CopyObject("im-private", "a/b/c/test.txt", "im-static", "x/y/copy.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Stat("im-static", "x/y/copy.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Delete("im-private", "a/b/c/test.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Stat("im-private", "a/b/c/test.txt"); !os.IsNotExist(err) {
+		t.Fatalf("expected not exist error, got %v", err)
+	}
+}
+
+func TestWebDAVFilesystem_Multipart(t *testing.T) {
+	client := newTestWebDAVFilesystem(t)
+
+	uploadId, err := client.InitiateMultipartUpload("im-private", "multipart/test.tmp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chunks := [][]byte{[]byte("hello "), []byte("multipart "), []byte("world")}
+
+	parts := make([]ObjectPart, 0)
+	for _, index := range []int{2, 3, 1} {
+		part, err := client.PutObjectPart("im-private", "multipart/test.tmp", uploadId, index, bytes.NewReader(chunks[index-1]), 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		parts = append(parts, part)
+	}
+
+	if err := client.CompleteMultipartUpload("im-private", "files/test.txt", uploadId, parts); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := client.GetObject("im-private", "files/test.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(content) != "hello multipart world" {
+		t.Fatalf("unexpected content: %q", content)
+	}
+
+	if _, err := client.Stat("im-private", parts[0].PartObjectName); !os.IsNotExist(err) {
+		t.Fatalf("expected parts removed, got %v", err)
+	}
+
+	if err := client.AbortMultipartUpload("im-private", "files/test.txt", uploadId); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWebDAVFilesystem_PublicUrl(t *testing.T) {
+	client := NewWebDAVFilesystem(WebDAVSystemConfig{
+		Endpoint:     "http://127.0.0.1/dav",
+		PublicDomain: "https://cdn.example.com/",
+	})
+
+	if got := client.PublicUrl("im-static", "/a/b.png"); got != "https://cdn.example.com/im-static/a/b.png" {
+		t.Fatalf("unexpected url: %s", got)
+	}
+}
@@ -0,0 +1,239 @@
+package imageutil
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/draw"
+)
+
+const (
+	exifTagOrientation = 0x0112
+	exifTagGPSInfo     = 0x8825
+)
+
+var exifHeader = []byte("Exif\x00\x00")
+
+// exifTypeSize EXIF 数据类型对应的字节数
+var exifTypeSize = map[uint16]uint32{
+	1: 1, 2: 1, 3: 2, 4: 4, 5: 8, 6: 1, 7: 1, 8: 2, 9: 4, 10: 8, 11: 4, 12: 8,
+}
+
+type tiff struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+func (t *tiff) uint16(offset uint32) (uint16, bool) {
+	if uint64(offset)+2 > uint64(len(t.data)) {
+		return 0, false
+	}
+
+	return t.order.Uint16(t.data[offset:]), true
+}
+
+func (t *tiff) uint32(offset uint32) (uint32, bool) {
+	if uint64(offset)+4 > uint64(len(t.data)) {
+		return 0, false
+	}
+
+	return t.order.Uint32(t.data[offset:]), true
+}
+
+// entry 查找 IFD 中指定标签的条目偏移量
+func (t *tiff) entry(ifd uint32, tag uint16) (uint32, bool) {
+	count, ok := t.uint16(ifd)
+	if !ok {
+		return 0, false
+	}
+
+	for i := uint32(0); i < uint32(count); i++ {
+		offset := ifd + 2 + i*12
+		if value, ok := t.uint16(offset); ok && value == tag {
+			return offset, true
+		}
+	}
+
+	return 0, false
+}
+
+func (t *tiff) ifd0() (uint32, bool) {
+	return t.uint32(4)
+}
+
+func parseTiff(data []byte) (*tiff, bool) {
+	if len(data) < 8 {
+		return nil, false
+	}
+
+	switch string(data[:4]) {
+	case "II*\x00":
+		return &tiff{data: data, order: binary.LittleEndian}, true
+	case "MM\x00*":
+		return &tiff{data: data, order: binary.BigEndian}, true
+	}
+
+	return nil, false
+}
+
+// exifSegments 遍历 JPEG 中的 EXIF(APP1) 段，fn 参数为 TIFF 数据
+func exifSegments(data []byte, fn func(t *tiff)) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return
+	}
+
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return
+		}
+
+		marker := data[i+1]
+
+		// 填充字节
+		if marker == 0xFF {
+			i++
+			continue
+		}
+
+		// 图像数据开始或结束，之后不再有元数据
+		if marker == 0xDA || marker == 0xD9 {
+			return
+		}
+
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 || i+2+length > len(data) {
+			return
+		}
+
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, exifHeader) {
+			if t, ok := parseTiff(segment[len(exifHeader):]); ok {
+				fn(t)
+			}
+		}
+
+		i += 2 + length
+	}
+}
+
+// StripGPS 清除 JPEG 图片 EXIF 中的 GPS 定位信息，其它元数据(如拍摄方向)保持不变
+func StripGPS(data []byte) []byte {
+	out := bytes.Clone(data)
+
+	exifSegments(out, func(t *tiff) {
+		ifd0, ok := t.ifd0()
+		if !ok {
+			return
+		}
+
+		entry, ok := t.entry(ifd0, exifTagGPSInfo)
+		if !ok {
+			return
+		}
+
+		gps, ok := t.uint32(entry + 8)
+		if !ok {
+			return
+		}
+
+		count, ok := t.uint16(gps)
+		if !ok {
+			return
+		}
+
+		for i := uint32(0); i < uint32(count); i++ {
+			offset := gps + 2 + i*12
+			if uint64(offset)+12 > uint64(len(t.data)) {
+				break
+			}
+
+			typ := t.order.Uint16(t.data[offset+2:])
+			size := uint64(exifTypeSize[typ]) * uint64(t.order.Uint32(t.data[offset+4:]))
+
+			// 超过4字节的值存放在偏移地址中
+			if size > 4 {
+				if value, ok := t.uint32(offset + 8); ok && uint64(value)+size <= uint64(len(t.data)) {
+					clear(t.data[value : uint64(value)+size])
+				}
+			}
+
+			clear(t.data[offset : offset+12])
+		}
+
+		// 置为空的 IFD
+		t.order.PutUint16(t.data[gps:], 0)
+	})
+
+	return out
+}
+
+// Orientation 读取 JPEG 图片 EXIF 中的拍摄方向，默认为 1
+func Orientation(data []byte) int {
+	orientation := 1
+
+	exifSegments(data, func(t *tiff) {
+		ifd0, ok := t.ifd0()
+		if !ok {
+			return
+		}
+
+		entry, ok := t.entry(ifd0, exifTagOrientation)
+		if !ok {
+			return
+		}
+
+		if value, ok := t.uint16(entry + 8); ok && value >= 1 && value <= 8 {
+			orientation = int(value)
+		}
+	})
+
+	return orientation
+}
+
+// applyOrientation 按 EXIF 拍摄方向旋转或翻转图片
+func applyOrientation(src image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return src
+	}
+
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	// 5-8 需要交换宽高
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Rect, src, b.Min, draw.Src)
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for dy := 0; dy < dh; dy++ {
+		for dx := 0; dx < dw; dx++ {
+			var sx, sy int
+			switch orientation {
+			case 2:
+				sx, sy = w-1-dx, dy
+			case 3:
+				sx, sy = w-1-dx, h-1-dy
+			case 4:
+				sx, sy = dx, h-1-dy
+			case 5:
+				sx, sy = dy, dx
+			case 6:
+				sx, sy = dy, h-1-dx
+			case 7:
+				sx, sy = w-1-dy, h-1-dx
+			case 8:
+				sx, sy = w-1-dy, dx
+			}
+
+			si := img.PixOffset(sx, sy)
+			di := dst.PixOffset(dx, dy)
+			copy(dst.Pix[di:di+4], img.Pix[si:si+4])
+		}
+	}
+
+	return dst
+}
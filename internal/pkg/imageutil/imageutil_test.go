@@ -0,0 +1,139 @@
+package imageutil
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestImage(width, height int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+
+	return img
+}
+
+// newTestExif 生成包含拍摄方向及 GPS 纬度的 EXIF 数据
+func newTestExif(orientation uint16) []byte {
+	tiff := make([]byte, 80)
+	order := binary.LittleEndian
+
+	copy(tiff, "II*\x00")
+	order.PutUint32(tiff[4:], 8)
+
+	// IFD0
+	order.PutUint16(tiff[8:], 2)
+	order.PutUint16(tiff[10:], exifTagOrientation)
+	order.PutUint16(tiff[12:], 3)
+	order.PutUint32(tiff[14:], 1)
+	order.PutUint16(tiff[18:], orientation)
+	order.PutUint16(tiff[22:], exifTagGPSInfo)
+	order.PutUint16(tiff[24:], 4)
+	order.PutUint32(tiff[26:], 1)
+	order.PutUint32(tiff[30:], 38)
+
+	// GPS IFD
+	order.PutUint16(tiff[38:], 1)
+	order.PutUint16(tiff[40:], 2)
+	order.PutUint16(tiff[42:], 5)
+	order.PutUint32(tiff[44:], 3)
+	order.PutUint32(tiff[48:], 56)
+	copy(tiff[56:], bytes.Repeat([]byte{0x11}, 24))
+
+	segment := append(bytes.Clone(exifHeader), tiff...)
+
+	app1 := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(app1[2:], uint16(len(segment)+2))
+
+	return append(app1, segment...)
+}
+
+func newTestJPEG(t *testing.T, width, height int, exif []byte) []byte {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, newTestImage(width, height), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	data := buf.Bytes()
+
+	out := append([]byte{}, data[:2]...)
+	out = append(out, exif...)
+	return append(out, data[2:]...)
+}
+
+func TestStripGPS(t *testing.T) {
+	data := newTestJPEG(t, 16, 16, newTestExif(6))
+
+	assert.True(t, bytes.Contains(data, bytes.Repeat([]byte{0x11}, 24)))
+
+	stripped := StripGPS(data)
+
+	assert.Equal(t, len(data), len(stripped))
+	assert.False(t, bytes.Contains(stripped, bytes.Repeat([]byte{0x11}, 24)))
+	assert.Equal(t, 6, Orientation(stripped))
+
+	_, err := jpeg.Decode(bytes.NewReader(stripped))
+	assert.NoError(t, err)
+}
+
+func TestStripGPS_NotJPEG(t *testing.T) {
+	data := []byte("not a jpeg")
+	assert.Equal(t, data, StripGPS(data))
+	assert.Equal(t, 1, Orientation(data))
+}
+
+func TestGenerateVariants(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, newTestImage(1000, 500)); err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := GenerateVariants(buf.Bytes())
+	assert.NoError(t, err)
+	assert.Len(t, items, 2)
+
+	assert.Equal(t, VariantThumb, items[0].Name)
+	assert.Equal(t, "png", items[0].Ext)
+	assert.Equal(t, 200, items[0].Width)
+	assert.Equal(t, 100, items[0].Height)
+
+	assert.Equal(t, VariantMedium, items[1].Name)
+	assert.Equal(t, 800, items[1].Width)
+	assert.Equal(t, 400, items[1].Height)
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(items[1].Data))
+	assert.NoError(t, err)
+	assert.Equal(t, 800, config.Width)
+}
+
+func TestGenerateVariants_Orientation(t *testing.T) {
+	items, err := GenerateVariants(newTestJPEG(t, 400, 100, newTestExif(6)))
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+
+	// 旋转90度后宽高互换
+	assert.Equal(t, "jpg", items[0].Ext)
+	assert.Equal(t, 50, items[0].Width)
+	assert.Equal(t, 200, items[0].Height)
+}
+
+func TestGenerateVariants_Small(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, newTestImage(100, 100)); err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := GenerateVariants(buf.Bytes())
+	assert.NoError(t, err)
+	assert.Empty(t, items)
+}
@@ -0,0 +1,115 @@
+package imageutil
+
+import (
+	"bytes"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+)
+
+const (
+	VariantThumb  = "thumb"  // 缩略图
+	VariantMedium = "medium" // 中图
+
+	// 超过该像素数的图片不生成变体，避免解码占用过多内存
+	maxDecodePixels = 50_000_000
+)
+
+// VariantSizes 变体名称及最大边长
+var VariantSizes = []struct {
+	Name string
+	Size int
+}{
+	{VariantThumb, 200},
+	{VariantMedium, 800},
+}
+
+type Variant struct {
+	Name   string // 变体名称
+	Ext    string // 文件后缀
+	Width  int    // 图片宽度
+	Height int    // 图片高度
+	Data   []byte // 图片内容
+}
+
+// GenerateVariants 生成缩略图及中图，支持 JPEG、PNG 及 GIF(首帧)
+// 图片尺寸不超过变体尺寸时不生成对应变体，不支持的格式返回空列表
+func GenerateVariants(stream []byte) ([]*Variant, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(stream))
+	if err != nil {
+		return nil, nil
+	}
+
+	if config.Width*config.Height > maxDecodePixels {
+		return nil, nil
+	}
+
+	switch format {
+	case "jpeg", "png", "gif":
+	default:
+		return nil, nil
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(stream))
+	if err != nil {
+		return nil, err
+	}
+
+	orientation := 1
+	if format == "jpeg" {
+		orientation = Orientation(stream)
+	}
+
+	items := make([]*Variant, 0, len(VariantSizes))
+	for _, item := range VariantSizes {
+		width, height, ok := fit(config.Width, config.Height, item.Size)
+		if !ok {
+			continue
+		}
+
+		dst := image.NewRGBA(image.Rect(0, 0, width, height))
+		draw.CatmullRom.Scale(dst, dst.Rect, src, src.Bounds(), draw.Over, nil)
+
+		img := applyOrientation(dst, orientation)
+
+		variant := &Variant{
+			Name:   item.Name,
+			Width:  img.Bounds().Dx(),
+			Height: img.Bounds().Dy(),
+		}
+
+		var buf bytes.Buffer
+		if format == "jpeg" {
+			variant.Ext = "jpg"
+			err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85})
+		} else {
+			variant.Ext = "png"
+			err = png.Encode(&buf, img)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		variant.Data = buf.Bytes()
+		items = append(items, variant)
+	}
+
+	return items, nil
+}
+
+// fit 按最大边长等比缩放，图片不超过最大边长时返回 false
+func fit(width, height, size int) (int, int, bool) {
+	if width <= size && height <= size {
+		return width, height, false
+	}
+
+	if width >= height {
+		return size, max(1, height*size/width), true
+	}
+
+	return max(1, width*size/height), size, true
+}
@@ -1,16 +1,23 @@
 package jwt
 
 import (
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
+	"go-chat/internal/pkg/logger"
 )
 
+// 密钥目录的重新加载间隔，轮换密钥后无需重启服务
+const reloadInterval = time.Minute
+
 type Options jwt.RegisteredClaims
 
 type AuthClaims struct {
-	Guard string `json:"guard"` // 授权守卫
+	Guard     string `json:"guard"`         // 授权守卫
+	SessionId string `json:"sid,omitempty"` // 登录会话ID
 	jwt.RegisteredClaims
 }
 
@@ -18,11 +25,50 @@ func NewNumericDate(t time.Time) *jwt.NumericDate {
 	return jwt.NewNumericDate(t)
 }
 
+// KeySet 签名及验签密钥集合
+//
+// 配置了密钥目录时使用目录中的当前密钥签名(RS256/EdDSA)，并在 JWT 头部写入 kid，
+// 目录中的其它密钥仅用于验签，便于平滑轮换；未配置密钥目录或目录中没有可用密钥时使用 HS256 共享密钥。
+// 存在当前签名密钥时，未携带 kid 的 HS256 令牌仅在 legacyUntil 之前有效。
+type KeySet struct {
+	secret      string
+	dir         string
+	legacyUntil time.Time
+
+	mu       sync.RWMutex
+	keys     map[string]*Key
+	items    []*Key
+	active   *Key
+	loadedAt time.Time
+}
+
+func NewKeySet(secret string, dir string, legacyUntil time.Time) (*KeySet, error) {
+	set := &KeySet{secret: secret, dir: dir, legacyUntil: legacyUntil, keys: make(map[string]*Key)}
+
+	if dir != "" {
+		if err := set.load(); err != nil {
+			return nil, err
+		}
+	}
+
+	if set.active == nil && secret == "" {
+		return nil, errors.New("未配置 jwt 签名密钥")
+	}
+
+	return set, nil
+}
+
 // GenerateToken 生成 JWT 令牌
-func GenerateToken(guard string, secret string, ops *Options) string {
+func (s *KeySet) GenerateToken(guard string, ops *Options) string {
+	return s.GenerateSessionToken(guard, "", ops)
+}
+
+// GenerateSessionToken 生成绑定登录会话的 JWT 令牌
+func (s *KeySet) GenerateSessionToken(guard string, sid string, ops *Options) string {
 
 	claims := AuthClaims{
-		Guard: guard,
+		Guard:     guard,
+		SessionId: sid,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Audience:  ops.Audience,
 			ExpiresAt: ops.ExpiresAt,
@@ -34,20 +80,49 @@ func GenerateToken(guard string, secret string, ops *Options) string {
 		},
 	}
 
-	tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if key := s.signingKey(); key != nil {
+		token := jwt.NewWithClaims(key.method(), claims)
+		token.Header["kid"] = key.Id
+
+		tokenString, _ := token.SignedString(key.Private)
+		return tokenString
+	}
+
+	tokenString, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(s.secret))
 
 	return tokenString
 }
 
 // ParseToken 解析 JWT Token
-func ParseToken(token string, secret string) (*AuthClaims, error) {
+func (s *KeySet) ParseToken(token string) (*AuthClaims, error) {
 
 	data, err := jwt.ParseWithClaims(token, &AuthClaims{}, func(token *jwt.Token) (any, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		kid, _ := token.Header["kid"].(string)
+
+		// 未携带 kid 的令牌按 HS256 共享密钥校验，启用非对称签名后仅在过渡期内兼容此前签发的令牌
+		if kid == "" {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok || s.secret == "" {
+				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+			}
+
+			if s.signingKey() != nil && !time.Now().Before(s.legacyUntil) {
+				return nil, errors.New("legacy hmac token is no longer accepted")
+			}
+
+			return []byte(s.secret), nil
+		}
+
+		key := s.verifyKey(kid)
+		if key == nil {
+			return nil, fmt.Errorf("unknown key id: %s", kid)
+		}
+
+		// 签名算法必须与密钥一致，防止算法混淆攻击
+		if token.Method.Alg() != key.Algorithm {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
 
-		return []byte(secret), nil
+		return key.Private.Public(), nil
 	})
 
 	if claims, ok := data.Claims.(*AuthClaims); ok && data.Valid {
@@ -56,3 +131,83 @@ func ParseToken(token string, secret string) (*AuthClaims, error) {
 
 	return nil, err
 }
+
+// JWKS 导出全部验签公钥
+func (s *KeySet) JWKS() *JWKS {
+	s.reload()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	items := make([]JWK, 0, len(s.items))
+	for _, key := range s.items {
+		items = append(items, key.JWK())
+	}
+
+	return &JWKS{Keys: items}
+}
+
+func (s *KeySet) signingKey() *Key {
+	s.reload()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.active
+}
+
+func (s *KeySet) verifyKey(kid string) *Key {
+	s.reload()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.keys[kid]
+}
+
+func (s *KeySet) reload() {
+	if s.dir == "" {
+		return
+	}
+
+	s.mu.RLock()
+	expired := time.Since(s.loadedAt) > reloadInterval
+	s.mu.RUnlock()
+
+	if !expired {
+		return
+	}
+
+	// 重新加载失败时继续使用已加载的密钥
+	if err := s.load(); err != nil {
+		logger.Errorf("加载 jwt 密钥目录失败 dir:%s err:%s", s.dir, err.Error())
+	}
+}
+
+func (s *KeySet) load() error {
+	items, active, err := LoadKeyDir(s.dir)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.loadedAt = time.Now()
+
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]*Key, len(items))
+	for _, key := range items {
+		keys[key.Id] = key
+	}
+
+	s.keys = keys
+	s.items = items
+	s.active = keys[active]
+
+	if active != "" && s.active == nil {
+		return fmt.Errorf("当前签名密钥 %s: %w", active, ErrKeyNotFound)
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package jwt
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func options(uid int) *Options {
+	return &Options{
+		ExpiresAt: NewNumericDate(time.Now().Add(time.Hour)),
+		ID:        strconv.Itoa(uid),
+		Issuer:    "im.web",
+		IssuedAt:  NewNumericDate(time.Now()),
+	}
+}
+
+func TestKeySetHmac(t *testing.T) {
+	set, err := NewKeySet("secret", "", time.Time{})
+	assert.NoError(t, err)
+
+	token := set.GenerateSessionToken("api", "sid", options(1))
+
+	claims, err := set.ParseToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "api", claims.Guard)
+	assert.Equal(t, "sid", claims.SessionId)
+	assert.Equal(t, "1", claims.ID)
+
+	other, _ := NewKeySet("other", "", time.Time{})
+	_, err = other.ParseToken(token)
+	assert.Error(t, err)
+}
+
+func TestKeySetRotation(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, alg := range []string{AlgRS256, AlgEdDSA} {
+		key, err := GenerateKey(alg)
+		assert.NoError(t, err)
+		assert.NoError(t, SaveKey(dir, key))
+		assert.NoError(t, SetActiveKey(dir, key.Id))
+
+		set, err := NewKeySet("secret", dir, time.Time{})
+		assert.NoError(t, err)
+
+		token := set.GenerateToken("api", options(2))
+
+		parsed, _, err := jwt.NewParser().ParseUnverified(token, &AuthClaims{})
+		assert.NoError(t, err)
+		assert.Equal(t, key.Id, parsed.Header["kid"])
+		assert.Equal(t, alg, parsed.Header["alg"])
+
+		claims, err := set.ParseToken(token)
+		assert.NoError(t, err)
+		assert.Equal(t, "2", claims.ID)
+	}
+
+	// 轮换后旧密钥签发的令牌仍可校验
+	keys, active, err := LoadKeyDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+
+	set, _ := NewKeySet("", dir, time.Time{})
+	assert.Len(t, set.JWKS().Keys, 2)
+	assert.Equal(t, active, set.signingKey().Id)
+
+	for _, key := range keys {
+		token := jwt.NewWithClaims(key.method(), AuthClaims{Guard: "api", RegisteredClaims: jwt.RegisteredClaims{ID: "3"}})
+		token.Header["kid"] = key.Id
+		value, _ := token.SignedString(key.Private)
+
+		_, err := set.ParseToken(value)
+		assert.NoError(t, err)
+	}
+
+	assert.ErrorIs(t, RemoveKey(dir, active), ErrKeyActive)
+	assert.NoError(t, RemoveKey(dir, keys[0].Id))
+	assert.ErrorIs(t, RemoveKey(dir, "../active"), ErrKeyId)
+}
+
+func TestKeySetRejectsAlgorithmConfusion(t *testing.T) {
+	dir := t.TempDir()
+
+	key, _ := GenerateKey(AlgRS256)
+	assert.NoError(t, SaveKey(dir, key))
+	assert.NoError(t, SetActiveKey(dir, key.Id))
+
+	set, err := NewKeySet("secret", dir, time.Time{})
+	assert.NoError(t, err)
+
+	// 使用公钥作为 HMAC 密钥伪造的令牌必须被拒绝
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, AuthClaims{Guard: "api"})
+	token.Header["kid"] = key.Id
+	value, _ := token.SignedString([]byte("secret"))
+
+	_, err = set.ParseToken(value)
+	assert.Error(t, err)
+
+	// 未知 kid 的令牌必须被拒绝
+	other, _ := GenerateKey(AlgEdDSA)
+	token = jwt.NewWithClaims(jwt.SigningMethodEdDSA, AuthClaims{Guard: "api"})
+	token.Header["kid"] = other.Id
+	value, _ = token.SignedString(other.Private)
+
+	_, err = set.ParseToken(value)
+	assert.Error(t, err)
+}
+
+func TestKeySetLegacySecret(t *testing.T) {
+	legacy, _ := NewKeySet("secret", "", time.Time{})
+	token := legacy.GenerateToken("api", options(4))
+
+	dir := t.TempDir()
+	key, _ := GenerateKey(AlgEdDSA)
+	assert.NoError(t, SaveKey(dir, key))
+	assert.NoError(t, SetActiveKey(dir, key.Id))
+
+	// 过渡期内仍接受共享密钥签发的令牌
+	set, err := NewKeySet("secret", dir, time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+
+	claims, err := set.ParseToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "4", claims.ID)
+
+	// 未配置过渡期或过渡期结束后，启用签名密钥时拒绝共享密钥签发的令牌
+	for _, until := range []time.Time{{}, time.Now().Add(-time.Hour)} {
+		set, err := NewKeySet("secret", dir, until)
+		assert.NoError(t, err)
+
+		_, err = set.ParseToken(token)
+		assert.Error(t, err)
+	}
+}
+
+func TestKeyJWK(t *testing.T) {
+	key, _ := GenerateKey(AlgEdDSA)
+
+	jwk := key.JWK()
+	assert.Equal(t, "OKP", jwk.Kty)
+	assert.Equal(t, "Ed25519", jwk.Crv)
+	assert.Equal(t, key.Id, jwk.Kid)
+	assert.NotEmpty(t, jwk.X)
+
+	data, err := key.EncodePEM()
+	assert.NoError(t, err)
+
+	parsed, err := ParseKey(key.Id, data)
+	assert.NoError(t, err)
+	assert.Equal(t, AlgEdDSA, parsed.Algorithm)
+	assert.Equal(t, jwk, parsed.JWK())
+}
@@ -0,0 +1,260 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+
+	activeFile = "active" // 密钥目录中记录当前签名密钥ID的文件
+)
+
+var (
+	ErrKeyAlgorithm = errors.New("不支持的签名算法，可选 RS256、EdDSA")
+	ErrKeyNotFound  = errors.New("签名密钥不存在")
+	ErrKeyActive    = errors.New("不能删除当前正在使用的签名密钥")
+	ErrKeyId        = errors.New("无效的密钥ID")
+)
+
+// Key 非对称签名密钥
+type Key struct {
+	Id        string        // 密钥ID，对应 JWT 头部的 kid
+	Algorithm string        // 签名算法[RS256;EdDSA;]
+	Private   crypto.Signer // 私钥
+	CreatedAt time.Time     // 创建时间
+}
+
+// JWK 公钥的 JSON Web Key 表示(RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// GenerateKey 生成签名密钥
+func GenerateKey(alg string) (*Key, error) {
+	var (
+		private crypto.Signer
+		err     error
+	)
+
+	switch alg {
+	case AlgRS256:
+		private, err = rsa.GenerateKey(rand.Reader, 2048)
+	case AlgEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, ErrKeyAlgorithm
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 4)
+	_, _ = rand.Read(buf)
+
+	now := time.Now()
+
+	return &Key{
+		Id:        fmt.Sprintf("%s-%s", now.Format("20060102"), hex.EncodeToString(buf)),
+		Algorithm: alg,
+		Private:   private,
+		CreatedAt: now,
+	}, nil
+}
+
+// ParseKey 解析 PKCS8 PEM 格式的私钥
+func ParseKey(kid string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("密钥 %s 不是有效的 PEM 文件", kid)
+	}
+
+	value, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("密钥 %s 解析失败: %w", kid, err)
+	}
+
+	key := &Key{Id: kid}
+	switch private := value.(type) {
+	case *rsa.PrivateKey:
+		key.Algorithm, key.Private = AlgRS256, private
+	case ed25519.PrivateKey:
+		key.Algorithm, key.Private = AlgEdDSA, private
+	default:
+		return nil, fmt.Errorf("密钥 %s: %w", kid, ErrKeyAlgorithm)
+	}
+
+	return key, nil
+}
+
+// EncodePEM 以 PKCS8 PEM 格式导出私钥
+func (k *Key) EncodePEM() ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(k.Private)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// JWK 导出公钥
+func (k *Key) JWK() JWK {
+	item := JWK{Use: "sig", Alg: k.Algorithm, Kid: k.Id}
+
+	switch public := k.Private.Public().(type) {
+	case *rsa.PublicKey:
+		item.Kty = "RSA"
+		item.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		item.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		item.Kty = "OKP"
+		item.Crv = "Ed25519"
+		item.X = base64.RawURLEncoding.EncodeToString(public)
+	}
+
+	return item
+}
+
+func (k *Key) method() jwt.SigningMethod {
+	if k.Algorithm == AlgEdDSA {
+		return jwt.SigningMethodEdDSA
+	}
+
+	return jwt.SigningMethodRS256
+}
+
+// LoadKeyDir 读取密钥目录，目录中每个 {kid}.pem 为一个私钥，active 文件记录当前签名密钥ID
+func LoadKeyDir(dir string) ([]*Key, string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, "", err
+	}
+
+	keys := make([]*Key, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, "", err
+		}
+
+		key, err := ParseKey(strings.TrimSuffix(filepath.Base(file), ".pem"), data)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if info, err := os.Stat(file); err == nil {
+			key.CreatedAt = info.ModTime()
+		}
+
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+
+	active, err := os.ReadFile(filepath.Join(dir, activeFile))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, "", err
+	}
+
+	return keys, strings.TrimSpace(string(active)), nil
+}
+
+// SaveKey 将私钥写入密钥目录
+func SaveKey(dir string, key *Key) error {
+	data, err := key.EncodePEM()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	tmp := filepath.Join(dir, key.Id+".pem.tmp")
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, filepath.Join(dir, key.Id+".pem"))
+}
+
+// SetActiveKey 设置当前签名密钥
+func SetActiveKey(dir string, kid string) error {
+	if !isValidKid(kid) {
+		return ErrKeyId
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, kid+".pem")); err != nil {
+		return ErrKeyNotFound
+	}
+
+	// 先写临时文件再重命名，避免服务读取到写了一半的内容
+	tmp := filepath.Join(dir, activeFile+".tmp")
+	if err := os.WriteFile(tmp, []byte(kid), 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, filepath.Join(dir, activeFile))
+}
+
+// RemoveKey 从密钥目录删除不再用于验签的密钥
+func RemoveKey(dir string, kid string) error {
+	if !isValidKid(kid) {
+		return ErrKeyId
+	}
+
+	_, active, err := LoadKeyDir(dir)
+	if err != nil {
+		return err
+	}
+
+	if kid == active {
+		return ErrKeyActive
+	}
+
+	if err := os.Remove(filepath.Join(dir, kid+".pem")); err != nil {
+		if os.IsNotExist(err) {
+			return ErrKeyNotFound
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func isValidKid(kid string) bool {
+	return kid != "" && kid != "." && kid != ".." && filepath.Base(kid) == kid
+}
@@ -0,0 +1,299 @@
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	ldapv3 "github.com/go-ldap/ldap/v3"
+)
+
+// 分页查询每页条数，需小于服务端的 sizelimit
+const pageSize = 500
+
+var (
+	ErrInvalidCredentials = errors.New("ldap: invalid credentials")
+	ErrUserNotFound       = errors.New("ldap: user not found")
+	ErrUserAmbiguous      = errors.New("ldap: multiple users matched")
+)
+
+// Config 目录服务连接配置
+type Config struct {
+	Addr               string     `json:"addr" yaml:"addr"`                                 // 服务地址，例如 ldap://127.0.0.1:389 或 ldaps://127.0.0.1:636
+	StartTLS           bool       `json:"start_tls" yaml:"start_tls"`                       // 是否使用 StartTLS 加密连接
+	InsecureSkipVerify bool       `json:"insecure_skip_verify" yaml:"insecure_skip_verify"` // 是否跳过证书校验，仅用于测试环境
+	BindDN             string     `json:"bind_dn" yaml:"bind_dn"`                           // 查询账号
+	BindPassword       string     `json:"bind_password" yaml:"bind_password"`               // 查询账号密码
+	BaseDN             string     `json:"base_dn" yaml:"base_dn"`                           // 用户查询的根节点
+	UserFilter         string     `json:"user_filter" yaml:"user_filter"`                   // 用户过滤条件，默认 (objectClass=person)
+	LoginAttrs         []string   `json:"login_attrs" yaml:"login_attrs"`                   // 可用于登录的属性，默认 uid
+	Attributes         Attributes `json:"attributes" yaml:"attributes"`
+	Timeout            int        `json:"timeout" yaml:"timeout"` // 超时时间(秒)，默认 10
+}
+
+// Attributes 目录属性与用户信息的映射
+type Attributes struct {
+	Id         string `json:"id" yaml:"id"`                 // 唯一标识，默认 uid，AD 可使用 objectGUID
+	Nickname   string `json:"nickname" yaml:"nickname"`     // 昵称，默认 cn
+	Email      string `json:"email" yaml:"email"`           // 邮箱，默认 mail
+	Mobile     string `json:"mobile" yaml:"mobile"`         // 手机号，默认 mobile
+	Department string `json:"department" yaml:"department"` // 部门，为空时按 DN 中的 OU 层级生成部门
+	Position   string `json:"position" yaml:"position"`     // 岗位，默认 title
+}
+
+// Entry 目录中的用户
+type Entry struct {
+	DN         string
+	Id         string
+	Nickname   string
+	Email      string
+	Mobile     string
+	Department string // 部门路径，多级部门以 / 分隔
+	Position   string
+}
+
+type Client struct {
+	config Config
+}
+
+func NewClient(conf Config) *Client {
+	if conf.UserFilter == "" {
+		conf.UserFilter = "(objectClass=person)"
+	}
+
+	if len(conf.LoginAttrs) == 0 {
+		conf.LoginAttrs = []string{"uid"}
+	}
+
+	if conf.Timeout <= 0 {
+		conf.Timeout = 10
+	}
+
+	attrs := &conf.Attributes
+	attrs.Id = withDefault(attrs.Id, "uid")
+	attrs.Nickname = withDefault(attrs.Nickname, "cn")
+	attrs.Email = withDefault(attrs.Email, "mail")
+	attrs.Mobile = withDefault(attrs.Mobile, "mobile")
+	attrs.Position = withDefault(attrs.Position, "title")
+
+	return &Client{config: conf}
+}
+
+// Authenticate 使用账号密码认证，账号可以是任意一个登录属性的值
+func (c *Client) Authenticate(ctx context.Context, account string, password string) (*Entry, error) {
+	// 空密码会被服务端视为匿名绑定而直接成功，必须拒绝
+	if account == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Close()
+
+	result, err := conn.Search(c.searchRequest(c.loginFilter(account), 2))
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(result.Entries) {
+	case 0:
+		return nil, ErrUserNotFound
+	case 1:
+	default:
+		return nil, ErrUserAmbiguous
+	}
+
+	entry := result.Entries[0]
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if ldapv3.IsErrorWithCode(err, ldapv3.LDAPResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+
+		return nil, err
+	}
+
+	return c.entry(entry), nil
+}
+
+// Users 查询目录中的全部用户
+func (c *Client) Users(ctx context.Context) ([]*Entry, error) {
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Close()
+
+	result, err := conn.SearchWithPaging(c.searchRequest(c.config.UserFilter, 0), pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*Entry, 0, len(result.Entries))
+	for _, entry := range result.Entries {
+		if item := c.entry(entry); item.Id != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items, nil
+}
+
+// 建立连接并使用查询账号绑定
+func (c *Client) dial(ctx context.Context) (*ldapv3.Conn, error) {
+	timeout := time.Duration(c.config.Timeout) * time.Second
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < timeout {
+		timeout = time.Until(deadline)
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: c.config.InsecureSkipVerify}
+	if host, _, err := net.SplitHostPort(strings.TrimPrefix(strings.TrimPrefix(c.config.Addr, "ldaps://"), "ldap://")); err == nil {
+		tlsConfig.ServerName = host
+	}
+
+	conn, err := ldapv3.DialURL(c.config.Addr,
+		ldapv3.DialWithDialer(&net.Dialer{Timeout: timeout}),
+		ldapv3.DialWithTLSConfig(tlsConfig),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	conn.SetTimeout(timeout)
+
+	if c.config.StartTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	if c.config.BindDN != "" {
+		if err := conn.Bind(c.config.BindDN, c.config.BindPassword); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("ldap: bind %s: %w", c.config.BindDN, err)
+		}
+	}
+
+	return conn, nil
+}
+
+func (c *Client) searchRequest(filter string, sizeLimit int) *ldapv3.SearchRequest {
+	attrs := c.config.Attributes
+
+	names := []string{attrs.Id, attrs.Nickname, attrs.Email, attrs.Mobile, attrs.Position}
+	if attrs.Department != "" {
+		names = append(names, attrs.Department)
+	}
+
+	return ldapv3.NewSearchRequest(
+		c.config.BaseDN,
+		ldapv3.ScopeWholeSubtree,
+		ldapv3.NeverDerefAliases,
+		sizeLimit,
+		c.config.Timeout,
+		false,
+		filter,
+		names,
+		nil,
+	)
+}
+
+// 生成登录查询条件，如 (&(objectClass=person)(|(uid=xx)(mobile=xx)))
+func (c *Client) loginFilter(account string) string {
+	value := ldapv3.EscapeFilter(account)
+
+	var sb strings.Builder
+	sb.WriteString("(&")
+	sb.WriteString(c.config.UserFilter)
+
+	if len(c.config.LoginAttrs) > 1 {
+		sb.WriteString("(|")
+	}
+
+	for _, attr := range c.config.LoginAttrs {
+		sb.WriteString(fmt.Sprintf("(%s=%s)", attr, value))
+	}
+
+	if len(c.config.LoginAttrs) > 1 {
+		sb.WriteString(")")
+	}
+
+	sb.WriteString(")")
+	return sb.String()
+}
+
+func (c *Client) entry(entry *ldapv3.Entry) *Entry {
+	attrs := c.config.Attributes
+
+	item := &Entry{
+		DN:       entry.DN,
+		Id:       attributeId(entry.GetRawAttributeValue(attrs.Id)),
+		Nickname: strings.TrimSpace(entry.GetAttributeValue(attrs.Nickname)),
+		Email:    strings.TrimSpace(entry.GetAttributeValue(attrs.Email)),
+		Mobile:   strings.TrimSpace(entry.GetAttributeValue(attrs.Mobile)),
+		Position: strings.TrimSpace(entry.GetAttributeValue(attrs.Position)),
+	}
+
+	if attrs.Department != "" {
+		item.Department = strings.TrimSpace(entry.GetAttributeValue(attrs.Department))
+	} else {
+		item.Department = DepartmentFromDN(entry.DN, c.config.BaseDN)
+	}
+
+	return item
+}
+
+// DepartmentFromDN 按 DN 中根节点以下的 OU 层级生成部门路径
+//
+// 例如 uid=alice,ou=后端组,ou=研发中心,dc=example,dc=com 生成 研发中心/后端组
+func DepartmentFromDN(dn string, baseDN string) string {
+	parsed, err := ldapv3.ParseDN(dn)
+	if err != nil {
+		return ""
+	}
+
+	base, err := ldapv3.ParseDN(baseDN)
+	if err != nil {
+		base = &ldapv3.DN{}
+	}
+
+	names := make([]string, 0)
+
+	// 跳过第一个 RDN(用户自身)及根节点部分
+	for i := len(parsed.RDNs) - len(base.RDNs) - 1; i >= 1; i-- {
+		for _, attr := range parsed.RDNs[i].Attributes {
+			if strings.EqualFold(attr.Type, "ou") {
+				names = append(names, attr.Value)
+			}
+		}
+	}
+
+	return strings.Join(names, "/")
+}
+
+// 二进制标识(如 AD 的 objectGUID)转换为十六进制字符串
+func attributeId(value []byte) string {
+	if utf8.Valid(value) {
+		return strings.TrimSpace(string(value))
+	}
+
+	return hex.EncodeToString(value)
+}
+
+func withDefault(value string, def string) string {
+	if value == "" {
+		return def
+	}
+
+	return value
+}
@@ -0,0 +1,57 @@
+package ldap
+
+import (
+	"testing"
+
+	ldapv3 "github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDepartmentFromDN(t *testing.T) {
+	base := "dc=example,dc=com"
+
+	assert.Equal(t, "研发中心/后端组", DepartmentFromDN("uid=alice,ou=后端组,ou=研发中心,dc=example,dc=com", base))
+	assert.Equal(t, "People", DepartmentFromDN("uid=bob,ou=People,dc=example,dc=com", base))
+	assert.Equal(t, "", DepartmentFromDN("uid=root,dc=example,dc=com", base))
+	assert.Equal(t, "Sales", DepartmentFromDN("CN=Carol,OU=Sales,DC=example,DC=com", base))
+	assert.Equal(t, "", DepartmentFromDN("invalid", base))
+}
+
+func TestLoginFilter(t *testing.T) {
+	client := NewClient(Config{})
+	assert.Equal(t, "(&(objectClass=person)(uid=alice))", client.loginFilter("alice"))
+
+	client = NewClient(Config{UserFilter: "(objectClass=inetOrgPerson)", LoginAttrs: []string{"uid", "mobile"}})
+	assert.Equal(t, "(&(objectClass=inetOrgPerson)(|(uid=13800000000)(mobile=13800000000)))", client.loginFilter("13800000000"))
+
+	// 账号中的特殊字符必须转义，防止注入查询条件
+	assert.Equal(t, `(&(objectClass=inetOrgPerson)(|(uid=\2a\29\28uid=\2a)(mobile=\2a\29\28uid=\2a)))`, client.loginFilter("*)(uid=*"))
+}
+
+func TestEntry(t *testing.T) {
+	client := NewClient(Config{BaseDN: "dc=example,dc=com"})
+
+	entry := client.entry(ldapv3.NewEntry("uid=alice,ou=后端组,ou=研发中心,dc=example,dc=com", map[string][]string{
+		"uid":    {"alice"},
+		"cn":     {"Alice"},
+		"mail":   {"alice@example.com"},
+		"mobile": {"13800000000"},
+		"title":  {"工程师"},
+	}))
+
+	assert.Equal(t, "alice", entry.Id)
+	assert.Equal(t, "Alice", entry.Nickname)
+	assert.Equal(t, "alice@example.com", entry.Email)
+	assert.Equal(t, "13800000000", entry.Mobile)
+	assert.Equal(t, "研发中心/后端组", entry.Department)
+	assert.Equal(t, "工程师", entry.Position)
+
+	client = NewClient(Config{Attributes: Attributes{Id: "objectGUID", Department: "department"}})
+	entry = client.entry(ldapv3.NewEntry("CN=Bob,OU=Sales,DC=example,DC=com", map[string][]string{
+		"objectGUID": {string([]byte{0xff, 0x01, 0x9a})},
+		"department": {"销售部"},
+	}))
+
+	assert.Equal(t, "ff019a", entry.Id)
+	assert.Equal(t, "销售部", entry.Department)
+}
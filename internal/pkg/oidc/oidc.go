@@ -0,0 +1,376 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	maxResponseSize = 1 << 20          // 响应内容最大读取 1MB
+	keysMinInterval = 10 * time.Second // 遇到未知 kid 时重新拉取公钥的最小间隔
+)
+
+var (
+	ErrIdTokenInvalid = errors.New("oidc: id_token invalid")
+	ErrNonceMismatch  = errors.New("oidc: nonce mismatch")
+	ErrSubjectInvalid = errors.New("oidc: userinfo subject mismatch")
+)
+
+// Config 身份提供方(IdP)的客户端配置
+type Config struct {
+	Issuer       string   // 颁发者地址，用于服务发现及校验 id_token 的 iss
+	ClientId     string   // 客户端ID
+	ClientSecret string   // 客户端密钥
+	RedirectUrl  string   // 授权回调地址
+	Scopes       []string // 授权范围，默认 openid profile email
+}
+
+// Discovery 服务发现文档 /.well-known/openid-configuration
+type Discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JwksUri               string `json:"jwks_uri"`
+}
+
+// Token 授权码换取的令牌
+type Token struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IdToken     string `json:"id_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// Claims 用户身份信息
+type Claims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Picture       string
+	Raw           map[string]any // 全部声明，包含自定义声明
+}
+
+// String 读取字符串类型的声明，支持 a.b 形式读取嵌套声明，数组取第一个元素
+func (c *Claims) String(name string) string {
+	var value any = c.Raw
+	for _, key := range strings.Split(name, ".") {
+		data, ok := value.(map[string]any)
+		if !ok {
+			return ""
+		}
+
+		value = data[key]
+	}
+
+	if items, ok := value.([]any); ok && len(items) > 0 {
+		value = items[0]
+	}
+
+	switch val := value.(type) {
+	case string:
+		return strings.TrimSpace(val)
+	case float64:
+		return fmt.Sprintf("%v", val)
+	}
+
+	return ""
+}
+
+// Provider OIDC 授权码模式(PKCE)客户端
+type Provider struct {
+	config Config
+	client *http.Client
+
+	mu        sync.Mutex
+	discovery *Discovery
+	keys      map[string]any
+	keysAt    time.Time
+}
+
+func NewProvider(conf Config, client *http.Client) *Provider {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	if len(conf.Scopes) == 0 {
+		conf.Scopes = []string{"openid", "profile", "email"}
+	}
+
+	return &Provider{config: conf, client: client}
+}
+
+// Discover 获取服务发现文档，成功后缓存
+func (p *Provider) Discover(ctx context.Context) (*Discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	var doc Discovery
+	if err := p.get(ctx, strings.TrimSuffix(p.config.Issuer, "/")+"/.well-known/openid-configuration", "", &doc); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSuffix(doc.Issuer, "/") != strings.TrimSuffix(p.config.Issuer, "/") {
+		return nil, fmt.Errorf("oidc: issuer mismatch, expected %s got %s", p.config.Issuer, doc.Issuer)
+	}
+
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.JwksUri == "" {
+		return nil, errors.New("oidc: incomplete discovery document")
+	}
+
+	p.discovery = &doc
+	return p.discovery, nil
+}
+
+// AuthCodeURL 生成授权地址
+func (p *Provider) AuthCodeURL(ctx context.Context, state string, nonce string, verifier string) (string, error) {
+	doc, err := p.Discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	values := url.Values{}
+	values.Set("response_type", "code")
+	values.Set("client_id", p.config.ClientId)
+	values.Set("redirect_uri", p.config.RedirectUrl)
+	values.Set("scope", strings.Join(p.config.Scopes, " "))
+	values.Set("state", state)
+	values.Set("nonce", nonce)
+	values.Set("code_challenge", Challenge(verifier))
+	values.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(doc.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+
+	return doc.AuthorizationEndpoint + sep + values.Encode(), nil
+}
+
+// Exchange 使用授权码及 PKCE 校验码换取令牌
+func (p *Provider) Exchange(ctx context.Context, code string, verifier string) (*Token, error) {
+	doc, err := p.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	values := url.Values{}
+	values.Set("grant_type", "authorization_code")
+	values.Set("code", code)
+	values.Set("redirect_uri", p.config.RedirectUrl)
+	values.Set("code_verifier", verifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, doc.TokenEndpoint, strings.NewReader(values.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.config.ClientId), url.QueryEscape(p.config.ClientSecret))
+
+	var token Token
+	if err := p.do(req, &token); err != nil {
+		return nil, err
+	}
+
+	if token.IdToken == "" {
+		return nil, errors.New("oidc: token response missing id_token")
+	}
+
+	return &token, nil
+}
+
+// VerifyIdToken 校验 id_token 的签名、颁发者、受众、有效期及 nonce
+func (p *Provider) VerifyIdToken(ctx context.Context, raw string, nonce string) (*Claims, error) {
+	if _, err := p.Discover(ctx); err != nil {
+		return nil, err
+	}
+
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{"RS256", "ES256", "EdDSA"}))
+
+	data := jwt.MapClaims{}
+	token, err := parser.ParseWithClaims(raw, data, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.publicKey(ctx, kid)
+	})
+
+	if err != nil || !token.Valid {
+		return nil, fmt.Errorf("%w: %v", ErrIdTokenInvalid, err)
+	}
+
+	if iss, _ := data["iss"].(string); strings.TrimSuffix(iss, "/") != strings.TrimSuffix(p.config.Issuer, "/") {
+		return nil, fmt.Errorf("%w: issuer mismatch", ErrIdTokenInvalid)
+	}
+
+	if !data.VerifyAudience(p.config.ClientId, true) {
+		return nil, fmt.Errorf("%w: audience mismatch", ErrIdTokenInvalid)
+	}
+
+	if _, ok := data["exp"]; !ok {
+		return nil, fmt.Errorf("%w: missing exp", ErrIdTokenInvalid)
+	}
+
+	if value, _ := data["nonce"].(string); value != nonce {
+		return nil, ErrNonceMismatch
+	}
+
+	claims := newClaims(data)
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing sub", ErrIdTokenInvalid)
+	}
+
+	return claims, nil
+}
+
+// UserInfo 获取用户信息，并合并到 id_token 的声明中
+func (p *Provider) UserInfo(ctx context.Context, accessToken string, claims *Claims) (*Claims, error) {
+	doc, err := p.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if doc.UserinfoEndpoint == "" {
+		return claims, nil
+	}
+
+	data := make(map[string]any)
+	if err := p.get(ctx, doc.UserinfoEndpoint, accessToken, &data); err != nil {
+		return nil, err
+	}
+
+	// 用户信息必须属于 id_token 对应的用户，防止令牌替换
+	if sub, _ := data["sub"].(string); sub != claims.Subject {
+		return nil, ErrSubjectInvalid
+	}
+
+	raw := make(map[string]any, len(claims.Raw)+len(data))
+	for key, value := range claims.Raw {
+		raw[key] = value
+	}
+
+	for key, value := range data {
+		raw[key] = value
+	}
+
+	return newClaims(raw), nil
+}
+
+// 根据 kid 查找验签公钥，未找到时重新拉取 JWKS 以支持 IdP 轮换密钥
+func (p *Provider) publicKey(ctx context.Context, kid string) (any, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.lookup(kid); ok {
+		return key, nil
+	}
+
+	if time.Since(p.keysAt) < keysMinInterval {
+		return nil, fmt.Errorf("oidc: unknown key id %q", kid)
+	}
+
+	var set jsonWebKeySet
+	if err := p.get(ctx, p.discovery.JwksUri, "", &set); err != nil {
+		return nil, err
+	}
+
+	p.keys = set.publicKeys()
+	p.keysAt = time.Now()
+
+	if key, ok := p.lookup(kid); ok {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("oidc: unknown key id %q", kid)
+}
+
+func (p *Provider) lookup(kid string) (any, bool) {
+	// 未携带 kid 时仅在 IdP 只有一个密钥的情况下使用该密钥
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+func (p *Provider) get(ctx context.Context, uri string, accessToken string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+
+	return p.do(req, out)
+}
+
+func (p *Provider) do(req *http.Request, out any) error {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("oidc: %s %s status %d: %s", req.Method, req.URL.Path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return json.Unmarshal(body, out)
+}
+
+func newClaims(data map[string]any) *Claims {
+	claims := &Claims{Raw: data}
+	claims.Subject, _ = data["sub"].(string)
+	claims.Email, _ = data["email"].(string)
+	claims.Name, _ = data["name"].(string)
+	claims.Picture, _ = data["picture"].(string)
+
+	// 部分 IdP 以字符串形式返回 email_verified
+	switch value := data["email_verified"].(type) {
+	case bool:
+		claims.EmailVerified = value
+	case string:
+		claims.EmailVerified = value == "true"
+	}
+
+	if claims.Name == "" {
+		claims.Name, _ = data["preferred_username"].(string)
+	}
+
+	return claims
+}
+
+// Providers 按标识索引的身份提供方
+type Providers map[string]*Provider
+
+// Get 获取身份提供方，不存在时返回 nil
+func (p Providers) Get(name string) *Provider {
+	return p[name]
+}
@@ -0,0 +1,139 @@
+package oidc_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"go-chat/internal/pkg/oidc"
+	"go-chat/internal/pkg/oidc/oidctest"
+)
+
+func newProvider(t *testing.T) (*oidctest.Server, *oidc.Provider) {
+	idp := oidctest.NewServer("lumenim", "secret")
+	t.Cleanup(idp.Close)
+
+	provider := oidc.NewProvider(oidc.Config{
+		Issuer:       idp.Issuer(),
+		ClientId:     "lumenim",
+		ClientSecret: "secret",
+		RedirectUrl:  "http://127.0.0.1/auth/oidc/callback",
+	}, nil)
+
+	return idp, provider
+}
+
+func login(t *testing.T, idp *oidctest.Server, provider *oidc.Provider, verifier string) (string, string) {
+	authURL, err := provider.AuthCodeURL(context.Background(), "state-1", "nonce-1", verifier)
+	assert.NoError(t, err)
+
+	uri, _ := url.Parse(authURL)
+	assert.Equal(t, "S256", uri.Query().Get("code_challenge_method"))
+	assert.Equal(t, oidc.Challenge(verifier), uri.Query().Get("code_challenge"))
+
+	code, state, err := idp.Login(authURL)
+	assert.NoError(t, err)
+	assert.Equal(t, "state-1", state)
+
+	return code, state
+}
+
+func TestProviderLogin(t *testing.T) {
+	idp, provider := newProvider(t)
+	idp.SetClaims(map[string]any{
+		"sub":            "u-1001",
+		"email":          "alice@example.com",
+		"email_verified": true,
+		"name":           "Alice",
+		"org":            map[string]any{"dept": []any{"研发中心/后端组"}},
+	})
+
+	ctx := context.Background()
+	verifier := oidc.NewVerifier()
+	code, _ := login(t, idp, provider, verifier)
+
+	token, err := provider.Exchange(ctx, code, verifier)
+	assert.NoError(t, err)
+
+	claims, err := provider.VerifyIdToken(ctx, token.IdToken, "nonce-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "u-1001", claims.Subject)
+	assert.Equal(t, "alice@example.com", claims.Email)
+	assert.True(t, claims.EmailVerified)
+	assert.Equal(t, "研发中心/后端组", claims.String("org.dept"))
+
+	claims, err = provider.UserInfo(ctx, token.AccessToken, claims)
+	assert.NoError(t, err)
+	assert.Equal(t, "Alice", claims.Name)
+
+	// 授权码只能使用一次
+	_, err = provider.Exchange(ctx, code, verifier)
+	assert.Error(t, err)
+}
+
+func TestProviderRejectsWrongVerifier(t *testing.T) {
+	idp, provider := newProvider(t)
+
+	code, _ := login(t, idp, provider, oidc.NewVerifier())
+
+	_, err := provider.Exchange(context.Background(), code, oidc.NewVerifier())
+	assert.Error(t, err)
+}
+
+func TestProviderVerifyIdToken(t *testing.T) {
+	idp, provider := newProvider(t)
+	ctx := context.Background()
+
+	exchange := func() string {
+		verifier := oidc.NewVerifier()
+		code, _ := login(t, idp, provider, verifier)
+
+		token, err := provider.Exchange(ctx, code, verifier)
+		assert.NoError(t, err)
+		return token.IdToken
+	}
+
+	_, err := provider.VerifyIdToken(ctx, exchange(), "other")
+	assert.ErrorIs(t, err, oidc.ErrNonceMismatch)
+
+	idp.SetIdTokenHook(func(claims jwt.MapClaims) { claims["aud"] = "other-client" })
+	_, err = provider.VerifyIdToken(ctx, exchange(), "nonce-1")
+	assert.ErrorIs(t, err, oidc.ErrIdTokenInvalid)
+
+	idp.SetIdTokenHook(func(claims jwt.MapClaims) { claims["iss"] = "https://evil.example.com" })
+	_, err = provider.VerifyIdToken(ctx, exchange(), "nonce-1")
+	assert.ErrorIs(t, err, oidc.ErrIdTokenInvalid)
+
+	idp.SetIdTokenHook(func(claims jwt.MapClaims) { claims["exp"] = time.Now().Add(-time.Minute).Unix() })
+	_, err = provider.VerifyIdToken(ctx, exchange(), "nonce-1")
+	assert.ErrorIs(t, err, oidc.ErrIdTokenInvalid)
+
+	idp.SetIdTokenHook(nil)
+	_, err = provider.VerifyIdToken(ctx, exchange(), "nonce-1")
+	assert.NoError(t, err)
+}
+
+func TestProviderKeyRotation(t *testing.T) {
+	idp, provider := newProvider(t)
+	ctx := context.Background()
+
+	verifier := oidc.NewVerifier()
+	code, _ := login(t, idp, provider, verifier)
+	token, _ := provider.Exchange(ctx, code, verifier)
+
+	_, err := provider.VerifyIdToken(ctx, token.IdToken, "nonce-1")
+	assert.NoError(t, err)
+
+	// 公钥刚拉取过，短时间内不会因未知 kid 重复请求 JWKS
+	idp.RotateKey()
+
+	verifier = oidc.NewVerifier()
+	code, _ = login(t, idp, provider, verifier)
+	token, _ = provider.Exchange(ctx, code, verifier)
+
+	_, err = provider.VerifyIdToken(ctx, token.IdToken, "nonce-1")
+	assert.Error(t, err)
+}
@@ -0,0 +1,25 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// NewVerifier 生成 PKCE 校验码(RFC 7636)
+func NewVerifier() string {
+	return RandomString(32)
+}
+
+// Challenge 计算 S256 方式的 PKCE 挑战码
+func Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// RandomString 生成 URL 安全的随机字符串，用于 state 及 nonce
+func RandomString(n int) string {
+	buf := make([]byte, n)
+	_, _ = rand.Read(buf)
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
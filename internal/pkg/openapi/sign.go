@@ -0,0 +1,109 @@
+package openapi
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	HeaderAppKey    = "X-App-Key"   // 应用标识
+	HeaderTimestamp = "X-Timestamp" // 请求时间戳(秒)
+	HeaderNonce     = "X-Nonce"     // 请求随机串
+	HeaderSignature = "X-Signature" // 请求签名
+)
+
+var (
+	ErrSignatureMissing = errors.New("缺少签名参数")
+	ErrSignatureInvalid = errors.New("签名校验失败")
+	ErrSignatureExpired = errors.New("请求已过期")
+)
+
+// NewAppKey 生成应用标识
+func NewAppKey() string {
+	return "ak_" + random(8)
+}
+
+// NewAppSecret 生成应用密钥
+func NewAppSecret() string {
+	return random(24)
+}
+
+func random(n int) string {
+	buf := make([]byte, n)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// Payload 待签名的请求信息
+type Payload struct {
+	Method    string
+	Path      string
+	Query     string // 原始查询字符串
+	Timestamp int64
+	Nonce     string
+	Body      []byte
+}
+
+// String 返回待签名字符串
+//
+//	METHOD\nPATH\nQUERY\nTIMESTAMP\nNONCE\nhex(SHA256(BODY))
+func (p *Payload) String() string {
+	hash := sha256.Sum256(p.Body)
+
+	return strings.Join([]string{
+		strings.ToUpper(p.Method),
+		p.Path,
+		p.Query,
+		strconv.FormatInt(p.Timestamp, 10),
+		p.Nonce,
+		hex.EncodeToString(hash[:]),
+	}, "\n")
+}
+
+// Sign 计算签名 hex(HMAC-SHA256(secret, payload))
+func Sign(secret string, payload *Payload) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload.String()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 校验签名，tolerance 为允许的时间误差
+func Verify(secret string, payload *Payload, signature string, tolerance time.Duration) error {
+	if payload.Nonce == "" || signature == "" {
+		return ErrSignatureMissing
+	}
+
+	if !hmac.Equal([]byte(Sign(secret, payload)), []byte(strings.ToLower(signature))) {
+		return ErrSignatureInvalid
+	}
+
+	if diff := time.Since(time.Unix(payload.Timestamp, 0)); diff > tolerance || diff < -tolerance {
+		return ErrSignatureExpired
+	}
+
+	return nil
+}
+
+// SignRequest 为请求添加签名请求头，body 需与请求实际发送的内容一致
+func SignRequest(req *http.Request, appKey string, secret string, body []byte) {
+	payload := &Payload{
+		Method:    req.Method,
+		Path:      req.URL.Path,
+		Query:     req.URL.RawQuery,
+		Timestamp: time.Now().Unix(),
+		Nonce:     random(8),
+		Body:      body,
+	}
+
+	req.Header.Set(HeaderAppKey, appKey)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(payload.Timestamp, 10))
+	req.Header.Set(HeaderNonce, payload.Nonce)
+	req.Header.Set(HeaderSignature, Sign(secret, payload))
+}
@@ -0,0 +1,60 @@
+package openapi
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	payload := &Payload{
+		Method:    http.MethodPost,
+		Path:      "/open/v1/message/send",
+		Timestamp: time.Now().Unix(),
+		Nonce:     "abc",
+		Body:      []byte(`{"content":"hello"}`),
+	}
+
+	signature := Sign("secret", payload)
+
+	assert.NoError(t, Verify("secret", payload, signature, time.Minute))
+	assert.ErrorIs(t, Verify("other", payload, signature, time.Minute), ErrSignatureInvalid)
+	assert.ErrorIs(t, Verify("secret", payload, "", time.Minute), ErrSignatureMissing)
+
+	tampered := *payload
+	tampered.Body = []byte(`{"content":"hacked"}`)
+	assert.ErrorIs(t, Verify("secret", &tampered, signature, time.Minute), ErrSignatureInvalid)
+
+	tampered = *payload
+	tampered.Query = "group_id=2"
+	assert.ErrorIs(t, Verify("secret", &tampered, signature, time.Minute), ErrSignatureInvalid)
+
+	expired := *payload
+	expired.Timestamp -= 600
+	assert.ErrorIs(t, Verify("secret", &expired, Sign("secret", &expired), time.Minute), ErrSignatureExpired)
+}
+
+func TestSignRequest(t *testing.T) {
+	body := []byte(`{"talk_mode":2}`)
+
+	req := httptest.NewRequest(http.MethodPost, "/open/v1/message/send?debug=1", bytes.NewReader(body))
+	SignRequest(req, NewAppKey(), "secret", body)
+
+	timestamp, _ := strconv.ParseInt(req.Header.Get(HeaderTimestamp), 10, 64)
+
+	err := Verify("secret", &Payload{
+		Method:    req.Method,
+		Path:      req.URL.Path,
+		Query:     req.URL.RawQuery,
+		Timestamp: timestamp,
+		Nonce:     req.Header.Get(HeaderNonce),
+		Body:      body,
+	}, req.Header.Get(HeaderSignature), time.Minute)
+
+	assert.NoError(t, err)
+}
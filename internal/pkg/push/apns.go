@@ -0,0 +1,184 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	ApnsEndpointProduction = "https://api.push.apple.com"
+	ApnsEndpointSandbox    = "https://api.sandbox.push.apple.com"
+
+	// 认证令牌有效期为 1 小时，且刷新间隔不能小于 20 分钟
+	apnsTokenTTL = 50 * time.Minute
+)
+
+// APNs 返回以下原因时设备令牌已失效
+var apnsInvalidReasons = map[string]struct{}{
+	"BadDeviceToken":         {},
+	"Unregistered":           {},
+	"DeviceTokenNotForTopic": {},
+}
+
+type ApnsConfig struct {
+	Endpoint   string // 接口地址，默认为生产环境
+	KeyId      string // 认证密钥ID
+	TeamId     string // 开发者团队ID
+	Topic      string // 应用的 Bundle ID
+	PrivateKey []byte // 认证密钥(.p8 文件内容)
+}
+
+// Apns 基于令牌认证的 APNs HTTP/2 推送
+type Apns struct {
+	conf   ApnsConfig
+	client *http.Client
+	key    *ecdsa.PrivateKey
+
+	mu       sync.Mutex
+	token    string
+	issuedAt time.Time
+}
+
+// NewApns client 为空时使用支持 HTTP/2 的默认客户端
+func NewApns(conf ApnsConfig, client *http.Client) (*Apns, error) {
+	key, err := jwt.ParseECPrivateKeyFromPEM(conf.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if conf.KeyId == "" || conf.TeamId == "" || conf.Topic == "" {
+		return nil, errors.New("push: apns key_id, team_id and topic are required")
+	}
+
+	if conf.Endpoint == "" {
+		conf.Endpoint = ApnsEndpointProduction
+	}
+
+	if client == nil {
+		client = &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: &http.Transport{ForceAttemptHTTP2: true},
+		}
+	}
+
+	return &Apns{conf: conf, client: client, key: key}, nil
+}
+
+type apnsAlert struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body"`
+}
+
+type apnsAps struct {
+	Alert apnsAlert `json:"alert"`
+	Badge int       `json:"badge"`
+	Sound string    `json:"sound"`
+}
+
+func (a *Apns) Send(ctx context.Context, n *Notification) error {
+	payload := map[string]any{
+		"aps": apnsAps{
+			Alert: apnsAlert{Title: n.Title, Body: n.Body},
+			Badge: n.Badge,
+			Sound: "default",
+		},
+	}
+
+	for k, v := range n.Data {
+		if k != "aps" {
+			payload[k] = v
+		}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	token, err := a.authToken()
+	if err != nil {
+		return err
+	}
+
+	url := strings.TrimRight(a.conf.Endpoint, "/") + "/3/device/" + n.Token
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "bearer "+token)
+	req.Header.Set("apns-topic", a.conf.Topic)
+	req.Header.Set("apns-push-type", "alert")
+	req.Header.Set("apns-priority", "10")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	data := readBody(resp)
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var result struct {
+		Reason string `json:"reason"`
+	}
+	_ = json.Unmarshal(data, &result)
+
+	if _, ok := apnsInvalidReasons[result.Reason]; ok || resp.StatusCode == http.StatusGone {
+		return ErrInvalidToken
+	}
+
+	// 认证令牌过期或被拒绝时，下次发送重新生成
+	if resp.StatusCode == http.StatusForbidden {
+		a.resetToken()
+	}
+
+	return &Error{StatusCode: resp.StatusCode, Reason: result.Reason}
+}
+
+// 生成或复用认证令牌
+func (a *Apns) authToken() (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token != "" && time.Since(a.issuedAt) < apnsTokenTTL {
+		return a.token, nil
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": a.conf.TeamId,
+		"iat": now.Unix(),
+	})
+	token.Header["kid"] = a.conf.KeyId
+
+	signed, err := token.SignedString(a.key)
+	if err != nil {
+		return "", err
+	}
+
+	a.token, a.issuedAt = signed, now
+
+	return signed, nil
+}
+
+func (a *Apns) resetToken() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.token = ""
+}
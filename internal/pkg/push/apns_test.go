@@ -0,0 +1,109 @@
+package push
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newApnsKey(t *testing.T) (*ecdsa.PrivateKey, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	return key, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func TestApns_Send(t *testing.T) {
+	key, data := newApnsKey(t)
+
+	var (
+		tokens  []string
+		payload map[string]any
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "com.example.app", r.Header.Get("apns-topic"))
+		assert.Equal(t, "alert", r.Header.Get("apns-push-type"))
+
+		auth := strings.TrimPrefix(r.Header.Get("Authorization"), "bearer ")
+		token, err := jwt.Parse(auth, func(token *jwt.Token) (any, error) {
+			assert.Equal(t, "KEY123", token.Header["kid"])
+			return &key.PublicKey, nil
+		})
+		if err != nil || !token.Valid {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"reason":"InvalidProviderToken"}`))
+			return
+		}
+
+		tokens = append(tokens, auth)
+
+		switch strings.TrimPrefix(r.URL.Path, "/3/device/") {
+		case "unregistered":
+			w.WriteHeader(http.StatusGone)
+			_, _ = w.Write([]byte(`{"reason":"Unregistered"}`))
+		case "bad":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"reason":"BadDeviceToken"}`))
+		case "busy":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"reason":"ServiceUnavailable"}`))
+		default:
+			_ = json.NewDecoder(r.Body).Decode(&payload)
+		}
+	}))
+	defer server.Close()
+
+	apns, err := NewApns(ApnsConfig{
+		Endpoint:   server.URL,
+		KeyId:      "KEY123",
+		TeamId:     "TEAM123",
+		Topic:      "com.example.app",
+		PrivateKey: data,
+	}, server.Client())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	err = apns.Send(ctx, &Notification{Token: "device", Title: "Alice", Body: "hello", Badge: 3, Data: map[string]string{"talk_mode": "1"}})
+	require.NoError(t, err)
+
+	aps := payload["aps"].(map[string]any)
+	assert.Equal(t, float64(3), aps["badge"])
+	assert.Equal(t, "hello", aps["alert"].(map[string]any)["body"])
+	assert.Equal(t, "1", payload["talk_mode"])
+
+	assert.ErrorIs(t, apns.Send(ctx, &Notification{Token: "unregistered"}), ErrInvalidToken)
+	assert.ErrorIs(t, apns.Send(ctx, &Notification{Token: "bad"}), ErrInvalidToken)
+
+	err = apns.Send(ctx, &Notification{Token: "busy"})
+	assert.True(t, IsTemporary(err))
+
+	// 认证令牌在有效期内复用
+	assert.Len(t, tokens, 4)
+	assert.Equal(t, tokens[0], tokens[3])
+}
+
+func TestNewApns(t *testing.T) {
+	_, err := NewApns(ApnsConfig{KeyId: "KEY123", TeamId: "TEAM123", Topic: "com.example.app", PrivateKey: []byte("invalid")}, nil)
+	assert.Error(t, err)
+
+	_, data := newApnsKey(t)
+	_, err = NewApns(ApnsConfig{PrivateKey: data}, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,220 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	FcmEndpoint = "https://fcm.googleapis.com"
+	FcmTokenUri = "https://oauth2.googleapis.com/token"
+
+	fcmScope = "https://www.googleapis.com/auth/firebase.messaging"
+)
+
+// FcmConfig 字段与 Firebase 服务账号密钥文件一致，可直接解析密钥文件
+type FcmConfig struct {
+	Endpoint    string `json:"endpoint"`     // 接口地址，默认为 FcmEndpoint
+	ProjectId   string `json:"project_id"`   // 项目ID
+	ClientEmail string `json:"client_email"` // 服务账号邮箱
+	PrivateKey  string `json:"private_key"`  // 服务账号私钥(PEM)
+	TokenUri    string `json:"token_uri"`    // 获取访问令牌的地址，默认为 FcmTokenUri
+}
+
+// Fcm 基于服务账号认证的 FCM HTTP v1 推送
+type Fcm struct {
+	conf   FcmConfig
+	client *http.Client
+	key    *rsa.PrivateKey
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// NewFcm client 为空时使用默认客户端
+func NewFcm(conf FcmConfig, client *http.Client) (*Fcm, error) {
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(conf.PrivateKey))
+	if err != nil {
+		return nil, err
+	}
+
+	if conf.ProjectId == "" || conf.ClientEmail == "" {
+		return nil, errors.New("push: fcm project_id and client_email are required")
+	}
+
+	if conf.Endpoint == "" {
+		conf.Endpoint = FcmEndpoint
+	}
+
+	if conf.TokenUri == "" {
+		conf.TokenUri = FcmTokenUri
+	}
+
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &Fcm{conf: conf, client: client, key: key}, nil
+}
+
+func (f *Fcm) Send(ctx context.Context, n *Notification) error {
+	message := map[string]any{
+		"token": n.Token,
+		"notification": map[string]string{
+			"title": n.Title,
+			"body":  n.Body,
+		},
+		"android": map[string]any{
+			"priority": "high",
+			"notification": map[string]any{
+				"notification_count": n.Badge,
+			},
+		},
+		"apns": map[string]any{
+			"payload": map[string]any{
+				"aps": map[string]any{"badge": n.Badge},
+			},
+		},
+	}
+
+	if len(n.Data) > 0 {
+		message["data"] = n.Data
+	}
+
+	body, err := json.Marshal(map[string]any{"message": message})
+	if err != nil {
+		return err
+	}
+
+	token, err := f.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/v1/projects/%s/messages:send", strings.TrimRight(f.conf.Endpoint, "/"), f.conf.ProjectId)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	data := readBody(resp)
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var result struct {
+		Error struct {
+			Status  string `json:"status"`
+			Details []struct {
+				ErrorCode string `json:"errorCode"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	_ = json.Unmarshal(data, &result)
+
+	reason := result.Error.Status
+	for _, detail := range result.Error.Details {
+		if detail.ErrorCode != "" {
+			reason = detail.ErrorCode
+		}
+	}
+
+	if reason == "UNREGISTERED" || resp.StatusCode == http.StatusNotFound {
+		return ErrInvalidToken
+	}
+
+	// 访问令牌失效时，下次发送重新获取
+	if resp.StatusCode == http.StatusUnauthorized {
+		f.resetToken()
+	}
+
+	return &Error{StatusCode: resp.StatusCode, Reason: reason}
+}
+
+// 使用服务账号签名的 JWT 换取访问令牌，过期前复用
+func (f *Fcm) accessToken(ctx context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.token != "" && time.Now().Before(f.expiresAt) {
+		return f.token, nil
+	}
+
+	now := time.Now()
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   f.conf.ClientEmail,
+		"scope": fcmScope,
+		"aud":   f.conf.TokenUri,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(f.key)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+	form.Set("assertion", assertion)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.conf.TokenUri, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Body.Close()
+
+	data := readBody(resp)
+	if resp.StatusCode != http.StatusOK {
+		return "", &Error{StatusCode: resp.StatusCode, Reason: "access token request failed"}
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil || result.AccessToken == "" {
+		return "", errors.New("push: fcm access token response invalid")
+	}
+
+	// 提前一分钟刷新，避免请求过程中令牌过期
+	f.token = result.AccessToken
+	f.expiresAt = now.Add(time.Duration(result.ExpiresIn)*time.Second - time.Minute)
+
+	return f.token, nil
+}
+
+func (f *Fcm) resetToken() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.token = ""
+}
@@ -0,0 +1,106 @@
+package push
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFcm_Send(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	privateKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	var (
+		tokenRequests int
+		message       map[string]any
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests++
+
+		assert.Equal(t, "urn:ietf:params:oauth:grant-type:jwt-bearer", r.FormValue("grant_type"))
+
+		token, err := jwt.Parse(r.FormValue("assertion"), func(token *jwt.Token) (any, error) {
+			return &key.PublicKey, nil
+		})
+		if err != nil || !token.Valid {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		assert.Equal(t, "push@example.iam.gserviceaccount.com", token.Claims.(jwt.MapClaims)["iss"])
+
+		_, _ = w.Write([]byte(`{"access_token":"access-token","expires_in":3600,"token_type":"Bearer"}`))
+	})
+	mux.HandleFunc("/v1/projects/example/messages:send", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var body struct {
+			Message map[string]any `json:"message"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		switch body.Message["token"] {
+		case "unregistered":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"code":404,"status":"NOT_FOUND","details":[{"errorCode":"UNREGISTERED"}]}}`))
+		case "invalid":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":{"code":400,"status":"INVALID_ARGUMENT","details":[{"errorCode":"INVALID_ARGUMENT"}]}}`))
+		case "quota":
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":{"code":429,"status":"RESOURCE_EXHAUSTED","details":[{"errorCode":"QUOTA_EXCEEDED"}]}}`))
+		default:
+			message = body.Message
+			_, _ = w.Write([]byte(`{"name":"projects/example/messages/1"}`))
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	fcm, err := NewFcm(FcmConfig{
+		Endpoint:    server.URL,
+		ProjectId:   "example",
+		ClientEmail: "push@example.iam.gserviceaccount.com",
+		PrivateKey:  string(privateKey),
+		TokenUri:    server.URL + "/token",
+	}, server.Client())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	err = fcm.Send(ctx, &Notification{Token: "device", Title: "Alice", Body: "hello", Badge: 2, Data: map[string]string{"talk_mode": "1"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "hello", message["notification"].(map[string]any)["body"])
+	assert.Equal(t, "1", message["data"].(map[string]any)["talk_mode"])
+
+	assert.ErrorIs(t, fcm.Send(ctx, &Notification{Token: "unregistered"}), ErrInvalidToken)
+
+	err = fcm.Send(ctx, &Notification{Token: "invalid"})
+	assert.Error(t, err)
+	assert.False(t, IsTemporary(err))
+
+	err = fcm.Send(ctx, &Notification{Token: "quota"})
+	assert.True(t, IsTemporary(err))
+
+	// 访问令牌在有效期内复用
+	assert.Equal(t, 1, tokenRequests)
+}
@@ -0,0 +1,112 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// 推送服务标识
+const (
+	ProviderApns = "apns" // Apple Push Notification service
+	ProviderFcm  = "fcm"  // Firebase Cloud Messaging
+)
+
+const maxResponseSize = 1 << 16 // 响应内容最大读取 64KB
+
+var (
+	ErrInvalidToken     = errors.New("push: device token invalid") // 设备令牌无效或已注销，应删除该设备
+	ErrProviderNotFound = errors.New("push: provider not found")
+)
+
+// Notification 推送通知
+type Notification struct {
+	Token string            // 设备令牌
+	Title string            // 标题
+	Body  string            // 内容
+	Badge int               // 角标数
+	Data  map[string]string // 自定义数据，客户端可用于打开对应的会话
+}
+
+// Provider 推送服务
+type Provider interface {
+	// Send 发送推送通知，设备令牌无效时返回 ErrInvalidToken
+	Send(ctx context.Context, n *Notification) error
+}
+
+// Error 推送服务返回的错误响应
+type Error struct {
+	StatusCode int
+	Reason     string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("push: response status %d reason %s", e.StatusCode, e.Reason)
+}
+
+// Temporary 是否为可重试的错误(限流或服务端异常)
+func (e *Error) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// IsTemporary 是否为可重试的错误，网络异常等非推送服务返回的错误均视为可重试
+func IsTemporary(err error) bool {
+	if err == nil || errors.Is(err, ErrInvalidToken) || errors.Is(err, ErrProviderNotFound) {
+		return false
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Temporary()
+	}
+
+	return true
+}
+
+// Client 按推送服务标识分发推送通知
+type Client struct {
+	mu        sync.RWMutex
+	providers map[string]Provider
+}
+
+func NewClient() *Client {
+	return &Client{providers: make(map[string]Provider)}
+}
+
+// Register 注册推送服务
+func (c *Client) Register(name string, provider Provider) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.providers[name] = provider
+}
+
+// Has 是否已注册推送服务
+func (c *Client) Has(name string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	_, ok := c.providers[name]
+	return ok
+}
+
+// Send 使用指定的推送服务发送推送通知
+func (c *Client) Send(ctx context.Context, name string, n *Notification) error {
+	c.mu.RLock()
+	provider, ok := c.providers[name]
+	c.mu.RUnlock()
+
+	if !ok {
+		return ErrProviderNotFound
+	}
+
+	return provider.Send(ctx, n)
+}
+
+func readBody(resp *http.Response) []byte {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	return data
+}
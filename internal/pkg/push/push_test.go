@@ -0,0 +1,44 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testProvider struct {
+	notifications []*Notification
+}
+
+func (t *testProvider) Send(_ context.Context, n *Notification) error {
+	t.notifications = append(t.notifications, n)
+	return nil
+}
+
+func TestClient_Send(t *testing.T) {
+	provider := &testProvider{}
+
+	client := NewClient()
+	client.Register(ProviderApns, provider)
+
+	assert.True(t, client.Has(ProviderApns))
+	assert.False(t, client.Has(ProviderFcm))
+
+	assert.NoError(t, client.Send(context.Background(), ProviderApns, &Notification{Token: "token"}))
+	assert.Len(t, provider.notifications, 1)
+
+	assert.ErrorIs(t, client.Send(context.Background(), ProviderFcm, &Notification{Token: "token"}), ErrProviderNotFound)
+}
+
+func TestIsTemporary(t *testing.T) {
+	assert.False(t, IsTemporary(nil))
+	assert.False(t, IsTemporary(ErrInvalidToken))
+	assert.False(t, IsTemporary(ErrProviderNotFound))
+	assert.False(t, IsTemporary(&Error{StatusCode: http.StatusBadRequest}))
+	assert.True(t, IsTemporary(&Error{StatusCode: http.StatusTooManyRequests}))
+	assert.True(t, IsTemporary(&Error{StatusCode: http.StatusServiceUnavailable}))
+	assert.True(t, IsTemporary(errors.New("connection reset")))
+}
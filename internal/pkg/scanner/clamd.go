@@ -0,0 +1,115 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+var _ Scanner = (*ClamdScanner)(nil)
+
+// clamd 单次发送的数据块大小，需小于 clamd 的 StreamMaxLength
+const clamdChunkSize = 64 << 10
+
+// ClamdConfig clamd 配置信息
+type ClamdConfig struct {
+	Addr    string `json:"addr" yaml:"addr"`       // clamd TCP 地址，例如 127.0.0.1:3310
+	Timeout int    `json:"timeout" yaml:"timeout"` // 扫描超时时间(秒)，默认 60
+}
+
+// ClamdScanner 基于 clamd INSTREAM 协议的扫描驱动
+type ClamdScanner struct {
+	config ClamdConfig
+}
+
+func NewClamdScanner(config ClamdConfig) *ClamdScanner {
+	if config.Timeout <= 0 {
+		config.Timeout = 60
+	}
+
+	return &ClamdScanner{config}
+}
+
+func (c ClamdScanner) Driver() string {
+	return ClamdDriver
+}
+
+func (c ClamdScanner) Scan(ctx context.Context, r io.Reader) (*Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(c.config.Timeout)*time.Second)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", c.config.Addr)
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 4)
+	buf := make([]byte, clamdChunkSize)
+
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(header, uint32(n))
+			if _, err := conn.Write(header); err != nil {
+				return nil, err
+			}
+
+			if _, err := conn.Write(buf[:n]); err != nil {
+				return nil, err
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 长度为 0 的数据块表示数据发送完毕
+	binary.BigEndian.PutUint32(header, 0)
+	if _, err := conn.Write(header); err != nil {
+		return nil, err
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return parseClamdReply(reply)
+}
+
+// parseClamdReply 解析扫描结果，格式如 "stream: OK" 或 "stream: Eicar-Signature FOUND"
+func parseClamdReply(reply string) (*Result, error) {
+	reply = strings.TrimSpace(strings.TrimRight(reply, "\x00"))
+	_, status, _ := strings.Cut(reply, ": ")
+
+	switch {
+	case status == "OK":
+		return &Result{}, nil
+	case strings.HasSuffix(status, " FOUND"):
+		return &Result{Infected: true, Signature: strings.TrimSuffix(status, " FOUND")}, nil
+	}
+
+	return nil, fmt.Errorf("clamd scan failed: %s", reply)
+}
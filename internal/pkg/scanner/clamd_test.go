@@ -0,0 +1,114 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const eicar = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// newFakeClamd 模拟 clamd INSTREAM 协议，内容包含 EICAR 特征时返回 FOUND
+func newFakeClamd(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func(conn net.Conn) {
+				defer conn.Close()
+
+				reader := bufio.NewReader(conn)
+				if command, err := reader.ReadString(0); err != nil || command != "zINSTREAM\x00" {
+					_, _ = conn.Write([]byte("UNKNOWN COMMAND\x00"))
+					return
+				}
+
+				var data bytes.Buffer
+				header := make([]byte, 4)
+				for {
+					if _, err := io.ReadFull(reader, header); err != nil {
+						return
+					}
+
+					size := binary.BigEndian.Uint32(header)
+					if size == 0 {
+						break
+					}
+
+					if _, err := io.CopyN(&data, reader, int64(size)); err != nil {
+						return
+					}
+				}
+
+				if strings.Contains(data.String(), "EICAR-STANDARD-ANTIVIRUS-TEST-FILE") {
+					_, _ = conn.Write([]byte("stream: Eicar-Signature FOUND\x00"))
+				} else {
+					_, _ = conn.Write([]byte("stream: OK\x00"))
+				}
+			}(conn)
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func TestClamdScanner_Scan(t *testing.T) {
+	client := NewClamdScanner(ClamdConfig{Addr: newFakeClamd(t), Timeout: 5})
+
+	result, err := client.Scan(context.Background(), strings.NewReader("hello world"))
+	assert.NoError(t, err)
+	assert.False(t, result.Infected)
+
+	// 超过单个数据块大小的内容
+	large := bytes.Repeat([]byte("a"), clamdChunkSize*2+10)
+	large = append(large, eicar...)
+
+	result, err = client.Scan(context.Background(), bytes.NewReader(large))
+	assert.NoError(t, err)
+	assert.True(t, result.Infected)
+	assert.Equal(t, "Eicar-Signature", result.Signature)
+}
+
+func TestClamdScanner_Unavailable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr := listener.Addr().String()
+	_ = listener.Close()
+
+	_, err = NewClamdScanner(ClamdConfig{Addr: addr, Timeout: 1}).Scan(context.Background(), strings.NewReader("data"))
+	assert.Error(t, err)
+}
+
+func TestParseClamdReply(t *testing.T) {
+	result, err := parseClamdReply("stream: OK\x00")
+	assert.NoError(t, err)
+	assert.False(t, result.Infected)
+
+	result, err = parseClamdReply("stream: Win.Test.EICAR_HDB-1 FOUND\x00")
+	assert.NoError(t, err)
+	assert.True(t, result.Infected)
+	assert.Equal(t, "Win.Test.EICAR_HDB-1", result.Signature)
+
+	_, err = parseClamdReply("INSTREAM size limit exceeded. ERROR\x00")
+	assert.Error(t, err)
+}
@@ -0,0 +1,43 @@
+package scanner
+
+import (
+	"context"
+	"io"
+)
+
+const (
+	ClamdDriver = "clamd"
+	NopDriver   = "none"
+)
+
+// Scanner 文件安全扫描
+type Scanner interface {
+	// Driver 驱动方式
+	Driver() string
+
+	// Scan 扫描文件内容
+	Scan(ctx context.Context, r io.Reader) (*Result, error)
+}
+
+// Result 扫描结果
+type Result struct {
+	Infected  bool   // 是否发现威胁
+	Signature string // 威胁特征名称
+}
+
+var _ Scanner = (*NopScanner)(nil)
+
+// NopScanner 不做任何扫描，所有文件视为安全
+type NopScanner struct{}
+
+func NewNopScanner() *NopScanner {
+	return &NopScanner{}
+}
+
+func (NopScanner) Driver() string {
+	return NopDriver
+}
+
+func (NopScanner) Scan(_ context.Context, _ io.Reader) (*Result, error) {
+	return &Result{}, nil
+}
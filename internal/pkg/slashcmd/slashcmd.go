@@ -0,0 +1,127 @@
+package slashcmd
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const Prefix = "/"
+
+var nameRegexp = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]{0,31}$`)
+
+var ErrUnclosedQuote = errors.New("引号未闭合")
+
+// Command 斜杠命令
+type Command struct {
+	Name string   // 命令名称(小写)
+	Args []string // 命令参数，支持使用单引号或双引号包含空格
+	Text string   // 命令名称之后的原始文本
+}
+
+// Parse 解析以 / 开头的文本消息，不是合法命令时返回 false
+//
+//	/poll "午饭吃什么" 面条 米饭
+func Parse(content string) (*Command, bool) {
+	content = strings.TrimSpace(content)
+	if !strings.HasPrefix(content, Prefix) {
+		return nil, false
+	}
+
+	content = strings.TrimPrefix(content, Prefix)
+
+	name, text, _ := strings.Cut(content, " ")
+	if i := strings.IndexAny(name, "\n\t"); i >= 0 {
+		name, text = name[:i], content[i:]
+	}
+
+	if !IsValidName(name) {
+		return nil, false
+	}
+
+	text = strings.TrimSpace(text)
+
+	args, err := Split(text)
+	if err != nil {
+		args = strings.Fields(text)
+	}
+
+	return &Command{Name: strings.ToLower(name), Args: args, Text: text}, true
+}
+
+// IsValidName 判断命令名称是否合法(字母开头，可包含字母、数字、下划线和中划线)
+func IsValidName(name string) bool {
+	return nameRegexp.MatchString(name)
+}
+
+// Split 按空白字符拆分参数，引号内的空白字符不拆分
+func Split(text string) ([]string, error) {
+	args := make([]string, 0)
+
+	var (
+		quote   rune
+		current strings.Builder
+		inArg   bool
+	)
+
+	for _, r := range text {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, ErrUnclosedQuote
+	}
+
+	if inArg {
+		args = append(args, current.String())
+	}
+
+	return args, nil
+}
+
+// ParseDuration 解析时长，在 time.ParseDuration 的基础上支持天(d)，如 1d12h
+func ParseDuration(value string) (time.Duration, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	var days int
+	if before, after, ok := strings.Cut(value, "d"); ok {
+		n, err := strconv.Atoi(before)
+		if err != nil || n < 0 {
+			return 0, errors.New("时长格式错误")
+		}
+
+		days, value = n, after
+	}
+
+	var duration time.Duration
+	if value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, errors.New("时长格式错误")
+		}
+
+		duration = d
+	}
+
+	return time.Duration(days)*24*time.Hour + duration, nil
+}
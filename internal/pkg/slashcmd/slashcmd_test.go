@@ -0,0 +1,72 @@
+package slashcmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	cmd, ok := Parse(`  /Poll "午饭 吃什么" 面条 '米饭'  `)
+	assert.True(t, ok)
+	assert.Equal(t, "poll", cmd.Name)
+	assert.Equal(t, []string{"午饭 吃什么", "面条", "米饭"}, cmd.Args)
+	assert.Equal(t, `"午饭 吃什么" 面条 '米饭'`, cmd.Text)
+
+	cmd, ok = Parse("/help")
+	assert.True(t, ok)
+	assert.Equal(t, "help", cmd.Name)
+	assert.Empty(t, cmd.Args)
+
+	cmd, ok = Parse("/remind\n10m 开会")
+	assert.True(t, ok)
+	assert.Equal(t, "remind", cmd.Name)
+	assert.Equal(t, []string{"10m", "开会"}, cmd.Args)
+
+	// 引号未闭合时按空白字符拆分
+	cmd, ok = Parse(`/echo "hello world`)
+	assert.True(t, ok)
+	assert.Equal(t, []string{`"hello`, "world"}, cmd.Args)
+
+	for _, content := range []string{"hello", "/", "//comment", "/usr/bin/env", "/ help", "/1abc"} {
+		_, ok := Parse(content)
+		assert.False(t, ok, content)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	args, err := Split(`a "b c" 'd"e' `)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b c", `d"e`}, args)
+
+	args, err = Split(`"" x`)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"", "x"}, args)
+
+	_, err = Split(`"abc`)
+	assert.ErrorIs(t, err, ErrUnclosedQuote)
+}
+
+func TestParseDuration(t *testing.T) {
+	cases := map[string]time.Duration{
+		"10m":   10 * time.Minute,
+		"2h":    2 * time.Hour,
+		"1d":    24 * time.Hour,
+		"1d12h": 36 * time.Hour,
+		"1h30m": 90 * time.Minute,
+	}
+
+	for value, expect := range cases {
+		d, err := ParseDuration(value)
+		assert.NoError(t, err, value)
+		assert.Equal(t, expect, d, value)
+	}
+
+	for _, value := range []string{"", "abc", "xd", "10"} {
+		d, err := ParseDuration(value)
+		if err == nil {
+			assert.Zero(t, d, value)
+		}
+	}
+}
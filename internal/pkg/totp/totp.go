@@ -0,0 +1,138 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	Digits = 6  // 动态码位数
+	Period = 30 // 时间步长(秒)
+	Skew   = 1  // 允许前后偏移的时间步数
+)
+
+var ErrInvalidSecret = errors.New("无效的 TOTP 密钥")
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret 生成 Base32 编码的随机密钥(160 位)
+func GenerateSecret() string {
+	buf := make([]byte, 20)
+	_, _ = rand.Read(buf)
+	return encoding.EncodeToString(buf)
+}
+
+// Code 计算指定时间的动态码
+func Code(secret string, t time.Time) (string, error) {
+	key, err := decode(secret)
+	if err != nil {
+		return "", err
+	}
+
+	return hotp(key, uint64(t.Unix()/Period), Digits), nil
+}
+
+// Validate 校验动态码，成功时返回匹配的时间步
+//
+// 调用方应记录最后一次使用的时间步，拒绝小于等于该值的时间步以防止动态码重放
+func Validate(secret string, code string, t time.Time) (int64, bool) {
+	if len(code) != Digits {
+		return 0, false
+	}
+
+	key, err := decode(secret)
+	if err != nil {
+		return 0, false
+	}
+
+	counter := t.Unix() / Period
+	for i := -Skew; i <= Skew; i++ {
+		step := counter + int64(i)
+		if step < 0 {
+			continue
+		}
+
+		if subtle.ConstantTimeCompare([]byte(hotp(key, uint64(step), Digits)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// ProvisioningURI 生成身份验证器扫码绑定使用的 otpauth 地址，客户端将其渲染为二维码
+func ProvisioningURI(issuer string, account string, secret string) string {
+	values := url.Values{}
+	values.Set("secret", secret)
+	values.Set("issuer", issuer)
+	values.Set("algorithm", "SHA1")
+	values.Set("digits", fmt.Sprintf("%d", Digits))
+	values.Set("period", fmt.Sprintf("%d", Period))
+
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(account)
+
+	return fmt.Sprintf("otpauth://totp/%s?%s", label, values.Encode())
+}
+
+// GenerateRecoveryCodes 生成一次性恢复码，格式为 xxxxx-xxxxx
+func GenerateRecoveryCodes(n int) []string {
+	codes := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		buf := make([]byte, 5)
+		_, _ = rand.Read(buf)
+		value := hex.EncodeToString(buf)
+		codes = append(codes, value[:5]+"-"+value[5:])
+	}
+
+	return codes
+}
+
+// HashRecoveryCode 计算恢复码摘要，存储时只保存摘要
+func HashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+func decode(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	secret = strings.TrimRight(secret, "=")
+
+	key, err := encoding.DecodeString(secret)
+	if err != nil || len(key) == 0 {
+		return nil, ErrInvalidSecret
+	}
+
+	return key, nil
+}
+
+// RFC 4226 HOTP 算法
+func hotp(key []byte, counter uint64, digits int) string {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], counter)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(buf[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, value%mod)
+}
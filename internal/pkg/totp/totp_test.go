@@ -0,0 +1,78 @@
+package totp
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// RFC 6238 附录 B 中 SHA1 算法的测试向量
+func TestHotpRFC6238(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	cases := map[int64]string{
+		59:          "94287082",
+		1111111109:  "07081804",
+		1111111111:  "14050471",
+		1234567890:  "89005924",
+		2000000000:  "69279037",
+		20000000000: "65353130",
+	}
+
+	for unix, expect := range cases {
+		assert.Equal(t, expect, hotp(key, uint64(unix/Period), 8), unix)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret := GenerateSecret()
+	now := time.Unix(1700000000, 0)
+
+	code, err := Code(secret, now)
+	assert.NoError(t, err)
+	assert.Len(t, code, Digits)
+
+	step, ok := Validate(secret, code, now)
+	assert.True(t, ok)
+	assert.Equal(t, now.Unix()/Period, step)
+
+	// 允许前后一个时间步的时钟偏差
+	_, ok = Validate(secret, code, now.Add(Period*time.Second))
+	assert.True(t, ok)
+
+	_, ok = Validate(secret, code, now.Add(3*Period*time.Second))
+	assert.False(t, ok)
+
+	_, ok = Validate(secret, "12345", now)
+	assert.False(t, ok)
+
+	_, ok = Validate("!invalid!", code, now)
+	assert.False(t, ok)
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI("LumenIM", "admin@example.com", "JBSWY3DPEHPK3PXP")
+
+	u, err := url.Parse(uri)
+	assert.NoError(t, err)
+	assert.Equal(t, "otpauth", u.Scheme)
+	assert.Equal(t, "totp", u.Host)
+	assert.Equal(t, "/LumenIM:admin@example.com", u.Path)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", u.Query().Get("secret"))
+	assert.Equal(t, "LumenIM", u.Query().Get("issuer"))
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes := GenerateRecoveryCodes(10)
+	assert.Len(t, codes, 10)
+
+	for _, code := range codes {
+		assert.Len(t, code, 11)
+		assert.Equal(t, HashRecoveryCode(code), HashRecoveryCode(strings.ToUpper(strings.ReplaceAll(code, "-", ""))))
+	}
+
+	assert.NotEqual(t, codes[0], codes[1])
+}
@@ -0,0 +1,109 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	HeaderTimestamp = "X-Lumenim-Timestamp" // 请求时间戳(秒)
+	HeaderSignature = "X-Lumenim-Signature" // 请求签名
+
+	maxResponseSize = 1 << 20 // 响应内容最大读取 1MB
+)
+
+var (
+	ErrSignatureInvalid = errors.New("webhook signature invalid")
+	ErrSignatureExpired = errors.New("webhook signature expired")
+)
+
+// NewSecret 生成签名密钥
+func NewSecret() string {
+	buf := make([]byte, 24)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// Sign 计算签名 hex(HMAC-SHA256(secret, timestamp + "\n" + body))
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("\n"))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 校验签名，tolerance 为允许的时间误差
+func Verify(secret string, timestamp int64, body []byte, signature string, tolerance time.Duration) error {
+	if !hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature)) {
+		return ErrSignatureInvalid
+	}
+
+	if diff := time.Since(time.Unix(timestamp, 0)); diff > tolerance || diff < -tolerance {
+		return ErrSignatureExpired
+	}
+
+	return nil
+}
+
+type Client struct {
+	client *http.Client
+}
+
+func NewClient(timeout time.Duration) *Client {
+	return &Client{client: &http.Client{Timeout: timeout}}
+}
+
+type Response struct {
+	StatusCode int
+	Body       []byte
+}
+
+// Post 发送带签名的 JSON 请求，响应状态码非 2xx 时返回错误
+func (c *Client) Post(ctx context.Context, url string, secret string, payload any) (*Response, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp := time.Now().Unix()
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "LumenIM-Webhook")
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(secret, timestamp, body))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("webhook response status: %s", resp.Status)
+	}
+
+	return &Response{StatusCode: resp.StatusCode, Body: data}, nil
+}
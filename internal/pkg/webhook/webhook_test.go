@@ -0,0 +1,56 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	now := time.Now().Unix()
+	body := []byte(`{"content":"hello"}`)
+
+	signature := Sign("secret", now, body)
+
+	assert.NoError(t, Verify("secret", now, body, signature, time.Minute))
+	assert.ErrorIs(t, Verify("other", now, body, signature, time.Minute), ErrSignatureInvalid)
+	assert.ErrorIs(t, Verify("secret", now, []byte(`{}`), signature, time.Minute), ErrSignatureInvalid)
+
+	expired := now - 600
+	assert.ErrorIs(t, Verify("secret", expired, body, Sign("secret", expired, body), time.Minute), ErrSignatureExpired)
+}
+
+func TestClient_Post(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+
+		if Verify("secret", timestamp, body, r.Header.Get(HeaderSignature), time.Minute) != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"reply":{"content":"pong"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(time.Second)
+
+	resp, err := client.Post(context.Background(), server.URL, "secret", map[string]any{"content": "ping"})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"reply":{"content":"pong"}}`, string(resp.Body))
+
+	_, err = client.Post(context.Background(), server.URL, "wrong", map[string]any{"content": "ping"})
+	assert.Error(t, err)
+}
+
+func TestNewSecret(t *testing.T) {
+	assert.Len(t, NewSecret(), 48)
+	assert.NotEqual(t, NewSecret(), NewSecret())
+}
@@ -5,7 +5,14 @@ import (
 	"go-chat/internal/pkg/email"
 )
 
-func NewEmailClient(conf *config.Config) *email.Client {
+func NewEmailClient(conf *config.Config) email.Sender {
+	switch conf.Email.Driver {
+	case config.EmailDriverFile:
+		return email.NewFileSender(conf.Email.Path, conf.Email.UserName, conf.Email.FromName)
+	case config.EmailDriverLog:
+		return email.NewLogSender(nil, conf.Email.UserName, conf.Email.FromName)
+	}
+
 	return email.NewEmail(&email.Config{
 		Host:     conf.Email.Host,
 		Port:     conf.Email.Port,
@@ -1,14 +1,33 @@
 package provider
 
 import (
+	"fmt"
+
 	"go-chat/config"
 	"go-chat/internal/pkg/filesystem"
 )
 
 func NewFilesystem(conf *config.Config) filesystem.IFilesystem {
-	if conf.Filesystem.Default == filesystem.MinioDriver {
-		return filesystem.NewMinioFilesystem(conf.Filesystem.Minio)
+	fs, err := NewFilesystemDriver(conf, conf.Filesystem.Default)
+	if err != nil {
+		return filesystem.NewLocalFilesystem(conf.Filesystem.Local)
+	}
+
+	return fs
+}
+
+// NewFilesystemDriver 根据驱动名称创建文件系统
+func NewFilesystemDriver(conf *config.Config, driver string) (filesystem.IFilesystem, error) {
+	switch driver {
+	case filesystem.LocalDriver:
+		return filesystem.NewLocalFilesystem(conf.Filesystem.Local), nil
+	case filesystem.MinioDriver:
+		return filesystem.NewMinioFilesystem(conf.Filesystem.Minio), nil
+	case filesystem.S3Driver:
+		return filesystem.NewS3Filesystem(conf.Filesystem.S3), nil
+	case filesystem.WebDAVDriver:
+		return filesystem.NewWebDAVFilesystem(conf.Filesystem.Webdav), nil
 	}
 
-	return filesystem.NewLocalFilesystem(conf.Filesystem.Local)
+	return nil, fmt.Errorf("unsupported filesystem driver: %s", driver)
 }
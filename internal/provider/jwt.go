@@ -0,0 +1,17 @@
+package provider
+
+import (
+	"fmt"
+
+	"go-chat/config"
+	"go-chat/internal/pkg/jwt"
+)
+
+func NewJwtKeySet(conf *config.Config) *jwt.KeySet {
+	keys, err := jwt.NewKeySet(conf.Jwt.Secret, conf.Jwt.KeyDir, conf.Jwt.GetLegacySecretUntil())
+	if err != nil {
+		panic(fmt.Errorf("jwt key set error: %s", err))
+	}
+
+	return keys
+}
@@ -0,0 +1,14 @@
+package provider
+
+import (
+	"go-chat/config"
+	"go-chat/internal/pkg/ldap"
+)
+
+func NewLdapClient(conf *config.Config) *ldap.Client {
+	if conf.Ldap == nil {
+		return ldap.NewClient(ldap.Config{})
+	}
+
+	return ldap.NewClient(conf.Ldap.Server)
+}
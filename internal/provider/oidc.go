@@ -0,0 +1,28 @@
+package provider
+
+import (
+	"net/http"
+
+	"go-chat/config"
+	"go-chat/internal/pkg/oidc"
+)
+
+// NewOidcProviders 第三方登录的身份提供方，首次使用时才请求服务发现文档
+func NewOidcProviders(conf *config.Config, client *http.Client) oidc.Providers {
+	providers := make(oidc.Providers)
+	if conf.Oidc == nil {
+		return providers
+	}
+
+	for _, item := range conf.Oidc.Providers {
+		providers[item.Name] = oidc.NewProvider(oidc.Config{
+			Issuer:       item.Issuer,
+			ClientId:     item.ClientId,
+			ClientSecret: item.ClientSecret,
+			RedirectUrl:  item.RedirectUrl,
+			Scopes:       item.Scopes,
+		}, client)
+	}
+
+	return providers
+}
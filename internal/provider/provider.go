@@ -3,5 +3,5 @@ package provider
 import "go-chat/internal/pkg/email"
 
 type Providers struct {
-	EmailClient *email.Client
+	EmailClient email.Sender
 }
@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"go-chat/config"
+	"go-chat/internal/pkg/push"
+)
+
+func NewPushClient(conf *config.Config) *push.Client {
+	client := push.NewClient()
+	if !conf.Push.IsEnabled() {
+		return client
+	}
+
+	if conf.Push.Apns != nil {
+		key, err := os.ReadFile(conf.Push.Apns.KeyFile)
+		if err != nil {
+			panic(fmt.Errorf("push apns key error: %s", err))
+		}
+
+		apns, err := push.NewApns(push.ApnsConfig{
+			Endpoint:   conf.Push.Apns.Endpoint,
+			KeyId:      conf.Push.Apns.KeyId,
+			TeamId:     conf.Push.Apns.TeamId,
+			Topic:      conf.Push.Apns.Topic,
+			PrivateKey: key,
+		}, nil)
+		if err != nil {
+			panic(fmt.Errorf("push apns error: %s", err))
+		}
+
+		client.Register(push.ProviderApns, apns)
+	}
+
+	if conf.Push.Fcm != nil {
+		data, err := os.ReadFile(conf.Push.Fcm.CredentialsFile)
+		if err != nil {
+			panic(fmt.Errorf("push fcm credentials error: %s", err))
+		}
+
+		var fcmConf push.FcmConfig
+		if err := json.Unmarshal(data, &fcmConf); err != nil {
+			panic(fmt.Errorf("push fcm credentials error: %s", err))
+		}
+
+		fcmConf.Endpoint = conf.Push.Fcm.Endpoint
+
+		fcm, err := push.NewFcm(fcmConf, nil)
+		if err != nil {
+			panic(fmt.Errorf("push fcm error: %s", err))
+		}
+
+		client.Register(push.ProviderFcm, fcm)
+	}
+
+	return client
+}
@@ -0,0 +1,14 @@
+package provider
+
+import (
+	"go-chat/config"
+	"go-chat/internal/pkg/scanner"
+)
+
+func NewScanner(conf *config.Config) scanner.Scanner {
+	if conf.Scanner != nil && conf.Scanner.Driver == scanner.ClamdDriver {
+		return scanner.NewClamdScanner(conf.Scanner.Clamd)
+	}
+
+	return scanner.NewNopScanner()
+}
@@ -14,5 +14,11 @@ var ProviderSet = wire.NewSet(
 	NewBase64Captcha,
 	NewIpAddressClient,
 	NewRsa,
+	NewJwtKeySet,
+	NewOidcProviders,
+	NewLdapClient,
+	NewNsqProducer,
+	NewScanner,
+	NewPushClient,
 	wire.Struct(new(Providers), "*"),
 )
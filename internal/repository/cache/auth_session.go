@@ -0,0 +1,169 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var ErrAuthSessionConflict = errors.New("刷新令牌已被使用")
+
+// AuthSessionStorage 登录会话存储，每个会话对应一个设备上的刷新令牌族
+type AuthSessionStorage struct {
+	redis *redis.Client
+}
+
+func NewAuthSessionStorage(rds *redis.Client) *AuthSessionStorage {
+	return &AuthSessionStorage{rds}
+}
+
+type AuthSession struct {
+	Id              string `redis:"id"`                // 会话ID
+	UserId          int    `redis:"user_id"`           // 用户ID
+	Platform        string `redis:"platform"`          // 登录平台
+	Agent           string `redis:"agent"`             // 登录设备
+	IpAddr          string `redis:"ip_addr"`           // 最近一次使用的IP
+	RefreshHash     string `redis:"refresh_hash"`      // 当前有效的刷新令牌摘要
+	AccessToken     string `redis:"access_token"`      // 当前有效的访问令牌
+	AccessExpiresAt int64  `redis:"access_expires_at"` // 访问令牌过期时间
+	ExpiresAt       int64  `redis:"expires_at"`        // 刷新令牌过期时间
+	CreatedAt       int64  `redis:"created_at"`        // 登录时间
+	RefreshedAt     int64  `redis:"refreshed_at"`      // 最近一次刷新时间
+}
+
+// 刷新令牌摘要一致时才允许轮换，保证同一个刷新令牌只能使用一次
+var rotateScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return -1
+end
+if redis.call("HGET", KEYS[1], "refresh_hash") ~= ARGV[1] then
+	return 0
+end
+redis.call("HSET", KEYS[1], unpack(ARGV, 4))
+redis.call("EXPIREAT", KEYS[1], ARGV[2])
+redis.call("ZADD", KEYS[2], ARGV[2], ARGV[3])
+local last = redis.call("ZRANGE", KEYS[2], -1, -1, "WITHSCORES")
+redis.call("EXPIREAT", KEYS[2], last[2])
+return 1
+`)
+
+// 更新用户会话索引，索引的过期时间与最晚过期的会话保持一致，避免仍有效的会话被提前移出索引
+var indexScript = redis.NewScript(`
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", ARGV[1])
+redis.call("ZADD", KEYS[1], ARGV[2], ARGV[3])
+local last = redis.call("ZRANGE", KEYS[1], -1, -1, "WITHSCORES")
+redis.call("EXPIREAT", KEYS[1], last[2])
+return 1
+`)
+
+// Set 保存会话
+func (s *AuthSessionStorage) Set(ctx context.Context, session *AuthSession) error {
+	_, err := s.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, s.name(session.Id), session)
+		pipe.ExpireAt(ctx, s.name(session.Id), time.Unix(session.ExpiresAt, 0))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return indexScript.Run(ctx, s.redis, []string{s.userName(session.UserId)},
+		time.Now().Unix(), session.ExpiresAt, session.Id,
+	).Err()
+}
+
+// Get 获取会话，会话不存在时返回 redis.Nil
+func (s *AuthSessionStorage) Get(ctx context.Context, sid string) (*AuthSession, error) {
+	res := s.redis.HGetAll(ctx, s.name(sid))
+	if res.Err() != nil {
+		return nil, res.Err()
+	}
+
+	if len(res.Val()) == 0 {
+		return nil, redis.Nil
+	}
+
+	var session AuthSession
+	if err := res.Scan(&session); err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// Rotate 轮换刷新令牌，oldHash 与当前摘要不一致时返回 ErrAuthSessionConflict
+func (s *AuthSessionStorage) Rotate(ctx context.Context, oldHash string, session *AuthSession) error {
+	args := []any{oldHash, session.ExpiresAt, session.Id,
+		"refresh_hash", session.RefreshHash,
+		"access_token", session.AccessToken,
+		"access_expires_at", session.AccessExpiresAt,
+		"ip_addr", session.IpAddr,
+		"refreshed_at", session.RefreshedAt,
+	}
+
+	keys := []string{s.name(session.Id), s.userName(session.UserId)}
+
+	code, err := rotateScript.Run(ctx, s.redis, keys, args...).Int()
+	if err != nil {
+		return err
+	}
+
+	switch code {
+	case -1:
+		return redis.Nil
+	case 0:
+		return ErrAuthSessionConflict
+	}
+
+	return nil
+}
+
+// ListByUser 获取用户所有未过期的会话
+func (s *AuthSessionStorage) ListByUser(ctx context.Context, uid int) ([]*AuthSession, error) {
+	ids, err := s.redis.ZRangeByScore(ctx, s.userName(uid), &redis.ZRangeBy{
+		Min: strconv.FormatInt(time.Now().Unix(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*AuthSession, 0, len(ids))
+	for _, id := range ids {
+		session, err := s.Get(ctx, id)
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		items = append(items, session)
+	}
+
+	return items, nil
+}
+
+// Del 删除会话
+func (s *AuthSessionStorage) Del(ctx context.Context, uid int, sid string) error {
+	_, err := s.redis.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, s.name(sid))
+		pipe.ZRem(ctx, s.userName(uid), sid)
+		return nil
+	})
+
+	return err
+}
+
+func (s *AuthSessionStorage) name(sid string) string {
+	return fmt.Sprintf("im:auth:session:%s", sid)
+}
+
+func (s *AuthSessionStorage) userName(uid int) string {
+	return fmt.Sprintf("im:auth:session:user:%d", uid)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 	"go-chat/config"
@@ -83,6 +84,32 @@ func (c *ClientStorage) GetClientIdFromUid(ctx context.Context, sid, channel str
 	return strconv.ParseInt(uid, 10, 64)
 }
 
+// FindLastSeen 查询最后在线时间在 [min, max] 区间内的用户，用户在线期间的最后在线时间为建立连接的时间
+// @params min     最早时间戳
+// @params max     最晚时间戳
+// @params offset  偏移量
+// @params count   查询数量
+func (c *ClientStorage) FindLastSeen(ctx context.Context, min, max int64, offset, count int64) ([]int, error) {
+	items, err := c.redis.ZRangeByScore(ctx, c.lastSeenKey(), &redis.ZRangeBy{
+		Min:    strconv.FormatInt(min, 10),
+		Max:    strconv.FormatInt(max, 10),
+		Offset: offset,
+		Count:  count,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	uids := make([]int, 0, len(items))
+	for _, item := range items {
+		if uid, err := strconv.Atoi(item); err == nil {
+			uids = append(uids, uid)
+		}
+	}
+
+	return uids, nil
+}
+
 // 设置客户端与用户绑定关系
 // @params channel  渠道分组
 // @params fd       客户端连接ID
@@ -91,6 +118,7 @@ func (c *ClientStorage) set(ctx context.Context, sid, channel string, clientId i
 	_, err := c.redis.Pipelined(ctx, func(pipe redis.Pipeliner) error {
 		pipe.HSet(ctx, c.clientKey(sid, channel), clientId, uid)
 		pipe.SAdd(ctx, c.userKey(sid, channel, strconv.Itoa(uid)), clientId)
+		pipe.ZAdd(ctx, c.lastSeenKey(), redis.Z{Score: float64(time.Now().Unix()), Member: uid})
 		return nil
 	})
 	return err
@@ -105,6 +133,9 @@ func (c *ClientStorage) del(ctx context.Context, sid, channel, fd string) error
 	_, err := c.redis.Pipelined(ctx, func(pipe redis.Pipeliner) error {
 		pipe.HDel(ctx, key, fd)
 		pipe.SRem(ctx, c.userKey(sid, channel, uid), fd)
+		if uid != "" {
+			pipe.ZAdd(ctx, c.lastSeenKey(), redis.Z{Score: float64(time.Now().Unix()), Member: uid})
+		}
 		return nil
 	})
 	return err
@@ -117,3 +148,8 @@ func (c *ClientStorage) clientKey(sid, channel string) string {
 func (c *ClientStorage) userKey(sid, channel, uid string) string {
 	return fmt.Sprintf("ws:%s:%s:user:%s", sid, channel, uid)
 }
+
+// 用户最后在线时间，score 为时间戳
+func (c *ClientStorage) lastSeenKey() string {
+	return "im:user:last-seen"
+}
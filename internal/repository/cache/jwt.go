@@ -3,6 +3,7 @@ package cache
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -25,6 +26,56 @@ func (s *JwtTokenStorage) IsBlackList(ctx context.Context, token string) bool {
 	return s.redis.Get(ctx, s.name(token)).Val() != ""
 }
 
+// AddUserToken 记录用户已签发的 token，用于强制下线
+func (s *JwtTokenStorage) AddUserToken(ctx context.Context, uid int, token string, expiresAt time.Time) error {
+	key := s.userKey(uid)
+
+	_, err := s.redis.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(time.Now().Unix(), 10))
+		pipe.ZAdd(ctx, key, redis.Z{Score: float64(expiresAt.Unix()), Member: token})
+		pipe.ExpireAt(ctx, key, expiresAt)
+		return nil
+	})
+
+	return err
+}
+
+// RevokeUserTokens 将用户所有未过期的 token 加入黑名单
+func (s *JwtTokenStorage) RevokeUserTokens(ctx context.Context, uid int) error {
+	key := s.userKey(uid)
+
+	now := time.Now().Unix()
+	items, err := s.redis.ZRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{
+		Min: strconv.FormatInt(now, 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.redis.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, item := range items {
+			token, ok := item.Member.(string)
+			if !ok {
+				continue
+			}
+
+			if ex := int64(item.Score) - now; ex > 0 {
+				pipe.Set(ctx, s.name(token), 1, time.Duration(ex)*time.Second)
+			}
+		}
+
+		pipe.Del(ctx, key)
+		return nil
+	})
+
+	return err
+}
+
 func (s *JwtTokenStorage) name(token string) string {
 	return fmt.Sprintf("jwt:blacklist:%s", encrypt.Md5(token))
 }
+
+func (s *JwtTokenStorage) userKey(uid int) string {
+	return fmt.Sprintf("jwt:user:%d", uid)
+}
@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 登录限制的统计维度
+const (
+	LoginLimitAccount   = "account"
+	LoginLimitIp        = "ip"
+	LoginLimitTwoFactor = "two-factor" // 两步验证动态码，按账号统计
+)
+
+// LoginLimitStorage 登录失败次数统计及临时锁定
+type LoginLimitStorage struct {
+	redis *redis.Client
+}
+
+func NewLoginLimitStorage(rds *redis.Client) *LoginLimitStorage {
+	return &LoginLimitStorage{rds}
+}
+
+// LoginLimitState 账号或 IP 当前的登录限制状态
+type LoginLimitState struct {
+	Failures int64         // 统计周期内的失败次数
+	Locked   time.Duration // 剩余锁定时间，未锁定时为 0
+	Delay    time.Duration // 下次允许尝试前的剩余等待时间，无需等待时为 0
+}
+
+// 首次失败时设置统计周期，周期内的失败次数累加
+var loginFailScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 then
+	redis.call("EXPIRE", KEYS[1], ARGV[1])
+end
+return count
+`)
+
+// State 获取登录限制状态
+func (l *LoginLimitStorage) State(ctx context.Context, kind string, key string) (*LoginLimitState, error) {
+	var (
+		failures *redis.StringCmd
+		locked   *redis.DurationCmd
+		delay    *redis.DurationCmd
+	)
+
+	_, err := l.redis.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		failures = pipe.Get(ctx, l.name("fail", kind, key))
+		locked = pipe.PTTL(ctx, l.name("lock", kind, key))
+		delay = pipe.PTTL(ctx, l.name("delay", kind, key))
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	// 键不存在时 PTTL 返回负数
+	state := &LoginLimitState{Locked: max(locked.Val(), 0), Delay: max(delay.Val(), 0)}
+	state.Failures, _ = failures.Int64()
+
+	return state, nil
+}
+
+// Fail 记录一次登录失败，返回统计周期内的失败次数
+func (l *LoginLimitStorage) Fail(ctx context.Context, kind string, key string, window time.Duration) (int64, error) {
+	return loginFailScript.Run(ctx, l.redis, []string{l.name("fail", kind, key)}, int64(window.Seconds())).Int64()
+}
+
+// Lock 临时锁定
+func (l *LoginLimitStorage) Lock(ctx context.Context, kind string, key string, expire time.Duration) error {
+	return l.redis.Set(ctx, l.name("lock", kind, key), 1, expire).Err()
+}
+
+// Delay 设置下次允许尝试前的等待时间
+func (l *LoginLimitStorage) Delay(ctx context.Context, kind string, key string, expire time.Duration) error {
+	return l.redis.Set(ctx, l.name("delay", kind, key), 1, expire).Err()
+}
+
+// Clear 清除失败次数及等待时间，不解除锁定
+func (l *LoginLimitStorage) Clear(ctx context.Context, kind string, key string) error {
+	return l.redis.Del(ctx, l.name("fail", kind, key), l.name("delay", kind, key)).Err()
+}
+
+func (l *LoginLimitStorage) name(typ string, kind string, key string) string {
+	return fmt.Sprintf("im:auth:login-%s:%s:%s", typ, kind, key)
+}
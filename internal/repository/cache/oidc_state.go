@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go-chat/internal/pkg/jsonutil"
+)
+
+// OidcStateStorage 第三方登录授权请求的临时状态
+type OidcStateStorage struct {
+	redis *redis.Client
+}
+
+func NewOidcStateStorage(rds *redis.Client) *OidcStateStorage {
+	return &OidcStateStorage{rds}
+}
+
+type OidcState struct {
+	Provider string `json:"provider"` // 身份提供方标识
+	Verifier string `json:"verifier"` // PKCE 校验码
+	Nonce    string `json:"nonce"`    // id_token 防重放随机数
+	Platform string `json:"platform"` // 登录平台
+	Binding  string `json:"binding"`  // 客户端绑定值摘要，回调时校验发起授权的客户端
+}
+
+func (o *OidcStateStorage) Set(ctx context.Context, state string, value *OidcState, expire time.Duration) error {
+	return o.redis.Set(ctx, o.name(state), jsonutil.Encode(value), expire).Err()
+}
+
+// Take 读取并删除授权状态，保证每个 state 只能使用一次
+func (o *OidcStateStorage) Take(ctx context.Context, state string) (*OidcState, error) {
+	value, err := o.redis.GetDel(ctx, o.name(state)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var data OidcState
+	if err := jsonutil.Decode(value, &data); err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+func (o *OidcStateStorage) name(state string) string {
+	return fmt.Sprintf("im:auth:oidc-state:%s", state)
+}
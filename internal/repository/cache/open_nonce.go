@@ -0,0 +1,26 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type OpenNonceStorage struct {
+	redis *redis.Client
+}
+
+func NewOpenNonceStorage(rds *redis.Client) *OpenNonceStorage {
+	return &OpenNonceStorage{rds}
+}
+
+// Use 记录开放平台请求随机串，已存在时返回 false
+func (o *OpenNonceStorage) Use(ctx context.Context, appKey string, nonce string, expire time.Duration) bool {
+	return o.redis.SetNX(ctx, o.name(appKey, nonce), 1, expire).Val()
+}
+
+func (o *OpenNonceStorage) name(appKey string, nonce string) string {
+	return fmt.Sprintf("im:open:nonce:%s:%s", appKey, nonce)
+}
@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go-chat/internal/pkg/jsonutil"
+)
+
+// PreAuthStorage 登录两步验证的临时凭证
+type PreAuthStorage struct {
+	redis *redis.Client
+}
+
+func NewPreAuthStorage(rds *redis.Client) *PreAuthStorage {
+	return &PreAuthStorage{rds}
+}
+
+type PreAuth struct {
+	Guard    string `json:"guard"`    // 登录端[api:用户端;admin:管理端;]
+	Action   string `json:"action"`   // 待完成的操作[verify:校验动态码;setup:强制绑定;]
+	UserId   int    `json:"user_id"`  // 用户ID或管理员ID
+	Platform string `json:"platform"` // 登录平台
+	IpAddr   string `json:"ip_addr"`  // 登录IP
+	Agent    string `json:"agent"`    // 登录设备
+	Account  string `json:"account"`  // 登录账号，完成两步验证后清除该账号的登录失败记录
+}
+
+func (p *PreAuthStorage) Set(ctx context.Context, token string, value *PreAuth, expire time.Duration) error {
+	return p.redis.Set(ctx, p.name(token), jsonutil.Encode(value), expire).Err()
+}
+
+func (p *PreAuthStorage) Get(ctx context.Context, token string) (*PreAuth, error) {
+	value, err := p.redis.Get(ctx, p.name(token)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var data PreAuth
+	if err := jsonutil.Decode(value, &data); err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+func (p *PreAuthStorage) Del(ctx context.Context, token string) error {
+	return p.redis.Del(ctx, p.name(token), p.failName(token)).Err()
+}
+
+// Fail 记录一次校验失败，失败次数达到上限时作废凭证并返回 true
+func (p *PreAuthStorage) Fail(ctx context.Context, token string, limit int64) bool {
+	num := p.redis.Incr(ctx, p.failName(token)).Val()
+	if num == 1 {
+		p.redis.Expire(ctx, p.failName(token), 10*time.Minute)
+	}
+
+	if num >= limit {
+		_ = p.Del(ctx, token)
+		return true
+	}
+
+	return false
+}
+
+func (p *PreAuthStorage) name(token string) string {
+	return fmt.Sprintf("im:auth:pre-auth:%s", token)
+}
+
+func (p *PreAuthStorage) failName(token string) string {
+	return fmt.Sprintf("im:auth:pre-auth:fail:%s", token)
+}
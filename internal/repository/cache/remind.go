@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go-chat/internal/pkg/jsonutil"
+)
+
+type RemindStorage struct {
+	redis *redis.Client
+}
+
+func NewRemindStorage(rds *redis.Client) *RemindStorage {
+	return &RemindStorage{rds}
+}
+
+type RemindItem struct {
+	Id       string `json:"id"`         // 提醒ID
+	UserId   int    `json:"user_id"`    // 提醒用户ID
+	TalkMode int    `json:"talk_mode"`  // 创建提醒的对话类型
+	ToFromId int    `json:"to_from_id"` // 创建提醒的对话ID
+	Content  string `json:"content"`    // 提醒内容
+	RemindAt int64  `json:"remind_at"`  // 提醒时间(秒)
+}
+
+// Add 添加定时提醒
+func (r *RemindStorage) Add(ctx context.Context, item *RemindItem) error {
+	pipe := r.redis.Pipeline()
+	pipe.ZAdd(ctx, r.name(), redis.Z{Score: float64(item.RemindAt), Member: jsonutil.Encode(item)})
+	pipe.ZAdd(ctx, r.userName(item.UserId), redis.Z{Score: float64(item.RemindAt), Member: item.Id})
+	pipe.Expire(ctx, r.userName(item.UserId), 31*24*time.Hour)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// CountByUser 统计用户待提醒数量
+func (r *RemindStorage) CountByUser(ctx context.Context, uid int) int64 {
+	r.redis.ZRemRangeByScore(ctx, r.userName(uid), "-inf", strconv.FormatInt(time.Now().Unix(), 10))
+	return r.redis.ZCard(ctx, r.userName(uid)).Val()
+}
+
+// PopDue 取出已到期的提醒，多节点并发执行时每条提醒只会被取出一次
+func (r *RemindStorage) PopDue(ctx context.Context, now int64, limit int64) ([]*RemindItem, error) {
+	values, err := r.redis.ZRangeByScore(ctx, r.name(), &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(now, 10),
+		Count: limit,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*RemindItem, 0, len(values))
+	for _, value := range values {
+		if r.redis.ZRem(ctx, r.name(), value).Val() == 0 {
+			continue
+		}
+
+		var item RemindItem
+		if err := json.Unmarshal([]byte(value), &item); err == nil {
+			items = append(items, &item)
+		}
+	}
+
+	return items, nil
+}
+
+func (r *RemindStorage) name() string {
+	return "im:command:remind"
+}
+
+func (r *RemindStorage) userName(uid int) string {
+	return fmt.Sprintf("im:command:remind:user:%d", uid)
+}
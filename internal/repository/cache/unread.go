@@ -39,6 +39,16 @@ func (u *UnreadStorage) PipeIncr(ctx context.Context, pipe redis.Pipeliner, uid,
 	pipe.Expire(ctx, name, unreadExpireAt)
 }
 
+// PipeIncrMention @消息未读数自增
+// @params uid     被@的用户ID
+// @params mode    对话模式 1私信 2群聊
+// @params sender  发送者ID(群ID)
+func (u *UnreadStorage) PipeIncrMention(ctx context.Context, pipe redis.Pipeliner, uid, mode, sender int) {
+	name := u.mentionName(uid, mode, sender)
+	pipe.Incr(ctx, name)
+	pipe.Expire(ctx, name, unreadExpireAt)
+}
+
 // Get 获取消息未读数
 // @params uid     用户ID
 // @params mode    对话模式 1私信 2群聊
@@ -52,12 +62,67 @@ func (u *UnreadStorage) Get(ctx context.Context, uid, mode, sender int) int {
 	return i
 }
 
+// GetMention 获取@消息未读数
+// @params uid     用户ID
+// @params mode    对话模式 1私信 2群聊
+// @params sender  发送者ID(群ID)
+func (u *UnreadStorage) GetMention(ctx context.Context, uid, mode, sender int) int {
+	i, err := u.redis.Get(ctx, u.mentionName(uid, mode, sender)).Int()
+	if err != nil {
+		return 0
+	}
+
+	return i
+}
+
+// UnreadKey 批量查询未读数的条件
+type UnreadKey struct {
+	Uid     int  // 用户ID
+	Mode    int  // 对话模式 1私信 2群聊
+	Sender  int  // 发送者ID(群ID)
+	Mention bool // 是否查询@消息未读数
+}
+
+// MGet 批量获取消息未读数，返回值与查询条件一一对应
+func (u *UnreadStorage) MGet(ctx context.Context, keys []UnreadKey) []int {
+	items := make([]int, len(keys))
+
+	pipe := u.redis.Pipeline()
+	cmds := make([]*redis.StringCmd, len(keys))
+	for i, key := range keys {
+		name := u.name(key.Uid, key.Mode, key.Sender)
+		if key.Mention {
+			name = u.mentionName(key.Uid, key.Mode, key.Sender)
+		}
+
+		cmds[i] = pipe.Get(ctx, name)
+	}
+
+	_, _ = pipe.Exec(ctx)
+
+	for i, cmd := range cmds {
+		if num, err := cmd.Int(); err == nil {
+			items[i] = num
+		}
+	}
+
+	return items
+}
+
+// DelMention 删除@消息未读数
+// @params uid     用户ID
+// @params mode    对话模式 1私信 2群聊
+// @params sender  发送者ID(群ID)
+func (u *UnreadStorage) DelMention(ctx context.Context, uid, mode, sender int) {
+	u.redis.Del(ctx, u.mentionName(uid, mode, sender))
+}
+
 // Del 删除消息未读数
 // @params uid     用户ID
 // @params mode    对话模式 1私信 2群聊
 // @params sender  发送者ID(群ID)
 func (u *UnreadStorage) Del(ctx context.Context, uid, mode, sender int) {
-	u.redis.Del(ctx, u.name(uid, mode, sender))
+	u.redis.Del(ctx, u.name(uid, mode, sender), u.mentionName(uid, mode, sender))
 }
 
 // Reset 消息未读数重置
@@ -74,3 +139,9 @@ func (u *UnreadStorage) Reset(ctx context.Context, uid, mode, sender int) {
 func (u *UnreadStorage) name(uid, mode, sender int) string {
 	return fmt.Sprintf("im:unread:%d:%d_%d", uid, mode, sender)
 }
+
+// @消息未读数缓存
+// im:unread:uid:mention:mode_sender
+func (u *UnreadStorage) mentionName(uid, mode, sender int) string {
+	return fmt.Sprintf("im:unread:%d:mention:%d_%d", uid, mode, sender)
+}
@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go-chat/internal/pkg/jsonutil"
+)
+
+// UploadChallengeStorage 秒传持有性校验区间
+type UploadChallengeStorage struct {
+	redis *redis.Client
+}
+
+func NewUploadChallengeStorage(rds *redis.Client) *UploadChallengeStorage {
+	return &UploadChallengeStorage{rds}
+}
+
+// UploadChallenge 客户端需提交文件在该区间内容的 SHA-256 以证明持有文件
+type UploadChallenge struct {
+	Offset int64 `json:"offset"` // 区间起始位置
+	Length int64 `json:"length"` // 区间长度
+}
+
+func (u *UploadChallengeStorage) Set(ctx context.Context, uid int, hash string, value *UploadChallenge, expire time.Duration) error {
+	return u.redis.Set(ctx, u.name(uid, hash), jsonutil.Encode(value), expire).Err()
+}
+
+// Take 读取并删除校验区间，每个区间只能校验一次
+func (u *UploadChallengeStorage) Take(ctx context.Context, uid int, hash string) (*UploadChallenge, error) {
+	value, err := u.redis.GetDel(ctx, u.name(uid, hash)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var data UploadChallenge
+	if err := jsonutil.Decode(value, &data); err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+func (u *UploadChallengeStorage) name(uid int, hash string) string {
+	return fmt.Sprintf("im:upload:challenge:%d:%s", uid, hash)
+}
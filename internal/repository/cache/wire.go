@@ -19,4 +19,11 @@ var ProviderSet = wire.NewSet(
 	NewVote,
 	NewUnreadStorage,
 	NewGroupApplyStorage,
+	NewOpenNonceStorage,
+	NewRemindStorage,
+	NewPreAuthStorage,
+	NewAuthSessionStorage,
+	NewOidcStateStorage,
+	NewLoginLimitStorage,
+	NewUploadChallengeStorage,
 )
@@ -0,0 +1,54 @@
+package model
+
+import "time"
+
+const (
+	AdminAuditTargetUser    = "user"     // 用户
+	AdminAuditTargetGroup   = "group"    // 群组
+	AdminAuditTargetMessage = "message"  // 消息
+	AdminAuditTargetRole    = "role"     // 角色
+	AdminAuditTargetAdmin   = "admin"    // 管理员
+	AdminAuditTargetRobot   = "robot"    // 机器人
+	AdminAuditTargetOpenApp = "open_app" // 开放平台应用
+
+	AdminAuditUserDisable         = "user.disable"           // 禁用账号
+	AdminAuditUserEnable          = "user.enable"            // 启用账号
+	AdminAuditUserResetPassword   = "user.reset_password"    // 重置密码
+	AdminAuditUserForceLogout     = "user.force_logout"      // 强制下线
+	AdminAuditGroupDismiss        = "group.dismiss"          // 解散群组
+	AdminAuditGroupTransfer       = "group.transfer"         // 转让群主
+	AdminAuditGroupMute           = "group.mute"             // 全员禁言
+	AdminAuditGroupMemberMute     = "group.member_mute"      // 禁言群成员
+	AdminAuditMessageView         = "message.view"           // 查看消息
+	AdminAuditMessageRevoke       = "message.revoke"         // 撤回消息
+	AdminAuditRoleCreate          = "role.create"            // 创建角色
+	AdminAuditRoleUpdate          = "role.update"            // 更新角色
+	AdminAuditRoleDelete          = "role.delete"            // 删除角色
+	AdminAuditAdminCreate         = "admin.create"           // 创建管理员
+	AdminAuditAdminUpdate         = "admin.update"           // 更新管理员
+	AdminAuditAdminDelete         = "admin.delete"           // 删除管理员
+	AdminAuditAdminResetTwoFactor = "admin.reset_two_factor" // 重置管理员两步验证
+	AdminAuditRobotCreate         = "robot.create"           // 创建机器人
+	AdminAuditRobotUpdate         = "robot.update"           // 更新机器人
+	AdminAuditRobotDelete         = "robot.delete"           // 删除机器人
+	AdminAuditRobotResetSecret    = "robot.reset_secret"     // 重置机器人密钥
+	AdminAuditOpenAppCreate       = "open_app.create"        // 创建开放平台应用
+	AdminAuditOpenAppUpdate       = "open_app.update"        // 更新开放平台应用
+	AdminAuditOpenAppDelete       = "open_app.delete"        // 删除开放平台应用
+	AdminAuditOpenAppResetSecret  = "open_app.reset_secret"  // 重置开放平台应用密钥
+)
+
+type AdminAuditLog struct {
+	Id         int       `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"` // 日志ID
+	AdminId    int       `gorm:"column:admin_id;" json:"admin_id"`               // 管理员ID
+	Action     string    `gorm:"column:action;" json:"action"`                   // 操作类型
+	TargetType string    `gorm:"column:target_type;" json:"target_type"`         // 操作对象类型
+	TargetId   string    `gorm:"column:target_id;" json:"target_id"`             // 操作对象ID
+	Detail     string    `gorm:"column:detail;" json:"detail"`                   // 操作详情(json)
+	Ip         string    `gorm:"column:ip;" json:"ip"`                           // 操作IP
+	CreatedAt  time.Time `gorm:"column:created_at;" json:"created_at"`           // 操作时间
+}
+
+func (AdminAuditLog) TableName() string {
+	return "admin_audit_log"
+}
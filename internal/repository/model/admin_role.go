@@ -0,0 +1,55 @@
+package model
+
+import "time"
+
+const (
+	AdminRoleSuperAdmin = "superadmin" // 超级管理员角色标识
+
+	AdminPermissionAll = "*" // 全部权限
+
+	AdminPermissionUserView      = "user.view"      // 查看用户
+	AdminPermissionUserManage    = "user.manage"    // 管理用户
+	AdminPermissionGroupView     = "group.view"     // 查看群组
+	AdminPermissionGroupManage   = "group.manage"   // 管理群组
+	AdminPermissionMessageView   = "message.view"   // 查看消息
+	AdminPermissionMessageManage = "message.manage" // 管理消息
+	AdminPermissionAuditView     = "audit.view"     // 查看审计日志
+	AdminPermissionRoleManage    = "role.manage"    // 管理角色
+	AdminPermissionAdminManage   = "admin.manage"   // 管理管理员账号
+	AdminPermissionRobotManage   = "robot.manage"   // 管理机器人
+	AdminPermissionOpenManage    = "open.manage"    // 管理开放平台应用
+)
+
+// AdminPermissions 全部可分配的权限
+var AdminPermissions = []AdminPermission{
+	{Code: AdminPermissionUserView, Name: "查看用户"},
+	{Code: AdminPermissionUserManage, Name: "管理用户"},
+	{Code: AdminPermissionGroupView, Name: "查看群组"},
+	{Code: AdminPermissionGroupManage, Name: "管理群组"},
+	{Code: AdminPermissionMessageView, Name: "查看消息"},
+	{Code: AdminPermissionMessageManage, Name: "管理消息"},
+	{Code: AdminPermissionAuditView, Name: "查看审计日志"},
+	{Code: AdminPermissionRoleManage, Name: "管理角色"},
+	{Code: AdminPermissionAdminManage, Name: "管理管理员账号"},
+	{Code: AdminPermissionRobotManage, Name: "管理机器人"},
+	{Code: AdminPermissionOpenManage, Name: "管理开放平台应用"},
+}
+
+type AdminPermission struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+type AdminRole struct {
+	Id          int       `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"` // 角色ID
+	Name        string    `gorm:"column:name;" json:"name"`                       // 角色名称
+	Code        string    `gorm:"column:code;" json:"code"`                       // 角色标识
+	Permissions string    `gorm:"column:permissions;" json:"permissions"`         // 权限列表(json)
+	Remark      string    `gorm:"column:remark;" json:"remark"`                   // 角色备注
+	CreatedAt   time.Time `gorm:"column:created_at;" json:"created_at"`           // 创建时间
+	UpdatedAt   time.Time `gorm:"column:updated_at;" json:"updated_at"`           // 更新时间
+}
+
+func (AdminRole) TableName() string {
+	return "admin_role"
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+const (
+	EmailOutboxStatusWait    = 1 // 等待发送
+	EmailOutboxStatusSuccess = 2 // 发送成功
+	EmailOutboxStatusFail    = 3 // 发送失败
+)
+
+type EmailOutbox struct {
+	Id        int        `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"` // 邮件ID
+	UserId    int        `gorm:"column:user_id;" json:"user_id"`                 // 收件用户ID，非用户邮件为 0
+	Email     string     `gorm:"column:email;" json:"email"`                     // 收件邮箱
+	Template  string     `gorm:"column:template;" json:"template"`               // 邮件模板
+	Subject   string     `gorm:"column:subject;" json:"subject"`                 // 邮件主题
+	Body      string     `gorm:"column:body;" json:"body"`                       // 邮件正文
+	Status    int        `gorm:"column:status;" json:"status"`                   // 发送状态[1:等待发送;2:发送成功;3:发送失败;]
+	Attempts  int        `gorm:"column:attempts;" json:"attempts"`               // 已尝试发送次数
+	Reason    string     `gorm:"column:reason;" json:"reason"`                   // 最近一次发送失败的原因
+	SentAt    *time.Time `gorm:"column:sent_at;" json:"sent_at"`                 // 发送成功时间
+	CreatedAt time.Time  `gorm:"column:created_at;" json:"created_at"`           // 创建时间
+	UpdatedAt time.Time  `gorm:"column:updated_at;" json:"updated_at"`           // 更新时间
+}
+
+func (EmailOutbox) TableName() string {
+	return "email_outbox"
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+)
+
+// FileObject 内容寻址的文件对象，相同内容的文件只保存一份
+type FileObject struct {
+	Id        int       `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"` // 文件对象ID
+	Hash      string    `gorm:"column:hash;" json:"hash"`                       // 文件SHA-256
+	Drive     int       `gorm:"column:drive;" json:"drive"`                     // 驱动类型[1:local;2:minio;3:s3;4:webdav;]
+	Path      string    `gorm:"column:path;" json:"path"`                       // 文件保存路径(私有桶)
+	FileSize  int64     `gorm:"column:file_size;" json:"file_size"`             // 文件大小
+	RefCount  int       `gorm:"column:ref_count;" json:"ref_count"`             // 引用计数
+	CreatedAt time.Time `gorm:"column:created_at;" json:"created_at"`           // 创建时间
+	UpdatedAt time.Time `gorm:"column:updated_at;" json:"updated_at"`           // 更新时间
+}
+
+func (FileObject) TableName() string {
+	return "file_object"
+}
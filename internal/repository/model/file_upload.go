@@ -4,10 +4,16 @@ import (
 	"time"
 )
 
+const (
+	FileScanStatusNone     = 0 // 未扫描
+	FileScanStatusSafe     = 1 // 安全
+	FileScanStatusRejected = 2 // 已拒绝
+)
+
 type FileUpload struct {
 	Id           int       `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"` // 临时文件ID
 	Type         int       `gorm:"column:type;" json:"type"`                       // 文件属性[1:合并文件;2:拆分文件]
-	Drive        int       `gorm:"column:drive;" json:"drive"`                     // 驱动类型[1:local;2:minio;]
+	Drive        int       `gorm:"column:drive;" json:"drive"`                     // 驱动类型[1:local;2:minio;3:s3;4:webdav;]
 	UploadId     string    `gorm:"column:upload_id;" json:"upload_id"`             // 临时文件hash名
 	UserId       int       `gorm:"column:user_id;" json:"user_id"`                 // 上传的用户ID
 	OriginalName string    `gorm:"column:original_name;" json:"original_name"`     // 原文件名
@@ -16,6 +22,8 @@ type FileUpload struct {
 	Path         string    `gorm:"column:path;" json:"path"`                       // 临时保存路径
 	FileExt      string    `gorm:"column:file_ext;" json:"file_ext"`               // 文件后缀名
 	FileSize     int64     `gorm:"column:file_size;" json:"file_size"`             // 文件大小
+	Hash         string    `gorm:"column:hash;" json:"hash"`                       // 文件SHA-256
+	ScanStatus   int       `gorm:"column:scan_status;" json:"scan_status"`         // 安全扫描状态[0:未扫描;1:安全;2:已拒绝;]
 	IsDelete     int       `gorm:"column:is_delete;" json:"is_delete"`             // 文件是否删除[0:否;1:是;]
 	Attr         string    `gorm:"column:attr;" json:"attr"`                       // 额外参数json
 	CreatedAt    time.Time `gorm:"column:created_at;" json:"created_at"`           // 更新时间
@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+const (
+	OpenAppStatusNormal  = 1 // 正常
+	OpenAppStatusDisable = 2 // 已禁用
+
+	OpenScopeMessageSend = "message.send" // 发送消息
+	OpenScopeFileUpload  = "file.upload"  // 上传文件
+	OpenScopeGroupMember = "group.member" // 查询群成员
+)
+
+// OpenScopes 全部可授权的开放平台权限范围
+var OpenScopes = []OpenScope{
+	{Code: OpenScopeMessageSend, Name: "发送消息"},
+	{Code: OpenScopeFileUpload, Name: "上传文件"},
+	{Code: OpenScopeGroupMember, Name: "查询群成员"},
+}
+
+type OpenScope struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+type OpenApp struct {
+	Id        int       `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"` // 应用ID
+	AppName   string    `gorm:"column:app_name;" json:"app_name"`               // 应用名称
+	AppKey    string    `gorm:"column:app_key;" json:"app_key"`                 // 应用标识
+	AppSecret string    `gorm:"column:app_secret;" json:"-"`                    // 应用密钥
+	RobotId   int       `gorm:"column:robot_id;" json:"robot_id"`               // 绑定机器人ID
+	Scopes    string    `gorm:"column:scopes;" json:"scopes"`                   // 权限范围(json)
+	Status    int       `gorm:"column:status;" json:"status"`                   // 状态[1:正常;2:已禁用;]
+	Remark    string    `gorm:"column:remark;" json:"remark"`                   // 备注
+	CreatedAt time.Time `gorm:"column:created_at;" json:"created_at"`           // 创建时间
+	UpdatedAt time.Time `gorm:"column:updated_at;" json:"updated_at"`           // 更新时间
+}
+
+func (OpenApp) TableName() string {
+	return "open_app"
+}
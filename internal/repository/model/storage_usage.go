@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	StorageOwnerUser  = 1 // 用户
+	StorageOwnerGroup = 2 // 群组
+)
+
+type StorageUsage struct {
+	Id        int       `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"` // 自增ID
+	OwnerType int       `gorm:"column:owner_type;" json:"owner_type"`           // 归属类型[1:用户;2:群组;]
+	OwnerId   int       `gorm:"column:owner_id;" json:"owner_id"`               // 用户ID或群组ID
+	UsedSize  int64     `gorm:"column:used_size;" json:"used_size"`             // 已使用空间(字节)
+	FileCount int       `gorm:"column:file_count;" json:"file_count"`           // 文件数量
+	CreatedAt time.Time `gorm:"column:created_at;" json:"created_at"`           // 创建时间
+	UpdatedAt time.Time `gorm:"column:updated_at;" json:"updated_at"`           // 更新时间
+}
+
+func (StorageUsage) TableName() string {
+	return "storage_usage"
+}
@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+const (
+	TalkExportStatusWait    = 1 // 等待导出
+	TalkExportStatusRunning = 2 // 导出中
+	TalkExportStatusSuccess = 3 // 导出完成
+	TalkExportStatusFail    = 4 // 导出失败
+)
+
+type TalkExport struct {
+	Id        int       `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"` // 导出任务ID
+	UserId    int       `gorm:"column:user_id;" json:"user_id"`                 // 申请导出的用户ID
+	TalkMode  int       `gorm:"column:talk_mode;" json:"talk_mode"`             // 对话类型[1:私聊;2:群聊;]
+	ToFromId  int       `gorm:"column:to_from_id;" json:"to_from_id"`           // 接收者ID（用户ID 或 群ID）
+	StartTime time.Time `gorm:"column:start_time;" json:"start_time"`           // 导出开始时间
+	EndTime   time.Time `gorm:"column:end_time;" json:"end_time"`               // 导出结束时间
+	Status    int       `gorm:"column:status;" json:"status"`                   // 导出状态[1:等待导出;2:导出中;3:导出完成;4:导出失败;]
+	Drive     int       `gorm:"column:drive;" json:"drive"`                     // 文件驱动[1:local;2:minio;3:s3;4:webdav;]
+	Path      string    `gorm:"column:path;" json:"path"`                       // 压缩包地址（私有桶相对地址）
+	Size      int       `gorm:"column:size;" json:"size"`                       // 压缩包大小
+	MsgNum    int       `gorm:"column:msg_num;" json:"msg_num"`                 // 导出消息数
+	Reason    string    `gorm:"column:reason;" json:"reason"`                   // 失败原因
+	CreatedAt time.Time `gorm:"column:created_at;" json:"created_at"`           // 创建时间
+	UpdatedAt time.Time `gorm:"column:updated_at;" json:"updated_at"`           // 更新时间
+}
+
+func (TalkExport) TableName() string {
+	return "talk_export"
+}
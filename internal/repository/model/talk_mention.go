@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+type TalkMention struct {
+	Id        int       `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"` // 记录ID
+	UserId    int       `gorm:"column:user_id;" json:"user_id"`                 // 被@的用户ID
+	GroupId   int       `gorm:"column:group_id;" json:"group_id"`               // 群组ID
+	MsgId     string    `gorm:"column:msg_id;" json:"msg_id"`                   // 消息ID
+	FromId    int       `gorm:"column:from_id;" json:"from_id"`                 // 消息发送者ID
+	IsAll     int       `gorm:"column:is_all;" json:"is_all"`                   // 是否@所有人[1:是;2:否;]
+	IsRead    int       `gorm:"column:is_read;" json:"is_read"`                 // 是否已读[1:是;2:否;]
+	CreatedAt time.Time `gorm:"column:created_at;" json:"created_at"`           // 创建时间
+	UpdatedAt time.Time `gorm:"column:updated_at;" json:"updated_at"`           // 更新时间
+}
+
+func (TalkMention) TableName() string {
+	return "talk_mention"
+}
+
+// TalkMentionItem @我的消息列表项
+type TalkMentionItem struct {
+	Id          int       `json:"id"`
+	GroupId     int       `json:"group_id"`
+	GroupName   string    `json:"group_name"`
+	GroupAvatar string    `json:"group_avatar"`
+	MsgId       string    `json:"msg_id"`
+	FromId      int       `json:"from_id"`
+	Nickname    string    `json:"nickname"`
+	Avatar      string    `json:"avatar"`
+	MsgType     int       `json:"msg_type"`
+	Extra       string    `json:"extra"`
+	IsRevoked   int       `json:"is_revoked"`
+	IsAll       int       `json:"is_all"`
+	IsRead      int       `json:"is_read"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -62,11 +62,13 @@ type TalkRecordExtraFile struct {
 }
 
 type TalkRecordExtraImage struct {
-	Name   string `json:"name"`   // 图片名称
-	Size   int    `json:"size"`   // 图片大小
-	Url    string `json:"url"`    // 图片地址
-	Width  int    `json:"width"`  // 图片宽度
-	Height int    `json:"height"` // 图片高度
+	Name   string `json:"name"`             // 图片名称
+	Size   int    `json:"size"`             // 图片大小
+	Url    string `json:"url"`              // 图片地址
+	Thumb  string `json:"thumb,omitempty"`  // 缩略图地址
+	Medium string `json:"medium,omitempty"` // 中图地址
+	Width  int    `json:"width"`            // 图片宽度
+	Height int    `json:"height"`           // 图片高度
 }
 
 type TalkRecordExtraAudio struct {
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+const (
+	TwoFactorOwnerUser  = 1 // 用户
+	TwoFactorOwnerAdmin = 2 // 管理员
+)
+
+type TwoFactor struct {
+	Id            int        `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"` // 自增ID
+	OwnerType     int        `gorm:"column:owner_type;" json:"owner_type"`           // 归属类型[1:用户;2:管理员;]
+	OwnerId       int        `gorm:"column:owner_id;" json:"owner_id"`               // 用户ID或管理员ID
+	Secret        string     `gorm:"column:secret;" json:"-"`                        // TOTP 密钥
+	RecoveryCodes string     `gorm:"column:recovery_codes;" json:"-"`                // 恢复码摘要(JSON 数组)
+	LastStep      int64      `gorm:"column:last_step;" json:"last_step"`             // 最后一次使用的时间步
+	IsEnabled     int        `gorm:"column:is_enabled;" json:"is_enabled"`           // 是否已启用[1:是;2:否;]
+	EnabledAt     *time.Time `gorm:"column:enabled_at;" json:"enabled_at"`           // 启用时间
+	CreatedAt     time.Time  `gorm:"column:created_at;" json:"created_at"`           // 创建时间
+	UpdatedAt     time.Time  `gorm:"column:updated_at;" json:"updated_at"`           // 更新时间
+}
+
+func (TwoFactor) TableName() string {
+	return "two_factor"
+}
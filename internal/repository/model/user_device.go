@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+type UserDevice struct {
+	Id        int       `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"` // 设备ID
+	UserId    int       `gorm:"column:user_id;" json:"user_id"`                 // 用户ID
+	SessionId string    `gorm:"column:session_id;" json:"session_id"`           // 登录会话ID
+	Provider  string    `gorm:"column:provider;" json:"provider"`               // 推送服务[apns;fcm;]
+	Platform  string    `gorm:"column:platform;" json:"platform"`               // 设备平台[ios;android;]
+	Token     string    `gorm:"column:token;" json:"token"`                     // 设备推送令牌
+	CreatedAt time.Time `gorm:"column:created_at;" json:"created_at"`           // 创建时间
+	UpdatedAt time.Time `gorm:"column:updated_at;" json:"updated_at"`           // 更新时间
+}
+
+func (UserDevice) TableName() string {
+	return "user_device"
+}
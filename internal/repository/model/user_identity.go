@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// UserIdentity 用户与第三方身份提供方账号的绑定关系
+type UserIdentity struct {
+	Id          int       `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"` // 自增ID
+	UserId      int       `gorm:"column:user_id;" json:"user_id"`                 // 用户ID
+	Provider    string    `gorm:"column:provider;" json:"provider"`               // 身份提供方标识
+	Subject     string    `gorm:"column:subject;" json:"subject"`                 // 身份提供方中的用户唯一标识(sub)
+	Email       string    `gorm:"column:email;" json:"email"`                     // 身份提供方中的邮箱
+	LastLoginAt time.Time `gorm:"column:last_login_at;" json:"last_login_at"`     // 最后登录时间
+	CreatedAt   time.Time `gorm:"column:created_at;" json:"created_at"`           // 创建时间
+	UpdatedAt   time.Time `gorm:"column:updated_at;" json:"updated_at"`           // 更新时间
+}
+
+func (UserIdentity) TableName() string {
+	return "user_identity"
+}
@@ -6,6 +6,9 @@ import (
 
 const (
 	UsersGenderDefault = 3
+
+	UsersStatusNormal   = 1 // 正常
+	UsersStatusDisabled = 2 // 已禁用
 )
 
 type Users struct {
@@ -19,6 +22,7 @@ type Users struct {
 	Email     string    `gorm:"column:email;" json:"email"`                     // 用户邮箱
 	Birthday  string    `gorm:"column:birthday;" json:"birthday"`               // 生日
 	IsRobot   int       `gorm:"column:is_robot;" json:"is_robot"`               // 是否机器人[1:否;2:是;]
+	Status    int       `gorm:"column:status;" json:"status"`                   // 账号状态[1:正常;2:已禁用;]
 	CreatedAt time.Time `gorm:"column:created_at;" json:"created_at"`           // 注册时间
 	UpdatedAt time.Time `gorm:"column:updated_at;" json:"updated_at"`           // 更新时间
 }
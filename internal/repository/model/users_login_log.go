@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+type UsersLoginLog struct {
+	Id        int       `gorm:"column:id;primary_key;AUTO_INCREMENT" json:"id"` // 日志ID
+	UserId    int       `gorm:"column:user_id;" json:"user_id"`                 // 用户ID
+	Ip        string    `gorm:"column:ip;" json:"ip"`                           // 登录IP
+	Address   string    `gorm:"column:address;" json:"address"`                 // 登录地址
+	Platform  string    `gorm:"column:platform;" json:"platform"`               // 登录平台
+	Agent     string    `gorm:"column:agent;" json:"agent"`                     // 设备信息
+	CreatedAt time.Time `gorm:"column:created_at;" json:"created_at"`           // 登录时间
+}
+
+func (UsersLoginLog) TableName() string {
+	return "users_login_log"
+}
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+const (
+	DigestFrequencyDaily  = 1 // 每天
+	DigestFrequencyWeekly = 2 // 每周
+)
+
+type UsersSetting struct {
+	UserId          int        `gorm:"column:user_id;primary_key" json:"user_id"`        // 用户ID
+	DigestEnabled   int        `gorm:"column:digest_enabled;" json:"digest_enabled"`     // 离线消息邮件提醒[1:开启;2:关闭;]
+	DigestFrequency int        `gorm:"column:digest_frequency;" json:"digest_frequency"` // 离线消息邮件提醒频率[1:每天;2:每周;]
+	DigestAt        *time.Time `gorm:"column:digest_at;" json:"digest_at"`               // 最近一次发送离线消息邮件的时间
+	CreatedAt       time.Time  `gorm:"column:created_at;" json:"created_at"`             // 创建时间
+	UpdatedAt       time.Time  `gorm:"column:updated_at;" json:"updated_at"`             // 更新时间
+}
+
+func (UsersSetting) TableName() string {
+	return "users_setting"
+}
+
+// DigestInterval 两次离线消息邮件提醒的最小间隔
+func (u UsersSetting) DigestInterval() time.Duration {
+	if u.DigestFrequency == DigestFrequencyWeekly {
+		return 7 * 24 * time.Hour
+	}
+
+	return 24 * time.Hour
+}
@@ -0,0 +1,63 @@
+package repo
+
+import (
+	"context"
+
+	"go-chat/internal/pkg/core"
+	"go-chat/internal/repository/model"
+	"gorm.io/gorm"
+)
+
+type AdminAuditLog struct {
+	core.Repo[model.AdminAuditLog]
+}
+
+func NewAdminAuditLog(db *gorm.DB) *AdminAuditLog {
+	return &AdminAuditLog{Repo: core.NewRepo[model.AdminAuditLog](db)}
+}
+
+type SearchAdminAuditLogOpt struct {
+	AdminId    int
+	Action     string
+	TargetType string
+	TargetId   string
+	Page       int
+	Size       int
+}
+
+// SearchList 分页查询审计日志
+func (a *AdminAuditLog) SearchList(ctx context.Context, opt *SearchAdminAuditLogOpt) ([]*model.AdminAuditLog, int64, error) {
+	where := func(db *gorm.DB) *gorm.DB {
+		if opt.AdminId > 0 {
+			db = db.Where("admin_id = ?", opt.AdminId)
+		}
+
+		if opt.Action != "" {
+			db = db.Where("action = ?", opt.Action)
+		}
+
+		if opt.TargetType != "" {
+			db = db.Where("target_type = ?", opt.TargetType)
+		}
+
+		if opt.TargetId != "" {
+			db = db.Where("target_id = ?", opt.TargetId)
+		}
+
+		return db
+	}
+
+	var total int64
+	if err := where(a.Repo.Model(ctx)).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	items, err := a.Repo.FindAll(ctx, func(db *gorm.DB) {
+		where(db).Order("id desc").Offset((opt.Page - 1) * opt.Size).Limit(opt.Size)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return items, total, nil
+}
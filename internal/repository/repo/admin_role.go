@@ -0,0 +1,15 @@
+package repo
+
+import (
+	"go-chat/internal/pkg/core"
+	"go-chat/internal/repository/model"
+	"gorm.io/gorm"
+)
+
+type AdminRole struct {
+	core.Repo[model.AdminRole]
+}
+
+func NewAdminRole(db *gorm.DB) *AdminRole {
+	return &AdminRole{Repo: core.NewRepo[model.AdminRole](db)}
+}
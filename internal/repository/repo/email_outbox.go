@@ -0,0 +1,25 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"go-chat/internal/pkg/core"
+	"go-chat/internal/repository/model"
+	"gorm.io/gorm"
+)
+
+type EmailOutbox struct {
+	core.Repo[model.EmailOutbox]
+}
+
+func NewEmailOutbox(db *gorm.DB) *EmailOutbox {
+	return &EmailOutbox{Repo: core.NewRepo[model.EmailOutbox](db)}
+}
+
+// FindStale 查询创建于 since 之后、且在 before 之前未再处理过的待发送邮件
+func (e *EmailOutbox) FindStale(ctx context.Context, since time.Time, before time.Time, limit int) ([]*model.EmailOutbox, error) {
+	return e.Repo.FindAll(ctx, func(db *gorm.DB) {
+		db.Where("status = ? and updated_at < ? and created_at > ?", model.EmailOutboxStatusWait, before, since).Order("id asc").Limit(limit)
+	})
+}
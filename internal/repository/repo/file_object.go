@@ -0,0 +1,73 @@
+package repo
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"go-chat/internal/pkg/core"
+	"go-chat/internal/repository/model"
+	"gorm.io/gorm"
+)
+
+type FileObject struct {
+	core.Repo[model.FileObject]
+}
+
+func NewFileObject(db *gorm.DB) *FileObject {
+	return &FileObject{Repo: core.NewRepo[model.FileObject](db)}
+}
+
+func (f *FileObject) FindByHash(ctx context.Context, hash string) (*model.FileObject, error) {
+	return f.Repo.FindByWhere(ctx, "hash = ?", hash)
+}
+
+// IncrRef 增加引用计数，引用计数已归零(等待清理)的对象不可再引用
+func (f *FileObject) IncrRef(ctx context.Context, id int) (bool, error) {
+	affected, err := f.Repo.UpdateByWhere(ctx, map[string]any{
+		"ref_count": gorm.Expr("ref_count + 1"),
+	}, "id = ? and ref_count > 0", id)
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// DecrRef 减少引用计数
+func (f *FileObject) DecrRef(ctx context.Context, id int) error {
+	_, err := f.Repo.UpdateByWhere(ctx, map[string]any{
+		"ref_count": gorm.Expr("ref_count - 1"),
+	}, "id = ? and ref_count > 0", id)
+
+	return err
+}
+
+// IncrRefByPath 按文件路径增加引用计数，文件对象以 objects/xx/xx/{hash} 的路径保存
+func (f *FileObject) IncrRefByPath(ctx context.Context, objectName string) (bool, error) {
+	if !strings.HasPrefix(objectName, "objects/") {
+		return false, nil
+	}
+
+	affected, err := f.Repo.UpdateByWhere(ctx, map[string]any{
+		"ref_count": gorm.Expr("ref_count + 1"),
+	}, "hash = ? and path = ? and ref_count > 0", path.Base(objectName), objectName)
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// DecrRefByPath 按文件路径减少引用计数
+func (f *FileObject) DecrRefByPath(ctx context.Context, objectName string) error {
+	if !strings.HasPrefix(objectName, "objects/") {
+		return nil
+	}
+
+	_, err := f.Repo.UpdateByWhere(ctx, map[string]any{
+		"ref_count": gorm.Expr("ref_count - 1"),
+	}, "hash = ? and path = ? and ref_count > 0", path.Base(objectName), objectName)
+
+	return err
+}
@@ -34,3 +34,39 @@ func (g *Group) SearchOvertList(ctx context.Context, opt *SearchOvertListOpt) ([
 		db.Where("is_dismiss = ?", model.No).Order("created_at desc").Offset((opt.Page - 1) * opt.Size).Limit(opt.Size)
 	})
 }
+
+type SearchGroupListOpt struct {
+	Name      string
+	IsDismiss int
+	Page      int
+	Size      int
+}
+
+// SearchList 分页搜索群组列表
+func (g *Group) SearchList(ctx context.Context, opt *SearchGroupListOpt) ([]*model.Group, int64, error) {
+	where := func(db *gorm.DB) *gorm.DB {
+		if opt.Name != "" {
+			db = db.Where("name like ?", "%"+opt.Name+"%")
+		}
+
+		if opt.IsDismiss > 0 {
+			db = db.Where("is_dismiss = ?", opt.IsDismiss)
+		}
+
+		return db
+	}
+
+	var total int64
+	if err := where(g.Repo.Model(ctx)).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	items, err := g.Repo.FindAll(ctx, func(db *gorm.DB) {
+		where(db).Order("id desc").Offset((opt.Page - 1) * opt.Size).Limit(opt.Size)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return items, total, nil
+}
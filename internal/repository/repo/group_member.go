@@ -64,6 +64,18 @@ func (g *GroupMember) GetMemberIds(ctx context.Context, groupId int) []int {
 	return ids
 }
 
+// FilterMemberIds 筛选出属于群成员的用户ID
+func (g *GroupMember) FilterMemberIds(ctx context.Context, groupId int, uids []int) []int {
+	if len(uids) == 0 {
+		return nil
+	}
+
+	var ids []int
+	_ = g.Repo.Model(ctx).Where("group_id = ? and user_id in ? and is_quit = ?", groupId, uids, model.No).Pluck("user_id", &ids)
+
+	return ids
+}
+
 // GetUserGroupIds 获取所有群成员ID
 func (g *GroupMember) GetUserGroupIds(ctx context.Context, uid int) []int {
 
@@ -0,0 +1,22 @@
+package repo
+
+import (
+	"context"
+
+	"go-chat/internal/pkg/core"
+	"go-chat/internal/repository/model"
+	"gorm.io/gorm"
+)
+
+type OpenApp struct {
+	core.Repo[model.OpenApp]
+}
+
+func NewOpenApp(db *gorm.DB) *OpenApp {
+	return &OpenApp{Repo: core.NewRepo[model.OpenApp](db)}
+}
+
+// FindByAppKey 根据应用标识查询应用
+func (o *OpenApp) FindByAppKey(ctx context.Context, appKey string) (*model.OpenApp, error) {
+	return o.Repo.FindByWhere(ctx, "app_key = ?", appKey)
+}
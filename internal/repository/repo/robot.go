@@ -18,5 +18,22 @@ func NewRobot(db *gorm.DB) *Robot {
 
 // GetLoginRobot 获取登录机器的信息
 func (r *Robot) GetLoginRobot(ctx context.Context) (*model.Robot, error) {
-	return r.Repo.FindByWhere(ctx, "type = ? and status = ?", 1, model.RootStatusNormal)
+	return r.Repo.FindByWhere(ctx, "type = ? and status = ?", model.RobotTypeLogin, model.RootStatusNormal)
+}
+
+// FindWebhookRobots 查询用户ID对应的可接收消息的 Webhook 机器人
+func (r *Robot) FindWebhookRobots(ctx context.Context, userIds []int) ([]*model.Robot, error) {
+	if len(userIds) == 0 {
+		return []*model.Robot{}, nil
+	}
+
+	return r.Repo.FindAll(ctx, func(db *gorm.DB) {
+		db.Where("user_id in ? and type = ? and status = ? and webhook_url <> ''", userIds, model.RobotTypeWebhook, model.RootStatusNormal)
+	})
+}
+
+// IsTalkRobot 判断用户是否为可发送消息的机器人
+func (r *Robot) IsTalkRobot(ctx context.Context, userId int) bool {
+	exist, _ := r.Repo.IsExist(ctx, "user_id = ? and is_talk = ? and status = ?", userId, model.Yes, model.RootStatusNormal)
+	return exist
 }
@@ -0,0 +1,65 @@
+package repo
+
+import (
+	"context"
+
+	"go-chat/internal/pkg/core"
+	"go-chat/internal/repository/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type StorageUsage struct {
+	core.Repo[model.StorageUsage]
+}
+
+func NewStorageUsage(db *gorm.DB) *StorageUsage {
+	return &StorageUsage{Repo: core.NewRepo[model.StorageUsage](db)}
+}
+
+// Find 查询存储使用情况，不存在时返回空记录
+func (s *StorageUsage) Find(ctx context.Context, ownerType int, ownerId int) (*model.StorageUsage, error) {
+	var usage model.StorageUsage
+
+	err := s.Model(ctx).Where("owner_type = ? and owner_id = ?", ownerType, ownerId).Limit(1).Find(&usage).Error
+	if err != nil {
+		return nil, err
+	}
+
+	usage.OwnerType, usage.OwnerId = ownerType, ownerId
+	return &usage, nil
+}
+
+// Incr 增加已使用空间，quota 大于 0 时超出配额返回 false
+func (s *StorageUsage) Incr(ctx context.Context, ownerType int, ownerId int, size int64, quota int64) (bool, error) {
+	err := s.Model(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&model.StorageUsage{
+		OwnerType: ownerType,
+		OwnerId:   ownerId,
+	}).Error
+	if err != nil {
+		return false, err
+	}
+
+	tx := s.Model(ctx).Where("owner_type = ? and owner_id = ?", ownerType, ownerId)
+	if quota > 0 {
+		tx = tx.Where("used_size + ? <= ?", size, quota)
+	}
+
+	res := tx.Updates(map[string]any{
+		"used_size":  gorm.Expr("used_size + ?", size),
+		"file_count": gorm.Expr("file_count + 1"),
+	})
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+// Decr 释放已使用空间
+func (s *StorageUsage) Decr(ctx context.Context, ownerType int, ownerId int, size int64) error {
+	return s.Model(ctx).Where("owner_type = ? and owner_id = ?", ownerType, ownerId).Updates(map[string]any{
+		"used_size":  gorm.Expr("greatest(used_size - ?, 0)", size),
+		"file_count": gorm.Expr("greatest(file_count - 1, 0)"),
+	}).Error
+}
@@ -0,0 +1,15 @@
+package repo
+
+import (
+	"go-chat/internal/pkg/core"
+	"go-chat/internal/repository/model"
+	"gorm.io/gorm"
+)
+
+type TalkExport struct {
+	core.Repo[model.TalkExport]
+}
+
+func NewTalkExport(db *gorm.DB) *TalkExport {
+	return &TalkExport{Repo: core.NewRepo[model.TalkExport](db)}
+}
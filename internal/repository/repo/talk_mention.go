@@ -0,0 +1,90 @@
+package repo
+
+import (
+	"context"
+
+	"go-chat/internal/pkg/core"
+	"go-chat/internal/repository/model"
+	"gorm.io/gorm"
+)
+
+type TalkMention struct {
+	core.Repo[model.TalkMention]
+}
+
+func NewTalkMention(db *gorm.DB) *TalkMention {
+	return &TalkMention{Repo: core.NewRepo[model.TalkMention](db)}
+}
+
+type TalkMentionListOpt struct {
+	UserId     int
+	Cursor     int  // 上一页最后一条记录ID，为 0 时查询第一页
+	Limit      int  // 查询数量
+	UnreadOnly bool // 是否仅查询未读
+}
+
+// List @我的消息列表，按时间倒序
+func (t *TalkMention) List(ctx context.Context, opt *TalkMentionListOpt) ([]*model.TalkMentionItem, error) {
+	fields := []string{
+		"mention.id", "mention.group_id", "mention.msg_id", "mention.from_id",
+		"mention.is_all", "mention.is_read", "mention.created_at",
+		"`group`.name as group_name", "`group`.avatar as group_avatar",
+		"`users`.nickname", "`users`.avatar",
+		"msg.msg_type", "msg.extra", "msg.is_revoked",
+	}
+
+	query := t.Db.WithContext(ctx).Table("talk_mention mention")
+	query.Joins("left join `group` on `group`.id = mention.group_id")
+	query.Joins("left join `users` on `users`.id = mention.from_id")
+	query.Joins("left join talk_group_message msg on msg.msg_id = mention.msg_id")
+	query.Where("mention.user_id = ?", opt.UserId)
+
+	if opt.Cursor > 0 {
+		query.Where("mention.id < ?", opt.Cursor)
+	}
+
+	if opt.UnreadOnly {
+		query.Where("mention.is_read = ?", model.No)
+	}
+
+	var items []*model.TalkMentionItem
+	err := query.Select(fields).Order("mention.id desc").Limit(opt.Limit).Scan(&items).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// CountUnread 未读的@我的消息数
+func (t *TalkMention) CountUnread(ctx context.Context, uid int) (int64, error) {
+	return t.FindCount(ctx, "user_id = ? and is_read = ?", uid, model.No)
+}
+
+// FindUnreadGroupIds 查询有未读@我的消息的群组，ids 不为空时仅查询指定的记录
+func (t *TalkMention) FindUnreadGroupIds(ctx context.Context, uid int, ids []int) ([]int, error) {
+	query := t.Model(ctx).Where("user_id = ? and is_read = ?", uid, model.No)
+	if len(ids) > 0 {
+		query.Where("id in ?", ids)
+	}
+
+	var groupIds []int
+	err := query.Distinct().Pluck("group_id", &groupIds).Error
+	return groupIds, err
+}
+
+// Read 标记为已读，ids 为空时标记全部
+func (t *TalkMention) Read(ctx context.Context, uid int, ids []int) (int64, error) {
+	query := t.Model(ctx).Where("user_id = ? and is_read = ?", uid, model.No)
+	if len(ids) > 0 {
+		query.Where("id in ?", ids)
+	}
+
+	res := query.Update("is_read", model.Yes)
+	return res.RowsAffected, res.Error
+}
+
+// ReadByGroup 将群聊中@我的消息标记为已读
+func (t *TalkMention) ReadByGroup(ctx context.Context, uid int, groupId int) (int64, error) {
+	return t.UpdateByWhere(ctx, map[string]any{"is_read": model.Yes}, "user_id = ? and group_id = ? and is_read = ?", uid, groupId, model.No)
+}
@@ -0,0 +1,27 @@
+package repo
+
+import (
+	"context"
+
+	"go-chat/internal/pkg/core"
+	"go-chat/internal/repository/model"
+	"gorm.io/gorm"
+)
+
+type TwoFactor struct {
+	core.Repo[model.TwoFactor]
+}
+
+func NewTwoFactor(db *gorm.DB) *TwoFactor {
+	return &TwoFactor{Repo: core.NewRepo[model.TwoFactor](db)}
+}
+
+// FindByOwner 查询用户或管理员的两步验证配置
+func (t *TwoFactor) FindByOwner(ctx context.Context, ownerType int, ownerId int) (*model.TwoFactor, error) {
+	return t.Repo.FindByWhere(ctx, "owner_type = ? and owner_id = ?", ownerType, ownerId)
+}
+
+// IsEnabled 是否已开启两步验证
+func (t *TwoFactor) IsEnabled(ctx context.Context, ownerType int, ownerId int) (bool, error) {
+	return t.Repo.IsExist(ctx, "owner_type = ? and owner_id = ? and is_enabled = ?", ownerType, ownerId, model.Yes)
+}
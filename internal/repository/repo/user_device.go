@@ -0,0 +1,76 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"go-chat/internal/pkg/core"
+	"go-chat/internal/repository/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 每个用户最多保留的推送设备数
+const userDeviceMaxNum = 10
+
+type UserDevice struct {
+	core.Repo[model.UserDevice]
+}
+
+func NewUserDevice(db *gorm.DB) *UserDevice {
+	return &UserDevice{Repo: core.NewRepo[model.UserDevice](db)}
+}
+
+// Register 注册推送设备并绑定登录会话，设备令牌已被其它账号注册时转移到当前账号
+func (u *UserDevice) Register(ctx context.Context, uid int, sid string, provider string, platform string, token string) error {
+	err := u.Model(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "provider"}, {Name: "token"}},
+		DoUpdates: clause.AssignmentColumns([]string{"user_id", "session_id", "platform", "updated_at"}),
+	}).Create(&model.UserDevice{
+		UserId:    uid,
+		SessionId: sid,
+		Provider:  provider,
+		Platform:  platform,
+		Token:     token,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}).Error
+	if err != nil {
+		return err
+	}
+
+	// 删除超出数量限制的最早注册的设备
+	var ids []int
+	err = u.Model(ctx).Where("user_id = ?", uid).Order("updated_at desc, id desc").Offset(userDeviceMaxNum).Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return err
+	}
+
+	return u.Db.WithContext(ctx).Delete(&model.UserDevice{}, "id in ?", ids).Error
+}
+
+// FindByUserIds 查询用户的推送设备
+func (u *UserDevice) FindByUserIds(ctx context.Context, uids []int) ([]*model.UserDevice, error) {
+	if len(uids) == 0 {
+		return nil, nil
+	}
+
+	return u.FindAll(ctx, func(db *gorm.DB) {
+		db.Where("user_id in ?", uids)
+	})
+}
+
+// DeleteByToken 删除推送设备
+func (u *UserDevice) DeleteByToken(ctx context.Context, provider string, token string) error {
+	return u.Db.WithContext(ctx).Delete(&model.UserDevice{}, "provider = ? and token = ?", provider, token).Error
+}
+
+// DeleteBySession 删除登录会话绑定的推送设备
+func (u *UserDevice) DeleteBySession(ctx context.Context, uid int, sid string) error {
+	return u.Db.WithContext(ctx).Delete(&model.UserDevice{}, "user_id = ? and session_id = ?", uid, sid).Error
+}
+
+// DeleteByUserId 删除用户的所有推送设备
+func (u *UserDevice) DeleteByUserId(ctx context.Context, uid int) error {
+	return u.Db.WithContext(ctx).Delete(&model.UserDevice{}, "user_id = ?", uid).Error
+}
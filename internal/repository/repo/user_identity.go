@@ -0,0 +1,27 @@
+package repo
+
+import (
+	"context"
+
+	"go-chat/internal/pkg/core"
+	"go-chat/internal/repository/model"
+	"gorm.io/gorm"
+)
+
+type UserIdentity struct {
+	core.Repo[model.UserIdentity]
+}
+
+func NewUserIdentity(db *gorm.DB) *UserIdentity {
+	return &UserIdentity{Repo: core.NewRepo[model.UserIdentity](db)}
+}
+
+// FindBySubject 查询身份提供方账号绑定的用户
+func (u *UserIdentity) FindBySubject(ctx context.Context, provider string, subject string) (*model.UserIdentity, error) {
+	return u.Repo.FindByWhere(ctx, "provider = ? and subject = ?", provider, subject)
+}
+
+// IsBound 用户是否已绑定指定身份提供方的账号
+func (u *UserIdentity) IsBound(ctx context.Context, provider string, uid int) (bool, error) {
+	return u.Repo.IsExist(ctx, "provider = ? and user_id = ?", provider, uid)
+}
@@ -32,6 +32,42 @@ func (u *Users) IsMobileExist(ctx context.Context, mobile string) bool {
 	return exist
 }
 
+type SearchUserListOpt struct {
+	Keyword string // 手机号、昵称或邮箱
+	Status  int
+	Page    int
+	Size    int
+}
+
+// SearchList 分页搜索用户列表
+func (u *Users) SearchList(ctx context.Context, opt *SearchUserListOpt) ([]*model.Users, int64, error) {
+	where := func(db *gorm.DB) *gorm.DB {
+		if opt.Keyword != "" {
+			db = db.Where("mobile like ? or nickname like ? or email like ?", "%"+opt.Keyword+"%", "%"+opt.Keyword+"%", "%"+opt.Keyword+"%")
+		}
+
+		if opt.Status > 0 {
+			db = db.Where("status = ?", opt.Status)
+		}
+
+		return db.Where("is_robot = ?", model.No)
+	}
+
+	var total int64
+	if err := where(u.Repo.Model(ctx)).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	items, err := u.Repo.FindAll(ctx, func(db *gorm.DB) {
+		where(db).Order("id desc").Offset((opt.Page - 1) * opt.Size).Limit(opt.Size)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return items, total, nil
+}
+
 func (u *Users) FindByIdWithCache(ctx context.Context, id int) (*model.Users, error) {
 	return u.tableCache.GetOrSet(ctx, id, func(ctx context.Context) (*model.Users, error) {
 		return u.Repo.FindById(ctx, id)
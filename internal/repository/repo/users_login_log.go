@@ -0,0 +1,41 @@
+package repo
+
+import (
+	"context"
+
+	"go-chat/internal/pkg/core"
+	"go-chat/internal/repository/model"
+	"gorm.io/gorm"
+)
+
+type UsersLoginLog struct {
+	core.Repo[model.UsersLoginLog]
+}
+
+func NewUsersLoginLog(db *gorm.DB) *UsersLoginLog {
+	return &UsersLoginLog{Repo: core.NewRepo[model.UsersLoginLog](db)}
+}
+
+// FindUserLogs 分页查询用户登录日志
+func (u *UsersLoginLog) FindUserLogs(ctx context.Context, uid int, page, size int) ([]*model.UsersLoginLog, int64, error) {
+	var total int64
+	if err := u.Repo.Model(ctx).Where("user_id = ?", uid).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	items, err := u.Repo.FindAll(ctx, func(db *gorm.DB) {
+		db.Where("user_id = ?", uid).Order("id desc").Offset((page - 1) * size).Limit(size)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return items, total, nil
+}
+
+// FindLatest 查询用户最近的登录日志
+func (u *UsersLoginLog) FindLatest(ctx context.Context, uid int, limit int) ([]*model.UsersLoginLog, error) {
+	return u.Repo.FindAll(ctx, func(db *gorm.DB) {
+		db.Where("user_id = ?", uid).Order("id desc").Limit(limit)
+	})
+}
@@ -0,0 +1,52 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"go-chat/internal/pkg/core"
+	"go-chat/internal/repository/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UsersSetting struct {
+	core.Repo[model.UsersSetting]
+}
+
+func NewUsersSetting(db *gorm.DB) *UsersSetting {
+	return &UsersSetting{Repo: core.NewRepo[model.UsersSetting](db)}
+}
+
+// Find 查询用户设置，不存在时返回默认设置
+func (u *UsersSetting) Find(ctx context.Context, uid int) (*model.UsersSetting, error) {
+	setting := model.UsersSetting{
+		DigestEnabled:   model.Yes,
+		DigestFrequency: model.DigestFrequencyDaily,
+	}
+
+	err := u.Model(ctx).Where("user_id = ?", uid).Limit(1).Find(&setting).Error
+	if err != nil {
+		return nil, err
+	}
+
+	setting.UserId = uid
+	return &setting, nil
+}
+
+// Save 更新用户设置，不存在时按默认设置创建
+func (u *UsersSetting) Save(ctx context.Context, uid int, data map[string]any) error {
+	err := u.Model(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&model.UsersSetting{
+		UserId:          uid,
+		DigestEnabled:   model.Yes,
+		DigestFrequency: model.DigestFrequencyDaily,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}).Error
+	if err != nil {
+		return err
+	}
+
+	_, err = u.UpdateByWhere(ctx, data, "user_id = ?", uid)
+	return err
+}
@@ -30,4 +30,17 @@ var ProviderSet = wire.NewSet(
 	NewRobot,
 	NewSequence,
 	NewAdmin,
+	NewTalkExport,
+	NewUsersLoginLog,
+	NewAdminAuditLog,
+	NewAdminRole,
+	NewFileObject,
+	NewStorageUsage,
+	NewOpenApp,
+	NewTwoFactor,
+	NewUserIdentity,
+	NewEmailOutbox,
+	NewUsersSetting,
+	NewUserDevice,
+	NewTalkMention,
 )
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/encrypt"
+	"go-chat/internal/pkg/utils"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+)
+
+var _ IAdminAccountService = (*AdminAccountService)(nil)
+
+type IAdminAccountService interface {
+	// Create 创建管理员账号
+	Create(ctx context.Context, operatorId int, opt *AdminAccountOpt) (*model.Admin, error)
+	// Update 更新管理员账号
+	Update(ctx context.Context, operatorId int, adminId int, opt *AdminAccountOpt) error
+	// Delete 删除管理员账号
+	Delete(ctx context.Context, operatorId int, adminId int) error
+	// CheckManageable 校验操作者是否可管理指定管理员账号
+	CheckManageable(ctx context.Context, operatorId int, adminId int) error
+}
+
+type AdminAccountService struct {
+	AdminRepo        *repo.Admin
+	AdminRoleRepo    *repo.AdminRole
+	AdminRoleService IAdminRoleService
+}
+
+type AdminAccountOpt struct {
+	Username     string
+	Password     string // 为空时不修改密码
+	Email        string
+	Mobile       string
+	RoleId       int
+	Status       int
+	TotpRequired int // 强制两步验证[1:是;2:否;]，为空时视为否
+}
+
+func (s *AdminAccountService) Create(ctx context.Context, operatorId int, opt *AdminAccountOpt) (*model.Admin, error) {
+	if opt.Password == "" {
+		return nil, errors.New("密码不能为空")
+	}
+
+	if err := s.check(ctx, operatorId, 0, opt); err != nil {
+		return nil, err
+	}
+
+	admin := &model.Admin{
+		Username:     opt.Username,
+		Password:     encrypt.HashPassword(opt.Password),
+		Gender:       model.UsersGenderDefault,
+		Mobile:       opt.Mobile,
+		Email:        opt.Email,
+		Status:       int8(opt.Status),
+		RoleId:       opt.RoleId,
+		TotpRequired: int8(opt.totpRequired()),
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+
+	if err := s.AdminRepo.Create(ctx, admin); err != nil {
+		return nil, err
+	}
+
+	return admin, nil
+}
+
+func (s *AdminAccountService) Update(ctx context.Context, operatorId int, adminId int, opt *AdminAccountOpt) error {
+	if err := s.CheckManageable(ctx, operatorId, adminId); err != nil {
+		return err
+	}
+
+	if err := s.check(ctx, operatorId, adminId, opt); err != nil {
+		return err
+	}
+
+	data := map[string]any{
+		"username":      opt.Username,
+		"email":         opt.Email,
+		"mobile":        opt.Mobile,
+		"role_id":       opt.RoleId,
+		"status":        opt.Status,
+		"totp_required": opt.totpRequired(),
+		"updated_at":    time.Now(),
+	}
+
+	if opt.Password != "" {
+		data["password"] = encrypt.HashPassword(opt.Password)
+	}
+
+	_, err := s.AdminRepo.UpdateById(ctx, adminId, data)
+	return err
+}
+
+func (s *AdminAccountService) Delete(ctx context.Context, operatorId int, adminId int) error {
+	if err := s.CheckManageable(ctx, operatorId, adminId); err != nil {
+		return err
+	}
+
+	return s.AdminRepo.Delete(ctx, adminId)
+}
+
+func (s *AdminAccountService) CheckManageable(ctx context.Context, operatorId int, adminId int) error {
+	if operatorId == adminId {
+		return errors.New("不能操作当前登录账号")
+	}
+
+	admin, err := s.find(ctx, adminId)
+	if err != nil {
+		return err
+	}
+
+	role, err := s.AdminRoleRepo.FindById(ctx, admin.RoleId)
+	if err != nil {
+		if utils.IsSqlNoRows(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	// 不能管理权限高于自身的账号(包括超级管理员)
+	if !s.AdminRoleService.CanGrant(ctx, operatorId, DecodeAdminPermissions(role.Permissions)) {
+		return entity.ErrPermissionDenied
+	}
+
+	return nil
+}
+
+func (o *AdminAccountOpt) totpRequired() int {
+	if o.TotpRequired == model.Yes {
+		return model.Yes
+	}
+
+	return model.No
+}
+
+func (s *AdminAccountService) check(ctx context.Context, operatorId int, adminId int, opt *AdminAccountOpt) error {
+	if exist, _ := s.AdminRepo.IsExist(ctx, "username = ? and id != ?", opt.Username, adminId); exist {
+		return errors.New("账号已存在")
+	}
+
+	if exist, _ := s.AdminRepo.IsExist(ctx, "email = ? and id != ?", opt.Email, adminId); exist {
+		return errors.New("邮箱已存在")
+	}
+
+	role, err := s.AdminRoleRepo.FindById(ctx, opt.RoleId)
+	if err != nil {
+		if utils.IsSqlNoRows(err) {
+			return errors.New("角色不存在")
+		}
+
+		return err
+	}
+
+	// 超级管理员角色仅允许超级管理员分配，其余角色不能超出操作者自身权限
+	if role.Code == model.AdminRoleSuperAdmin && !s.AdminRoleService.CanGrant(ctx, operatorId, []string{model.AdminPermissionAll}) {
+		return errors.New("无权分配超级管理员角色")
+	}
+
+	if !s.AdminRoleService.CanGrant(ctx, operatorId, DecodeAdminPermissions(role.Permissions)) {
+		return errors.New("不能分配权限超出自身的角色")
+	}
+
+	return nil
+}
+
+func (s *AdminAccountService) find(ctx context.Context, adminId int) (*model.Admin, error) {
+	admin, err := s.AdminRepo.FindById(ctx, adminId)
+	if err != nil {
+		if utils.IsSqlNoRows(err) {
+			return nil, entity.ErrDataNotFound
+		}
+
+		return nil, err
+	}
+
+	return admin, nil
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go-chat/internal/pkg/jsonutil"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+)
+
+var _ IAdminAuditService = (*AdminAuditService)(nil)
+
+type IAdminAuditService interface {
+	// Record 记录管理员操作日志
+	Record(ctx context.Context, opt *AdminAuditRecordOpt) error
+}
+
+type AdminAuditService struct {
+	AdminAuditLogRepo *repo.AdminAuditLog
+}
+
+type AdminAuditRecordOpt struct {
+	AdminId    int
+	Action     string
+	TargetType string
+	TargetId   string
+	Detail     any
+	Ip         string
+}
+
+func (s *AdminAuditService) Record(ctx context.Context, opt *AdminAuditRecordOpt) error {
+	detail := "{}"
+	if opt.Detail != nil {
+		detail = jsonutil.Encode(opt.Detail)
+	}
+
+	return s.AdminAuditLogRepo.Create(ctx, &model.AdminAuditLog{
+		AdminId:    opt.AdminId,
+		Action:     opt.Action,
+		TargetType: opt.TargetType,
+		TargetId:   opt.TargetId,
+		Detail:     detail,
+		Ip:         opt.Ip,
+		CreatedAt:  time.Now(),
+	})
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/utils"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+	"go-chat/internal/service/message"
+	"gorm.io/gorm"
+)
+
+var _ IAdminGroupService = (*AdminGroupService)(nil)
+
+type IAdminGroupService interface {
+	// Dismiss 解散群组
+	Dismiss(ctx context.Context, groupId int) error
+	// Transfer 转让群主
+	Transfer(ctx context.Context, groupId int, userId int) error
+	// Mute 全员禁言
+	Mute(ctx context.Context, groupId int, status int) error
+	// MemberMute 禁言群成员
+	MemberMute(ctx context.Context, groupId int, userId int, status int) error
+	// FindMessage 根据消息ID查询消息
+	FindMessage(ctx context.Context, talkMode int, msgId string) (any, error)
+	// RevokeMessage 撤回任意消息
+	RevokeMessage(ctx context.Context, talkMode int, msgId string) error
+}
+
+type AdminGroupService struct {
+	*repo.Source
+	GroupRepo          *repo.Group
+	GroupMemberRepo    *repo.GroupMember
+	UsersRepo          *repo.Users
+	GroupService       IGroupService
+	GroupMemberService IGroupMemberService
+	TalkService        ITalkService
+	Message            message.IService
+}
+
+func (s *AdminGroupService) Dismiss(ctx context.Context, groupId int) error {
+	group, err := s.find(ctx, groupId)
+	if err != nil {
+		return err
+	}
+
+	if err := s.GroupService.Dismiss(ctx, group.Id, group.CreatorId); err != nil {
+		return err
+	}
+
+	return s.Message.CreateGroupSysMessage(ctx, message.CreateGroupSysMessageOption{
+		GroupId: group.Id,
+		Content: "该群已被管理员解散！",
+	})
+}
+
+func (s *AdminGroupService) Transfer(ctx context.Context, groupId int, userId int) error {
+	group, err := s.find(ctx, groupId)
+	if err != nil {
+		return err
+	}
+
+	owner, err := s.GroupMemberRepo.FindByWhere(ctx, "group_id = ? and leader = ? and is_quit = ?", groupId, model.GroupMemberLeaderOwner, model.No)
+	if err != nil && !utils.IsSqlNoRows(err) {
+		return err
+	}
+
+	if owner != nil && owner.UserId == userId {
+		return errors.New("该成员已是群主")
+	}
+
+	if !s.GroupMemberRepo.IsMember(ctx, groupId, userId, false) {
+		return errors.New("该用户不是群成员")
+	}
+
+	user, err := s.UsersRepo.FindByIdWithCache(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	ownerId := group.CreatorId
+	if owner != nil {
+		ownerId = owner.UserId
+	}
+
+	// 群主身份与群创建者需同时变更，避免出现群主与创建者不一致
+	err = s.Source.Db().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.GroupMember{}).Where("group_id = ? and user_id = ? and leader = ?", groupId, ownerId, model.GroupMemberLeaderOwner).Update("leader", model.GroupMemberLeaderOrdinary).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&model.GroupMember{}).Where("group_id = ? and user_id = ?", groupId, userId).Update("leader", model.GroupMemberLeaderOwner).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&model.Group{}).Where("id = ?", groupId).Updates(map[string]any{
+			"creator_id": userId,
+			"updated_at": time.Now(),
+		}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.Message.CreateGroupSysMessage(ctx, message.CreateGroupSysMessageOption{
+		GroupId: groupId,
+		Content: fmt.Sprintf("管理员已将群主转让给【%s】", user.Nickname),
+	})
+}
+
+func (s *AdminGroupService) Mute(ctx context.Context, groupId int, status int) error {
+	if _, err := s.find(ctx, groupId); err != nil {
+		return err
+	}
+
+	affected, err := s.GroupRepo.UpdateByWhere(ctx, map[string]any{
+		"is_mute":    status,
+		"updated_at": time.Now(),
+	}, "id = ?", groupId)
+	if err != nil || affected == 0 {
+		return err
+	}
+
+	content := "管理员已开启全员禁言"
+	if status == model.No {
+		content = "管理员已解除全员禁言"
+	}
+
+	return s.Message.CreateGroupSysMessage(ctx, message.CreateGroupSysMessageOption{
+		GroupId: groupId,
+		Content: content,
+	})
+}
+
+func (s *AdminGroupService) MemberMute(ctx context.Context, groupId int, userId int, status int) error {
+	if _, err := s.find(ctx, groupId); err != nil {
+		return err
+	}
+
+	if !s.GroupMemberRepo.IsMember(ctx, groupId, userId, false) {
+		return errors.New("该用户不是群成员")
+	}
+
+	user, err := s.UsersRepo.FindByIdWithCache(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	if err := s.GroupMemberService.SetMuteStatus(ctx, groupId, userId, status); err != nil {
+		return err
+	}
+
+	content := fmt.Sprintf("管理员已将【%s】禁言", user.Nickname)
+	if status == model.No {
+		content = fmt.Sprintf("管理员已解除【%s】的禁言", user.Nickname)
+	}
+
+	return s.Message.CreateGroupSysMessage(ctx, message.CreateGroupSysMessageOption{
+		GroupId: groupId,
+		Content: content,
+	})
+}
+
+func (s *AdminGroupService) FindMessage(ctx context.Context, talkMode int, msgId string) (any, error) {
+	var record any
+	switch talkMode {
+	case entity.ChatPrivateMode:
+		record = &model.TalkUserMessage{}
+	case entity.ChatGroupMode:
+		record = &model.TalkGroupMessage{}
+	default:
+		return nil, entity.ErrInvalidParams
+	}
+
+	if err := s.Source.Db().WithContext(ctx).First(record, "msg_id = ?", msgId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrDataNotFound
+		}
+
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func (s *AdminGroupService) RevokeMessage(ctx context.Context, talkMode int, msgId string) error {
+	return s.TalkService.Revoke(ctx, &TalkRevokeOption{
+		TalkMode: talkMode,
+		MsgId:    msgId,
+		IsAdmin:  true,
+	})
+}
+
+func (s *AdminGroupService) find(ctx context.Context, groupId int) (*model.Group, error) {
+	group, err := s.GroupRepo.FindById(ctx, groupId)
+	if err != nil {
+		if utils.IsSqlNoRows(err) {
+			return nil, entity.ErrGroupNotExist
+		}
+
+		return nil, err
+	}
+
+	if group.IsDismiss == model.Yes {
+		return nil, entity.ErrGroupDismissed
+	}
+
+	return group, nil
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/jsonutil"
+	"go-chat/internal/pkg/utils"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+)
+
+var _ IAdminRoleService = (*AdminRoleService)(nil)
+
+type IAdminRoleService interface {
+	// HasPermission 判断管理员是否拥有指定权限
+	HasPermission(ctx context.Context, adminId int, permission string) bool
+	// GetPermissions 获取管理员拥有的权限列表
+	GetPermissions(ctx context.Context, adminId int) ([]string, error)
+	// CanGrant 判断管理员是否可授予指定权限，不能授予自身未拥有的权限
+	CanGrant(ctx context.Context, adminId int, permissions []string) bool
+	// Create 创建角色
+	Create(ctx context.Context, operatorId int, opt *AdminRoleOpt) (*model.AdminRole, error)
+	// Update 更新角色
+	Update(ctx context.Context, operatorId int, roleId int, opt *AdminRoleOpt) error
+	// Delete 删除角色
+	Delete(ctx context.Context, roleId int) error
+}
+
+type AdminRoleService struct {
+	AdminRepo     *repo.Admin
+	AdminRoleRepo *repo.AdminRole
+}
+
+type AdminRoleOpt struct {
+	Name        string
+	Code        string
+	Permissions []string
+	Remark      string
+}
+
+func (s *AdminRoleService) HasPermission(ctx context.Context, adminId int, permission string) bool {
+	permissions, err := s.GetPermissions(ctx, adminId)
+	if err != nil {
+		return false
+	}
+
+	return slices.Contains(permissions, model.AdminPermissionAll) || slices.Contains(permissions, permission)
+}
+
+func (s *AdminRoleService) GetPermissions(ctx context.Context, adminId int) ([]string, error) {
+	admin, err := s.AdminRepo.FindById(ctx, adminId)
+	if err != nil {
+		return nil, err
+	}
+
+	if admin.Status != model.AdminStatusNormal {
+		return nil, entity.ErrAccountDisabled
+	}
+
+	role, err := s.AdminRoleRepo.FindById(ctx, admin.RoleId)
+	if err != nil {
+		return nil, err
+	}
+
+	return DecodeAdminPermissions(role.Permissions), nil
+}
+
+func (s *AdminRoleService) CanGrant(ctx context.Context, adminId int, permissions []string) bool {
+	owned, err := s.GetPermissions(ctx, adminId)
+	if err != nil {
+		return false
+	}
+
+	if slices.Contains(owned, model.AdminPermissionAll) {
+		return true
+	}
+
+	for _, permission := range permissions {
+		if !slices.Contains(owned, permission) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *AdminRoleService) Create(ctx context.Context, operatorId int, opt *AdminRoleOpt) (*model.AdminRole, error) {
+	if err := checkAdminPermissions(opt.Permissions); err != nil {
+		return nil, err
+	}
+
+	if !s.CanGrant(ctx, operatorId, opt.Permissions) {
+		return nil, errors.New("不能授予自身未拥有的权限")
+	}
+
+	if exist, _ := s.AdminRoleRepo.IsExist(ctx, "code = ?", opt.Code); exist {
+		return nil, errors.New("角色标识已存在")
+	}
+
+	role := &model.AdminRole{
+		Name:        opt.Name,
+		Code:        opt.Code,
+		Permissions: jsonutil.Encode(opt.Permissions),
+		Remark:      opt.Remark,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	if err := s.AdminRoleRepo.Create(ctx, role); err != nil {
+		return nil, err
+	}
+
+	return role, nil
+}
+
+func (s *AdminRoleService) Update(ctx context.Context, operatorId int, roleId int, opt *AdminRoleOpt) error {
+	role, err := s.find(ctx, roleId)
+	if err != nil {
+		return err
+	}
+
+	if role.Code == model.AdminRoleSuperAdmin {
+		return errors.New("超级管理员角色不允许修改")
+	}
+
+	operator, err := s.AdminRepo.FindById(ctx, operatorId)
+	if err != nil {
+		return err
+	}
+
+	if operator.RoleId == roleId {
+		return errors.New("不能修改当前登录账号所属角色")
+	}
+
+	if err := checkAdminPermissions(opt.Permissions); err != nil {
+		return err
+	}
+
+	// 原有权限及新权限均需在操作者权限范围内
+	if !s.CanGrant(ctx, operatorId, DecodeAdminPermissions(role.Permissions)) || !s.CanGrant(ctx, operatorId, opt.Permissions) {
+		return errors.New("不能授予自身未拥有的权限")
+	}
+
+	if exist, _ := s.AdminRoleRepo.IsExist(ctx, "code = ? and id != ?", opt.Code, roleId); exist {
+		return errors.New("角色标识已存在")
+	}
+
+	_, err = s.AdminRoleRepo.UpdateById(ctx, roleId, map[string]any{
+		"name":        opt.Name,
+		"code":        opt.Code,
+		"permissions": jsonutil.Encode(opt.Permissions),
+		"remark":      opt.Remark,
+		"updated_at":  time.Now(),
+	})
+
+	return err
+}
+
+func (s *AdminRoleService) Delete(ctx context.Context, roleId int) error {
+	role, err := s.find(ctx, roleId)
+	if err != nil {
+		return err
+	}
+
+	if role.Code == model.AdminRoleSuperAdmin {
+		return errors.New("超级管理员角色不允许删除")
+	}
+
+	if exist, _ := s.AdminRepo.IsExist(ctx, "role_id = ?", roleId); exist {
+		return errors.New("角色下存在管理员，无法删除")
+	}
+
+	return s.AdminRoleRepo.Delete(ctx, roleId)
+}
+
+func (s *AdminRoleService) find(ctx context.Context, roleId int) (*model.AdminRole, error) {
+	role, err := s.AdminRoleRepo.FindById(ctx, roleId)
+	if err != nil {
+		if utils.IsSqlNoRows(err) {
+			return nil, entity.ErrDataNotFound
+		}
+
+		return nil, err
+	}
+
+	return role, nil
+}
+
+// DecodeAdminPermissions 解析角色权限列表
+func DecodeAdminPermissions(value string) []string {
+	permissions := make([]string, 0)
+	_ = json.Unmarshal([]byte(value), &permissions)
+	return permissions
+}
+
+func checkAdminPermissions(permissions []string) error {
+	for _, permission := range permissions {
+		if !slices.ContainsFunc(model.AdminPermissions, func(item model.AdminPermission) bool {
+			return item.Code == permission
+		}) {
+			return errors.New("权限标识不存在: " + permission)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"go-chat/internal/business"
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/encrypt"
+	"go-chat/internal/pkg/jsonutil"
+	"go-chat/internal/pkg/utils"
+	"go-chat/internal/repository/cache"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+)
+
+var _ IAdminUserService = (*AdminUserService)(nil)
+
+type IAdminUserService interface {
+	// Disable 禁用账号并强制下线
+	Disable(ctx context.Context, uid int) error
+	// Enable 启用账号
+	Enable(ctx context.Context, uid int) error
+	// ResetPassword 重置账号密码并强制下线
+	ResetPassword(ctx context.Context, uid int, password string) error
+	// ForceLogout 强制账号下线
+	ForceLogout(ctx context.Context, uid int, reason string) error
+}
+
+type AdminUserService struct {
+	UsersRepo          *repo.Users
+	JwtTokenStorage    *cache.JwtTokenStorage
+	AuthSessionService IAuthSessionService
+	PushMessage        *business.PushMessage
+}
+
+func (s *AdminUserService) Disable(ctx context.Context, uid int) error {
+	if err := s.updateStatus(ctx, uid, model.UsersStatusDisabled); err != nil {
+		return err
+	}
+
+	return s.ForceLogout(ctx, uid, "账号已被管理员禁用")
+}
+
+func (s *AdminUserService) Enable(ctx context.Context, uid int) error {
+	return s.updateStatus(ctx, uid, model.UsersStatusNormal)
+}
+
+func (s *AdminUserService) ResetPassword(ctx context.Context, uid int, password string) error {
+	if _, err := s.find(ctx, uid); err != nil {
+		return err
+	}
+
+	_, err := s.UsersRepo.UpdateById(ctx, uid, map[string]any{
+		"password": encrypt.HashPassword(password),
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.ForceLogout(ctx, uid, "账号密码已被管理员重置，请重新登录")
+}
+
+func (s *AdminUserService) ForceLogout(ctx context.Context, uid int, reason string) error {
+	// 吊销所有登录会话，避免通过刷新令牌重新获取访问令牌
+	if err := s.AuthSessionService.RevokeAll(ctx, uid); err != nil {
+		return err
+	}
+
+	// 通知所有节点断开用户的长连接
+	return s.PushMessage.Push(ctx, entity.ImTopicChat, &entity.SubscribeMessage{
+		Event: entity.SubEventUserKickout,
+		Payload: jsonutil.Encode(entity.SubEventUserKickoutPayload{
+			UserId: uid,
+			Reason: reason,
+		}),
+	})
+}
+
+func (s *AdminUserService) updateStatus(ctx context.Context, uid int, status int) error {
+	user, err := s.find(ctx, uid)
+	if err != nil {
+		return err
+	}
+
+	if user.Status == status {
+		return nil
+	}
+
+	if _, err := s.UsersRepo.UpdateById(ctx, uid, map[string]any{"status": status}); err != nil {
+		return err
+	}
+
+	return s.UsersRepo.ClearTableCache(ctx, uid)
+}
+
+func (s *AdminUserService) find(ctx context.Context, uid int) (*model.Users, error) {
+	user, err := s.UsersRepo.FindById(ctx, uid)
+	if err != nil {
+		if utils.IsSqlNoRows(err) {
+			return nil, entity.ErrUserNotExist
+		}
+
+		return nil, err
+	}
+
+	if user.IsRobot == model.Yes {
+		return nil, errors.New("机器人账号不支持该操作")
+	}
+
+	return user, nil
+}
@@ -2,14 +2,22 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
+	"time"
 
+	"go-chat/config"
+	"go-chat/internal/entity"
 	"go-chat/internal/pkg/filesystem"
+	"go-chat/internal/pkg/strutil"
 	"go-chat/internal/pkg/timeutil"
 	"go-chat/internal/repository/model"
 	"go-chat/internal/repository/repo"
 )
 
 type IArticleAnnexService interface {
+	// Upload 上传附件(校验上传策略及存储配额)
+	Upload(ctx context.Context, opt *ArticleAnnexUploadOpt) (*model.ArticleAnnex, error)
 	Create(ctx context.Context, data *model.ArticleAnnex) error
 	UpdateStatus(ctx context.Context, uid int, id int, status int) error
 	ForeverDelete(ctx context.Context, uid int, id int) error
@@ -19,6 +27,65 @@ type ArticleAnnexService struct {
 	*repo.Source
 	ArticleAnnex *repo.ArticleAnnex
 	FileSystem   filesystem.IFilesystem
+
+	StorageQuotaService IStorageQuotaService
+	FileScanService     IFileScanService
+}
+
+type ArticleAnnexUploadOpt struct {
+	UserId    int
+	ArticleId int
+	Filename  string
+	Stream    []byte
+}
+
+func (s *ArticleAnnexService) Upload(ctx context.Context, opt *ArticleAnnexUploadOpt) (*model.ArticleAnnex, error) {
+	size := int64(len(opt.Stream))
+
+	if err := s.StorageQuotaService.CheckPolicy(config.UploadTypeAnnex, opt.Filename, size); err != nil {
+		return nil, err
+	}
+
+	if err := s.StorageQuotaService.CheckMimeType(config.UploadTypeAnnex, opt.Stream); err != nil {
+		return nil, err
+	}
+
+	if err := s.FileScanService.ScanStream(ctx, &FileScanOpt{UserId: opt.UserId, Filename: opt.Filename}, opt.Stream); err != nil {
+		return nil, err
+	}
+
+	if err := s.StorageQuotaService.Reserve(ctx, model.StorageOwnerUser, opt.UserId, size); err != nil {
+		return nil, err
+	}
+
+	ext := strutil.FileSuffix(opt.Filename)
+
+	data := &model.ArticleAnnex{
+		UserId:       opt.UserId,
+		ArticleId:    opt.ArticleId,
+		Drive:        entity.FileDriveMode(s.FileSystem.Driver()),
+		Suffix:       ext,
+		Size:         int(size),
+		Path:         fmt.Sprintf("article-files/%s/%s", time.Now().Format("200601"), strutil.GenFileName(ext)),
+		OriginalName: opt.Filename,
+		Status:       1,
+		DeletedAt: sql.NullTime{
+			Valid: false,
+		},
+	}
+
+	if err := s.FileSystem.Write(s.FileSystem.BucketPrivateName(), data.Path, opt.Stream); err != nil {
+		_ = s.StorageQuotaService.Release(ctx, model.StorageOwnerUser, opt.UserId, size)
+		return nil, err
+	}
+
+	if err := s.Create(ctx, data); err != nil {
+		_ = s.FileSystem.Delete(s.FileSystem.BucketPrivateName(), data.Path)
+		_ = s.StorageQuotaService.Release(ctx, model.StorageOwnerUser, opt.UserId, size)
+		return nil, err
+	}
+
+	return data, nil
 }
 
 func (s *ArticleAnnexService) Create(ctx context.Context, data *model.ArticleAnnex) error {
@@ -50,5 +117,9 @@ func (s *ArticleAnnexService) ForeverDelete(ctx context.Context, uid int, id int
 
 	_ = s.FileSystem.Delete(s.FileSystem.BucketPrivateName(), annex.Path)
 
-	return s.Source.Db().Delete(&model.ArticleAnnex{}, id).Error
+	if err := s.Source.Db().Delete(&model.ArticleAnnex{}, id).Error; err != nil {
+		return err
+	}
+
+	return s.StorageQuotaService.Release(ctx, model.StorageOwnerUser, uid, int64(annex.Size))
 }
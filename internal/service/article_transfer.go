@@ -0,0 +1,572 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"go-chat/internal/pkg/filesystem"
+	"go-chat/internal/pkg/logger"
+	"go-chat/internal/pkg/sliceutil"
+	"go-chat/internal/pkg/strutil"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+	"gopkg.in/yaml.v2"
+	"gorm.io/gorm"
+)
+
+const (
+	articleImportMaxFileSize  = 10 << 20  // 导入时单个文件最大解压大小
+	articleImportMaxTotalSize = 500 << 20 // 导入时压缩包最大解压总大小
+	articleFrontMatterSep     = "---"
+)
+
+var _ IArticleTransferService = (*ArticleTransferService)(nil)
+
+type IArticleTransferService interface {
+	// Export 导出用户笔记为 zip 压缩包
+	Export(ctx context.Context, uid int, w io.Writer) error
+	// Import 导入 zip 压缩包或 markdown 文件
+	Import(ctx context.Context, uid int, filename string, stream []byte) (*ArticleImportResult, error)
+}
+
+type ArticleTransferService struct {
+	*repo.Source
+	ArticleRepo         *repo.Article
+	ArticleClass        *repo.ArticleClass
+	ArticleAnnex        *repo.ArticleAnnex
+	ArticleService      IArticleService
+	ArticleClassService IArticleClassService
+	ArticleTagService   IArticleTagService
+	ArticleAnnexService IArticleAnnexService
+	FileSystem          filesystem.IFilesystem
+}
+
+// ArticleFrontMatter 导出 markdown 文件头部信息
+type ArticleFrontMatter struct {
+	Title     string   `yaml:"title"`
+	Tags      []string `yaml:"tags,omitempty"`
+	Asterisk  bool     `yaml:"asterisk,omitempty"`
+	CreatedAt string   `yaml:"created_at,omitempty"`
+	UpdatedAt string   `yaml:"updated_at,omitempty"`
+	Annexes   []string `yaml:"annexes,omitempty"`
+}
+
+type ArticleImportResult struct {
+	ArticleNum int                     `json:"article_num"` // 导入的笔记数
+	ClassNum   int                     `json:"class_num"`   // 新建的分类数
+	TagNum     int                     `json:"tag_num"`     // 新建的标签数
+	AnnexNum   int                     `json:"annex_num"`   // 导入的附件数
+	FailNum    int                     `json:"fail_num"`    // 导入失败的文件数
+	Failures   []*ArticleImportFailure `json:"failures"`    // 导入失败的文件及原因
+}
+
+type ArticleImportFailure struct {
+	File   string `json:"file"`   // 压缩包内文件路径
+	Reason string `json:"reason"` // 失败原因
+}
+
+// 导入文件内容，压缩包内的文件按需解压，避免一次性将全部文件读入内存
+type articleImportFile func() ([]byte, error)
+
+// Export 按分类目录导出笔记，标签写入 front-matter，附件存放于同名 .assets 目录
+func (s *ArticleTransferService) Export(ctx context.Context, uid int, w io.Writer) error {
+
+	articles, err := s.ArticleRepo.FindAll(ctx, func(db *gorm.DB) {
+		db.Where("user_id = ? and status = ?", uid, model.ArticleStatusNormal).Order("id asc")
+	})
+	if err != nil {
+		return err
+	}
+
+	classes, err := s.ArticleClass.FindAll(ctx, func(db *gorm.DB) {
+		db.Where("user_id = ?", uid)
+	})
+	if err != nil {
+		return err
+	}
+
+	tags, err := s.ArticleTagService.List(ctx, uid)
+	if err != nil {
+		return err
+	}
+
+	annexes, err := s.ArticleAnnex.FindAll(ctx, func(db *gorm.DB) {
+		db.Where("user_id = ? and status = 1", uid).Order("id asc")
+	})
+	if err != nil {
+		return err
+	}
+
+	classNames := make(map[int]string)
+	for _, class := range classes {
+		classNames[class.Id] = class.ClassName
+	}
+
+	tagNames := make(map[int]string)
+	for _, tag := range tags {
+		tagNames[tag.Id] = tag.TagName
+	}
+
+	articleAnnexes := make(map[int][]*model.ArticleAnnex)
+	for _, annex := range annexes {
+		articleAnnexes[annex.ArticleId] = append(articleAnnexes[annex.ArticleId], annex)
+	}
+
+	archive := zip.NewWriter(w)
+	names := newArchiveNames()
+
+	for _, article := range articles {
+		className, ok := classNames[article.ClassId]
+		if !ok {
+			className = "默认分类"
+		}
+
+		filename := names.Unique(path.Join(sanitizeArchiveName(className), sanitizeArchiveName(article.Title)), ".md")
+		assetsDir := strings.TrimSuffix(path.Base(filename), ".md") + ".assets"
+
+		matter := &ArticleFrontMatter{
+			Title:     article.Title,
+			Asterisk:  article.IsAsterisk == model.Yes,
+			CreatedAt: article.CreatedAt.Format(time.DateTime),
+			UpdatedAt: article.UpdatedAt.Format(time.DateTime),
+		}
+
+		for _, id := range sliceutil.ParseIds(article.TagsId) {
+			if name, ok := tagNames[id]; ok {
+				matter.Tags = append(matter.Tags, name)
+			}
+		}
+
+		files := make([]string, 0)
+		for _, annex := range articleAnnexes[article.Id] {
+			ext := path.Ext(annex.OriginalName)
+			name := names.Unique(path.Join(path.Dir(filename), assetsDir, sanitizeArchiveName(strings.TrimSuffix(annex.OriginalName, ext))), ext)
+			files = append(files, name)
+			matter.Annexes = append(matter.Annexes, strings.TrimPrefix(name, path.Dir(filename)+"/"))
+		}
+
+		content, err := encodeFrontMatter(matter, html.UnescapeString(article.MdContent))
+		if err != nil {
+			return err
+		}
+
+		if err := writeArchiveFile(archive, filename, article.UpdatedAt, content); err != nil {
+			return err
+		}
+
+		for index, annex := range articleAnnexes[article.Id] {
+			stream, err := s.FileSystem.GetObject(s.FileSystem.BucketPrivateName(), annex.Path)
+			if err != nil {
+				logger.Errorf("笔记附件导出失败 annex_id:%d err:%s", annex.Id, err.Error())
+				continue
+			}
+
+			if err := writeArchiveFile(archive, files[index], annex.CreatedAt, stream); err != nil {
+				return err
+			}
+		}
+	}
+
+	return archive.Close()
+}
+
+// Import 导入笔记，支持导出的 zip 压缩包、普通 markdown 目录压缩包或单个 markdown 文件
+func (s *ArticleTransferService) Import(ctx context.Context, uid int, filename string, stream []byte) (*ArticleImportResult, error) {
+
+	files := make(map[string]articleImportFile)
+
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".zip":
+		items, err := readArchiveFiles(stream)
+		if err != nil {
+			return nil, err
+		}
+
+		for name, file := range items {
+			files[name] = func() ([]byte, error) {
+				return readArchiveFile(name, file)
+			}
+		}
+	case ".md", ".markdown":
+		files[path.Base(filename)] = func() ([]byte, error) {
+			return stream, nil
+		}
+	default:
+		return nil, errors.New("仅支持导入 zip 压缩包或 markdown 文件")
+	}
+
+	importer := &articleImporter{
+		ArticleTransferService: s,
+		uid:                    uid,
+		result:                 &ArticleImportResult{Failures: make([]*ArticleImportFailure, 0)},
+		classes:                make(map[string]int),
+		tags:                   make(map[string]int),
+	}
+
+	if err := importer.loadExisting(ctx); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		if ext := strings.ToLower(path.Ext(name)); ext == ".md" || ext == ".markdown" {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	// 逐个文件导入，单个文件失败时记录原因并继续导入其它文件
+	for _, name := range names {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if err := importer.importArticle(ctx, name, files); err != nil {
+			importer.fail(name, err)
+		}
+	}
+
+	return importer.result, nil
+}
+
+type articleImporter struct {
+	*ArticleTransferService
+	uid     int
+	result  *ArticleImportResult
+	classes map[string]int
+	tags    map[string]int
+}
+
+func (i *articleImporter) loadExisting(ctx context.Context) error {
+	classes, err := i.ArticleClass.FindAll(ctx, func(db *gorm.DB) {
+		db.Where("user_id = ?", i.uid)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, class := range classes {
+		i.classes[class.ClassName] = class.Id
+	}
+
+	tags, err := i.ArticleTagService.List(ctx, i.uid)
+	if err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		i.tags[tag.TagName] = tag.Id
+	}
+
+	return nil
+}
+
+func (i *articleImporter) importArticle(ctx context.Context, name string, files map[string]articleImportFile) error {
+
+	content, err := files[name]()
+	if err != nil {
+		return err
+	}
+
+	matter, body := decodeFrontMatter(content)
+
+	title := matter.Title
+	if title == "" {
+		title = strings.TrimSuffix(path.Base(name), path.Ext(name))
+	}
+
+	classId, err := i.classId(ctx, path.Base(path.Dir(name)))
+	if err != nil {
+		return err
+	}
+
+	articleId, err := i.ArticleService.Create(ctx, &ArticleEditOpt{
+		UserId:    i.uid,
+		ClassId:   classId,
+		Title:     strutil.MtSubstr(title, 0, 255),
+		MdContent: body,
+	})
+	if err != nil {
+		return err
+	}
+
+	// 标签或收藏设置失败时删除已创建的笔记，避免留下不完整的数据
+	if err := i.decorate(ctx, articleId, matter); err != nil {
+		i.remove(ctx, articleId)
+		return err
+	}
+
+	i.result.ArticleNum++
+
+	// 附件导入失败不影响笔记本身，单独记录失败原因
+	for _, annex := range matter.Annexes {
+		annexName := path.Clean(path.Join(path.Dir(name), annex))
+
+		file, ok := files[annexName]
+		if !ok {
+			continue
+		}
+
+		if err := i.createAnnex(ctx, articleId, annexName, file); err != nil {
+			i.fail(annexName, err)
+			continue
+		}
+
+		i.result.AnnexNum++
+	}
+
+	return nil
+}
+
+func (i *articleImporter) decorate(ctx context.Context, articleId int, matter *ArticleFrontMatter) error {
+	tagIds := make([]int32, 0, len(matter.Tags))
+	for _, tag := range sliceutil.Unique(matter.Tags) {
+		tagId, err := i.tagId(ctx, tag)
+		if err != nil {
+			return err
+		}
+
+		tagIds = append(tagIds, int32(tagId))
+	}
+
+	if len(tagIds) > 0 {
+		if err := i.ArticleService.Tag(ctx, i.uid, articleId, tagIds); err != nil {
+			return err
+		}
+	}
+
+	if matter.Asterisk {
+		if err := i.ArticleService.Asterisk(ctx, i.uid, articleId, model.Yes); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// remove 删除导入失败的笔记
+func (i *articleImporter) remove(ctx context.Context, articleId int) {
+	err := i.ArticleService.UpdateStatus(ctx, i.uid, articleId, model.ArticleStatusDelete)
+	if err == nil {
+		err = i.ArticleService.ForeverDelete(ctx, i.uid, articleId)
+	}
+
+	if err != nil {
+		logger.Errorf("删除导入失败的笔记失败 article_id:%d err:%s", articleId, err.Error())
+	}
+}
+
+func (i *articleImporter) fail(name string, err error) {
+	i.result.FailNum++
+	i.result.Failures = append(i.result.Failures, &ArticleImportFailure{File: name, Reason: err.Error()})
+}
+
+// classId 根据目录名获取分类ID，不存在则创建，根目录下的笔记归入默认分类
+func (i *articleImporter) classId(ctx context.Context, dir string) (int, error) {
+	if dir == "." || dir == "/" || dir == "" {
+		return 0, nil
+	}
+
+	if id, ok := i.classes[dir]; ok {
+		return id, nil
+	}
+
+	id, err := i.ArticleClassService.Create(ctx, i.uid, strutil.MtSubstr(dir, 0, 64), model.No)
+	if err != nil {
+		return 0, err
+	}
+
+	i.classes[dir] = id
+	i.result.ClassNum++
+
+	return id, nil
+}
+
+func (i *articleImporter) tagId(ctx context.Context, tag string) (int, error) {
+	if id, ok := i.tags[tag]; ok {
+		return id, nil
+	}
+
+	id, err := i.ArticleTagService.Create(ctx, i.uid, strutil.MtSubstr(tag, 0, 32))
+	if err != nil {
+		return 0, err
+	}
+
+	i.tags[tag] = id
+	i.result.TagNum++
+
+	return id, nil
+}
+
+func (i *articleImporter) createAnnex(ctx context.Context, articleId int, name string, file articleImportFile) error {
+	stream, err := file()
+	if err != nil {
+		return err
+	}
+
+	_, err = i.ArticleAnnexService.Upload(ctx, &ArticleAnnexUploadOpt{
+		UserId:    i.uid,
+		ArticleId: articleId,
+		Filename:  path.Base(name),
+		Stream:    stream,
+	})
+
+	return err
+}
+
+func encodeFrontMatter(matter *ArticleFrontMatter, body string) ([]byte, error) {
+	header, err := yaml.Marshal(matter)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bytes.NewBuffer(nil)
+	buf.WriteString(articleFrontMatterSep + "\n")
+	buf.Write(header)
+	buf.WriteString(articleFrontMatterSep + "\n\n")
+	buf.WriteString(body)
+
+	return buf.Bytes(), nil
+}
+
+// decodeFrontMatter 解析 markdown 头部信息，无头部信息时原样返回内容
+func decodeFrontMatter(content []byte) (*ArticleFrontMatter, string) {
+	matter := &ArticleFrontMatter{}
+
+	text := strings.TrimPrefix(strings.ReplaceAll(string(content), "\r\n", "\n"), "\ufeff")
+	if !strings.HasPrefix(text, articleFrontMatterSep+"\n") {
+		return matter, text
+	}
+
+	rest := text[len(articleFrontMatterSep)+1:]
+
+	end := strings.Index(rest, "\n"+articleFrontMatterSep+"\n")
+	if end == -1 {
+		return matter, text
+	}
+
+	if err := yaml.Unmarshal([]byte(rest[:end]), matter); err != nil {
+		return &ArticleFrontMatter{}, text
+	}
+
+	return matter, strings.TrimLeft(rest[end+len(articleFrontMatterSep)+2:], "\n")
+}
+
+// readArchiveFiles 校验压缩包并返回其中的文件，文件内容在导入时按需读取
+func readArchiveFiles(stream []byte) (map[string]*zip.File, error) {
+	reader, err := zip.NewReader(bytes.NewReader(stream), int64(len(stream)))
+	if err != nil {
+		return nil, errors.New("压缩包格式错误")
+	}
+
+	var total uint64
+	files := make(map[string]*zip.File)
+
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+
+		name := path.Clean(strings.ReplaceAll(file.Name, "\\", "/"))
+		if strings.HasPrefix(name, "../") || strings.HasPrefix(name, "/") || strings.HasPrefix(path.Base(name), ".") || strings.HasPrefix(name, "__MACOSX/") {
+			continue
+		}
+
+		if file.UncompressedSize64 > articleImportMaxFileSize {
+			return nil, fmt.Errorf("文件 %s 超过 10M 限制", name)
+		}
+
+		if total += file.UncompressedSize64; total > articleImportMaxTotalSize {
+			return nil, errors.New("压缩包解压后超过 500M 限制")
+		}
+
+		files[name] = file
+	}
+
+	return files, nil
+}
+
+// readArchiveFile 读取压缩包内的文件，按实际解压大小校验，防止文件头中的大小被篡改
+func readArchiveFile(name string, file *zip.File) ([]byte, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, articleImportMaxFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > articleImportMaxFileSize {
+		return nil, fmt.Errorf("文件 %s 超过 10M 限制", name)
+	}
+
+	return data, nil
+}
+
+func writeArchiveFile(archive *zip.Writer, name string, modified time.Time, content []byte) error {
+	fw, err := archive.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: modified,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = fw.Write(content)
+	return err
+}
+
+// sanitizeArchiveName 过滤压缩包内文件名中的非法字符
+func sanitizeArchiveName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|', '\n', '\r', '\t':
+			return '_'
+		}
+
+		return r
+	}, strings.TrimSpace(name))
+
+	name = strings.Trim(name, ".")
+	if name == "" {
+		return "untitled"
+	}
+
+	return name
+}
+
+type archiveNames map[string]struct{}
+
+func newArchiveNames() archiveNames {
+	return make(archiveNames)
+}
+
+// Unique 生成压缩包内不重复的文件名
+func (a archiveNames) Unique(base string, ext string) string {
+	name := base + ext
+	for n := 1; ; n++ {
+		if _, ok := a[name]; !ok {
+			break
+		}
+
+		name = fmt.Sprintf("%s(%d)%s", base, n, ext)
+	}
+
+	a[name] = struct{}{}
+	return name
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+)
+
+type fakeArticleService struct {
+	IArticleService
+	nextId  int
+	deleted []int
+}
+
+func (f *fakeArticleService) Create(_ context.Context, opt *ArticleEditOpt) (int, error) {
+	if opt.Title == "broken" {
+		return 0, errors.New("create failed")
+	}
+
+	f.nextId++
+	return f.nextId, nil
+}
+
+func (f *fakeArticleService) Tag(_ context.Context, _ int, _ int, _ []int32) error {
+	return errors.New("tag failed")
+}
+
+func (f *fakeArticleService) UpdateStatus(_ context.Context, _ int, _ int, _ int) error {
+	return nil
+}
+
+func (f *fakeArticleService) ForeverDelete(_ context.Context, _ int, articleId int) error {
+	f.deleted = append(f.deleted, articleId)
+	return nil
+}
+
+type fakeArticleClassService struct {
+	IArticleClassService
+}
+
+func (f *fakeArticleClassService) Create(_ context.Context, _ int, _ string, _ model.State) (int, error) {
+	return 10, nil
+}
+
+type fakeArticleTagService struct {
+	IArticleTagService
+}
+
+func (f *fakeArticleTagService) List(_ context.Context, _ int) ([]*model.TagItem, error) {
+	return []*model.TagItem{{Id: 1, TagName: "go"}}, nil
+}
+
+type fakeArticleAnnexService struct {
+	IArticleAnnexService
+}
+
+func (f *fakeArticleAnnexService) Upload(_ context.Context, _ *ArticleAnnexUploadOpt) (*model.ArticleAnnex, error) {
+	return nil, errors.New("存储空间不足")
+}
+
+func newTestArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	buf := bytes.NewBuffer(nil)
+	archive := zip.NewWriter(buf)
+	for name, content := range files {
+		fw, err := archive.Create(name)
+		assert.NoError(t, err)
+
+		_, _ = fw.Write([]byte(content))
+	}
+
+	assert.NoError(t, archive.Close())
+
+	return buf.Bytes()
+}
+
+func TestArticleTransferService_ImportReportsFailures(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectQuery("SELECT \\* FROM `article_class`").WillReturnRows(sqlmock.NewRows([]string{"id", "class_name"}))
+
+	articles := &fakeArticleService{}
+	svc := &ArticleTransferService{
+		ArticleClass:        repo.NewArticleClass(db),
+		ArticleService:      articles,
+		ArticleClassService: &fakeArticleClassService{},
+		ArticleTagService:   &fakeArticleTagService{},
+		ArticleAnnexService: &fakeArticleAnnexService{},
+	}
+
+	stream := newTestArchive(t, map[string]string{
+		"a.md":            "---\ntitle: ok\nannexes:\n  - a.assets/1.png\n---\n\nhello",
+		"a.assets/1.png":  "png",
+		"b.md":            "---\ntitle: broken\n---\n\nbroken",
+		"c.md":            "---\ntitle: tagged\ntags:\n  - go\n---\n\ntagged",
+		"notes/readme.md": "# readme",
+	})
+
+	result, err := svc.Import(context.Background(), 1, "notes.zip", stream)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 创建失败及标签设置失败的笔记记录失败原因，其它文件继续导入
+	assert.Equal(t, 2, result.ArticleNum)
+	assert.Equal(t, 1, result.ClassNum)
+	assert.Equal(t, 0, result.AnnexNum)
+	assert.Equal(t, 3, result.FailNum)
+	assert.Equal(t, []int{2}, articles.deleted)
+
+	files := make(map[string]string)
+	for _, item := range result.Failures {
+		files[item.File] = item.Reason
+	}
+
+	assert.Equal(t, map[string]string{
+		"a.assets/1.png": "存储空间不足",
+		"b.md":           "create failed",
+		"c.md":           "tag failed",
+	}, files)
+}
+
+func TestReadArchiveFilesLimit(t *testing.T) {
+	stream := newTestArchive(t, map[string]string{
+		"a.md":         "a",
+		"../escape.md": "b",
+		".hidden.md":   "c",
+	})
+
+	files, err := readArchiveFiles(stream)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	data, err := readArchiveFile("a.md", files["a.md"])
+	assert.NoError(t, err)
+	assert.Equal(t, "a", string(data))
+
+	_, err = readArchiveFiles([]byte("invalid"))
+	assert.Error(t, err)
+}
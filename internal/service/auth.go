@@ -21,6 +21,7 @@ type AuthService struct {
 	ContactRepo     *repo.Contact
 	GroupRepo       *repo.Group
 	GroupMemberRepo *repo.GroupMember
+	RobotRepo       *repo.Robot
 }
 
 type AuthOption struct {
@@ -43,6 +44,11 @@ func (a *AuthService) IsAuth(ctx context.Context, opt *AuthOption) error {
 			return nil
 		}
 
+		// 可对话的机器人无需添加好友
+		if a.RobotRepo.IsTalkRobot(ctx, opt.ToFromId) {
+			return nil
+		}
+
 		return errors.New("暂无权限发送消息！")
 	}
 
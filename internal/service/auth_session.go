@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go-chat/config"
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/jwt"
+	"go-chat/internal/pkg/logger"
+	"go-chat/internal/pkg/strutil"
+	"go-chat/internal/repository/cache"
+	"go-chat/internal/repository/repo"
+)
+
+var _ IAuthSessionService = (*AuthSessionService)(nil)
+
+type IAuthSessionService interface {
+	// Create 登录成功后创建会话并签发令牌
+	Create(ctx context.Context, opt *AuthSessionOpt) (*AuthToken, error)
+	// Refresh 使用刷新令牌换取新的令牌，检测到刷新令牌被重复使用时吊销整个令牌族
+	Refresh(ctx context.Context, refreshToken string, ipAddr string) (*AuthToken, error)
+	// List 用户当前的登录会话
+	List(ctx context.Context, uid int, currentSid string) ([]*AuthSessionItem, error)
+	// Revoke 吊销指定会话
+	Revoke(ctx context.Context, uid int, sid string) error
+	// RevokeAll 吊销用户所有会话
+	RevokeAll(ctx context.Context, uid int) error
+}
+
+type AuthSessionService struct {
+	Config             *config.Config
+	JwtKeySet          *jwt.KeySet
+	AuthSessionStorage *cache.AuthSessionStorage
+	JwtTokenStorage    *cache.JwtTokenStorage
+	UserDeviceRepo     *repo.UserDevice
+}
+
+type AuthSessionOpt struct {
+	UserId   int
+	Platform string
+	Agent    string
+	IpAddr   string
+}
+
+type AuthToken struct {
+	SessionId        string // 会话ID
+	AccessToken      string // 访问令牌
+	ExpiresIn        int32  // 访问令牌有效期(秒)
+	RefreshToken     string // 刷新令牌
+	RefreshExpiresIn int32  // 刷新令牌有效期(秒)
+}
+
+type AuthSessionItem struct {
+	SessionId   string `json:"session_id"`
+	Platform    string `json:"platform"`
+	Agent       string `json:"agent"`
+	IpAddr      string `json:"ip_addr"`
+	CreatedAt   string `json:"created_at"`
+	RefreshedAt string `json:"refreshed_at"`
+	IsCurrent   bool   `json:"is_current"` // 是否为当前会话
+}
+
+func (s *AuthSessionService) Create(ctx context.Context, opt *AuthSessionOpt) (*AuthToken, error) {
+	now := time.Now()
+
+	session := &cache.AuthSession{
+		Id:          strutil.NewMsgId(),
+		UserId:      opt.UserId,
+		Platform:    opt.Platform,
+		Agent:       opt.Agent,
+		IpAddr:      opt.IpAddr,
+		CreatedAt:   now.Unix(),
+		RefreshedAt: now.Unix(),
+	}
+
+	token := s.issue(ctx, session, now)
+
+	if err := s.AuthSessionStorage.Set(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+func (s *AuthSessionService) Refresh(ctx context.Context, refreshToken string, ipAddr string) (*AuthToken, error) {
+	sid, _, ok := strings.Cut(refreshToken, ".")
+	if !ok || sid == "" {
+		return nil, entity.ErrRefreshTokenInvalid
+	}
+
+	session, err := s.AuthSessionStorage.Get(ctx, sid)
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, entity.ErrRefreshTokenInvalid
+		}
+
+		return nil, err
+	}
+
+	hash := s.hash(refreshToken)
+
+	// 已轮换过的刷新令牌再次出现，说明令牌可能已泄露，吊销整个令牌族
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(session.RefreshHash)) != 1 {
+		return nil, s.reused(ctx, session)
+	}
+
+	previous := session.AccessToken
+	previousExpiresAt := session.AccessExpiresAt
+
+	now := time.Now()
+	session.IpAddr = ipAddr
+	session.RefreshedAt = now.Unix()
+	token := s.issue(ctx, session, now)
+
+	if err := s.AuthSessionStorage.Rotate(ctx, hash, session); err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, entity.ErrRefreshTokenInvalid
+		}
+
+		// 并发刷新时同一个刷新令牌已被其他请求使用
+		if errors.Is(err, cache.ErrAuthSessionConflict) {
+			s.blacklist(ctx, token.AccessToken, session.AccessExpiresAt)
+
+			latest, err := s.AuthSessionStorage.Get(ctx, sid)
+			if err != nil {
+				return nil, entity.ErrRefreshTokenReused
+			}
+
+			return nil, s.reused(ctx, latest)
+		}
+
+		return nil, err
+	}
+
+	// 旧的访问令牌随刷新令牌一起作废，同一秒内轮换时新旧令牌内容相同，不能加入黑名单
+	if previous != token.AccessToken {
+		s.blacklist(ctx, previous, previousExpiresAt)
+	}
+
+	return token, nil
+}
+
+func (s *AuthSessionService) List(ctx context.Context, uid int, currentSid string) ([]*AuthSessionItem, error) {
+	sessions, err := s.AuthSessionStorage.ListByUser(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*AuthSessionItem, 0, len(sessions))
+	for _, session := range sessions {
+		items = append(items, &AuthSessionItem{
+			SessionId:   session.Id,
+			Platform:    session.Platform,
+			Agent:       session.Agent,
+			IpAddr:      session.IpAddr,
+			CreatedAt:   time.Unix(session.CreatedAt, 0).Format(time.DateTime),
+			RefreshedAt: time.Unix(session.RefreshedAt, 0).Format(time.DateTime),
+			IsCurrent:   session.Id == currentSid,
+		})
+	}
+
+	return items, nil
+}
+
+func (s *AuthSessionService) Revoke(ctx context.Context, uid int, sid string) error {
+	session, err := s.AuthSessionStorage.Get(ctx, sid)
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			// 会话已过期，仍需清理绑定的推送设备
+			return s.UserDeviceRepo.DeleteBySession(ctx, uid, sid)
+		}
+
+		return err
+	}
+
+	if session.UserId != uid {
+		return entity.ErrPermissionDenied
+	}
+
+	return s.revoke(ctx, session)
+}
+
+func (s *AuthSessionService) RevokeAll(ctx context.Context, uid int) error {
+	sessions, err := s.AuthSessionStorage.ListByUser(ctx, uid)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if err := s.revoke(ctx, session); err != nil {
+			return err
+		}
+	}
+
+	// 删除所有推送设备，包括未绑定会话的历史设备
+	if err := s.UserDeviceRepo.DeleteByUserId(ctx, uid); err != nil {
+		return err
+	}
+
+	// 兼容未绑定会话的历史令牌
+	return s.JwtTokenStorage.RevokeUserTokens(ctx, uid)
+}
+
+// 签发访问令牌及刷新令牌，并更新会话中的令牌信息
+func (s *AuthSessionService) issue(ctx context.Context, session *cache.AuthSession, now time.Time) *AuthToken {
+	accessExpiresAt := now.Add(time.Duration(s.Config.Jwt.ExpiresTime) * time.Second)
+	refreshExpiresAt := now.Add(time.Duration(s.Config.Jwt.GetRefreshTime()) * time.Second)
+
+	accessToken := s.JwtKeySet.GenerateSessionToken("api", session.Id, &jwt.Options{
+		ExpiresAt: jwt.NewNumericDate(accessExpiresAt),
+		ID:        strconv.Itoa(session.UserId),
+		Issuer:    "im.web",
+		IssuedAt:  jwt.NewNumericDate(now),
+	})
+
+	// 记录已签发的 token，便于管理员强制下线
+	if err := s.JwtTokenStorage.AddUserToken(ctx, session.UserId, accessToken, accessExpiresAt); err != nil {
+		logger.Errorf("记录用户 token 失败 uid:%d err:%s", session.UserId, err.Error())
+	}
+
+	buf := make([]byte, 32)
+	_, _ = rand.Read(buf)
+	refreshToken := session.Id + "." + hex.EncodeToString(buf)
+
+	session.RefreshHash = s.hash(refreshToken)
+	session.AccessToken = accessToken
+	session.AccessExpiresAt = accessExpiresAt.Unix()
+	session.ExpiresAt = refreshExpiresAt.Unix()
+
+	return &AuthToken{
+		SessionId:        session.Id,
+		AccessToken:      accessToken,
+		ExpiresIn:        int32(s.Config.Jwt.ExpiresTime),
+		RefreshToken:     refreshToken,
+		RefreshExpiresIn: int32(s.Config.Jwt.GetRefreshTime()),
+	}
+}
+
+func (s *AuthSessionService) reused(ctx context.Context, session *cache.AuthSession) error {
+	logger.Warnf("检测到刷新令牌重复使用，吊销会话 uid:%d sid:%s", session.UserId, session.Id)
+
+	if err := s.revoke(ctx, session); err != nil {
+		return err
+	}
+
+	return entity.ErrRefreshTokenReused
+}
+
+func (s *AuthSessionService) revoke(ctx context.Context, session *cache.AuthSession) error {
+	s.blacklist(ctx, session.AccessToken, session.AccessExpiresAt)
+
+	if err := s.UserDeviceRepo.DeleteBySession(ctx, session.UserId, session.Id); err != nil {
+		return err
+	}
+
+	return s.AuthSessionStorage.Del(ctx, session.UserId, session.Id)
+}
+
+func (s *AuthSessionService) blacklist(ctx context.Context, token string, expiresAt int64) {
+	if token == "" {
+		return
+	}
+
+	if ex := expiresAt - time.Now().Unix(); ex > 0 {
+		_ = s.JwtTokenStorage.SetBlackList(ctx, token, time.Duration(ex)*time.Second)
+	}
+}
+
+func (s *AuthSessionService) hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
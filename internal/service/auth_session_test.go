@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go-chat/config"
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/jwt"
+	"go-chat/internal/repository/cache"
+	"go-chat/internal/repository/repo"
+)
+
+func newTestAuthSessionService(t *testing.T) (*AuthSessionService, sqlmock.Sqlmock, func(time.Duration)) {
+	t.Helper()
+
+	rds, mr := newTestRedis(t)
+	db, mock := newTestDB(t)
+
+	keySet, err := jwt.NewKeySet("secret", "", time.Time{})
+	assert.NoError(t, err)
+
+	return &AuthSessionService{
+		Config:             &config.Config{Jwt: &config.Jwt{Secret: "secret", ExpiresTime: 60, RefreshTime: 100}},
+		JwtKeySet:          keySet,
+		AuthSessionStorage: cache.NewAuthSessionStorage(rds),
+		JwtTokenStorage:    cache.NewTokenSessionStorage(rds),
+		UserDeviceRepo:     repo.NewUserDevice(db),
+	}, mock, mr.FastForward
+}
+
+func TestAuthSessionService_RevokeAllAfterRotation(t *testing.T) {
+	ctx := context.Background()
+	svc, mock, fastForward := newTestAuthSessionService(t)
+
+	svc.Config.Jwt.RefreshTime = 10
+	token, err := svc.Create(ctx, &AuthSessionOpt{UserId: 1, Platform: "web"})
+	assert.NoError(t, err)
+
+	// 刷新后的会话有效期超过首次登录时的有效期
+	svc.Config.Jwt.RefreshTime = 100
+	token, err = svc.Refresh(ctx, token.RefreshToken, "127.0.0.1")
+	assert.NoError(t, err)
+
+	fastForward(50 * time.Second)
+
+	items, err := svc.List(ctx, 1, "")
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+
+	mock.ExpectExec("DELETE FROM `user_device`").WithArgs(1, token.SessionId).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM `user_device`").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.NoError(t, svc.RevokeAll(ctx, 1))
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	_, err = svc.Refresh(ctx, token.RefreshToken, "127.0.0.1")
+	assert.ErrorIs(t, err, entity.ErrRefreshTokenInvalid)
+}
+
+func TestAuthSessionService_RefreshRotation(t *testing.T) {
+	ctx := context.Background()
+	svc, mock, _ := newTestAuthSessionService(t)
+
+	first, err := svc.Create(ctx, &AuthSessionOpt{UserId: 1, Platform: "web"})
+	assert.NoError(t, err)
+
+	second, err := svc.Refresh(ctx, first.RefreshToken, "127.0.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, first.SessionId, second.SessionId)
+	assert.NotEqual(t, first.RefreshToken, second.RefreshToken)
+
+	// 轮换后旧的访问令牌立即失效，同一秒内签发的令牌内容相同时新令牌仍然有效
+	assert.False(t, svc.JwtTokenStorage.IsBlackList(ctx, second.AccessToken))
+	if first.AccessToken != second.AccessToken {
+		assert.True(t, svc.JwtTokenStorage.IsBlackList(ctx, first.AccessToken))
+	}
+
+	third, err := svc.Refresh(ctx, second.RefreshToken, "127.0.0.1")
+	assert.NoError(t, err)
+
+	items, err := svc.List(ctx, 1, third.SessionId)
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+	assert.True(t, items[0].IsCurrent)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthSessionService_RefreshReuse(t *testing.T) {
+	ctx := context.Background()
+	svc, mock, _ := newTestAuthSessionService(t)
+
+	first, err := svc.Create(ctx, &AuthSessionOpt{UserId: 1, Platform: "web"})
+	assert.NoError(t, err)
+
+	second, err := svc.Refresh(ctx, first.RefreshToken, "127.0.0.1")
+	assert.NoError(t, err)
+
+	// 已使用过的刷新令牌再次出现时吊销整个令牌族
+	mock.ExpectExec("DELETE FROM `user_device`").WithArgs(1, first.SessionId).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	_, err = svc.Refresh(ctx, first.RefreshToken, "127.0.0.1")
+	assert.ErrorIs(t, err, entity.ErrRefreshTokenReused)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	assert.True(t, svc.JwtTokenStorage.IsBlackList(ctx, second.AccessToken))
+
+	_, err = svc.Refresh(ctx, second.RefreshToken, "127.0.0.1")
+	assert.ErrorIs(t, err, entity.ErrRefreshTokenInvalid)
+}
+
+func TestAuthSessionService_RefreshInvalid(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newTestAuthSessionService(t)
+
+	for _, token := range []string{"", "invalid", "unknown.token"} {
+		_, err := svc.Refresh(ctx, token, "127.0.0.1")
+		assert.ErrorIs(t, err, entity.ErrRefreshTokenInvalid)
+	}
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"html"
+	"slices"
+
+	"github.com/nsqio/go-nsq"
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/jsonutil"
+	"go-chat/internal/pkg/logger"
+	"go-chat/internal/pkg/slashcmd"
+	"go-chat/internal/repository/cache"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+	"go-chat/internal/service/message"
+)
+
+var _ ICommandService = (*CommandService)(nil)
+
+type ICommandService interface {
+	// Dispatch 执行斜杠命令，命令不存在时返回 false(按普通文本消息发送)
+	Dispatch(ctx context.Context, inv *CommandInvocation) (bool, error)
+	// Commands 获取对话中可使用的命令列表
+	Commands(ctx context.Context, uid int, talkMode int, toFromId int) []*CommandItem
+}
+
+type CommandService struct {
+	*repo.Source
+	GroupRepo          *repo.Group
+	GroupMemberRepo    *repo.GroupMember
+	UsersRepo          *repo.Users
+	RobotRepo          *repo.Robot
+	RemindStorage      *cache.RemindStorage
+	GroupVoteService   IGroupVoteService
+	GroupMemberService IGroupMemberService
+	MessageService     message.IService
+	Producer           *nsq.Producer
+}
+
+// CommandInvocation 命令调用信息
+type CommandInvocation struct {
+	TalkMode int               // 对话类型
+	UserId   int               // 命令发送者
+	ToFromId int               // 接收者ID(好友ID或者群ID)
+	Mentions []int             // @用户ID列表
+	Command  *slashcmd.Command // 解析后的命令
+}
+
+type CommandItem struct {
+	Name      string `json:"name"`                 // 命令名称
+	Usage     string `json:"usage"`                // 使用说明
+	Describe  string `json:"describe"`             // 命令描述
+	Source    string `json:"source"`               // 命令来源[builtin:内置命令;robot:机器人命令;]
+	RobotId   int    `json:"robot_id,omitempty"`   // 机器人ID
+	RobotName string `json:"robot_name,omitempty"` // 机器人名称
+}
+
+func (s *CommandService) Dispatch(ctx context.Context, inv *CommandInvocation) (bool, error) {
+	if handler := s.builtin(inv.Command.Name); handler != nil {
+		return true, s.reply(ctx, inv, s.execute(ctx, handler, inv))
+	}
+
+	robot := s.findCommandRobot(ctx, inv.TalkMode, inv.ToFromId, inv.Command.Name)
+	if robot == nil {
+		return false, nil
+	}
+
+	// 命令交由机器人 Webhook 处理，机器人的响应内容仅回复给命令发送者
+	err := s.Producer.Publish(entity.RobotWebhookTopic, []byte(jsonutil.Encode(entity.RobotWebhookMessage{
+		RobotId:  robot.Id,
+		TalkMode: inv.TalkMode,
+		Event:    entity.RobotWebhookEventCommand,
+		UserId:   inv.UserId,
+		ToFromId: inv.ToFromId,
+		Command: &entity.RobotWebhookCommand{
+			Name: inv.Command.Name,
+			Args: inv.Command.Args,
+			Text: inv.Command.Text,
+		},
+	})))
+	if err != nil {
+		return true, err
+	}
+
+	return true, nil
+}
+
+func (s *CommandService) execute(ctx context.Context, handler *commandHandler, inv *CommandInvocation) string {
+	if handler.GroupOnly && inv.TalkMode != entity.ChatGroupMode {
+		return "该命令仅支持在群聊中使用"
+	}
+
+	if handler.LeaderOnly && !s.GroupMemberRepo.IsLeader(ctx, inv.ToFromId, inv.UserId) {
+		return "仅群主或管理员可使用该命令"
+	}
+
+	content, err := handler.Handle(ctx, inv)
+	if err != nil {
+		logger.Errorf("斜杠命令执行失败 command:%s uid:%d err:%s", inv.Command.Name, inv.UserId, err.Error())
+		return err.Error()
+	}
+
+	return content
+}
+
+func (s *CommandService) Commands(ctx context.Context, uid int, talkMode int, toFromId int) []*CommandItem {
+	items := make([]*CommandItem, 0)
+
+	isLeader := talkMode == entity.ChatGroupMode && s.GroupMemberRepo.IsLeader(ctx, toFromId, uid)
+	for _, handler := range s.builtins() {
+		if handler.GroupOnly && talkMode != entity.ChatGroupMode || handler.LeaderOnly && !isLeader {
+			continue
+		}
+
+		items = append(items, &CommandItem{
+			Name:     handler.Name,
+			Usage:    handler.Usage,
+			Describe: handler.Describe,
+			Source:   "builtin",
+		})
+	}
+
+	for _, robot := range s.findRobots(ctx, talkMode, toFromId) {
+		for _, command := range DecodeRobotCommands(robot.Commands) {
+			if s.builtin(command.Name) != nil || slices.ContainsFunc(items, func(item *CommandItem) bool {
+				return item.Name == command.Name
+			}) {
+				continue
+			}
+
+			items = append(items, &CommandItem{
+				Name:      command.Name,
+				Usage:     command.Usage,
+				Describe:  command.Describe,
+				Source:    "robot",
+				RobotId:   robot.Id,
+				RobotName: robot.RobotName,
+			})
+		}
+	}
+
+	return items
+}
+
+// findRobots 查询对话中可接收命令的机器人，群聊为已加入群的机器人，私聊为对话的机器人
+func (s *CommandService) findRobots(ctx context.Context, talkMode int, toFromId int) []*model.Robot {
+	userIds := []int{toFromId}
+	if talkMode == entity.ChatGroupMode {
+		userIds = s.GroupMemberRepo.GetMemberIds(ctx, toFromId)
+	}
+
+	robots, err := s.RobotRepo.FindWebhookRobots(ctx, userIds)
+	if err != nil {
+		return nil
+	}
+
+	return robots
+}
+
+func (s *CommandService) findCommandRobot(ctx context.Context, talkMode int, toFromId int, name string) *model.Robot {
+	for _, robot := range s.findRobots(ctx, talkMode, toFromId) {
+		if slices.ContainsFunc(DecodeRobotCommands(robot.Commands), func(command model.RobotCommand) bool {
+			return command.Name == name
+		}) {
+			return robot
+		}
+	}
+
+	return nil
+}
+
+// reply 以系统身份回复临时消息，仅命令发送者可见
+func (s *CommandService) reply(ctx context.Context, inv *CommandInvocation, content string) error {
+	if content == "" {
+		return nil
+	}
+
+	return s.MessageService.CreateEphemeralMessage(ctx, message.CreateEphemeralMessage{
+		TalkMode: inv.TalkMode,
+		ToFromId: inv.ToFromId,
+		UserId:   inv.UserId,
+		Content:  html.EscapeString(content),
+	})
+}
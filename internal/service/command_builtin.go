@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/jsonutil"
+	"go-chat/internal/pkg/slashcmd"
+	"go-chat/internal/repository/cache"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/service/message"
+)
+
+const (
+	remindMinDuration = time.Minute
+	remindMaxDuration = 30 * 24 * time.Hour
+	remindMaxPending  = 20 // 每个用户最多待提醒数量
+)
+
+type commandHandler struct {
+	Name       string
+	Usage      string
+	Describe   string
+	GroupOnly  bool // 仅群聊可用
+	LeaderOnly bool // 仅群主或管理员可用
+	Handle     func(ctx context.Context, inv *CommandInvocation) (string, error)
+}
+
+// builtins 内置命令列表
+func (s *CommandService) builtins() []*commandHandler {
+	return []*commandHandler{
+		{
+			Name:     "help",
+			Usage:    "/help",
+			Describe: "查看当前对话可用的命令",
+			Handle:   s.onHelp,
+		},
+		{
+			Name:      "poll",
+			Usage:     `/poll "标题" 选项1 选项2 [--multi] [--anonymous]`,
+			Describe:  "发起群投票，--multi 多选，--anonymous 匿名",
+			GroupOnly: true,
+			Handle:    s.onPoll,
+		},
+		{
+			Name:     "remind",
+			Usage:    "/remind 30m 提醒内容",
+			Describe: "定时提醒自己，时长支持 m(分钟)、h(小时)、d(天)",
+			Handle:   s.onRemind,
+		},
+		{
+			Name:       "mute",
+			Usage:      "/mute @成员",
+			Describe:   "禁言 @ 的群成员",
+			GroupOnly:  true,
+			LeaderOnly: true,
+			Handle:     s.onMute(model.Yes),
+		},
+		{
+			Name:       "unmute",
+			Usage:      "/unmute @成员",
+			Describe:   "解除 @ 的群成员禁言",
+			GroupOnly:  true,
+			LeaderOnly: true,
+			Handle:     s.onMute(model.No),
+		},
+	}
+}
+
+func (s *CommandService) builtin(name string) *commandHandler {
+	for _, handler := range s.builtins() {
+		if handler.Name == name {
+			return handler
+		}
+	}
+
+	return nil
+}
+
+func (s *CommandService) onHelp(ctx context.Context, inv *CommandInvocation) (string, error) {
+	var sb strings.Builder
+	sb.WriteString("可用命令：")
+
+	for _, item := range s.Commands(ctx, inv.UserId, inv.TalkMode, inv.ToFromId) {
+		sb.WriteString(fmt.Sprintf("\n%s  %s", item.Usage, item.Describe))
+		if item.RobotName != "" {
+			sb.WriteString(fmt.Sprintf("(%s)", item.RobotName))
+		}
+	}
+
+	return sb.String(), nil
+}
+
+func (s *CommandService) onPoll(ctx context.Context, inv *CommandInvocation) (string, error) {
+	opt := &GroupVoteCreateOpt{
+		GroupId:    inv.ToFromId,
+		UserId:     inv.UserId,
+		AnswerMode: model.VoteAnswerModeSingle,
+	}
+
+	args := make([]string, 0, len(inv.Command.Args))
+	for _, arg := range inv.Command.Args {
+		switch arg {
+		case "--multi", "-m":
+			opt.AnswerMode = model.VoteAnswerModeMultiple
+		case "--anonymous", "-a":
+			opt.IsAnonymous = true
+		default:
+			if arg = strings.TrimSpace(arg); arg != "" {
+				args = append(args, arg)
+			}
+		}
+	}
+
+	if len(args) < 3 || len(args) > 7 {
+		return `用法：/poll "标题" 选项1 选项2 [--multi] [--anonymous]，选项数量为 2~6 个`, nil
+	}
+
+	opt.Title, opt.AnswerOptions = args[0], args[1:]
+
+	voteId, err := s.GroupVoteService.Create(ctx, opt)
+	if err != nil {
+		return "", err
+	}
+
+	return "", s.MessageService.CreateVoteMessage(ctx, message.CreateVoteMessage{
+		TalkMode: entity.ChatGroupMode,
+		FromId:   inv.UserId,
+		ToFromId: inv.ToFromId,
+		VoteId:   voteId,
+	})
+}
+
+func (s *CommandService) onRemind(ctx context.Context, inv *CommandInvocation) (string, error) {
+	usage := "用法：/remind 30m 提醒内容，时长范围 1m~30d"
+	if len(inv.Command.Args) < 2 {
+		return usage, nil
+	}
+
+	duration, err := slashcmd.ParseDuration(inv.Command.Args[0])
+	if err != nil || duration < remindMinDuration || duration > remindMaxDuration {
+		return usage, nil
+	}
+
+	content := strings.TrimSpace(strings.TrimPrefix(inv.Command.Text, inv.Command.Args[0]))
+	if content == "" {
+		return usage, nil
+	}
+
+	if s.RemindStorage.CountByUser(ctx, inv.UserId) >= remindMaxPending {
+		return fmt.Sprintf("待提醒事项已达上限(%d 条)", remindMaxPending), nil
+	}
+
+	remindAt := time.Now().Add(duration)
+	err = s.RemindStorage.Add(ctx, &cache.RemindItem{
+		Id:       uuid.New().String(),
+		UserId:   inv.UserId,
+		TalkMode: inv.TalkMode,
+		ToFromId: inv.ToFromId,
+		Content:  content,
+		RemindAt: remindAt.Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("好的，将于 %s 提醒你：%s", remindAt.Format("2006-01-02 15:04"), content), nil
+}
+
+func (s *CommandService) onMute(status int) func(ctx context.Context, inv *CommandInvocation) (string, error) {
+	return func(ctx context.Context, inv *CommandInvocation) (string, error) {
+		members := make([]model.TalkRecordExtraGroupMember, 0)
+		for _, uid := range inv.Mentions {
+			if uid == inv.UserId || s.GroupMemberRepo.IsLeader(ctx, inv.ToFromId, uid) {
+				continue
+			}
+
+			if !s.GroupMemberRepo.IsMember(ctx, inv.ToFromId, uid, false) {
+				continue
+			}
+
+			user, err := s.UsersRepo.FindByIdWithCache(ctx, uid)
+			if err != nil {
+				continue
+			}
+
+			if err := s.GroupMemberService.SetMuteStatus(ctx, inv.ToFromId, uid, status); err != nil {
+				return "", err
+			}
+
+			members = append(members, model.TalkRecordExtraGroupMember{UserId: uid, Nickname: user.Nickname})
+		}
+
+		if len(members) == 0 {
+			return fmt.Sprintf("用法：/%s @成员(群主和管理员不可禁言)", inv.Command.Name), nil
+		}
+
+		operator, err := s.UsersRepo.FindByIdWithCache(ctx, inv.UserId)
+		if err != nil {
+			return "", err
+		}
+
+		data := message.CreateGroupMessageOption{
+			MsgType:  entity.ChatMsgSysGroupMemberMuted,
+			FromId:   inv.UserId,
+			ToFromId: inv.ToFromId,
+		}
+
+		if status == model.Yes {
+			data.Extra = jsonutil.Encode(model.TalkRecordExtraGroupMemberMuted{
+				OwnerId:   inv.UserId,
+				OwnerName: operator.Nickname,
+				Members:   members,
+			})
+		} else {
+			data.MsgType = entity.ChatMsgSysGroupMemberCancelMuted
+			data.Extra = jsonutil.Encode(model.TalkRecordExtraGroupMemberCancelMuted{
+				OwnerId:   inv.UserId,
+				OwnerName: operator.Nickname,
+				Members:   members,
+			})
+		}
+
+		return "", s.MessageService.CreateGroupMessage(ctx, data)
+	}
+}
+
+// DecodeRobotCommands 解析机器人命令列表
+func DecodeRobotCommands(value string) []model.RobotCommand {
+	commands := make([]model.RobotCommand, 0)
+	if value != "" {
+		_ = jsonutil.Decode(value, &commands)
+	}
+
+	if commands == nil {
+		return make([]model.RobotCommand, 0)
+	}
+
+	return commands
+}
+
+// checkRobotCommands 校验机器人命令列表
+func checkRobotCommands(commands []model.RobotCommand) error {
+	names := make(map[string]struct{}, len(commands))
+	for _, command := range commands {
+		if !slashcmd.IsValidName(command.Name) || command.Name != strings.ToLower(command.Name) {
+			return errors.New("命令名称不合法: " + command.Name)
+		}
+
+		if _, ok := names[command.Name]; ok {
+			return errors.New("命令名称重复: " + command.Name)
+		}
+
+		names[command.Name] = struct{}{}
+	}
+
+	return nil
+}
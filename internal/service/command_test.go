@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"html"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/slashcmd"
+	"go-chat/internal/repository/repo"
+	"go-chat/internal/service/message"
+)
+
+type fakeMessageService struct {
+	message.IService
+	ephemeral []message.CreateEphemeralMessage
+}
+
+func (f *fakeMessageService) CreateEphemeralMessage(_ context.Context, option message.CreateEphemeralMessage) error {
+	f.ephemeral = append(f.ephemeral, option)
+	return nil
+}
+
+func newTestCommandService(t *testing.T) (*CommandService, sqlmock.Sqlmock, *fakeMessageService) {
+	t.Helper()
+
+	db, mock := newTestDB(t)
+	messages := &fakeMessageService{}
+
+	return &CommandService{
+		GroupMemberRepo: repo.NewGroupMember(db, nil),
+		RobotRepo:       repo.NewRobot(db),
+		MessageService:  messages,
+	}, mock, messages
+}
+
+func expectIsLeader(mock sqlmock.Sqlmock, isLeader bool) {
+	rows := sqlmock.NewRows([]string{"1"})
+	if isLeader {
+		rows.AddRow(1)
+	}
+
+	mock.ExpectQuery("SELECT 1 FROM `group_member` WHERE group_id = \\? and user_id = \\? and leader in").WillReturnRows(rows)
+}
+
+func dispatchCommand(t *testing.T, svc *CommandService, messages *fakeMessageService, inv *CommandInvocation, text string) string {
+	t.Helper()
+
+	command, ok := slashcmd.Parse(text)
+	assert.True(t, ok)
+
+	inv.Command = command
+	handled, err := svc.Dispatch(context.Background(), inv)
+	assert.NoError(t, err)
+	assert.True(t, handled)
+
+	if len(messages.ephemeral) == 0 {
+		return ""
+	}
+
+	reply := messages.ephemeral[len(messages.ephemeral)-1]
+	assert.Equal(t, inv.UserId, reply.UserId)
+
+	return html.UnescapeString(reply.Content)
+}
+
+func TestCommandService_GroupOnly(t *testing.T) {
+	svc, mock, messages := newTestCommandService(t)
+
+	for _, text := range []string{"/mute @a", `/poll "标题" 1 2`} {
+		reply := dispatchCommand(t, svc, messages, &CommandInvocation{TalkMode: entity.ChatPrivateMode, UserId: 1, ToFromId: 2}, text)
+		assert.Equal(t, "该命令仅支持在群聊中使用", reply)
+	}
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCommandService_LeaderOnly(t *testing.T) {
+	svc, mock, messages := newTestCommandService(t)
+
+	// 普通成员不能使用禁言命令
+	expectIsLeader(mock, false)
+	reply := dispatchCommand(t, svc, messages, &CommandInvocation{TalkMode: entity.ChatGroupMode, UserId: 1, ToFromId: 10, Mentions: []int{2}}, "/mute")
+	assert.Equal(t, "仅群主或管理员可使用该命令", reply)
+
+	// 群主或管理员不能禁言其它群主或管理员
+	expectIsLeader(mock, true)
+	expectIsLeader(mock, true)
+	reply = dispatchCommand(t, svc, messages, &CommandInvocation{TalkMode: entity.ChatGroupMode, UserId: 1, ToFromId: 10, Mentions: []int{2}}, "/unmute")
+	assert.Equal(t, "用法：/unmute @成员(群主和管理员不可禁言)", reply)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCommandService_Commands(t *testing.T) {
+	svc, mock, _ := newTestCommandService(t)
+	ctx := context.Background()
+
+	names := func(items []*CommandItem) []string {
+		values := make([]string, 0, len(items))
+		for _, item := range items {
+			values = append(values, item.Name)
+		}
+
+		return values
+	}
+
+	robotRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "user_id", "robot_name", "commands"})
+	}
+
+	memberRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"user_id"}).AddRow(1).AddRow(2)
+	}
+
+	// 私聊中不展示群聊命令
+	mock.ExpectQuery("SELECT \\* FROM `robot`").WillReturnRows(robotRows())
+	assert.Equal(t, []string{"help", "remind"}, names(svc.Commands(ctx, 1, entity.ChatPrivateMode, 2)))
+
+	// 普通成员不展示群主或管理员命令
+	expectIsLeader(mock, false)
+	mock.ExpectQuery("SELECT .user_id. FROM `group_member`").WillReturnRows(memberRows())
+	mock.ExpectQuery("SELECT \\* FROM `robot`").WillReturnRows(robotRows())
+	assert.Equal(t, []string{"help", "poll", "remind"}, names(svc.Commands(ctx, 1, entity.ChatGroupMode, 10)))
+
+	expectIsLeader(mock, true)
+	mock.ExpectQuery("SELECT .user_id. FROM `group_member`").WillReturnRows(memberRows())
+	mock.ExpectQuery("SELECT \\* FROM `robot`").WillReturnRows(robotRows().AddRow(1, 2, "bot", `[{"name":"deploy","usage":"/deploy","describe":"部署"},{"name":"mute"}]`))
+	assert.Equal(t, []string{"help", "poll", "remind", "mute", "unmute", "deploy"}, names(svc.Commands(ctx, 1, entity.ChatGroupMode, 10)))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
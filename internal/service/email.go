@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/nsqio/go-nsq"
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/email"
+	"go-chat/internal/pkg/jsonutil"
+	"go-chat/internal/pkg/logger"
+	"go-chat/internal/pkg/strutil"
+	"go-chat/internal/pkg/utils"
+	"go-chat/internal/repository/cache"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+)
+
+// 邮件模板，对应 templates/email 目录下的模板文件
+const (
+	EmailTemplateVerifyCode    = "verify_code"    // 验证码
+	EmailTemplateResetPassword = "reset_password" // 密码重置通知
+	EmailTemplateLoginAlert    = "login_alert"    // 新设备或异地登录提醒
+	EmailTemplateOfflineDigest = "offline_digest" // 离线消息摘要
+)
+
+const (
+	EmailMaxAttempts = 5                // 发送失败最大尝试次数，重试间隔由 consumer.BackoffStrategy 决定
+	EmailCodeExpire  = 15 * time.Minute // 邮箱验证码有效期
+)
+
+// 各模板的默认邮件主题
+var emailSubjects = map[string]string{
+	EmailTemplateVerifyCode:    "邮箱验证码",
+	EmailTemplateResetPassword: "账号安全提醒：密码已重置",
+	EmailTemplateLoginAlert:    "账号安全提醒：新设备登录",
+	EmailTemplateOfflineDigest: "您有未读的离线消息",
+}
+
+// 各验证码渠道对应的操作名称
+var emailCodeServices = map[string]string{
+	entity.EmailChangeChannel: "绑定邮箱",
+}
+
+var _ IEmailService = (*EmailService)(nil)
+
+type IEmailService interface {
+	// Send 渲染模板并写入发件箱，由队列异步发送
+	Send(ctx context.Context, opt *EmailSendOpt) error
+	// Deliver 发送发件箱中的邮件，返回错误时由队列重试
+	Deliver(ctx context.Context, id int) error
+	// Republish 重新投递长时间未处理的待发送邮件，返回投递数量
+	Republish(ctx context.Context) (int, error)
+	// SendCode 发送邮箱验证码
+	SendCode(ctx context.Context, channel string, address string) error
+	// VerifyCode 验证邮箱验证码是否正确
+	VerifyCode(ctx context.Context, channel string, address string, code string) bool
+	// DeleteCode 删除邮箱验证码
+	DeleteCode(ctx context.Context, channel string, address string)
+}
+
+type EmailService struct {
+	Producer        *nsq.Producer
+	EmailClient     email.Sender
+	EmailOutboxRepo *repo.EmailOutbox
+	TemplateService ITemplateService
+	CodeStorage     *cache.SmsStorage
+}
+
+type EmailSendOpt struct {
+	UserId   int    // 收件用户ID，非用户邮件为 0
+	Email    string // 收件邮箱
+	Template string // 邮件模板
+	Subject  string // 邮件主题，为空时使用模板的默认主题
+	Data     any    // 模板数据
+}
+
+func (s *EmailService) Send(ctx context.Context, opt *EmailSendOpt) error {
+	if opt.Email == "" {
+		return errors.New("收件邮箱不能为空")
+	}
+
+	body, err := s.TemplateService.EmailTemplate(opt.Template, opt.Data)
+	if err != nil {
+		return err
+	}
+
+	subject := opt.Subject
+	if subject == "" {
+		subject = emailSubjects[opt.Template]
+	}
+
+	item := &model.EmailOutbox{
+		UserId:    opt.UserId,
+		Email:     opt.Email,
+		Template:  opt.Template,
+		Subject:   subject,
+		Body:      body,
+		Status:    model.EmailOutboxStatusWait,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if err := s.EmailOutboxRepo.Create(ctx, item); err != nil {
+		return err
+	}
+
+	// 投递失败的邮件由定时任务重新投递
+	s.publish(item.Id)
+
+	return nil
+}
+
+func (s *EmailService) Deliver(ctx context.Context, id int) error {
+	item, err := s.EmailOutboxRepo.FindById(ctx, id)
+	if err != nil {
+		if utils.IsSqlNoRows(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	if item.Status != model.EmailOutboxStatusWait {
+		return nil
+	}
+
+	sendErr := s.EmailClient.SendMail(&email.Option{
+		To:      []string{item.Email},
+		Subject: item.Subject,
+		Body:    item.Body,
+	})
+
+	attempts := item.Attempts + 1
+	data := map[string]any{"attempts": attempts}
+
+	if sendErr == nil {
+		data["status"] = model.EmailOutboxStatusSuccess
+		data["sent_at"] = time.Now()
+	} else {
+		logger.Errorf("邮件发送失败 id:%d template:%s attempts:%d err:%s", item.Id, item.Template, attempts, sendErr.Error())
+
+		data["reason"] = strutil.MtSubstr(sendErr.Error(), 0, 500)
+		if attempts >= EmailMaxAttempts {
+			data["status"] = model.EmailOutboxStatusFail
+		}
+	}
+
+	if _, err := s.EmailOutboxRepo.UpdateByWhere(ctx, data, "id = ? and status = ?", item.Id, model.EmailOutboxStatusWait); err != nil {
+		return err
+	}
+
+	// 达到最大尝试次数后不再重试
+	if sendErr != nil && attempts < EmailMaxAttempts {
+		return sendErr
+	}
+
+	return nil
+}
+
+func (s *EmailService) Republish(ctx context.Context) (int, error) {
+	// 队列重试期间每次尝试都会更新 updated_at，超过 10 分钟未更新的邮件视为投递丢失
+	items, err := s.EmailOutboxRepo.FindStale(ctx, time.Now().Add(-24*time.Hour), time.Now().Add(-10*time.Minute), 500)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, item := range items {
+		_, _ = s.EmailOutboxRepo.UpdateById(ctx, item.Id, map[string]any{"updated_at": time.Now()})
+		s.publish(item.Id)
+	}
+
+	return len(items), nil
+}
+
+func (s *EmailService) SendCode(ctx context.Context, channel string, address string) error {
+	serviceName, ok := emailCodeServices[channel]
+	if !ok {
+		return errors.New("渠道不存在")
+	}
+
+	code := strutil.GenValidateCode(6)
+	if err := s.CodeStorage.Set(ctx, channel, address, code, EmailCodeExpire); err != nil {
+		return err
+	}
+
+	return s.Send(ctx, &EmailSendOpt{
+		Email:    address,
+		Template: EmailTemplateVerifyCode,
+		Data: map[string]string{
+			"service_name": serviceName,
+			"code":         code,
+		},
+	})
+}
+
+func (s *EmailService) VerifyCode(ctx context.Context, channel string, address string, code string) bool {
+	return s.CodeStorage.Verify(ctx, channel, address, code)
+}
+
+func (s *EmailService) DeleteCode(ctx context.Context, channel string, address string) {
+	_ = s.CodeStorage.Del(ctx, channel, address)
+}
+
+func (s *EmailService) publish(id int) {
+	if err := s.Producer.Publish(entity.EmailTopic, []byte(jsonutil.Encode(entity.EmailMessage{OutboxId: id}))); err != nil {
+		logger.Errorf("邮件投递队列失败 id:%d err:%s", id, err.Error())
+	}
+}
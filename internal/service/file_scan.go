@@ -0,0 +1,126 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"github.com/google/uuid"
+	"go-chat/config"
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/filesystem"
+	"go-chat/internal/pkg/logger"
+	"go-chat/internal/pkg/scanner"
+	"go-chat/internal/repository/repo"
+	"go-chat/internal/service/message"
+)
+
+var _ IFileScanService = (*FileScanService)(nil)
+
+type IFileScanService interface {
+	// ScanStream 扫描待保存的文件内容，发现威胁时隔离文件并通知用户
+	ScanStream(ctx context.Context, opt *FileScanOpt, stream []byte) error
+	// ScanObject 扫描已保存的文件，发现威胁时将文件移入隔离区并通知用户
+	ScanObject(ctx context.Context, opt *FileScanOpt, bucketName string, objectName string) error
+}
+
+type FileScanOpt struct {
+	UserId   int    // 上传用户ID
+	Filename string // 原文件名
+}
+
+type FileScanService struct {
+	Config             *config.Config
+	Scanner            scanner.Scanner
+	FileSystem         filesystem.IFilesystem
+	RobotRepo          *repo.Robot
+	TalkSessionService ITalkSessionService
+	MessageService     message.IService
+}
+
+func (s *FileScanService) ScanStream(ctx context.Context, opt *FileScanOpt, stream []byte) error {
+	result, err := s.scan(ctx, opt, bytes.NewReader(stream))
+	if err != nil || !result.Infected {
+		return err
+	}
+
+	if err := s.FileSystem.Write(s.FileSystem.BucketPrivateName(), s.quarantineName(opt.Filename), stream); err != nil {
+		logger.Errorf("文件隔离失败 uid:%d err:%s", opt.UserId, err.Error())
+	}
+
+	s.reject(ctx, opt, result)
+	return entity.ErrFileInfected
+}
+
+func (s *FileScanService) ScanObject(ctx context.Context, opt *FileScanOpt, bucketName string, objectName string) error {
+	reader, err := s.FileSystem.ReadObject(bucketName, objectName)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.scan(ctx, opt, reader)
+	_ = reader.Close()
+
+	if err != nil || !result.Infected {
+		return err
+	}
+
+	quarantine := s.quarantineName(opt.Filename)
+	if err := s.FileSystem.CopyObject(bucketName, objectName, s.FileSystem.BucketPrivateName(), quarantine); err != nil {
+		logger.Errorf("文件隔离失败 uid:%d object:%s err:%s", opt.UserId, objectName, err.Error())
+	}
+
+	_ = s.FileSystem.Delete(bucketName, objectName)
+
+	s.reject(ctx, opt, result)
+	return entity.ErrFileInfected
+}
+
+func (s *FileScanService) scan(ctx context.Context, opt *FileScanOpt, r io.Reader) (*scanner.Result, error) {
+	result, err := s.Scanner.Scan(ctx, r)
+	if err == nil {
+		return result, nil
+	}
+
+	logger.Errorf("文件安全扫描失败 uid:%d file:%s err:%s", opt.UserId, opt.Filename, err.Error())
+
+	if s.Config.Scanner != nil && s.Config.Scanner.FailOpen {
+		return &scanner.Result{}, nil
+	}
+
+	return nil, entity.ErrFileScanUnavailable
+}
+
+// quarantineName 隔离区文件路径
+func (s *FileScanService) quarantineName(filename string) string {
+	return fmt.Sprintf("quarantine/%s/%s%s", time.Now().Format("20060102"), uuid.New().String(), path.Ext(filename))
+}
+
+// reject 通过登录机器人通知用户文件已被拦截
+func (s *FileScanService) reject(ctx context.Context, opt *FileScanOpt, result *scanner.Result) {
+	logger.Warnf("拦截风险文件 uid:%d file:%s signature:%s", opt.UserId, opt.Filename, result.Signature)
+
+	robot, err := s.RobotRepo.GetLoginRobot(ctx)
+	if err != nil {
+		return
+	}
+
+	_, _ = s.TalkSessionService.Create(ctx, &TalkSessionCreateOpt{
+		UserId:     opt.UserId,
+		TalkType:   entity.ChatPrivateMode,
+		ReceiverId: robot.UserId,
+		IsBoot:     true,
+	})
+
+	err = s.MessageService.CreatePrivateSysMessage(ctx, message.CreatePrivateSysMessageOption{
+		FromId:   opt.UserId,
+		ToFromId: robot.UserId,
+		Content:  fmt.Sprintf("你上传的文件「%s」未通过安全检测(%s)，已被拦截并隔离。", opt.Filename, result.Signature),
+	})
+	if err != nil {
+		logger.Errorf("风险文件通知失败 uid:%d err:%s", opt.UserId, err.Error())
+	}
+}
@@ -3,8 +3,14 @@ package service
 import (
 	"bytes"
 	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"math"
+	"math/rand/v2"
 	"mime/multipart"
 	"path"
 	"strings"
@@ -12,6 +18,8 @@ import (
 
 	"github.com/google/uuid"
 	"go-chat/internal/pkg/jsonutil"
+	"go-chat/internal/pkg/utils"
+	"go-chat/internal/repository/cache"
 	"go-chat/internal/repository/model"
 	"go-chat/internal/repository/repo"
 	"gorm.io/gorm"
@@ -23,9 +31,20 @@ import (
 
 var _ ISplitUploadService = (*FileSplitUploadService)(nil)
 
+const (
+	multipartShardSize = 5 << 20  // 默认分片大小
+	instantProofLength = 64 << 10 // 秒传校验区间的最大长度
+)
+
 type ISplitUploadService interface {
-	InitiateMultipartUpload(ctx context.Context, params *MultipartInitiateOpt) (*model.FileUpload, error)
-	MultipartUpload(ctx context.Context, opt *MultipartUploadOpt) error
+	// InitiateMultipartUpload 初始化分片上传，存在相同内容的文件且校验持有性通过时直接秒传
+	InitiateMultipartUpload(ctx context.Context, params *MultipartInitiateOpt) (*MultipartInitiateResult, error)
+	// MultipartUpload 上传分片，所有分片上传完成后自动合并，返回是否已合并
+	MultipartUpload(ctx context.Context, opt *MultipartUploadOpt) (bool, error)
+	// MultipartStatus 查询分片上传进度
+	MultipartStatus(ctx context.Context, uid int, uploadId string) (*MultipartStatus, error)
+	// Upload 一次性上传完整文件，返回合并后的上传记录
+	Upload(ctx context.Context, uid int, file *multipart.FileHeader) (*model.FileUpload, error)
 }
 
 type FileSplitUploadService struct {
@@ -33,17 +52,67 @@ type FileSplitUploadService struct {
 	SplitUploadRepo *repo.FileUpload
 	Config          *config.Config
 	FileSystem      filesystem.IFilesystem
+	RedisLock       *cache.RedisLock
+	FileObjectRepo  *repo.FileObject
+
+	UploadChallengeStorage *cache.UploadChallengeStorage
+
+	StorageQuotaService IStorageQuotaService
+	FileScanService     IFileScanService
 }
 
 type MultipartInitiateOpt struct {
 	UserId int
 	Name   string
 	Size   int64
+	Hash   string // 文件 SHA-256(可选)
+	Proof  string // 秒传校验值(可选)，文件在校验区间内容的 SHA-256
+
+	SplitNum int // 分片数量(可选)，为空时按 5M 拆分
+}
+
+type MultipartInitiateResult struct {
+	Upload    *model.FileUpload      // 上传记录，返回秒传校验区间时为空
+	IsInstant bool                   // 是否秒传
+	Challenge *cache.UploadChallenge // 秒传校验区间，客户端需携带校验值重新初始化
+}
+
+func (s *FileSplitUploadService) InitiateMultipartUpload(ctx context.Context, params *MultipartInitiateOpt) (*MultipartInitiateResult, error) {
+	if err := s.StorageQuotaService.CheckPolicy(config.UploadTypeFile, params.Name, params.Size); err != nil {
+		return nil, err
+	}
+
+	if err := s.StorageQuotaService.Reserve(ctx, model.StorageOwnerUser, params.UserId, params.Size); err != nil {
+		return nil, err
+	}
+
+	result, err := s.initiate(ctx, params)
+	if err != nil || result.Upload == nil {
+		_ = s.StorageQuotaService.Release(ctx, model.StorageOwnerUser, params.UserId, params.Size)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
 }
 
-func (s *FileSplitUploadService) InitiateMultipartUpload(ctx context.Context, params *MultipartInitiateOpt) (*model.FileUpload, error) {
+func (s *FileSplitUploadService) initiate(ctx context.Context, params *MultipartInitiateOpt) (*MultipartInitiateResult, error) {
+	params.Hash = strings.ToLower(params.Hash)
+
+	if params.Hash != "" && params.Size > 0 {
+		result, err := s.instantUpload(ctx, params)
+		if err != nil || result != nil {
+			return result, err
+		}
+	}
+
 	// 计算拆分数量 5M
-	num := math.Ceil(float64(params.Size) / float64(5*1024*1024))
+	num := math.Ceil(float64(params.Size) / float64(multipartShardSize))
+	if params.SplitNum > 0 {
+		num = float64(params.SplitNum)
+	}
 
 	now := time.Now()
 	m := &model.FileUpload{
@@ -54,6 +123,7 @@ func (s *FileSplitUploadService) InitiateMultipartUpload(ctx context.Context, pa
 		SplitNum:     int(num),
 		FileExt:      strings.TrimPrefix(path.Ext(params.Name), "."),
 		FileSize:     params.Size,
+		Hash:         params.Hash,
 		Path:         fmt.Sprintf("multipart/%s/%s.tmp", now.Format("20060102"), uuid.New().String()),
 		Attr:         "{}",
 	}
@@ -69,7 +139,112 @@ func (s *FileSplitUploadService) InitiateMultipartUpload(ctx context.Context, pa
 		return nil, err
 	}
 
-	return m, nil
+	return &MultipartInitiateResult{Upload: m}, nil
+}
+
+// instantUpload 秒传，已存在相同内容的文件时直接引用，不再传输文件内容。返回 nil 时按普通上传处理
+func (s *FileSplitUploadService) instantUpload(ctx context.Context, params *MultipartInitiateOpt) (*MultipartInitiateResult, error) {
+	object, err := s.FileObjectRepo.FindByHash(ctx, params.Hash)
+	if err != nil {
+		if utils.IsSqlNoRows(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	drive := entity.FileDriveMode(s.FileSystem.Driver())
+	if object.Drive != drive || object.FileSize != params.Size {
+		return nil, nil
+	}
+
+	if _, err := s.FileSystem.Stat(s.FileSystem.BucketPrivateName(), object.Path); err != nil {
+		return nil, nil
+	}
+
+	// SHA-256 及文件大小可能被泄露，需校验随机区间的内容证明客户端确实持有文件
+	if params.Proof == "" {
+		challenge, err := s.challenge(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+
+		return &MultipartInitiateResult{Challenge: challenge}, nil
+	}
+
+	if !s.verifyProof(ctx, params, object.Path) {
+		return nil, nil
+	}
+
+	// 引用计数已归零的对象等待定时任务清理，不再复用
+	ok, err := s.FileObjectRepo.IncrRef(ctx, object.Id)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	m := &model.FileUpload{
+		Type:         1,
+		Drive:        drive,
+		UserId:       params.UserId,
+		UploadId:     uuid.New().String(),
+		OriginalName: params.Name,
+		SplitNum:     0,
+		FileExt:      strings.TrimPrefix(path.Ext(params.Name), "."),
+		FileSize:     params.Size,
+		Hash:         params.Hash,
+		ScanStatus:   model.FileScanStatusSafe,
+		Path:         object.Path,
+		Attr:         "{}",
+	}
+
+	if err := s.Source.Db().WithContext(ctx).Create(m).Error; err != nil {
+		_ = s.FileObjectRepo.DecrRef(ctx, object.Id)
+		return nil, err
+	}
+
+	return &MultipartInitiateResult{Upload: m, IsInstant: true}, nil
+}
+
+// challenge 生成秒传校验区间
+func (s *FileSplitUploadService) challenge(ctx context.Context, params *MultipartInitiateOpt) (*cache.UploadChallenge, error) {
+	length := min(params.Size, instantProofLength)
+
+	challenge := &cache.UploadChallenge{
+		Offset: rand.Int64N(params.Size - length + 1),
+		Length: length,
+	}
+
+	if err := s.UploadChallengeStorage.Set(ctx, params.UserId, params.Hash, challenge, 10*time.Minute); err != nil {
+		return nil, err
+	}
+
+	return challenge, nil
+}
+
+// verifyProof 校验客户端提交的区间内容 SHA-256
+func (s *FileSplitUploadService) verifyProof(ctx context.Context, params *MultipartInitiateOpt, objectName string) bool {
+	challenge, err := s.UploadChallengeStorage.Take(ctx, params.UserId, params.Hash)
+	if err != nil {
+		return false
+	}
+
+	reader, err := s.FileSystem.ReadObject(s.FileSystem.BucketPrivateName(), objectName)
+	if err != nil {
+		return false
+	}
+
+	defer reader.Close()
+
+	if _, err := io.CopyN(io.Discard, reader, challenge.Offset); err != nil {
+		return false
+	}
+
+	hash := sha256.New()
+	if _, err := io.CopyN(hash, reader, challenge.Length); err != nil {
+		return false
+	}
+
+	return strings.EqualFold(hex.EncodeToString(hash.Sum(nil)), params.Proof)
 }
 
 type MultipartUploadOpt struct {
@@ -77,18 +252,56 @@ type MultipartUploadOpt struct {
 	UploadId   string
 	SplitIndex int
 	SplitNum   int
+	Checksum   string // 分片 MD5 校验值(可选)
 	File       *multipart.FileHeader
 }
 
-func (s *FileSplitUploadService) MultipartUpload(ctx context.Context, opt *MultipartUploadOpt) error {
-	info, err := s.SplitUploadRepo.FindByWhere(ctx, "upload_id = ? and type = 1", opt.UploadId)
+type MultipartStatus struct {
+	UploadId string `json:"upload_id"`
+	SplitNum int    `json:"split_num"`
+	Uploaded []int  `json:"uploaded"`  // 已上传的分片索引
+	IsMerge  bool   `json:"is_merge"`  // 是否已合并完成
+	IsReject bool   `json:"is_reject"` // 是否未通过安全检测
+}
+
+func (s *FileSplitUploadService) MultipartUpload(ctx context.Context, opt *MultipartUploadOpt) (bool, error) {
+	info, err := s.SplitUploadRepo.GetFile(ctx, opt.UserId, opt.UploadId)
 	if err != nil {
-		return err
+		if utils.IsSqlNoRows(err) {
+			return false, entity.ErrDataNotFound
+		}
+
+		return false, err
+	}
+
+	if info.ScanStatus == model.FileScanStatusRejected {
+		return false, entity.ErrFileInfected
+	}
+
+	if opt.SplitIndex < 1 || opt.SplitIndex > info.SplitNum {
+		return false, fmt.Errorf("分片索引超出范围[1-%d]", info.SplitNum)
+	}
+
+	// 配额按初始化时声明的文件大小预留，分片大小不能超出按声明大小拆分的分片大小
+	if shardSize := s.shardSize(info); opt.File.Size > shardSize {
+		return false, fmt.Errorf("分片大小超出限制[%d]", shardSize)
 	}
 
 	stream, err := filesystem.ReadMultipartStream(opt.File)
 	if err != nil {
-		return err
+		return false, err
+	}
+
+	// 根据首个分片的内容校验文件类型
+	if opt.SplitIndex == 1 {
+		if err := s.StorageQuotaService.CheckMimeType(config.UploadTypeFile, stream); err != nil {
+			return false, err
+		}
+	}
+
+	// 校验分片完整性
+	if opt.Checksum != "" && !strings.EqualFold(opt.Checksum, fmt.Sprintf("%x", md5.Sum(stream))) {
+		return false, errors.New("分片校验失败，请重新上传")
 	}
 
 	data := &model.FileUpload{
@@ -98,7 +311,7 @@ func (s *FileSplitUploadService) MultipartUpload(ctx context.Context, opt *Multi
 		UploadId:     opt.UploadId,
 		OriginalName: info.OriginalName,
 		SplitIndex:   opt.SplitIndex,
-		SplitNum:     opt.SplitNum,
+		SplitNum:     info.SplitNum,
 		Path:         "",
 		FileExt:      info.FileExt,
 		FileSize:     opt.File.Size,
@@ -116,7 +329,7 @@ func (s *FileSplitUploadService) MultipartUpload(ctx context.Context, opt *Multi
 		read.Size(),
 	)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	if objectPart.PartObjectName != "" {
@@ -125,29 +338,196 @@ func (s *FileSplitUploadService) MultipartUpload(ctx context.Context, opt *Multi
 
 	data.Attr = jsonutil.Encode(objectPart)
 
-	if err = s.Source.Db().Create(data).Error; err != nil {
-		return err
+	// 同一分片重复上传时覆盖原记录
+	err = s.Source.Db().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&model.FileUpload{}, "upload_id = ? and type = 2 and split_index = ?", opt.UploadId, opt.SplitIndex).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(data).Error
+	})
+	if err != nil {
+		return false, err
+	}
+
+	uploaded, err := s.uploadedIndexes(ctx, info.UploadId)
+	if err != nil {
+		return false, err
 	}
 
-	// 判断是否为最后一个分片上传
-	if opt.SplitNum == opt.SplitIndex {
-		err = s.merge(info)
+	// 分片可乱序上传，全部分片到达后再合并
+	if len(uploaded) < info.SplitNum {
+		return false, nil
 	}
 
-	return err
+	return s.merge(ctx, info)
 }
 
-// combine
-func (s *FileSplitUploadService) merge(info *model.FileUpload) error {
-	items, err := s.SplitUploadRepo.FindAll(context.Background(), func(db *gorm.DB) {
-		db.Where("upload_id =? and type = 2", info.UploadId).Order("split_index asc")
+func (s *FileSplitUploadService) Upload(ctx context.Context, uid int, file *multipart.FileHeader) (*model.FileUpload, error) {
+	result, err := s.InitiateMultipartUpload(ctx, &MultipartInitiateOpt{
+		UserId:   uid,
+		Name:     file.Filename,
+		Size:     file.Size,
+		SplitNum: 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	info := result.Upload
+
+	isMerged, err := s.MultipartUpload(ctx, &MultipartUploadOpt{
+		UserId:     uid,
+		UploadId:   info.UploadId,
+		SplitIndex: 1,
+		SplitNum:   1,
+		File:       file,
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !isMerged {
+		return nil, errors.New("文件上传失败，请重试")
+	}
+
+	return info, nil
+}
+
+func (s *FileSplitUploadService) MultipartStatus(ctx context.Context, uid int, uploadId string) (*MultipartStatus, error) {
+	info, err := s.SplitUploadRepo.GetFile(ctx, uid, uploadId)
+	if err != nil {
+		if utils.IsSqlNoRows(err) {
+			return nil, entity.ErrDataNotFound
+		}
+
+		return nil, err
+	}
 
+	uploaded, err := s.uploadedIndexes(ctx, uploadId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MultipartStatus{
+		UploadId: uploadId,
+		SplitNum: info.SplitNum,
+		Uploaded: uploaded,
+		IsMerge:  s.isMerged(info),
+		IsReject: info.ScanStatus == model.FileScanStatusRejected,
+	}, nil
+}
+
+func (s *FileSplitUploadService) uploadedIndexes(ctx context.Context, uploadId string) ([]int, error) {
+	indexes := make([]int, 0)
+
+	err := s.SplitUploadRepo.Model(ctx).
+		Where("upload_id = ? and type = 2", uploadId).
+		Distinct("split_index").
+		Order("split_index asc").
+		Pluck("split_index", &indexes).Error
+
+	return indexes, err
+}
+
+// isMerged 判断是否已合并完成(合并且通过安全扫描)
+func (s *FileSplitUploadService) isMerged(info *model.FileUpload) bool {
+	if info.ScanStatus != model.FileScanStatusSafe {
+		return false
+	}
+
+	_, err := s.FileSystem.Stat(s.FileSystem.BucketPrivateName(), info.Path)
+	return err == nil
+}
+
+// merge 合并分片文件
+func (s *FileSplitUploadService) merge(ctx context.Context, info *model.FileUpload) (bool, error) {
+	lockName := fmt.Sprintf("upload:merge:%s", info.UploadId)
+	if !s.RedisLock.Lock(ctx, lockName, 600) {
+		// 其它请求正在合并
+		return false, nil
+	}
+
+	defer s.RedisLock.UnLock(ctx, lockName)
+
+	// 重新读取，其它请求可能已合并完成并变更了文件路径
+	info, err := s.SplitUploadRepo.FindById(ctx, info.Id)
+	if err != nil {
+		return false, err
+	}
+
+	if s.isMerged(info) {
+		return true, nil
+	}
+
+	if info.ScanStatus == model.FileScanStatusRejected {
+		return false, entity.ErrFileInfected
+	}
+
+	// 已合并但未完成安全扫描时无需重复合并
+	if _, err := s.FileSystem.Stat(s.FileSystem.BucketPrivateName(), info.Path); err != nil {
+		if err := s.complete(ctx, info); err != nil {
+			return false, err
+		}
+	}
+
+	err = s.FileScanService.ScanObject(ctx, &FileScanOpt{
+		UserId:   info.UserId,
+		Filename: info.OriginalName,
+	}, s.FileSystem.BucketPrivateName(), info.Path)
+	if err != nil {
+		if errors.Is(err, entity.ErrFileInfected) {
+			_, _ = s.SplitUploadRepo.UpdateById(ctx, info.Id, map[string]any{"scan_status": model.FileScanStatusRejected})
+		}
+
+		return false, err
+	}
+
+	if info.Hash != "" {
+		if err := s.storeObject(ctx, info); err != nil {
+			return false, err
+		}
+	}
+
+	if _, err := s.SplitUploadRepo.UpdateById(ctx, info.Id, map[string]any{"scan_status": model.FileScanStatusSafe}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// shardSize 按声明的文件大小及分片数量计算单个分片的最大大小
+func (s *FileSplitUploadService) shardSize(info *model.FileUpload) int64 {
+	if info.SplitNum <= 1 {
+		return info.FileSize
+	}
+
+	size := (info.FileSize + int64(info.SplitNum) - 1) / int64(info.SplitNum)
+
+	// 默认按固定大小拆分，除最后一个分片外均为固定大小
+	return max(size, min(info.FileSize, multipartShardSize))
+}
+
+// complete 合并分片文件
+func (s *FileSplitUploadService) complete(ctx context.Context, info *model.FileUpload) error {
+	items, err := s.SplitUploadRepo.FindAll(ctx, func(db *gorm.DB) {
+		db.Where("upload_id = ? and type = 2", info.UploadId).Order("split_index asc")
+	})
 	if err != nil {
 		return err
 	}
 
-	parts := make([]filesystem.ObjectPart, 0)
+	var total int64
+	for _, item := range items {
+		total += item.FileSize
+	}
+
+	// 实际上传的大小须与初始化时声明的大小一致，避免绕过存储配额
+	if total != info.FileSize {
+		return fmt.Errorf("文件大小不一致，声明大小 %d，实际大小 %d", info.FileSize, total)
+	}
+
+	parts := make([]filesystem.ObjectPart, 0, len(items))
 	for _, item := range items {
 		var obj filesystem.ObjectPart
 		if err = jsonutil.Decode(item.Attr, &obj); err != nil {
@@ -157,10 +537,96 @@ func (s *FileSplitUploadService) merge(info *model.FileUpload) error {
 		parts = append(parts, obj)
 	}
 
-	// 合并文件
-	if err := s.FileSystem.CompleteMultipartUpload(s.FileSystem.BucketPrivateName(), info.Path, info.UploadId, parts); err != nil {
+	return s.FileSystem.CompleteMultipartUpload(s.FileSystem.BucketPrivateName(), info.Path, info.UploadId, parts)
+}
+
+// storeObject 校验合并后的文件并转存为内容寻址的文件对象，供后续相同文件秒传
+func (s *FileSplitUploadService) storeObject(ctx context.Context, info *model.FileUpload) error {
+	bucketName := s.FileSystem.BucketPrivateName()
+
+	hash, err := s.checksum(bucketName, info.Path)
+	if err != nil {
+		return err
+	}
+
+	if hash != info.Hash {
+		_ = s.FileSystem.Delete(bucketName, info.Path)
+		return errors.New("文件校验失败，SHA-256 不一致")
+	}
+
+	lockName := fmt.Sprintf("file:object:%s", hash)
+	if !s.RedisLock.Lock(ctx, lockName, 600) {
+		// 相同文件正在入库，保留为普通文件
+		return nil
+	}
+
+	defer s.RedisLock.UnLock(ctx, lockName)
+
+	object, err := s.FileObjectRepo.FindByHash(ctx, hash)
+	if err != nil && !utils.IsSqlNoRows(err) {
+		return err
+	}
+
+	// 并发上传了相同文件，直接引用已有对象
+	if object != nil {
+		if object.Drive != info.Drive {
+			return nil
+		}
+
+		ok, err := s.FileObjectRepo.IncrRef(ctx, object.Id)
+		if err != nil || !ok {
+			return err
+		}
+
+		if _, err := s.SplitUploadRepo.UpdateById(ctx, info.Id, map[string]any{"path": object.Path}); err != nil {
+			_ = s.FileObjectRepo.DecrRef(ctx, object.Id)
+			return err
+		}
+
+		_ = s.FileSystem.Delete(bucketName, info.Path)
+		return nil
+	}
+
+	objectName := fmt.Sprintf("objects/%s/%s/%s", hash[:2], hash[2:4], hash)
+	if err := s.FileSystem.Copy(bucketName, info.Path, objectName); err != nil {
 		return err
 	}
 
+	err = s.Source.Db().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&model.FileObject{
+			Hash:     hash,
+			Drive:    info.Drive,
+			Path:     objectName,
+			FileSize: info.FileSize,
+			RefCount: 1,
+		}).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&model.FileUpload{}).Where("id = ?", info.Id).Update("path", objectName).Error
+	})
+	if err != nil {
+		_ = s.FileSystem.Delete(bucketName, objectName)
+		return err
+	}
+
+	_ = s.FileSystem.Delete(bucketName, info.Path)
 	return nil
 }
+
+// checksum 计算文件 SHA-256
+func (s *FileSplitUploadService) checksum(bucketName string, objectName string) (string, error) {
+	reader, err := s.FileSystem.ReadObject(bucketName, objectName)
+	if err != nil {
+		return "", err
+	}
+
+	defer reader.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, reader); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,336 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+	"unicode/utf8"
+
+	"go-chat/config"
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/encrypt"
+	"go-chat/internal/pkg/ldap"
+	"go-chat/internal/pkg/logger"
+	"go-chat/internal/pkg/strutil"
+	"go-chat/internal/pkg/utils"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+	"gorm.io/gorm"
+)
+
+// LdapProvider 目录账号在 user_identity 中的身份提供方标识
+const LdapProvider = "ldap"
+
+var mobileRegexp = regexp.MustCompile(`^1\d{10}$`)
+
+var _ ILdapService = (*LdapService)(nil)
+
+type ILdapService interface {
+	// Enabled 是否启用目录认证
+	Enabled() bool
+	// Login 使用目录账号登录，首次登录时关联或创建本地账号；账号不在目录中且允许本地登录时校验本地密码
+	Login(ctx context.Context, account string, password string) (*model.Users, error)
+	// Sync 同步目录中的用户及组织架构，并禁用已离开目录的用户
+	Sync(ctx context.Context) (*LdapSyncResult, error)
+}
+
+type LdapService struct {
+	Config              *config.Config
+	Source              *repo.Source
+	LdapClient          *ldap.Client
+	UsersRepo           *repo.Users
+	UserIdentityRepo    *repo.UserIdentity
+	UserService         IUserService
+	AdminUserService    IAdminUserService
+	OrganizeSyncService IOrganizeSyncService
+}
+
+type LdapSyncResult struct {
+	Total    int // 目录中的用户数
+	Created  int // 新创建的账号数
+	Disabled int // 因离开目录被禁用的账号数
+	Failed   int // 同步失败的用户数
+}
+
+func (s *LdapService) Enabled() bool {
+	return s.Config.Ldap != nil && s.Config.Ldap.Enabled
+}
+
+func (s *LdapService) Login(ctx context.Context, account string, password string) (*model.Users, error) {
+	entry, err := s.LdapClient.Authenticate(ctx, account, password)
+	if err != nil {
+		if errors.Is(err, ldap.ErrUserNotFound) && s.Config.Ldap.FallbackLocal {
+			return s.UserService.Login(ctx, account, password)
+		}
+
+		if errors.Is(err, ldap.ErrUserNotFound) || errors.Is(err, ldap.ErrInvalidCredentials) || errors.Is(err, ldap.ErrUserAmbiguous) {
+			return nil, entity.ErrAccountOrPassword
+		}
+
+		logger.Errorf("目录服务认证异常 account:%s err:%s", account, err.Error())
+		return nil, errors.New("目录服务暂不可用，请稍后再试")
+	}
+
+	user, _, err := s.upsert(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Status == model.UsersStatusDisabled {
+		return nil, entity.ErrAccountDisabled
+	}
+
+	if err := s.OrganizeSyncService.SyncMember(ctx, user.Id, entry.Department, entry.Position); err != nil {
+		logger.Errorf("目录账号同步组织架构失败 uid:%d err:%s", user.Id, err.Error())
+	}
+
+	_, _ = s.UserIdentityRepo.UpdateByWhere(ctx, map[string]any{
+		"email":         entry.Email,
+		"last_login_at": time.Now(),
+	}, "provider = ? and subject = ?", LdapProvider, entry.Id)
+
+	return user, nil
+}
+
+func (s *LdapService) Sync(ctx context.Context) (*LdapSyncResult, error) {
+	entries, err := s.LdapClient.Users(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 目录配置错误时可能查询不到任何用户，此时不能禁用全部账号
+	if len(entries) == 0 {
+		return nil, errors.New("目录中未查询到用户，已跳过同步")
+	}
+
+	result := &LdapSyncResult{Total: len(entries)}
+	exists := make(map[string]struct{}, len(entries))
+
+	for _, entry := range entries {
+		exists[entry.Id] = struct{}{}
+
+		user, created, err := s.upsert(ctx, entry)
+		if err != nil {
+			result.Failed++
+			logger.Errorf("目录用户同步失败 dn:%s err:%s", entry.DN, err.Error())
+			continue
+		}
+
+		if created {
+			result.Created++
+		}
+
+		if err := s.OrganizeSyncService.SyncMember(ctx, user.Id, entry.Department, entry.Position); err != nil {
+			result.Failed++
+			logger.Errorf("目录用户同步组织架构失败 dn:%s uid:%d err:%s", entry.DN, user.Id, err.Error())
+		}
+	}
+
+	identities, err := s.UserIdentityRepo.FindAll(ctx, func(db *gorm.DB) {
+		db.Where("provider = ?", LdapProvider)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, identity := range identities {
+		if _, ok := exists[identity.Subject]; ok {
+			continue
+		}
+
+		disabled, err := s.disable(ctx, identity.UserId)
+		if err != nil {
+			result.Failed++
+			logger.Errorf("禁用已离开目录的用户失败 uid:%d err:%s", identity.UserId, err.Error())
+			continue
+		}
+
+		if disabled {
+			result.Disabled++
+		}
+	}
+
+	return result, nil
+}
+
+// 查找目录账号对应的本地账号，依次按已绑定账号、手机号、邮箱关联，均未找到时创建账号
+func (s *LdapService) upsert(ctx context.Context, entry *ldap.Entry) (*model.Users, bool, error) {
+	identity, err := s.UserIdentityRepo.FindBySubject(ctx, LdapProvider, entry.Id)
+	if err == nil {
+		user, err := s.UsersRepo.FindById(ctx, identity.UserId)
+		if err != nil {
+			return nil, false, err
+		}
+
+		return user, false, nil
+	}
+
+	if !utils.IsSqlNoRows(err) {
+		return nil, false, err
+	}
+
+	user, err := s.match(ctx, entry)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if user != nil {
+		if err := s.UserIdentityRepo.Create(ctx, s.identity(user.Id, entry)); err != nil {
+			return nil, false, err
+		}
+
+		logger.Infof("目录账号关联已有账号 dn:%s uid:%d", entry.DN, user.Id)
+		return user, false, nil
+	}
+
+	user, err = s.register(ctx, entry)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return user, true, nil
+}
+
+// 按手机号或邮箱匹配尚未绑定目录账号的本地账号
+func (s *LdapService) match(ctx context.Context, entry *ldap.Entry) (*model.Users, error) {
+	candidates := make([]*model.Users, 0)
+
+	if mobileRegexp.MatchString(entry.Mobile) {
+		user, err := s.UsersRepo.FindByMobile(ctx, entry.Mobile)
+		if err == nil {
+			candidates = append(candidates, user)
+		} else if !utils.IsSqlNoRows(err) {
+			return nil, err
+		}
+	}
+
+	if len(candidates) == 0 && entry.Email != "" {
+		users, err := s.UsersRepo.FindAll(ctx, func(db *gorm.DB) {
+			db.Where("email = ?", entry.Email).Limit(2)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// 同一邮箱对应多个账号时无法确定关联哪个账号
+		if len(users) > 1 {
+			return nil, fmt.Errorf("邮箱 %s 对应多个账号", entry.Email)
+		}
+
+		candidates = append(candidates, users...)
+	}
+
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	user := candidates[0]
+	if user.IsRobot == model.Yes {
+		return nil, fmt.Errorf("目录账号 %s 与机器人账号冲突", entry.DN)
+	}
+
+	isBound, err := s.UserIdentityRepo.IsBound(ctx, LdapProvider, user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	// 已绑定其它目录账号，说明目录中存在重复的手机号或邮箱
+	if isBound {
+		return nil, fmt.Errorf("账号 %d 已绑定其它目录账号", user.Id)
+	}
+
+	return user, nil
+}
+
+func (s *LdapService) register(ctx context.Context, entry *ldap.Entry) (*model.Users, error) {
+	// 目录中没有可用的手机号时，手机号仅用于满足唯一索引，用户可自行修改
+	mobile := fmt.Sprintf("l%s", strutil.Random(10))
+	if mobileRegexp.MatchString(entry.Mobile) && !s.UsersRepo.IsMobileExist(ctx, entry.Mobile) {
+		mobile = entry.Mobile
+	}
+
+	nickname := entry.Nickname
+	if nickname == "" {
+		nickname = entry.Id
+	}
+
+	if utf8.RuneCountInString(nickname) > 20 {
+		nickname = string([]rune(nickname)[:20])
+	}
+
+	user := &model.Users{
+		Mobile:    mobile,
+		Nickname:  nickname,
+		Gender:    model.UsersGenderDefault,
+		Password:  encrypt.HashPassword(strutil.Random(32)),
+		IsRobot:   model.No,
+		Status:    model.UsersStatusNormal,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	// users.email 字段长度有限，超长的邮箱仅保存在绑定记录中
+	if len(entry.Email) <= 30 {
+		user.Email = entry.Email
+	}
+
+	err := s.Source.Db().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(s.identity(user.Id, entry)).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("目录账号自动创建账号 dn:%s uid:%d", entry.DN, user.Id)
+
+	return user, nil
+}
+
+func (s *LdapService) identity(uid int, entry *ldap.Entry) *model.UserIdentity {
+	return &model.UserIdentity{
+		UserId:      uid,
+		Provider:    LdapProvider,
+		Subject:     entry.Id,
+		Email:       entry.Email,
+		LastLoginAt: time.Now(),
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+}
+
+// 禁用已离开目录的用户并移出组织架构，已禁用的账号不重复处理
+//
+// 用户重新加入目录后不会自动启用，需由管理员确认后手动启用
+func (s *LdapService) disable(ctx context.Context, uid int) (bool, error) {
+	user, err := s.UsersRepo.FindById(ctx, uid)
+	if err != nil {
+		if utils.IsSqlNoRows(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	if user.Status == model.UsersStatusDisabled {
+		return false, nil
+	}
+
+	if err := s.AdminUserService.Disable(ctx, uid); err != nil {
+		return false, err
+	}
+
+	if err := s.OrganizeSyncService.RemoveMember(ctx, uid); err != nil {
+		return false, err
+	}
+
+	logger.Infof("用户已离开目录，禁用账号 uid:%d", uid)
+
+	return true, nil
+}
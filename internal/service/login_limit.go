@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"go-chat/config"
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/core/errorx"
+	"go-chat/internal/pkg/logger"
+	"go-chat/internal/repository/cache"
+)
+
+var _ ILoginLimitService = (*LoginLimitService)(nil)
+
+type ILoginLimitService interface {
+	// Check 登录前检查账号及 IP 是否被锁定或需要等待，返回是否需要填写图形验证码
+	Check(ctx context.Context, account string, ip string) (bool, error)
+	// Fail 记录一次登录失败，失败次数达到阈值时临时锁定
+	Fail(ctx context.Context, account string, ip string)
+	// Success 登录成功后清除账号的失败记录，IP 的失败记录不清除，避免通过一个可登录的账号重置计数
+	Success(ctx context.Context, account string)
+}
+
+type LoginLimitService struct {
+	Config            *config.Config
+	LoginLimitStorage *cache.LoginLimitStorage
+}
+
+func (s *LoginLimitService) Check(ctx context.Context, account string, ip string) (bool, error) {
+	conf := s.Config.Login.WithDefault()
+
+	accountState, err := s.LoginLimitStorage.State(ctx, cache.LoginLimitAccount, s.account(account))
+	if err != nil {
+		return false, err
+	}
+
+	ipState, err := s.LoginLimitStorage.State(ctx, cache.LoginLimitIp, ip)
+	if err != nil {
+		return false, err
+	}
+
+	if locked := max(accountState.Locked, ipState.Locked); locked > 0 {
+		minutes := int(math.Ceil(locked.Minutes()))
+		return false, errorx.New(entity.ErrLoginLocked.Code, fmt.Sprintf("登录失败次数过多，请 %d 分钟后再试", minutes))
+	}
+
+	if accountState.Delay > 0 {
+		seconds := int(math.Ceil(accountState.Delay.Seconds()))
+		return false, errorx.New(entity.ErrLoginTooFrequent.Code, fmt.Sprintf("登录尝试过于频繁，请 %d 秒后再试", seconds))
+	}
+
+	return accountState.Failures >= int64(conf.CaptchaThreshold) || ipState.Failures >= int64(conf.IpCaptchaThreshold), nil
+}
+
+func (s *LoginLimitService) Fail(ctx context.Context, account string, ip string) {
+	conf := s.Config.Login.WithDefault()
+	window := time.Duration(conf.Window) * time.Second
+	account = s.account(account)
+
+	count, err := s.LoginLimitStorage.Fail(ctx, cache.LoginLimitAccount, account, window)
+	if err != nil {
+		logger.Errorf("记录登录失败次数异常 account:%s err:%s", account, err.Error())
+		return
+	}
+
+	if count >= int64(conf.LockThreshold) {
+		_ = s.LoginLimitStorage.Lock(ctx, cache.LoginLimitAccount, account, time.Duration(conf.LockDuration)*time.Second)
+		logger.Infof("登录失败次数过多，临时锁定账号 account:%s ip:%s", account, ip)
+	} else if delay := s.delay(conf, count); delay > 0 {
+		_ = s.LoginLimitStorage.Delay(ctx, cache.LoginLimitAccount, account, delay)
+	}
+
+	count, err = s.LoginLimitStorage.Fail(ctx, cache.LoginLimitIp, ip, window)
+	if err != nil {
+		logger.Errorf("记录登录失败次数异常 ip:%s err:%s", ip, err.Error())
+		return
+	}
+
+	if count >= int64(conf.IpLockThreshold) {
+		_ = s.LoginLimitStorage.Lock(ctx, cache.LoginLimitIp, ip, time.Duration(conf.LockDuration)*time.Second)
+		logger.Infof("登录失败次数过多，临时禁止 IP 登录 ip:%s", ip)
+	}
+}
+
+func (s *LoginLimitService) Success(ctx context.Context, account string) {
+	_ = s.LoginLimitStorage.Clear(ctx, cache.LoginLimitAccount, s.account(account))
+}
+
+// 第二次失败起需等待后才能再次尝试，等待时间逐次翻倍
+func (s *LoginLimitService) delay(conf *config.Login, count int64) time.Duration {
+	if count < 2 {
+		return 0
+	}
+
+	seconds := math.Pow(2, float64(count-2))
+	return time.Duration(min(seconds, float64(conf.MaxDelay))) * time.Second
+}
+
+func (s *LoginLimitService) account(account string) string {
+	return strings.ToLower(strings.TrimSpace(account))
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"go-chat/config"
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/core/errorx"
+	"go-chat/internal/repository/cache"
+)
+
+func newTestLoginLimitService(t *testing.T, conf *config.Login) (*LoginLimitService, *miniredis.Miniredis) {
+	t.Helper()
+
+	rds, mr := newTestRedis(t)
+
+	return &LoginLimitService{
+		Config:            &config.Config{Login: conf},
+		LoginLimitStorage: cache.NewLoginLimitStorage(rds),
+	}, mr
+}
+
+func loginLimitCode(err error) int {
+	var e *errorx.Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+
+	return 0
+}
+
+func TestLoginLimitService_Delay(t *testing.T) {
+	ctx := context.Background()
+	svc, mr := newTestLoginLimitService(t, &config.Login{MaxDelay: 2})
+
+	svc.Fail(ctx, "Admin", "127.0.0.1")
+
+	captcha, err := svc.Check(ctx, "admin", "127.0.0.1")
+	assert.NoError(t, err)
+	assert.False(t, captcha)
+
+	// 第二次失败起需等待，等待时间逐次翻倍且不超过上限
+	for _, wait := range []time.Duration{time.Second, 2 * time.Second, 2 * time.Second} {
+		svc.Fail(ctx, "admin", "127.0.0.1")
+
+		_, err = svc.Check(ctx, " ADMIN ", "127.0.0.1")
+		assert.Equal(t, entity.ErrLoginTooFrequent.Code, loginLimitCode(err))
+
+		mr.FastForward(wait - time.Millisecond)
+		_, err = svc.Check(ctx, "admin", "127.0.0.1")
+		assert.Error(t, err)
+
+		mr.FastForward(time.Millisecond)
+	}
+
+	// 失败次数达到阈值后需填写图形验证码
+	captcha, err = svc.Check(ctx, "admin", "127.0.0.1")
+	assert.NoError(t, err)
+	assert.True(t, captcha)
+
+	// 登录成功后清除账号的失败记录
+	svc.Success(ctx, "admin")
+
+	captcha, err = svc.Check(ctx, "admin", "127.0.0.1")
+	assert.NoError(t, err)
+	assert.False(t, captcha)
+}
+
+func TestLoginLimitService_Lock(t *testing.T) {
+	ctx := context.Background()
+	svc, mr := newTestLoginLimitService(t, &config.Login{LockThreshold: 3, LockDuration: 60})
+
+	for i := 0; i < 3; i++ {
+		svc.Fail(ctx, "admin", "127.0.0.1")
+	}
+
+	_, err := svc.Check(ctx, "admin", "127.0.0.2")
+	assert.Equal(t, entity.ErrLoginLocked.Code, loginLimitCode(err))
+
+	// 登录成功不解除锁定
+	svc.Success(ctx, "admin")
+	_, err = svc.Check(ctx, "admin", "127.0.0.2")
+	assert.Equal(t, entity.ErrLoginLocked.Code, loginLimitCode(err))
+
+	// 其它账号不受影响
+	_, err = svc.Check(ctx, "other", "127.0.0.2")
+	assert.NoError(t, err)
+
+	mr.FastForward(time.Minute)
+	_, err = svc.Check(ctx, "admin", "127.0.0.2")
+	assert.NoError(t, err)
+}
+
+func TestLoginLimitService_IpLock(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestLoginLimitService(t, &config.Login{IpCaptchaThreshold: 2, IpLockThreshold: 4})
+
+	// 同一 IP 尝试不同账号时按 IP 统计失败次数
+	for _, account := range []string{"a", "b"} {
+		svc.Fail(ctx, account, "10.0.0.1")
+	}
+
+	captcha, err := svc.Check(ctx, "c", "10.0.0.1")
+	assert.NoError(t, err)
+	assert.True(t, captcha)
+
+	for _, account := range []string{"c", "d"} {
+		svc.Fail(ctx, account, "10.0.0.1")
+	}
+
+	_, err = svc.Check(ctx, "e", "10.0.0.1")
+	assert.Equal(t, entity.ErrLoginLocked.Code, loginLimitCode(err))
+
+	// 登录成功不清除 IP 的失败记录
+	svc.Success(ctx, "e")
+	_, err = svc.Check(ctx, "e", "10.0.0.1")
+	assert.Equal(t, entity.ErrLoginLocked.Code, loginLimitCode(err))
+
+	_, err = svc.Check(ctx, "e", "10.0.0.2")
+	assert.NoError(t, err)
+}
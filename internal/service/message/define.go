@@ -61,6 +61,8 @@ type CreateImageMessage struct {
 	ToFromId int    `json:"to_from_id"` // 接受者(好友ID或者群组ID)
 	QuoteId  string `json:"quote_id"`   // 引用消息id
 	Url      string `json:"url"`        // 图片地址
+	Thumb    string `json:"thumb"`      // 缩略图地址
+	Medium   string `json:"medium"`     // 中图地址
 	Width    int    `json:"width"`      // 图片宽度
 	Height   int    `json:"height"`     // 图片高度
 	Size     int    `json:"size"`       // 图片大小
@@ -154,3 +156,11 @@ type CreateMixedMessageItem struct {
 	Type    int    `json:"type"`    // 消息类型
 	Content string `json:"content"` // 消息内容
 }
+
+type CreateEphemeralMessage struct {
+	TalkMode int    `json:"talk_mode"`  // 发送模式，1-单聊，2-群聊
+	FromId   int    `json:"from_id"`    // 发送者(机器人用户ID，为0时为系统消息)
+	ToFromId int    `json:"to_from_id"` // 接收者所在对话ID(好友ID或者群组ID)
+	UserId   int    `json:"user_id"`    // 接收者用户ID
+	Content  string `json:"content"`    // 消息内容
+}
@@ -0,0 +1,45 @@
+package message
+
+import (
+	"context"
+	"time"
+
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/jsonutil"
+	"go-chat/internal/pkg/strutil"
+	"go-chat/internal/repository/model"
+)
+
+// CreateEphemeralMessage 临时消息，仅推送给指定用户的在线客户端，不保存消息记录
+func (s *Service) CreateEphemeralMessage(ctx context.Context, option CreateEphemeralMessage) error {
+	body := entity.ImMessagePayloadBody{
+		MsgId:    strutil.NewMsgId(),
+		MsgType:  entity.ChatMsgTypeText,
+		FromId:   option.FromId,
+		SendTime: time.Now().Format(time.DateTime),
+		Extra:    jsonutil.Encode(model.TalkRecordExtraText{Content: option.Content}),
+		Quote:    "{}",
+	}
+
+	if option.FromId > 0 {
+		if user, err := s.UsersRepo.FindByIdWithCache(ctx, option.FromId); err == nil {
+			body.Nickname = user.Nickname
+			body.Avatar = user.Avatar
+		}
+	} else {
+		body.MsgType = entity.ChatMsgSysText
+	}
+
+	return s.PushMessage.Push(ctx, entity.ImTopicChat, &entity.SubscribeMessage{
+		Event: entity.SubEventImMessageEphemeral,
+		Payload: jsonutil.Encode(entity.SubEventImMessageEphemeralPayload{
+			UserId: option.UserId,
+			Message: jsonutil.Encode(entity.ImMessagePayload{
+				TalkMode: option.TalkMode,
+				FromId:   option.FromId,
+				ToFromId: option.ToFromId,
+				Body:     body,
+			}),
+		}),
+	})
+}
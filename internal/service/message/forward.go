@@ -79,6 +79,8 @@ func (s *Service) toSplitForward(ctx context.Context, req ForwardMessageOpt) err
 		}
 
 		if err := db.Create(items).Error; err == nil {
+			s.referenceForwardObjects(ctx, messageItems)
+
 			err = s.PushMessage.MultiPush(ctx, entity.ImTopicChat,
 				lo.Map(items, func(item model.TalkGroupMessage, index int) *entity.SubscribeMessage {
 					return &entity.SubscribeMessage{
@@ -137,6 +139,8 @@ func (s *Service) toSplitForward(ctx context.Context, req ForwardMessageOpt) err
 		}
 
 		if err := db.Create(items).Error; err == nil {
+			s.referenceForwardObjects(ctx, messageItems)
+
 			list := lo.Map(items, func(item model.TalkUserMessage, _ int) *entity.SubscribeMessage {
 				return &entity.SubscribeMessage{
 					Event: entity.SubEventImMessage,
@@ -2,6 +2,7 @@ package message
 
 import (
 	"context"
+	"slices"
 	"time"
 
 	"go-chat/internal/entity"
@@ -63,20 +64,34 @@ func (s *Service) CreateGroupMessage(ctx context.Context, option CreateGroupMess
 		logger.Errorf("CreateGroupMessage publish message error:%s", err.Error())
 	}
 
+	mentions := parseMentions(item.MsgType, item.Extra)
+	mentioned := make([]int, 0)
+
 	pipe := s.Source.Redis().Pipeline()
 	for _, uid := range s.GroupMemberRepo.GetMemberIds(ctx, item.GroupId) {
-		if uid != item.FromId {
-			s.UnreadStorage.PipeIncr(ctx, pipe, uid, entity.ChatGroupMode, item.GroupId)
+		if uid == item.FromId {
+			continue
+		}
+
+		s.UnreadStorage.PipeIncr(ctx, pipe, uid, entity.ChatGroupMode, item.GroupId)
+		if len(mentions) > 0 && isMentioned(mentions, uid) {
+			s.UnreadStorage.PipeIncrMention(ctx, pipe, uid, entity.ChatGroupMode, item.GroupId)
+			mentioned = append(mentioned, uid)
 		}
 	}
 	_, _ = pipe.Exec(ctx)
 
+	s.createMentions(ctx, item, mentioned, slices.Contains(mentions, entity.MentionAll))
+
 	// 更新最后一条消息
 	_ = s.MessageStorage.Set(ctx, entity.ChatGroupMode, item.FromId, item.GroupId, &cache.LastCacheMessage{
 		Content:  s.getTextMessage(item.MsgType, option.Extra),
 		Datetime: item.CreatedAt.Format(time.DateTime),
 	})
 
+	s.dispatchRobotWebhook(ctx, entity.ChatGroupMode, item.FromId, item.GroupId, item.MsgId, item.MsgType, item.Extra)
+	s.dispatchPush(entity.ChatGroupMode, item.FromId, item.GroupId, item.MsgId, item.MsgType, item.Extra)
+
 	return nil
 }
 
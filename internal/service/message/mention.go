@@ -0,0 +1,89 @@
+package message
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/jsonutil"
+	"go-chat/internal/pkg/logger"
+	"go-chat/internal/pkg/sliceutil"
+	"go-chat/internal/repository/model"
+)
+
+// checkMentions 校验@的用户，群聊中@的用户必须是群成员，@所有人仅限群主及管理员，私聊消息不保留@
+func (s *Service) checkMentions(ctx context.Context, talkMode int, fromId int, groupId int, mentions []int) ([]int, error) {
+	if talkMode != entity.ChatGroupMode || len(mentions) == 0 {
+		return nil, nil
+	}
+
+	mentions = sliceutil.Unique(mentions)
+
+	uids := make([]int, 0, len(mentions))
+	for _, uid := range mentions {
+		if uid != entity.MentionAll {
+			uids = append(uids, uid)
+			continue
+		}
+
+		if !s.GroupMemberRepo.IsLeader(ctx, groupId, fromId) {
+			return nil, entity.ErrMentionAllNotAllowed
+		}
+	}
+
+	if len(s.GroupMemberRepo.FilterMemberIds(ctx, groupId, uids)) != len(uids) {
+		return nil, entity.ErrMentionNotMember
+	}
+
+	return mentions, nil
+}
+
+// createMentions 写入被@用户的@我的消息记录
+func (s *Service) createMentions(ctx context.Context, item *model.TalkGroupMessage, uids []int, isAll bool) {
+	if len(uids) == 0 {
+		return
+	}
+
+	isAllValue := model.No
+	if isAll {
+		isAllValue = model.Yes
+	}
+
+	items := make([]*model.TalkMention, 0, len(uids))
+	for _, uid := range uids {
+		items = append(items, &model.TalkMention{
+			UserId:    uid,
+			GroupId:   item.GroupId,
+			MsgId:     item.MsgId,
+			FromId:    item.FromId,
+			IsAll:     isAllValue,
+			IsRead:    model.No,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		})
+	}
+
+	if err := s.Db().WithContext(ctx).CreateInBatches(items, 500).Error; err != nil {
+		logger.Errorf("create talk mention err msg_id:%s err:%s", item.MsgId, err.Error())
+	}
+}
+
+// isMentioned 用户是否被@，@所有人时全部成员均视为被@
+func isMentioned(mentions []int, uid int) bool {
+	return slices.Contains(mentions, entity.MentionAll) || slices.Contains(mentions, uid)
+}
+
+// 获取文本消息中@的用户ID列表，可能包含 entity.MentionAll
+func parseMentions(msgType int, extra string) []int {
+	if msgType != entity.ChatMsgTypeText {
+		return nil
+	}
+
+	var text model.TalkRecordExtraText
+	if err := jsonutil.Decode(extra, &text); err != nil {
+		return nil
+	}
+
+	return text.Mentions
+}
@@ -0,0 +1,105 @@
+package message
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go-chat/internal/entity"
+	"go-chat/internal/repository/repo"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestMentionService(t *testing.T) (*Service, sqlmock.Sqlmock) {
+	t.Helper()
+
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = conn.Close() })
+
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: conn, SkipInitializeWithVersion: true}), &gorm.Config{
+		SkipDefaultTransaction: true,
+		Logger:                 logger.Discard,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &Service{GroupMemberRepo: repo.NewGroupMember(db, nil)}, mock
+}
+
+func expectMembers(mock sqlmock.Sqlmock, uids ...int) {
+	rows := sqlmock.NewRows([]string{"user_id"})
+	for _, uid := range uids {
+		rows.AddRow(uid)
+	}
+
+	mock.ExpectQuery("SELECT `user_id` FROM `group_member` WHERE group_id = \\? and user_id in").WillReturnRows(rows)
+}
+
+func expectLeader(mock sqlmock.Sqlmock, isLeader bool) {
+	rows := sqlmock.NewRows([]string{"1"})
+	if isLeader {
+		rows.AddRow(1)
+	}
+
+	mock.ExpectQuery("SELECT 1 FROM `group_member` WHERE group_id = \\? and user_id = \\? and leader in").WillReturnRows(rows)
+}
+
+func TestCheckMentions(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("private", func(t *testing.T) {
+		svc, mock := newTestMentionService(t)
+
+		mentions, err := svc.checkMentions(ctx, entity.ChatPrivateMode, 1, 2, []int{2})
+		assert.NoError(t, err)
+		assert.Empty(t, mentions)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("members", func(t *testing.T) {
+		svc, mock := newTestMentionService(t)
+		expectMembers(mock, 2, 3)
+
+		mentions, err := svc.checkMentions(ctx, entity.ChatGroupMode, 1, 10, []int{2, 3, 2})
+		assert.NoError(t, err)
+		assert.Equal(t, []int{2, 3}, mentions)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not member", func(t *testing.T) {
+		svc, mock := newTestMentionService(t)
+		expectMembers(mock, 2)
+
+		_, err := svc.checkMentions(ctx, entity.ChatGroupMode, 1, 10, []int{2, 4})
+		assert.ErrorIs(t, err, entity.ErrMentionNotMember)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("all by member", func(t *testing.T) {
+		svc, mock := newTestMentionService(t)
+		expectLeader(mock, false)
+
+		_, err := svc.checkMentions(ctx, entity.ChatGroupMode, 1, 10, []int{entity.MentionAll})
+		assert.ErrorIs(t, err, entity.ErrMentionAllNotAllowed)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("all by leader", func(t *testing.T) {
+		svc, mock := newTestMentionService(t)
+		expectLeader(mock, true)
+		expectMembers(mock, 2)
+
+		mentions, err := svc.checkMentions(ctx, entity.ChatGroupMode, 1, 10, []int{entity.MentionAll, 2})
+		assert.NoError(t, err)
+		assert.Equal(t, []int{entity.MentionAll, 2}, mentions)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -101,6 +101,9 @@ func (s *Service) CreatePrivateMessage(ctx context.Context, option CreatePrivate
 
 	_, _ = pipe.Exec(ctx)
 
+	s.dispatchRobotWebhook(ctx, entity.ChatPrivateMode, option.FromId, option.ToFromId, items[1].MsgId, option.MsgType, option.Extra)
+	s.dispatchPush(entity.ChatPrivateMode, option.FromId, option.ToFromId, items[1].MsgId, option.MsgType, option.Extra)
+
 	return nil
 }
 
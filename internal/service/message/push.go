@@ -0,0 +1,27 @@
+package message
+
+import (
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/jsonutil"
+	"go-chat/internal/pkg/logger"
+)
+
+// dispatchPush 将消息投递到离线推送队列，由消费者推送给所有客户端均不在线的接收者
+func (s *Service) dispatchPush(talkMode int, fromId int, toFromId int, msgId string, msgType int, extra string) {
+	if fromId <= 0 || !s.Config.Push.IsEnabled() {
+		return
+	}
+
+	body := jsonutil.Encode(entity.PushDispatchMessage{
+		TalkMode: talkMode,
+		FromId:   fromId,
+		ToFromId: toFromId,
+		MsgId:    msgId,
+		Content:  s.getTextMessage(msgType, extra),
+		Mentions: parseMentions(msgType, extra),
+	})
+
+	if err := s.Producer.Publish(entity.PushDispatchTopic, []byte(body)); err != nil {
+		logger.Errorf("push dispatch publish err msg_id:%s err:%s", msgId, err.Error())
+	}
+}
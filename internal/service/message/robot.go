@@ -0,0 +1,57 @@
+package message
+
+import (
+	"context"
+
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/jsonutil"
+	"go-chat/internal/pkg/logger"
+	"go-chat/internal/repository/model"
+)
+
+// dispatchRobotWebhook 将发给机器人的消息(私聊机器人或群聊中@机器人)投递到 Webhook 推送队列
+func (s *Service) dispatchRobotWebhook(ctx context.Context, talkMode int, fromId int, toFromId int, msgId string, msgType int, extra string) {
+	if fromId <= 0 {
+		return
+	}
+
+	userIds := []int{toFromId}
+	if talkMode == entity.ChatGroupMode {
+		if msgType != entity.ChatMsgTypeText {
+			return
+		}
+
+		var text model.TalkRecordExtraText
+		if err := jsonutil.Decode(extra, &text); err != nil || len(text.Mentions) == 0 {
+			return
+		}
+
+		userIds = text.Mentions
+	}
+
+	robots, err := s.RobotRepo.FindWebhookRobots(ctx, userIds)
+	if err != nil || len(robots) == 0 {
+		return
+	}
+
+	// 机器人发出的消息不再推送，避免机器人之间循环回复
+	if sender, err := s.UsersRepo.FindByIdWithCache(ctx, fromId); err != nil || sender.IsRobot == model.Yes {
+		return
+	}
+
+	for _, robot := range robots {
+		if talkMode == entity.ChatGroupMode && !s.GroupMemberRepo.IsMember(ctx, toFromId, robot.UserId, false) {
+			continue
+		}
+
+		body := jsonutil.Encode(entity.RobotWebhookMessage{
+			RobotId:  robot.Id,
+			TalkMode: talkMode,
+			MsgId:    msgId,
+		})
+
+		if err := s.Producer.Publish(entity.RobotWebhookTopic, []byte(body)); err != nil {
+			logger.Errorf("robot webhook publish err robot_id:%d msg_id:%s err:%s", robot.Id, msgId, err.Error())
+		}
+	}
+}
@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"time"
 
+	"go-chat/config"
 	"go-chat/internal/business"
 
 	"github.com/google/uuid"
+	"github.com/nsqio/go-nsq"
 	"go-chat/internal/entity"
 	"go-chat/internal/pkg/filesystem"
 	"go-chat/internal/pkg/jsonutil"
@@ -69,6 +71,8 @@ type IMessage interface {
 	CreateBusinessCardMessage(ctx context.Context, option CreateBusinessCardMessage) error
 	// CreateMixedMessage 图文消息
 	CreateMixedMessage(ctx context.Context, option CreateMixedMessage) error
+	// CreateEphemeralMessage 临时消息(仅推送给指定用户，不保存)
+	CreateEphemeralMessage(ctx context.Context, option CreateEphemeralMessage) error
 }
 
 type IService interface {
@@ -90,6 +94,10 @@ type Service struct {
 	ClientStorage       *cache.ClientStorage
 	Sequence            *repo.Sequence
 	RobotRepo           *repo.Robot
+	FileObjectRepo      *repo.FileObject
+	StorageUsageRepo    *repo.StorageUsage
+	Config              *config.Config
+	Producer            *nsq.Producer
 
 	PushMessage *business.PushMessage
 }
@@ -115,6 +123,11 @@ func (s *Service) CreateMessage(ctx context.Context, option CreateMessageOption)
 }
 
 func (s *Service) CreateTextMessage(ctx context.Context, option CreateTextMessage) error {
+	mentions, err := s.checkMentions(ctx, option.TalkMode, option.FromId, option.ToFromId, option.Mentions)
+	if err != nil {
+		return err
+	}
+
 	return s.CreateMessage(ctx, CreateMessageOption{
 		TalkMode: option.TalkMode,
 		FromId:   option.FromId,
@@ -123,7 +136,7 @@ func (s *Service) CreateTextMessage(ctx context.Context, option CreateTextMessag
 		QuoteId:  option.QuoteId,
 		Extra: jsonutil.Encode(model.TalkRecordExtraText{
 			Content:  option.Content,
-			Mentions: option.Mentions,
+			Mentions: mentions,
 		}),
 	})
 }
@@ -138,6 +151,8 @@ func (s *Service) CreateImageMessage(ctx context.Context, option CreateImageMess
 		Extra: jsonutil.Encode(model.TalkRecordExtraImage{
 			Size:   option.Size,
 			Url:    option.Url,
+			Thumb:  option.Thumb,
+			Medium: option.Medium,
 			Width:  option.Width,
 			Height: option.Height,
 		}),
@@ -175,7 +190,47 @@ func (s *Service) CreateVideoMessage(ctx context.Context, option CreateVideoMess
 	})
 }
 
-func (s *Service) CreateFileMessage(ctx context.Context, option CreateFileMessage) error {
+// referenceObject 引用上传文件对应的文件对象(引用计数+1)
+func (s *Service) referenceObject(ctx context.Context, file *model.FileUpload) (bool, error) {
+	if file.Hash == "" {
+		return false, nil
+	}
+
+	object, err := s.FileObjectRepo.FindByHash(ctx, file.Hash)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	if object.Path != file.Path {
+		return false, nil
+	}
+
+	return s.FileObjectRepo.IncrRef(ctx, object.Id)
+}
+
+// referenceForwardObjects 转发的文件消息与原消息共用文件对象，逐条增加引用计数
+func (s *Service) referenceForwardObjects(ctx context.Context, items []model.TalkRecord) {
+	for _, item := range items {
+		if item.MsgType != entity.ChatMsgTypeFile {
+			continue
+		}
+
+		var extra model.TalkRecordExtraFile
+		if err := jsonutil.Decode(item.Extra, &extra); err != nil {
+			continue
+		}
+
+		if _, err := s.FileObjectRepo.IncrRefByPath(ctx, extra.Path); err != nil {
+			logger.Errorf("forward message reference object failed :%s", err.Error())
+		}
+	}
+}
+
+func (s *Service) CreateFileMessage(ctx context.Context, option CreateFileMessage) (err error) {
 	now := time.Now()
 
 	file, err := s.SplitUploadRepo.GetFile(ctx, option.FromId, option.UploadId)
@@ -183,6 +238,30 @@ func (s *Service) CreateFileMessage(ctx context.Context, option CreateFileMessag
 		return err
 	}
 
+	// 未通过安全检测的文件不允许发送
+	if file.ScanStatus != model.FileScanStatusSafe {
+		return entity.ErrFileNotScanned
+	}
+
+	// 群聊中发送的文件计入群组文件配额
+	if option.TalkMode == entity.ChatGroupMode {
+		ok, err := s.StorageUsageRepo.Incr(ctx, model.StorageOwnerGroup, option.ToFromId, file.FileSize, s.Config.Upload.GetGroupQuota())
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			return entity.ErrGroupStorageQuotaExceeded
+		}
+
+		// 文件拷贝或消息发送失败时回滚群组文件配额
+		defer func() {
+			if err != nil {
+				_ = s.StorageUsageRepo.Decr(ctx, model.StorageOwnerGroup, option.ToFromId, file.FileSize)
+			}
+		}()
+	}
+
 	publicUrl := ""
 	filePath := fmt.Sprintf("talk-files/%s/%s.%s", now.Format("200601"), uuid.New().String(), file.FileExt)
 
@@ -198,6 +277,11 @@ func (s *Service) CreateFileMessage(ctx context.Context, option CreateFileMessag
 		}
 
 		publicUrl = s.Filesystem.PublicUrl(s.Filesystem.BucketPublicName(), filePath)
+	} else if ok, err := s.referenceObject(ctx, file); err != nil {
+		return err
+	} else if ok {
+		// 去重后的文件直接引用文件对象，不再复制
+		filePath = file.Path
 	} else {
 		if err := s.Filesystem.Copy(s.Filesystem.BucketPrivateName(), file.Path, filePath); err != nil {
 			return err
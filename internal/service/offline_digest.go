@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	"go-chat/config"
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/logger"
+	"go-chat/internal/repository/cache"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+)
+
+// 摘要中最多展示的会话数
+const offlineDigestMaxItems = 20
+
+var _ IOfflineDigestService = (*OfflineDigestService)(nil)
+
+type IOfflineDigestService interface {
+	// Send 向离线超过指定时长且有未读消息的用户发送邮件摘要，返回发送数量
+	Send(ctx context.Context) (int, error)
+}
+
+type OfflineDigestService struct {
+	Config             *config.Config
+	ClientStorage      *cache.ClientStorage
+	UnreadStorage      *cache.UnreadStorage
+	UsersRepo          *repo.Users
+	UsersSettingRepo   *repo.UsersSetting
+	TalkSessionService ITalkSessionService
+	EmailService       IEmailService
+}
+
+// OfflineDigestItem 摘要中的会话
+type OfflineDigestItem struct {
+	Name     string // 好友昵称或群名称
+	Unread   int    // 未读消息数
+	Mentions int    // @我的消息数
+}
+
+func (s *OfflineDigestService) Send(ctx context.Context) (int, error) {
+	// 未读数缓存 14 天后过期，更早离线的用户无需处理
+	now := time.Now()
+	min := now.Add(-14 * 24 * time.Hour).Unix()
+	max := now.Add(-time.Duration(s.Config.OfflineDigest.GetOfflineHours()) * time.Hour).Unix()
+
+	var (
+		num    int
+		offset int64
+		limit  int64 = 500
+	)
+
+	for {
+		uids, err := s.ClientStorage.FindLastSeen(ctx, min, max, offset, limit)
+		if err != nil {
+			return num, err
+		}
+
+		for _, uid := range uids {
+			ok, err := s.send(ctx, uid)
+			if err != nil {
+				logger.Errorf("离线消息摘要发送失败 uid:%d err:%s", uid, err.Error())
+				continue
+			}
+
+			if ok {
+				num++
+			}
+		}
+
+		if int64(len(uids)) < limit {
+			break
+		}
+
+		offset += limit
+	}
+
+	return num, nil
+}
+
+func (s *OfflineDigestService) send(ctx context.Context, uid int) (bool, error) {
+	if s.ClientStorage.IsOnline(ctx, entity.ImChannelChat, strconv.Itoa(uid)) {
+		return false, nil
+	}
+
+	setting, err := s.UsersSettingRepo.Find(ctx, uid)
+	if err != nil {
+		return false, err
+	}
+
+	if setting.DigestEnabled != model.Yes {
+		return false, nil
+	}
+
+	if setting.DigestAt != nil && time.Since(*setting.DigestAt) < setting.DigestInterval() {
+		return false, nil
+	}
+
+	user, err := s.UsersRepo.FindByIdWithCache(ctx, uid)
+	if err != nil {
+		return false, err
+	}
+
+	if user.Email == "" || user.IsRobot == model.Yes || user.Status != model.UsersStatusNormal {
+		return false, nil
+	}
+
+	items, total, err := s.items(ctx, uid)
+	if err != nil {
+		return false, err
+	}
+
+	if len(items) == 0 {
+		return false, nil
+	}
+
+	err = s.EmailService.Send(ctx, &EmailSendOpt{
+		UserId:   uid,
+		Email:    user.Email,
+		Template: EmailTemplateOfflineDigest,
+		Data: map[string]any{
+			"nickname": user.Nickname,
+			"total":    total,
+			"items":    items,
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if err := s.UsersSettingRepo.Save(ctx, uid, map[string]any{"digest_at": time.Now()}); err != nil {
+		return true, err
+	}
+
+	return true, nil
+}
+
+// 获取有未读消息的会话，免打扰的会话仅在有@我的消息时展示
+func (s *OfflineDigestService) items(ctx context.Context, uid int) ([]*OfflineDigestItem, int, error) {
+	sessions, err := s.TalkSessionService.List(ctx, uid)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total := 0
+	items := make([]*OfflineDigestItem, 0)
+	for _, session := range sessions {
+		if session.IsRobot == model.Yes {
+			continue
+		}
+
+		unread := s.UnreadStorage.Get(ctx, uid, session.TalkMode, session.ToFromId)
+		if unread == 0 {
+			continue
+		}
+
+		item := &OfflineDigestItem{Name: session.Nickname, Unread: unread}
+		if session.TalkMode == entity.ChatGroupMode {
+			item.Name = session.GroupName
+			item.Mentions = s.UnreadStorage.GetMention(ctx, uid, session.TalkMode, session.ToFromId)
+		}
+
+		if session.IsDisturb == model.Yes && item.Mentions == 0 {
+			continue
+		}
+
+		total += unread
+		items = append(items, item)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Mentions != items[j].Mentions {
+			return items[i].Mentions > items[j].Mentions
+		}
+
+		return items[i].Unread > items[j].Unread
+	})
+
+	if len(items) > offlineDigestMaxItems {
+		items = items[:offlineDigestMaxItems]
+	}
+
+	return items, total, nil
+}
@@ -0,0 +1,338 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/redis/go-redis/v9"
+	"go-chat/config"
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/encrypt"
+	"go-chat/internal/pkg/logger"
+	"go-chat/internal/pkg/oidc"
+	"go-chat/internal/pkg/strutil"
+	"go-chat/internal/pkg/utils"
+	"go-chat/internal/repository/cache"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+	"gorm.io/gorm"
+)
+
+// OidcStateExpire 授权请求的有效期
+const OidcStateExpire = 10 * time.Minute
+
+var _ IOidcService = (*OidcService)(nil)
+
+type IOidcService interface {
+	// Providers 已配置的第三方登录方式
+	Providers() []*OidcProviderItem
+	// Authorize 创建授权请求，返回身份提供方的授权地址及客户端需保存的绑定值
+	Authorize(ctx context.Context, provider string, platform string) (*OidcAuthorizeResult, error)
+	// Callback 校验授权回调，返回关联或自动创建的用户
+	Callback(ctx context.Context, opt *OidcCallbackOpt) (*OidcLoginResult, error)
+}
+
+type OidcService struct {
+	Config              *config.Config
+	Source              *repo.Source
+	OidcProviders       oidc.Providers
+	OidcStateStorage    *cache.OidcStateStorage
+	UsersRepo           *repo.Users
+	UserIdentityRepo    *repo.UserIdentity
+	OrganizeSyncService IOrganizeSyncService
+}
+
+type OidcProviderItem struct {
+	Name  string `json:"name"`
+	Title string `json:"title"`
+}
+
+type OidcAuthorizeResult struct {
+	Url     string // 身份提供方授权地址
+	Binding string // 客户端绑定值，回调时需原样提交
+}
+
+type OidcCallbackOpt struct {
+	Code    string // 授权码
+	State   string // 授权请求状态
+	Binding string // 发起授权时返回的客户端绑定值
+}
+
+type OidcLoginResult struct {
+	UserId     int    // 用户ID
+	Platform   string // 登录平台
+	IsRegister bool   // 是否为本次登录自动创建的账号
+}
+
+func (s *OidcService) Providers() []*OidcProviderItem {
+	items := make([]*OidcProviderItem, 0)
+	if s.Config.Oidc == nil {
+		return items
+	}
+
+	for _, provider := range s.Config.Oidc.Providers {
+		items = append(items, &OidcProviderItem{Name: provider.Name, Title: provider.Title})
+	}
+
+	return items
+}
+
+func (s *OidcService) Authorize(ctx context.Context, provider string, platform string) (*OidcAuthorizeResult, error) {
+	client := s.OidcProviders.Get(provider)
+	if client == nil {
+		return nil, entity.ErrOidcProviderNotExist
+	}
+
+	// state 仅保存在服务端时，攻击者可将自己的授权回调诱导他人提交(登录 CSRF)，
+	// 因此由发起授权的客户端保存绑定值，回调时一并校验
+	binding := oidc.RandomString(24)
+
+	state := oidc.RandomString(24)
+	value := &cache.OidcState{
+		Provider: provider,
+		Verifier: oidc.NewVerifier(),
+		Nonce:    oidc.RandomString(16),
+		Platform: platform,
+		Binding:  oidc.Challenge(binding),
+	}
+
+	uri, err := client.AuthCodeURL(ctx, state, value.Nonce, value.Verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.OidcStateStorage.Set(ctx, state, value, OidcStateExpire); err != nil {
+		return nil, err
+	}
+
+	return &OidcAuthorizeResult{Url: uri, Binding: binding}, nil
+}
+
+func (s *OidcService) Callback(ctx context.Context, opt *OidcCallbackOpt) (*OidcLoginResult, error) {
+	value, err := s.OidcStateStorage.Take(ctx, opt.State)
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, entity.ErrOidcStateInvalid
+		}
+
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(oidc.Challenge(opt.Binding)), []byte(value.Binding)) != 1 {
+		return nil, entity.ErrOidcStateInvalid
+	}
+
+	conf := s.Config.Oidc.GetProvider(value.Provider)
+	client := s.OidcProviders.Get(value.Provider)
+	if conf == nil || client == nil {
+		return nil, entity.ErrOidcProviderNotExist
+	}
+
+	claims, err := s.claims(ctx, client, opt.Code, value)
+	if err != nil {
+		logger.Errorf("第三方登录授权失败 provider:%s err:%s", value.Provider, err.Error())
+		return nil, entity.ErrOidcLoginFailed
+	}
+
+	user, isRegister, err := s.resolve(ctx, conf, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.IsRobot == model.Yes {
+		return nil, entity.ErrOidcAccountConflict
+	}
+
+	if user.Status == model.UsersStatusDisabled {
+		return nil, entity.ErrAccountDisabled
+	}
+
+	_, _ = s.UserIdentityRepo.UpdateByWhere(ctx, map[string]any{
+		"email":         claims.Email,
+		"last_login_at": time.Now(),
+	}, "provider = ? and subject = ?", conf.Name, claims.Subject)
+
+	// 部门及岗位同步失败不影响登录
+	if conf.SyncOrganize {
+		if err := s.OrganizeSyncService.SyncMember(ctx, user.Id, claims.String(conf.DeptClaim), claims.String(conf.PositionClaim)); err != nil {
+			logger.Errorf("第三方登录同步组织架构失败 provider:%s uid:%d err:%s", conf.Name, user.Id, err.Error())
+		}
+	}
+
+	return &OidcLoginResult{UserId: user.Id, Platform: value.Platform, IsRegister: isRegister}, nil
+}
+
+// 使用授权码换取令牌并校验 id_token，再合并用户信息接口返回的声明
+func (s *OidcService) claims(ctx context.Context, client *oidc.Provider, code string, value *cache.OidcState) (*oidc.Claims, error) {
+	token, err := client.Exchange(ctx, code, value.Verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := client.VerifyIdToken(ctx, token.IdToken, value.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	if token.AccessToken == "" {
+		return claims, nil
+	}
+
+	return client.UserInfo(ctx, token.AccessToken, claims)
+}
+
+// 查找第三方账号对应的用户，依次按已绑定账号、已验证邮箱关联、自动创建账号处理
+func (s *OidcService) resolve(ctx context.Context, conf *config.OidcProvider, claims *oidc.Claims) (*model.Users, bool, error) {
+	identity, err := s.UserIdentityRepo.FindBySubject(ctx, conf.Name, claims.Subject)
+	if err == nil {
+		user, err := s.UsersRepo.FindById(ctx, identity.UserId)
+		if err != nil {
+			if utils.IsSqlNoRows(err) {
+				return nil, false, entity.ErrUserNotExist
+			}
+
+			return nil, false, err
+		}
+
+		return user, false, nil
+	}
+
+	if !utils.IsSqlNoRows(err) {
+		return nil, false, err
+	}
+
+	// 仅信任身份提供方已验证的邮箱，避免通过伪造邮箱接管他人账号
+	if conf.LinkByEmail && claims.Email != "" && claims.EmailVerified {
+		user, err := s.link(ctx, conf, claims)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if user != nil {
+			return user, false, nil
+		}
+	}
+
+	if !conf.AutoRegister {
+		return nil, false, entity.ErrOidcAccountNotBound
+	}
+
+	user, err := s.register(ctx, conf, claims)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return user, true, nil
+}
+
+// 按邮箱关联已有账号，未找到账号时返回 nil
+func (s *OidcService) link(ctx context.Context, conf *config.OidcProvider, claims *oidc.Claims) (*model.Users, error) {
+	users, err := s.UsersRepo.FindAll(ctx, func(db *gorm.DB) {
+		db.Where("email = ? and is_robot = ?", claims.Email, model.No).Limit(2)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(users) == 0 {
+		return nil, nil
+	}
+
+	// 同一邮箱对应多个账号，或该账号已绑定同一身份提供方的其它账号时无法自动关联
+	if len(users) > 1 {
+		return nil, entity.ErrOidcAccountConflict
+	}
+
+	user := users[0]
+
+	isBound, err := s.UserIdentityRepo.IsBound(ctx, conf.Name, user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if isBound {
+		return nil, entity.ErrOidcAccountConflict
+	}
+
+	if err := s.UserIdentityRepo.Create(ctx, s.identity(conf, user.Id, claims)); err != nil {
+		return nil, err
+	}
+
+	logger.Infof("第三方账号关联已有账号 provider:%s sub:%s uid:%d", conf.Name, claims.Subject, user.Id)
+
+	return user, nil
+}
+
+// 自动创建账号并绑定第三方账号
+func (s *OidcService) register(ctx context.Context, conf *config.OidcProvider, claims *oidc.Claims) (*model.Users, error) {
+	user := &model.Users{
+		// 第三方登录创建的账号未绑定手机号，手机号仅用于满足唯一索引，用户可自行修改
+		Mobile:    fmt.Sprintf("o%s", strutil.Random(10)),
+		Nickname:  s.nickname(claims),
+		Gender:    model.UsersGenderDefault,
+		Password:  encrypt.HashPassword(oidc.RandomString(24)),
+		IsRobot:   model.No,
+		Status:    model.UsersStatusNormal,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if len(claims.Picture) <= 255 {
+		user.Avatar = claims.Picture
+	}
+
+	// users.email 字段长度有限，超长的邮箱仅保存在绑定记录中
+	if claims.EmailVerified && len(claims.Email) <= 30 {
+		user.Email = claims.Email
+	}
+
+	err := s.Source.Db().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(s.identity(conf, user.Id, claims)).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("第三方登录自动创建账号 provider:%s sub:%s uid:%d", conf.Name, claims.Subject, user.Id)
+
+	return user, nil
+}
+
+func (s *OidcService) identity(conf *config.OidcProvider, uid int, claims *oidc.Claims) *model.UserIdentity {
+	return &model.UserIdentity{
+		UserId:      uid,
+		Provider:    conf.Name,
+		Subject:     claims.Subject,
+		Email:       claims.Email,
+		LastLoginAt: time.Now(),
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+}
+
+func (s *OidcService) nickname(claims *oidc.Claims) string {
+	name := strings.TrimSpace(claims.Name)
+	if name == "" {
+		name, _, _ = strings.Cut(claims.Email, "@")
+	}
+
+	if name == "" {
+		name = "用户" + strutil.Random(6)
+	}
+
+	if utf8.RuneCountInString(name) > 20 {
+		name = string([]rune(name)[:20])
+	}
+
+	return name
+}
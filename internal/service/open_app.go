@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/core/middleware"
+	"go-chat/internal/pkg/jsonutil"
+	"go-chat/internal/pkg/openapi"
+	"go-chat/internal/pkg/utils"
+	"go-chat/internal/repository/cache"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+)
+
+var _ IOpenAppService = (*OpenAppService)(nil)
+
+type IOpenAppService interface {
+	middleware.IOpenAppStorage
+	// Create 创建开放平台应用
+	Create(ctx context.Context, opt *OpenAppOpt) (*model.OpenApp, error)
+	// Update 更新开放平台应用
+	Update(ctx context.Context, appId int, opt *OpenAppOpt) error
+	// Delete 删除开放平台应用
+	Delete(ctx context.Context, appId int) error
+	// ResetSecret 重置应用密钥
+	ResetSecret(ctx context.Context, appId int) (string, error)
+}
+
+type OpenAppService struct {
+	OpenAppRepo      *repo.OpenApp
+	RobotRepo        *repo.Robot
+	OpenNonceStorage *cache.OpenNonceStorage
+}
+
+type OpenAppOpt struct {
+	AppName string
+	RobotId int
+	Scopes  []string
+	Status  int
+	Remark  string
+}
+
+func (s *OpenAppService) GetOpenApp(ctx context.Context, appKey string) (*middleware.OpenApp, error) {
+	app, err := s.OpenAppRepo.FindByAppKey(ctx, appKey)
+	if err != nil {
+		if utils.IsSqlNoRows(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	if app.Status != model.OpenAppStatusNormal {
+		return nil, nil
+	}
+
+	robot, err := s.RobotRepo.FindById(ctx, app.RobotId)
+	if err != nil {
+		if utils.IsSqlNoRows(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	if robot.Status != model.RootStatusNormal {
+		return nil, nil
+	}
+
+	return &middleware.OpenApp{
+		OpenSession: middleware.OpenSession{
+			AppId:  app.Id,
+			AppKey: app.AppKey,
+			UserId: robot.UserId,
+			Scopes: DecodeOpenScopes(app.Scopes),
+		},
+		AppSecret: app.AppSecret,
+	}, nil
+}
+
+func (s *OpenAppService) UseNonce(ctx context.Context, appKey string, nonce string, expire time.Duration) bool {
+	return s.OpenNonceStorage.Use(ctx, appKey, nonce, expire)
+}
+
+func (s *OpenAppService) Create(ctx context.Context, opt *OpenAppOpt) (*model.OpenApp, error) {
+	if err := s.check(ctx, opt); err != nil {
+		return nil, err
+	}
+
+	app := &model.OpenApp{
+		AppName:   opt.AppName,
+		AppKey:    openapi.NewAppKey(),
+		AppSecret: openapi.NewAppSecret(),
+		RobotId:   opt.RobotId,
+		Scopes:    jsonutil.Encode(opt.Scopes),
+		Status:    opt.Status,
+		Remark:    opt.Remark,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if err := s.OpenAppRepo.Create(ctx, app); err != nil {
+		return nil, err
+	}
+
+	return app, nil
+}
+
+func (s *OpenAppService) Update(ctx context.Context, appId int, opt *OpenAppOpt) error {
+	if _, err := s.find(ctx, appId); err != nil {
+		return err
+	}
+
+	if err := s.check(ctx, opt); err != nil {
+		return err
+	}
+
+	_, err := s.OpenAppRepo.UpdateById(ctx, appId, map[string]any{
+		"app_name":   opt.AppName,
+		"robot_id":   opt.RobotId,
+		"scopes":     jsonutil.Encode(opt.Scopes),
+		"status":     opt.Status,
+		"remark":     opt.Remark,
+		"updated_at": time.Now(),
+	})
+
+	return err
+}
+
+func (s *OpenAppService) Delete(ctx context.Context, appId int) error {
+	if _, err := s.find(ctx, appId); err != nil {
+		return err
+	}
+
+	return s.OpenAppRepo.Delete(ctx, appId)
+}
+
+func (s *OpenAppService) ResetSecret(ctx context.Context, appId int) (string, error) {
+	if _, err := s.find(ctx, appId); err != nil {
+		return "", err
+	}
+
+	secret := openapi.NewAppSecret()
+	if _, err := s.OpenAppRepo.UpdateById(ctx, appId, map[string]any{"app_secret": secret, "updated_at": time.Now()}); err != nil {
+		return "", err
+	}
+
+	return secret, nil
+}
+
+func (s *OpenAppService) find(ctx context.Context, appId int) (*model.OpenApp, error) {
+	app, err := s.OpenAppRepo.FindById(ctx, appId)
+	if err != nil {
+		if utils.IsSqlNoRows(err) {
+			return nil, entity.ErrDataNotFound
+		}
+
+		return nil, err
+	}
+
+	return app, nil
+}
+
+// check 校验绑定的机器人及权限范围
+func (s *OpenAppService) check(ctx context.Context, opt *OpenAppOpt) error {
+	robot, err := s.RobotRepo.FindById(ctx, opt.RobotId)
+	if err != nil {
+		if utils.IsSqlNoRows(err) {
+			return errors.New("机器人不存在")
+		}
+
+		return err
+	}
+
+	if robot.Type == model.RobotTypeLogin {
+		return errors.New("内置机器人不允许绑定")
+	}
+
+	for _, scope := range opt.Scopes {
+		if !slices.ContainsFunc(model.OpenScopes, func(item model.OpenScope) bool {
+			return item.Code == scope
+		}) {
+			return errors.New("权限范围不存在: " + scope)
+		}
+	}
+
+	return nil
+}
+
+// DecodeOpenScopes 解析应用权限范围
+func DecodeOpenScopes(value string) []string {
+	scopes := make([]string, 0)
+	_ = json.Unmarshal([]byte(value), &scopes)
+	return scopes
+}
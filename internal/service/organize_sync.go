@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-chat/internal/pkg/strutil"
+	"go-chat/internal/pkg/utils"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+)
+
+var _ IOrganizeSyncService = (*OrganizeSyncService)(nil)
+
+// IOrganizeSyncService 从第三方登录或目录服务同步用户的组织架构信息
+type IOrganizeSyncService interface {
+	// SyncMember 同步用户的部门及岗位，多级部门以 / 分隔，不存在的部门及岗位自动创建
+	SyncMember(ctx context.Context, uid int, dept string, position string) error
+	// RemoveMember 将用户移出组织架构
+	RemoveMember(ctx context.Context, uid int) error
+}
+
+type OrganizeSyncService struct {
+	OrganizeRepo   *repo.Organize
+	DepartmentRepo *repo.Department
+	PositionRepo   *repo.Position
+}
+
+func (s *OrganizeSyncService) SyncMember(ctx context.Context, uid int, dept string, position string) error {
+	var deptId, positionId int
+
+	if dept != "" {
+		item, err := s.department(ctx, dept)
+		if err != nil {
+			return err
+		}
+
+		deptId = item.DeptId
+	}
+
+	if position != "" {
+		item, err := s.position(ctx, position)
+		if err != nil {
+			return err
+		}
+
+		positionId = item.PositionId
+	}
+
+	if deptId == 0 && positionId == 0 {
+		return nil
+	}
+
+	organize, err := s.OrganizeRepo.FindByWhere(ctx, "user_id = ?", uid)
+	if err != nil {
+		if !utils.IsSqlNoRows(err) {
+			return err
+		}
+
+		return s.OrganizeRepo.Create(ctx, &model.Organize{
+			UserId:     uid,
+			DeptId:     deptId,
+			PositionId: positionId,
+			CreatedAt:  time.Now(),
+			UpdatedAt:  time.Now(),
+		})
+	}
+
+	data := make(map[string]any)
+	if deptId > 0 && organize.DeptId != deptId {
+		data["dept_id"] = deptId
+	}
+
+	if positionId > 0 && organize.PositionId != positionId {
+		data["position_id"] = positionId
+	}
+
+	if len(data) == 0 {
+		return nil
+	}
+
+	data["updated_at"] = time.Now()
+	_, err = s.OrganizeRepo.UpdateByWhere(ctx, data, "user_id = ?", uid)
+	return err
+}
+
+func (s *OrganizeSyncService) RemoveMember(ctx context.Context, uid int) error {
+	return s.OrganizeRepo.Db.WithContext(ctx).Where("user_id = ?", uid).Delete(&model.Organize{}).Error
+}
+
+// 按部门路径逐级查找部门，如 研发中心/后端组
+func (s *OrganizeSyncService) department(ctx context.Context, path string) (*model.OrganizeDept, error) {
+	var parent *model.OrganizeDept
+
+	for _, name := range strings.Split(path, "/") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		parentId, ancestors := 0, "0"
+		if parent != nil {
+			parentId, ancestors = parent.DeptId, fmt.Sprintf("%s,%d", parent.Ancestors, parent.DeptId)
+		}
+
+		// is_deleted 与部门列表的查询条件保持一致
+		dept, err := s.DepartmentRepo.FindByWhere(ctx, "parent_id = ? and dept_name = ? and is_deleted = 1", parentId, name)
+		if err != nil {
+			if !utils.IsSqlNoRows(err) {
+				return nil, err
+			}
+
+			dept = &model.OrganizeDept{
+				ParentId:  parentId,
+				Ancestors: ancestors,
+				DeptName:  name,
+				OrderNum:  1,
+				Status:    1,
+				IsDeleted: 1,
+				CreatedAt: time.Now(),
+				UpdatedAt: time.Now(),
+			}
+
+			if err := s.DepartmentRepo.Create(ctx, dept); err != nil {
+				return nil, err
+			}
+		}
+
+		parent = dept
+	}
+
+	if parent == nil {
+		return nil, fmt.Errorf("invalid department %q", path)
+	}
+
+	return parent, nil
+}
+
+func (s *OrganizeSyncService) position(ctx context.Context, name string) (*model.OrganizePost, error) {
+	position, err := s.PositionRepo.FindByWhere(ctx, "post_name = ?", name)
+	if err == nil {
+		return position, nil
+	}
+
+	if !utils.IsSqlNoRows(err) {
+		return nil, err
+	}
+
+	position = &model.OrganizePost{
+		PostCode:  fmt.Sprintf("sync_%s", strutil.Random(8)),
+		PostName:  name,
+		Sort:      1,
+		Status:    1,
+		Remark:    "账号同步自动创建",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if err := s.PositionRepo.Create(ctx, position); err != nil {
+		return nil, err
+	}
+
+	return position, nil
+}
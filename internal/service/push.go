@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"html"
+	"slices"
+	"strconv"
+
+	"github.com/nsqio/go-nsq"
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/jsonutil"
+	"go-chat/internal/pkg/logger"
+	"go-chat/internal/pkg/push"
+	"go-chat/internal/pkg/strutil"
+	"go-chat/internal/pkg/utils"
+	"go-chat/internal/repository/cache"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+	"gorm.io/gorm"
+)
+
+var _ IPushService = (*PushService)(nil)
+
+type IPushService interface {
+	// RegisterDevice 注册推送设备
+	RegisterDevice(ctx context.Context, uid int, opt *PushDeviceOpt) error
+	// UnregisterDevice 注销推送设备
+	UnregisterDevice(ctx context.Context, uid int, provider string, token string) error
+	// Dispatch 筛选消息接收者中所有客户端均不在线的用户，按设备投递推送
+	Dispatch(ctx context.Context, in *entity.PushDispatchMessage) error
+	// Deliver 发送推送通知，返回错误时由队列重试
+	Deliver(ctx context.Context, in *entity.PushDeliverMessage) error
+}
+
+type PushService struct {
+	Producer        *nsq.Producer
+	PushClient      *push.Client
+	ClientStorage   *cache.ClientStorage
+	UnreadStorage   *cache.UnreadStorage
+	UserDeviceRepo  *repo.UserDevice
+	UsersRepo       *repo.Users
+	GroupRepo       *repo.Group
+	GroupMemberRepo *repo.GroupMember
+	TalkSessionRepo *repo.TalkSession
+}
+
+type PushDeviceOpt struct {
+	SessionId string // 登录会话ID，会话注销时删除设备
+	Provider  string // 推送服务[apns;fcm;]
+	Platform  string // 设备平台[ios;android;]
+	Token     string // 设备推送令牌
+}
+
+func (s *PushService) RegisterDevice(ctx context.Context, uid int, opt *PushDeviceOpt) error {
+	if !s.PushClient.Has(opt.Provider) {
+		return entity.ErrPushProviderNotSupported
+	}
+
+	return s.UserDeviceRepo.Register(ctx, uid, opt.SessionId, opt.Provider, opt.Platform, opt.Token)
+}
+
+func (s *PushService) UnregisterDevice(ctx context.Context, uid int, provider string, token string) error {
+	device, err := s.UserDeviceRepo.FindByWhere(ctx, "provider = ? and token = ?", provider, token)
+	if err != nil {
+		if utils.IsSqlNoRows(err) {
+			return entity.ErrPushDeviceNotExist
+		}
+
+		return err
+	}
+
+	if device.UserId != uid {
+		return entity.ErrPushDeviceNotExist
+	}
+
+	return s.UserDeviceRepo.Delete(ctx, device.Id)
+}
+
+func (s *PushService) Dispatch(ctx context.Context, in *entity.PushDispatchMessage) error {
+	// 私聊消息的接收者看到的会话为发送者，群聊消息为群组
+	uids, receiverId := []int{in.ToFromId}, in.FromId
+	if in.TalkMode == entity.ChatGroupMode {
+		uids, receiverId = s.GroupMemberRepo.GetMemberIds(ctx, in.ToFromId), in.ToFromId
+	}
+
+	devices, err := s.UserDeviceRepo.FindByUserIds(ctx, uids)
+	if err != nil {
+		return err
+	}
+
+	userDevices := make(map[int][]*model.UserDevice)
+	for _, device := range devices {
+		if device.UserId != in.FromId {
+			userDevices[device.UserId] = append(userDevices[device.UserId], device)
+		}
+	}
+
+	if len(userDevices) == 0 {
+		return nil
+	}
+
+	title, body, err := s.content(ctx, in)
+	if err != nil {
+		if utils.IsSqlNoRows(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	data := map[string]string{
+		"talk_mode":  strconv.Itoa(in.TalkMode),
+		"to_from_id": strconv.Itoa(receiverId),
+		"msg_id":     in.MsgId,
+	}
+
+	offline := make([]int, 0, len(userDevices))
+	for uid := range userDevices {
+		if !s.ClientStorage.IsOnline(ctx, entity.ImChannelChat, strconv.Itoa(uid)) {
+			offline = append(offline, uid)
+		}
+	}
+
+	badges, disturbs, err := s.badges(ctx, offline, in.TalkMode, receiverId)
+	if err != nil {
+		return err
+	}
+
+	for _, uid := range offline {
+		items, badge, isDisturb := userDevices[uid], badges[uid], disturbs[uid]
+
+		// 被@时忽略会话免打扰
+		content := body
+		if slices.Contains(in.Mentions, entity.MentionAll) || slices.Contains(in.Mentions, uid) {
+			content = "[有人@我] " + body
+		} else if isDisturb {
+			continue
+		}
+
+		for _, device := range items {
+			s.publish(&entity.PushDeliverMessage{
+				DeviceId: device.Id,
+				Title:    title,
+				Body:     content,
+				Badge:    badge,
+				Data:     data,
+			})
+		}
+	}
+
+	return nil
+}
+
+func (s *PushService) Deliver(ctx context.Context, in *entity.PushDeliverMessage) error {
+	device, err := s.UserDeviceRepo.FindById(ctx, in.DeviceId)
+	if err != nil {
+		if utils.IsSqlNoRows(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	err = s.PushClient.Send(ctx, device.Provider, &push.Notification{
+		Token: device.Token,
+		Title: in.Title,
+		Body:  in.Body,
+		Badge: in.Badge,
+		Data:  in.Data,
+	})
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, push.ErrInvalidToken) {
+		logger.Infof("推送设备令牌已失效 device_id:%d uid:%d provider:%s", device.Id, device.UserId, device.Provider)
+		return s.UserDeviceRepo.DeleteByToken(ctx, device.Provider, device.Token)
+	}
+
+	logger.Errorf("离线推送发送失败 device_id:%d provider:%s err:%s", device.Id, device.Provider, err.Error())
+
+	// 仅限流、服务端异常及网络异常时重试
+	if push.IsTemporary(err) {
+		return err
+	}
+
+	return nil
+}
+
+// 推送标题及内容，私聊为发送者昵称及消息摘要，群聊为群名称及带发送者昵称的消息摘要
+func (s *PushService) content(ctx context.Context, in *entity.PushDispatchMessage) (string, string, error) {
+	sender, err := s.UsersRepo.FindByIdWithCache(ctx, in.FromId)
+	if err != nil {
+		return "", "", err
+	}
+
+	content := strutil.MtSubstr(html.UnescapeString(in.Content), 0, 100)
+	if in.TalkMode != entity.ChatGroupMode {
+		return sender.Nickname, content, nil
+	}
+
+	group, err := s.GroupRepo.FindById(ctx, in.ToFromId)
+	if err != nil {
+		return "", "", err
+	}
+
+	return group.Name, sender.Nickname + ": " + content, nil
+}
+
+// 统计用户所有未开启免打扰的会话的未读消息数及免打扰会话中@我的消息数作为角标数，并返回当前会话是否开启了免打扰
+// 批量统计离线用户的角标未读数及当前会话是否免打扰，会话及未读数各查询一次
+func (s *PushService) badges(ctx context.Context, uids []int, talkMode int, receiverId int) (map[int]int, map[int]bool, error) {
+	badges, disturbs := make(map[int]int), make(map[int]bool)
+	if len(uids) == 0 {
+		return badges, disturbs, nil
+	}
+
+	sessions, err := s.TalkSessionRepo.FindAll(ctx, func(db *gorm.DB) {
+		db.Select("user_id", "talk_mode", "to_from_id", "is_disturb").Where("user_id in ? and is_delete = ?", uids, model.No)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keys := make([]cache.UnreadKey, 0, len(sessions))
+	for _, session := range sessions {
+		if session.TalkMode == talkMode && session.ToFromId == receiverId && session.IsDisturb == model.Yes {
+			disturbs[session.UserId] = true
+		}
+
+		// 免打扰会话仅统计群聊@消息
+		if session.IsDisturb == model.Yes && session.TalkMode != entity.ChatGroupMode {
+			continue
+		}
+
+		keys = append(keys, cache.UnreadKey{
+			Uid:     session.UserId,
+			Mode:    session.TalkMode,
+			Sender:  session.ToFromId,
+			Mention: session.IsDisturb == model.Yes,
+		})
+	}
+
+	for i, num := range s.UnreadStorage.MGet(ctx, keys) {
+		badges[keys[i].Uid] += num
+	}
+
+	return badges, disturbs, nil
+}
+
+func (s *PushService) publish(in *entity.PushDeliverMessage) {
+	if err := s.Producer.Publish(entity.PushDeliverTopic, []byte(jsonutil.Encode(in))); err != nil {
+		logger.Errorf("离线推送投递队列失败 device_id:%d err:%s", in.DeviceId, err.Error())
+	}
+}
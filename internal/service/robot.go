@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-chat/internal/pkg/encrypt"
+	"go-chat/internal/pkg/jsonutil"
+	"go-chat/internal/pkg/strutil"
+	"go-chat/internal/pkg/webhook"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+	"gorm.io/gorm"
+)
+
+var _ IRobotService = (*RobotService)(nil)
+
+type IRobotService interface {
+	// Create 创建 Webhook 机器人(同时创建机器人用户账号)
+	Create(ctx context.Context, opt *RobotOpt) (*model.Robot, error)
+	// Update 更新机器人信息
+	Update(ctx context.Context, robotId int, opt *RobotOpt) error
+	// Delete 删除机器人并停用机器人用户账号
+	Delete(ctx context.Context, robotId int) error
+	// ResetSecret 重置推送签名密钥
+	ResetSecret(ctx context.Context, robotId int) (string, error)
+}
+
+type RobotService struct {
+	*repo.Source
+	RobotRepo *repo.Robot
+	UsersRepo *repo.Users
+}
+
+type RobotOpt struct {
+	RobotName  string
+	Describe   string
+	Logo       string
+	WebhookUrl string
+	Commands   []model.RobotCommand // 支持的斜杠命令
+	Status     int                  // 状态[0:正常;1:已禁用;]
+}
+
+func (s *RobotService) Create(ctx context.Context, opt *RobotOpt) (*model.Robot, error) {
+	if err := checkRobotCommands(opt.Commands); err != nil {
+		return nil, err
+	}
+
+	robot := &model.Robot{
+		RobotName:     opt.RobotName,
+		Describe:      opt.Describe,
+		Logo:          opt.Logo,
+		IsTalk:        model.Yes,
+		Status:        opt.Status,
+		Type:          model.RobotTypeWebhook,
+		WebhookUrl:    opt.WebhookUrl,
+		WebhookSecret: webhook.NewSecret(),
+		Commands:      jsonutil.Encode(opt.Commands),
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+
+	err := s.Source.Db().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 机器人账号不允许登录，手机号仅用于满足唯一索引
+		user := &model.Users{
+			Mobile:    fmt.Sprintf("r%s", strutil.Random(10)),
+			Nickname:  opt.RobotName,
+			Avatar:    opt.Logo,
+			Gender:    model.UsersGenderDefault,
+			Password:  encrypt.HashPassword(webhook.NewSecret()),
+			Motto:     opt.Describe,
+			IsRobot:   model.Yes,
+			Status:    model.UsersStatusNormal,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+
+		robot.UserId = user.Id
+		return tx.Create(robot).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return robot, nil
+}
+
+func (s *RobotService) Update(ctx context.Context, robotId int, opt *RobotOpt) error {
+	robot, err := s.find(ctx, robotId)
+	if err != nil {
+		return err
+	}
+
+	if err := checkRobotCommands(opt.Commands); err != nil {
+		return err
+	}
+
+	err = s.Source.Db().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.Robot{}).Where("id = ?", robot.Id).Updates(map[string]any{
+			"robot_name":  opt.RobotName,
+			"describe":    opt.Describe,
+			"logo":        opt.Logo,
+			"webhook_url": opt.WebhookUrl,
+			"commands":    jsonutil.Encode(opt.Commands),
+			"status":      opt.Status,
+			"updated_at":  time.Now(),
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&model.Users{}).Where("id = ?", robot.UserId).Updates(map[string]any{
+			"nickname":   opt.RobotName,
+			"avatar":     opt.Logo,
+			"motto":      opt.Describe,
+			"updated_at": time.Now(),
+		}).Error
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return s.UsersRepo.ClearTableCache(ctx, robot.UserId)
+}
+
+func (s *RobotService) Delete(ctx context.Context, robotId int) error {
+	robot, err := s.find(ctx, robotId)
+	if err != nil {
+		return err
+	}
+
+	err = s.Source.Db().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&model.Robot{}, robot.Id).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&model.Users{}).Where("id = ?", robot.UserId).Updates(map[string]any{
+			"status":     model.UsersStatusDisabled,
+			"updated_at": time.Now(),
+		}).Error
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return s.UsersRepo.ClearTableCache(ctx, robot.UserId)
+}
+
+func (s *RobotService) ResetSecret(ctx context.Context, robotId int) (string, error) {
+	robot, err := s.find(ctx, robotId)
+	if err != nil {
+		return "", err
+	}
+
+	secret := webhook.NewSecret()
+	if _, err := s.RobotRepo.UpdateById(ctx, robot.Id, map[string]any{"webhook_secret": secret, "updated_at": time.Now()}); err != nil {
+		return "", err
+	}
+
+	return secret, nil
+}
+
+// find 查询 Webhook 机器人，内置的登录通知机器人不允许修改
+func (s *RobotService) find(ctx context.Context, robotId int) (*model.Robot, error) {
+	robot, err := s.RobotRepo.FindById(ctx, robotId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("机器人不存在")
+		}
+
+		return nil, err
+	}
+
+	if robot.Type != model.RobotTypeWebhook {
+		return nil, errors.New("内置机器人不允许修改")
+	}
+
+	return robot, nil
+}
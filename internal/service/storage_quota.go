@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go-chat/config"
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/core/errorx"
+	"go-chat/internal/pkg/strutil"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+)
+
+var _ IStorageQuotaService = (*StorageQuotaService)(nil)
+
+type IStorageQuotaService interface {
+	// CheckPolicy 校验上传策略(文件大小及后缀)
+	CheckPolicy(uploadType string, filename string, size int64) error
+	// CheckMimeType 根据文件内容校验媒体类型
+	CheckMimeType(uploadType string, stream []byte) error
+	// Reserve 占用存储配额，超出配额时返回错误
+	Reserve(ctx context.Context, ownerType int, ownerId int, size int64) error
+	// Release 释放存储配额
+	Release(ctx context.Context, ownerType int, ownerId int, size int64) error
+	// Usage 查询存储使用情况
+	Usage(ctx context.Context, ownerType int, ownerId int) (*StorageUsage, error)
+}
+
+type StorageUsage struct {
+	UsedSize  int64 `json:"used_size"`  // 已使用空间(字节)
+	Quota     int64 `json:"quota"`      // 存储配额(字节)，0 表示不限制
+	FileCount int   `json:"file_count"` // 文件数量
+}
+
+type StorageQuotaService struct {
+	Config           *config.Config
+	StorageUsageRepo *repo.StorageUsage
+}
+
+func (s *StorageQuotaService) CheckPolicy(uploadType string, filename string, size int64) error {
+	policy := s.Config.Upload.GetPolicy(uploadType)
+
+	if policy.MaxSize > 0 && size > policy.MaxSizeBytes() {
+		return errorx.New(entity.ErrUploadFileTooLarge.Code, fmt.Sprintf("上传文件大小不能超过%dM", policy.MaxSize))
+	}
+
+	if ext := strutil.FileSuffix(filename); !policy.AllowExtension(ext) {
+		return errorx.New(entity.ErrUploadFileTypeNotAllow.Code, fmt.Sprintf("不支持上传 %s 类型的文件", ext))
+	}
+
+	return nil
+}
+
+func (s *StorageQuotaService) CheckMimeType(uploadType string, stream []byte) error {
+	mimeType := http.DetectContentType(stream)
+	if !s.Config.Upload.GetPolicy(uploadType).AllowMimeType(mimeType) {
+		return errorx.New(entity.ErrUploadFileTypeNotAllow.Code, fmt.Sprintf("不支持上传 %s 类型的文件", mimeType))
+	}
+
+	return nil
+}
+
+func (s *StorageQuotaService) Reserve(ctx context.Context, ownerType int, ownerId int, size int64) error {
+	ok, err := s.StorageUsageRepo.Incr(ctx, ownerType, ownerId, size, s.quota(ownerType))
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		if ownerType == model.StorageOwnerGroup {
+			return entity.ErrGroupStorageQuotaExceeded
+		}
+
+		return entity.ErrUserStorageQuotaExceeded
+	}
+
+	return nil
+}
+
+func (s *StorageQuotaService) Release(ctx context.Context, ownerType int, ownerId int, size int64) error {
+	return s.StorageUsageRepo.Decr(ctx, ownerType, ownerId, size)
+}
+
+func (s *StorageQuotaService) Usage(ctx context.Context, ownerType int, ownerId int) (*StorageUsage, error) {
+	usage, err := s.StorageUsageRepo.Find(ctx, ownerType, ownerId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StorageUsage{
+		UsedSize:  usage.UsedSize,
+		Quota:     s.quota(ownerType),
+		FileCount: usage.FileCount,
+	}, nil
+}
+
+func (s *StorageQuotaService) quota(ownerType int) int64 {
+	if ownerType == model.StorageOwnerGroup {
+		return s.Config.Upload.GetGroupQuota()
+	}
+
+	return s.Config.Upload.GetUserQuota()
+}
@@ -22,6 +22,7 @@ type TalkRevokeOption struct {
 	UserId   int
 	TalkMode int
 	MsgId    string
+	IsAdmin  bool // 管理员撤回[不校验发送者及撤回时效]
 }
 
 type TalkDeleteRecordOption struct {
@@ -42,6 +43,7 @@ type TalkService struct {
 	UserRepo        *repo.Users
 	PushMessage     *business.PushMessage
 	MessageStorage  *cache.MessageStorage
+	FileObjectRepo  *repo.FileObject
 }
 
 // DeleteRecord 删除消息记录
@@ -89,16 +91,30 @@ func (t *TalkService) Revoke(ctx context.Context, opt *TalkRevokeOption) (err er
 	var (
 		fromId   int
 		toFromId int
+		msgType  int
+		extra    string
 	)
 
 	defer func() {
 		if err == nil {
+			// 撤回的文件消息不再可下载，释放对文件对象的引用
+			if msgType == entity.ChatMsgTypeFile {
+				var file model.TalkRecordExtraFile
+				if jsonutil.Decode(extra, &file) == nil {
+					_ = t.FileObjectRepo.DecrRefByPath(ctx, file.Path)
+				}
+			}
+
 			remark := "有消息已被撤回"
 
 			user, _ := t.UserRepo.FindByIdWithCache(ctx, fromId)
-			if user != nil {
+			if opt.IsAdmin {
+				remark = "管理员撤回了一条消息"
+			} else if user != nil {
 				remark = fmt.Sprintf("【%s】撤回了一条消息", user.Nickname)
+			}
 
+			if user != nil {
 				// 更新最后一条消息
 				_ = t.MessageStorage.Set(ctx, opt.TalkMode, fromId, toFromId, &cache.LastCacheMessage{
 					Content:  remark,
@@ -125,7 +141,7 @@ func (t *TalkService) Revoke(ctx context.Context, opt *TalkRevokeOption) (err er
 	case entity.ChatPrivateMode:
 		var record model.TalkUserMessage
 
-		err := db.First(&record, "msg_id = ? and from_id = ?", opt.MsgId, opt.UserId).Error
+		err := t.findRevokeRecord(db, opt).First(&record).Error
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return errors.New("消息ID不存在")
@@ -138,21 +154,28 @@ func (t *TalkService) Revoke(ctx context.Context, opt *TalkRevokeOption) (err er
 			return errors.New("消息已撤回")
 		}
 
-		if time.Now().Unix() > record.SendTime.Add(3*time.Minute).Unix() {
+		if !opt.IsAdmin && time.Now().Unix() > record.SendTime.Add(3*time.Minute).Unix() {
 			return errors.New("超出有效撤回时间范围，无法进行撤销！")
 		}
 
 		fromId = record.FromId
 		toFromId = record.ToFromId
+		msgType = record.MsgType
+		extra = record.Extra
+
+		res := db.Model(&model.TalkUserMessage{}).
+			Where("org_msg_id = ? and is_revoked = ?", record.OrgMsgId, model.No).
+			Update("is_revoked", model.Yes)
+		if res.Error == nil && res.RowsAffected == 0 {
+			return errors.New("消息已撤回")
+		}
 
-		return db.Model(&model.TalkUserMessage{}).
-			Where("org_msg_id = ?", record.OrgMsgId).
-			Update("is_revoked", model.Yes).Error
+		return res.Error
 
 	case entity.ChatGroupMode:
 		var record model.TalkGroupMessage
 
-		err := db.First(&record, "msg_id = ? and from_id = ?", opt.MsgId, opt.UserId).Error
+		err := t.findRevokeRecord(db, opt).First(&record).Error
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return errors.New("消息ID不存在")
@@ -165,17 +188,32 @@ func (t *TalkService) Revoke(ctx context.Context, opt *TalkRevokeOption) (err er
 			return errors.New("消息已撤回")
 		}
 
-		if time.Now().Unix() > record.SendTime.Add(3*time.Minute).Unix() {
+		if !opt.IsAdmin && time.Now().Unix() > record.SendTime.Add(3*time.Minute).Unix() {
 			return errors.New("超出有效撤回时间范围，无法进行撤销！")
 		}
 
 		fromId = record.FromId
 		toFromId = record.GroupId
+		msgType = record.MsgType
+		extra = record.Extra
 
-		return db.Model(&model.TalkGroupMessage{}).
-			Where("msg_id = ?", record.MsgId).
-			Update("is_revoked", model.Yes).Error
+		res := db.Model(&model.TalkGroupMessage{}).
+			Where("msg_id = ? and is_revoked = ?", record.MsgId, model.No).
+			Update("is_revoked", model.Yes)
+		if res.Error == nil && res.RowsAffected == 0 {
+			return errors.New("消息已撤回")
+		}
+
+		return res.Error
 	}
 
 	return errors.New("暂不支持撤回消息")
 }
+
+func (t *TalkService) findRevokeRecord(db *gorm.DB, opt *TalkRevokeOption) *gorm.DB {
+	if opt.IsAdmin {
+		return db.Where("msg_id = ?", opt.MsgId)
+	}
+
+	return db.Where("msg_id = ? and from_id = ?", opt.MsgId, opt.UserId)
+}
@@ -0,0 +1,499 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nsqio/go-nsq"
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/filesystem"
+	"go-chat/internal/pkg/jsonutil"
+	"go-chat/internal/pkg/logger"
+	"go-chat/internal/pkg/strutil"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+)
+
+const (
+	talkExportMaxRecords        = 100000   // 单次导出最大消息数
+	talkExportMaxMediaSize      = 50 << 20 // 单个媒体文件最大打包大小
+	talkExportMaxTotalMediaSize = 1 << 30  // 单次导出媒体文件总大小上限
+	talkExportPartSize          = 16 << 20 // 导出文件分片上传大小
+	talkExportMaxDays           = 366      // 单次导出最大时间跨度
+
+	// 导出任务超时时间，超时未完成的任务视为失败(队列消息丢失或导出进程异常退出)
+	talkExportTimeout = 2 * time.Hour
+)
+
+var _ ITalkExportService = (*TalkExportService)(nil)
+
+type ITalkExportService interface {
+	// Create 创建导出任务并投递到队列
+	Create(ctx context.Context, opt *TalkExportCreateOpt) (*model.TalkExport, error)
+	// Export 执行导出任务(队列消费)
+	Export(ctx context.Context, exportId int) (*model.TalkExport, error)
+	// Fail 标记导出任务失败
+	Fail(ctx context.Context, exportId int, reason string) error
+}
+
+type TalkExportService struct {
+	*repo.Source
+	TalkExportRepo    *repo.TalkExport
+	GroupMemberRepo   *repo.GroupMember
+	TalkRecordService ITalkRecordService
+	TemplateService   ITemplateService
+	Filesystem        filesystem.IFilesystem
+	Producer          *nsq.Producer
+}
+
+type TalkExportCreateOpt struct {
+	UserId    int
+	TalkMode  int
+	ToFromId  int
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+// TalkExportRecord 导出的消息记录
+type TalkExportRecord struct {
+	MsgId     string          `json:"msg_id"`          // 消息ID
+	Sequence  int             `json:"sequence"`        // 消息时序ID
+	MsgType   int             `json:"msg_type"`        // 消息类型
+	FromId    int             `json:"from_id"`         // 发送者ID
+	Nickname  string          `json:"nickname"`        // 发送者昵称
+	IsRevoked bool            `json:"is_revoked"`      // 是否已撤回
+	SendTime  string          `json:"send_time"`       // 发送时间
+	Content   string          `json:"content"`         // 消息文本内容
+	Media     string          `json:"media,omitempty"` // 压缩包内媒体文件路径
+	Extra     json.RawMessage `json:"extra"`           // 消息扩展字段
+}
+
+// TalkExportArchive 导出的聊天记录信息
+type TalkExportArchive struct {
+	TalkMode   int                 `json:"talk_mode"`
+	ToFromId   int                 `json:"to_from_id"`
+	StartTime  string              `json:"start_time"`
+	EndTime    string              `json:"end_time"`
+	ExportTime string              `json:"export_time"`
+	Records    []*TalkExportRecord `json:"records"`
+}
+
+func (s *TalkExportService) Create(ctx context.Context, opt *TalkExportCreateOpt) (*model.TalkExport, error) {
+
+	if !opt.EndTime.After(opt.StartTime) {
+		return nil, errors.New("导出时间范围错误")
+	}
+
+	if opt.EndTime.Sub(opt.StartTime) > talkExportMaxDays*24*time.Hour {
+		return nil, fmt.Errorf("导出时间范围不能超过%d天", talkExportMaxDays)
+	}
+
+	if opt.TalkMode == entity.ChatGroupMode && !s.GroupMemberRepo.IsMember(ctx, opt.ToFromId, opt.UserId, false) {
+		return nil, entity.ErrPermissionDenied
+	}
+
+	pending := []int{model.TalkExportStatusWait, model.TalkExportStatusRunning}
+
+	// 超时未完成的任务标记为失败，避免一直阻塞新的导出
+	_, err := s.TalkExportRepo.UpdateByWhere(ctx, map[string]any{
+		"status":     model.TalkExportStatusFail,
+		"reason":     "导出超时",
+		"updated_at": time.Now(),
+	}, "user_id = ? and status in ? and updated_at < ?", opt.UserId, pending, time.Now().Add(-talkExportTimeout))
+	if err != nil {
+		return nil, err
+	}
+
+	exist, err := s.TalkExportRepo.IsExist(ctx, "user_id = ? and status in ?", opt.UserId, pending)
+	if err != nil {
+		return nil, err
+	}
+
+	if exist {
+		return nil, errors.New("已有正在导出的任务，请稍后再试")
+	}
+
+	data := &model.TalkExport{
+		UserId:    opt.UserId,
+		TalkMode:  opt.TalkMode,
+		ToFromId:  opt.ToFromId,
+		StartTime: opt.StartTime,
+		EndTime:   opt.EndTime,
+		Status:    model.TalkExportStatusWait,
+		Drive:     entity.FileDriveMode(s.Filesystem.Driver()),
+	}
+
+	if err := s.TalkExportRepo.Create(ctx, data); err != nil {
+		return nil, err
+	}
+
+	body := jsonutil.Marshal(entity.TalkExportMessage{ExportId: data.Id})
+	if err := s.Producer.Publish(entity.TalkExportTopic, body); err != nil {
+		_ = s.Fail(ctx, data.Id, "任务投递失败")
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (s *TalkExportService) Export(ctx context.Context, exportId int) (*model.TalkExport, error) {
+
+	info, err := s.TalkExportRepo.FindById(ctx, exportId)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Status == model.TalkExportStatusSuccess || info.Status == model.TalkExportStatusFail {
+		return info, nil
+	}
+
+	_, _ = s.TalkExportRepo.UpdateById(ctx, info.Id, map[string]any{
+		"status":     model.TalkExportStatusRunning,
+		"updated_at": time.Now(),
+	})
+
+	records, err := s.findRecords(ctx, info)
+	if err != nil {
+		return nil, err
+	}
+
+	// 压缩包写入临时文件，避免大量媒体文件占满内存
+	tmp, err := os.CreateTemp("", "talk-export-*.zip")
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	archive := zip.NewWriter(tmp)
+
+	mediaQuota := int64(talkExportMaxTotalMediaSize)
+	items := make([]*TalkExportRecord, 0, len(records))
+	for _, record := range records {
+		item := &TalkExportRecord{
+			MsgId:     record.MsgId,
+			Sequence:  record.Sequence,
+			MsgType:   record.MsgType,
+			FromId:    record.FromId,
+			Nickname:  record.Nickname,
+			IsRevoked: record.IsRevoked == model.Yes,
+			SendTime:  record.SendTime.Format(time.DateTime),
+			Extra:     json.RawMessage("{}"),
+		}
+
+		if !item.IsRevoked {
+			item.Content = talkExportContent(record.MsgType, record.Extra)
+			if json.Valid([]byte(record.Extra)) {
+				item.Extra = json.RawMessage(record.Extra)
+			}
+
+			item.Media = s.writeMedia(archive, record, &mediaQuota)
+		}
+
+		items = append(items, item)
+	}
+
+	data := &TalkExportArchive{
+		TalkMode:   info.TalkMode,
+		ToFromId:   info.ToFromId,
+		StartTime:  info.StartTime.Format(time.DateTime),
+		EndTime:    info.EndTime.Format(time.DateTime),
+		ExportTime: time.Now().Format(time.DateTime),
+		Records:    items,
+	}
+
+	if err := s.writeDocuments(archive, data); err != nil {
+		return nil, err
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	filePath := fmt.Sprintf("talk-export/%s/%s", time.Now().Format("200601"), strutil.GenFileName("zip"))
+	if err := filesystem.WriteStream(s.Filesystem, s.Filesystem.BucketPrivateName(), filePath, tmp, talkExportPartSize); err != nil {
+		return nil, err
+	}
+
+	// 仅导出中的任务可标记完成，任务已因超时被标记失败时丢弃本次导出的文件
+	affected, err := s.TalkExportRepo.UpdateByWhere(ctx, map[string]any{
+		"status":  model.TalkExportStatusSuccess,
+		"drive":   entity.FileDriveMode(s.Filesystem.Driver()),
+		"path":    filePath,
+		"size":    int(size),
+		"msg_num": len(items),
+	}, "id = ? and status = ?", info.Id, model.TalkExportStatusRunning)
+	if err != nil {
+		return nil, err
+	}
+
+	if affected == 0 {
+		_ = s.Filesystem.Delete(s.Filesystem.BucketPrivateName(), filePath)
+		return s.TalkExportRepo.FindById(ctx, info.Id)
+	}
+
+	info.Status = model.TalkExportStatusSuccess
+	info.Path = filePath
+	info.Size = int(size)
+	info.MsgNum = len(items)
+
+	return info, nil
+}
+
+func (s *TalkExportService) Fail(ctx context.Context, exportId int, reason string) error {
+	_, err := s.TalkExportRepo.UpdateById(ctx, exportId, map[string]any{
+		"status": model.TalkExportStatusFail,
+		"reason": strutil.MtSubstr(reason, 0, 255),
+	})
+
+	return err
+}
+
+// findRecords 按游标倒序遍历会话记录，返回时间范围内按时间正序排列的消息
+func (s *TalkExportService) findRecords(ctx context.Context, info *model.TalkExport) ([]*model.TalkMessageRecord, error) {
+	var (
+		cursor = 0
+		items  = make([]*model.TalkMessageRecord, 0)
+	)
+
+	for {
+		records, err := s.TalkRecordService.FindAllTalkRecords(ctx, &FindAllTalkRecordsOpt{
+			TalkType:   info.TalkMode,
+			UserId:     info.UserId,
+			ReceiverId: info.ToFromId,
+			Cursor:     cursor,
+			Limit:      500,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if len(records) == 0 {
+			break
+		}
+
+		finished := false
+		for _, record := range records {
+			if record.SendTime.Before(info.StartTime) {
+				finished = true
+				break
+			}
+
+			if record.SendTime.After(info.EndTime) {
+				continue
+			}
+
+			items = append(items, record)
+		}
+
+		if finished || len(items) >= talkExportMaxRecords {
+			break
+		}
+
+		cursor = records[len(records)-1].Sequence
+	}
+
+	if len(items) > talkExportMaxRecords {
+		items = items[:talkExportMaxRecords]
+	}
+
+	slices.Reverse(items)
+
+	return items, nil
+}
+
+func (s *TalkExportService) writeDocuments(archive *zip.Writer, data *TalkExportArchive) error {
+
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := writeArchiveFile(archive, "messages.json", time.Now(), content); err != nil {
+		return err
+	}
+
+	text := bytes.NewBuffer(nil)
+	for _, item := range data.Records {
+		text.WriteString(fmt.Sprintf("[%s] %s(%d)\n", item.SendTime, item.Nickname, item.FromId))
+
+		switch {
+		case item.IsRevoked:
+			text.WriteString("[消息已撤回]\n")
+		case item.Media != "":
+			text.WriteString(fmt.Sprintf("%s %s\n", item.Content, item.Media))
+		default:
+			text.WriteString(item.Content + "\n")
+		}
+
+		text.WriteString("\n")
+	}
+
+	if err := writeArchiveFile(archive, "messages.txt", time.Now(), text.Bytes()); err != nil {
+		return err
+	}
+
+	body, err := s.TemplateService.TalkRecordsTemplate(data)
+	if err != nil {
+		return err
+	}
+
+	return writeArchiveFile(archive, "messages.html", time.Now(), []byte(body))
+}
+
+// writeMedia 将消息关联的媒体文件写入压缩包，返回压缩包内的文件路径，quota 为剩余可打包的媒体文件大小
+func (s *TalkExportService) writeMedia(archive *zip.Writer, record *model.TalkMessageRecord, quota *int64) string {
+	var (
+		bucketName string
+		objectName string
+		filename   string
+	)
+
+	switch record.MsgType {
+	case entity.ChatMsgTypeImage:
+		var extra model.TalkRecordExtraImage
+		if err := jsonutil.Decode(record.Extra, &extra); err != nil {
+			return ""
+		}
+
+		bucketName, objectName = s.Filesystem.BucketPublicName(), filesystem.PublicObjectName(s.Filesystem, extra.Url)
+	case entity.ChatMsgTypeAudio:
+		var extra model.TalkRecordExtraAudio
+		if err := jsonutil.Decode(record.Extra, &extra); err != nil {
+			return ""
+		}
+
+		bucketName, objectName = s.Filesystem.BucketPublicName(), filesystem.PublicObjectName(s.Filesystem, extra.Url)
+	case entity.ChatMsgTypeVideo:
+		var extra model.TalkRecordExtraVideo
+		if err := jsonutil.Decode(record.Extra, &extra); err != nil {
+			return ""
+		}
+
+		bucketName, objectName = s.Filesystem.BucketPublicName(), filesystem.PublicObjectName(s.Filesystem, extra.Url)
+	case entity.ChatMsgTypeFile:
+		var extra model.TalkRecordExtraFile
+		if err := jsonutil.Decode(record.Extra, &extra); err != nil {
+			return ""
+		}
+
+		bucketName, objectName, filename = s.Filesystem.BucketPrivateName(), extra.Path, extra.Name
+	default:
+		return ""
+	}
+
+	if objectName == "" {
+		return ""
+	}
+
+	stat, err := s.Filesystem.Stat(bucketName, objectName)
+	if err != nil || stat.Size > talkExportMaxMediaSize || stat.Size > *quota {
+		return ""
+	}
+
+	stream, err := s.Filesystem.ReadObject(bucketName, objectName)
+	if err != nil {
+		logger.Errorf("聊天记录导出读取文件失败 msg_id:%s err:%s", record.MsgId, err.Error())
+		return ""
+	}
+
+	defer stream.Close()
+
+	name := fmt.Sprintf("media/%s%s", record.MsgId, path.Ext(objectName))
+	if filename != "" {
+		name = fmt.Sprintf("media/%s_%s", record.MsgId, sanitizeArchiveName(filename))
+	}
+
+	fw, err := archive.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: record.SendTime,
+	})
+	if err != nil {
+		return ""
+	}
+
+	// 按文件信息中的大小截断，防止文件在打包期间被替换后超出限制
+	n, err := io.Copy(fw, io.LimitReader(stream, stat.Size))
+	*quota -= n
+	if err != nil {
+		logger.Errorf("聊天记录导出写入文件失败 msg_id:%s err:%s", record.MsgId, err.Error())
+		return ""
+	}
+
+	return name
+}
+
+// talkExportContent 消息转换为文本内容
+func talkExportContent(msgType int, extra string) string {
+	switch msgType {
+	case entity.ChatMsgTypeText, entity.ChatMsgSysText:
+		var data model.TalkRecordExtraText
+		if err := jsonutil.Decode(extra, &data); err == nil {
+			return data.Content
+		}
+	case entity.ChatMsgTypeCode:
+		var data model.TalkRecordExtraCode
+		if err := jsonutil.Decode(extra, &data); err == nil {
+			return fmt.Sprintf("```%s\n%s\n```", data.Lang, data.Code)
+		}
+	case entity.ChatMsgTypeFile:
+		var data model.TalkRecordExtraFile
+		if err := jsonutil.Decode(extra, &data); err == nil {
+			return fmt.Sprintf("[文件消息] %s", data.Name)
+		}
+	case entity.ChatMsgTypeLocation:
+		var data model.TalkRecordExtraLocation
+		if err := jsonutil.Decode(extra, &data); err == nil {
+			return fmt.Sprintf("[位置消息] %s (%s,%s)", data.Description, data.Longitude, data.Latitude)
+		}
+	case entity.ChatMsgTypeMixed:
+		var data model.TalkRecordExtraMixed
+		if err := jsonutil.Decode(extra, &data); err == nil {
+			parts := make([]string, 0, len(data.Items))
+			for _, item := range data.Items {
+				parts = append(parts, item.Content)
+			}
+
+			return strings.Join(parts, "\n")
+		}
+	case entity.ChatMsgTypeForward:
+		var data model.TalkRecordExtraForward
+		if err := jsonutil.Decode(extra, &data); err == nil {
+			parts := []string{"[转发消息]"}
+			for _, item := range data.Records {
+				parts = append(parts, fmt.Sprintf("%s: %s", item.Nickname, item.Content))
+			}
+
+			return strings.Join(parts, "\n")
+		}
+	}
+
+	if value, ok := entity.ChatMsgTypeMapping[msgType]; ok {
+		return value
+	}
+
+	return "未知消息"
+}
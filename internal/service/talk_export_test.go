@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/filesystem"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+)
+
+type fakeTalkRecordService struct {
+	ITalkRecordService
+	records []*model.TalkMessageRecord
+}
+
+func (f *fakeTalkRecordService) FindAllTalkRecords(_ context.Context, opt *FindAllTalkRecordsOpt) ([]*model.TalkMessageRecord, error) {
+	if opt.Cursor > 0 {
+		return nil, nil
+	}
+
+	return f.records, nil
+}
+
+func newTestTalkExportService(t *testing.T) (*TalkExportService, sqlmock.Sqlmock, string) {
+	t.Helper()
+
+	db, mock := newTestDB(t)
+	root := t.TempDir()
+
+	now := time.Now()
+	return &TalkExportService{
+		TalkExportRepo: repo.NewTalkExport(db),
+		TalkRecordService: &fakeTalkRecordService{records: []*model.TalkMessageRecord{
+			{MsgId: "m1", Sequence: 1, MsgType: entity.ChatMsgTypeText, FromId: 2, Nickname: "test", SendTime: now.Add(-time.Hour), Extra: `{"content":"hello"}`},
+		}},
+		TemplateService: &TemplateService{},
+		Filesystem: filesystem.NewLocalFilesystem(filesystem.LocalSystemConfig{
+			Root:          root,
+			BucketPublic:  "public",
+			BucketPrivate: "private",
+			Endpoint:      "127.0.0.1",
+		}),
+	}, mock, root
+}
+
+func talkExportRows(status int) *sqlmock.Rows {
+	now := time.Now()
+
+	return sqlmock.NewRows([]string{"id", "user_id", "talk_mode", "to_from_id", "start_time", "end_time", "status"}).
+		AddRow(1, 1, entity.ChatPrivateMode, 2, now.Add(-24*time.Hour), now, status)
+}
+
+func talkExportArchives(t *testing.T, root string) []string {
+	t.Helper()
+
+	items := make([]string, 0)
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && strings.HasSuffix(path, ".zip") {
+			items = append(items, path)
+		}
+
+		return nil
+	})
+
+	return items
+}
+
+func TestTalkExportService_CreateFailsStaleTasks(t *testing.T) {
+	svc, mock, _ := newTestTalkExportService(t)
+
+	// 超时任务先被标记为失败，仍在进行中的任务阻止新的导出
+	mock.ExpectExec("UPDATE `talk_export` SET").
+		WithArgs("导出超时", model.TalkExportStatusFail, sqlmock.AnyArg(), 1, model.TalkExportStatusWait, model.TalkExportStatusRunning, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT 1 FROM `talk_export`").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+
+	_, err := svc.Create(context.Background(), &TalkExportCreateOpt{
+		UserId:    1,
+		TalkMode:  entity.ChatPrivateMode,
+		ToFromId:  2,
+		StartTime: time.Now().Add(-time.Hour),
+		EndTime:   time.Now(),
+	})
+	assert.EqualError(t, err, "已有正在导出的任务，请稍后再试")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTalkExportService_ExportFinishedTask(t *testing.T) {
+	svc, mock, root := newTestTalkExportService(t)
+
+	for _, status := range []int{model.TalkExportStatusSuccess, model.TalkExportStatusFail} {
+		mock.ExpectQuery("SELECT \\* FROM `talk_export`").WillReturnRows(talkExportRows(status))
+
+		info, err := svc.Export(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, status, info.Status)
+	}
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Empty(t, talkExportArchives(t, root))
+}
+
+func TestTalkExportService_ExportSuccess(t *testing.T) {
+	svc, mock, root := newTestTalkExportService(t)
+
+	mock.ExpectQuery("SELECT \\* FROM `talk_export`").WillReturnRows(talkExportRows(model.TalkExportStatusWait))
+	mock.ExpectExec("UPDATE `talk_export` SET").
+		WithArgs(model.TalkExportStatusRunning, sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE `talk_export` SET .* WHERE id = \\? and status = \\?").
+		WithArgs(entity.FileDriveLocal, 1, sqlmock.AnyArg(), sqlmock.AnyArg(), model.TalkExportStatusSuccess, sqlmock.AnyArg(), 1, model.TalkExportStatusRunning).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	info, err := svc.Export(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, model.TalkExportStatusSuccess, info.Status)
+	assert.Equal(t, 1, info.MsgNum)
+	assert.Len(t, talkExportArchives(t, root), 1)
+}
+
+func TestTalkExportService_ExportTimedOut(t *testing.T) {
+	svc, mock, root := newTestTalkExportService(t)
+
+	// 导出期间任务已因超时被标记为失败，丢弃导出的文件
+	mock.ExpectQuery("SELECT \\* FROM `talk_export`").WillReturnRows(talkExportRows(model.TalkExportStatusRunning))
+	mock.ExpectExec("UPDATE `talk_export` SET").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE `talk_export` SET .* WHERE id = \\? and status = \\?").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT \\* FROM `talk_export`").WillReturnRows(talkExportRows(model.TalkExportStatusFail))
+
+	info, err := svc.Export(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, model.TalkExportStatusFail, info.Status)
+	assert.Empty(t, talkExportArchives(t, root))
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"html"
+	"time"
+
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/jsonutil"
+	"go-chat/internal/repository/cache"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+)
+
+var _ ITalkMentionService = (*TalkMentionService)(nil)
+
+type ITalkMentionService interface {
+	// List @我的消息列表
+	List(ctx context.Context, opt *repo.TalkMentionListOpt) ([]*TalkMentionItem, error)
+	// UnreadNum 未读的@我的消息数
+	UnreadNum(ctx context.Context, uid int) (int64, error)
+	// Read 标记@我的消息为已读，ids 为空时标记全部
+	Read(ctx context.Context, uid int, ids []int) error
+}
+
+type TalkMentionService struct {
+	TalkMentionRepo *repo.TalkMention
+	UnreadStorage   *cache.UnreadStorage
+}
+
+type TalkMentionItem struct {
+	Id          int    `json:"id"`
+	GroupId     int    `json:"group_id"`
+	GroupName   string `json:"group_name"`
+	GroupAvatar string `json:"group_avatar"`
+	MsgId       string `json:"msg_id"`
+	FromId      int    `json:"from_id"`
+	Nickname    string `json:"nickname"`
+	Avatar      string `json:"avatar"`
+	Content     string `json:"content"`    // 消息内容
+	IsRevoked   int    `json:"is_revoked"` // 消息是否已撤回[1:是;2:否;]
+	IsAll       int    `json:"is_all"`     // 是否@所有人[1:是;2:否;]
+	IsRead      int    `json:"is_read"`    // 是否已读[1:是;2:否;]
+	CreatedAt   string `json:"created_at"`
+}
+
+func (s *TalkMentionService) List(ctx context.Context, opt *repo.TalkMentionListOpt) ([]*TalkMentionItem, error) {
+	items, err := s.TalkMentionRepo.List(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*TalkMentionItem, 0, len(items))
+	for _, item := range items {
+		value := &TalkMentionItem{
+			Id:          item.Id,
+			GroupId:     item.GroupId,
+			GroupName:   item.GroupName,
+			GroupAvatar: item.GroupAvatar,
+			MsgId:       item.MsgId,
+			FromId:      item.FromId,
+			Nickname:    item.Nickname,
+			Avatar:      item.Avatar,
+			IsRevoked:   item.IsRevoked,
+			IsAll:       item.IsAll,
+			IsRead:      item.IsRead,
+			CreatedAt:   item.CreatedAt.Format(time.DateTime),
+		}
+
+		if item.IsRevoked == model.Yes {
+			value.Content = "此消息已被撤回"
+		} else {
+			var text model.TalkRecordExtraText
+			_ = jsonutil.Decode(item.Extra, &text)
+			value.Content = html.UnescapeString(text.Content)
+		}
+
+		list = append(list, value)
+	}
+
+	return list, nil
+}
+
+func (s *TalkMentionService) UnreadNum(ctx context.Context, uid int) (int64, error) {
+	return s.TalkMentionRepo.CountUnread(ctx, uid)
+}
+
+func (s *TalkMentionService) Read(ctx context.Context, uid int, ids []int) error {
+	groupIds, err := s.TalkMentionRepo.FindUnreadGroupIds(ctx, uid, ids)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.TalkMentionRepo.Read(ctx, uid, ids); err != nil {
+		return err
+	}
+
+	// 群聊中已没有未读的@我的消息时，清除会话列表中的@提醒
+	for _, groupId := range groupIds {
+		remaining, err := s.TalkMentionRepo.FindCount(ctx, "user_id = ? and group_id = ? and is_read = ?", uid, groupId, model.No)
+		if err == nil && remaining == 0 {
+			s.UnreadStorage.DelMention(ctx, uid, entity.ChatGroupMode, groupId)
+		}
+	}
+
+	return nil
+}
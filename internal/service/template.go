@@ -1,6 +1,8 @@
 package service
 
 import (
+	"fmt"
+
 	"go-chat/internal/pkg/utils"
 	"go-chat/internal/resource"
 )
@@ -9,6 +11,8 @@ var _ ITemplateService = (*TemplateService)(nil)
 
 type ITemplateService interface {
 	CodeTemplate(data map[string]string) (string, error)
+	TalkRecordsTemplate(data any) (string, error)
+	EmailTemplate(name string, data any) (string, error)
 }
 
 type TemplateService struct {
@@ -24,3 +28,25 @@ func (t *TemplateService) CodeTemplate(data map[string]string) (string, error) {
 
 	return utils.RenderTemplate(fileContent, data)
 }
+
+// TalkRecordsTemplate 聊天记录导出模板
+func (t *TemplateService) TalkRecordsTemplate(data any) (string, error) {
+
+	fileContent, err := resource.Template().ReadFile("templates/export/talk_records.tmpl")
+	if err != nil {
+		return "", err
+	}
+
+	return utils.RenderTemplate(fileContent, data)
+}
+
+// EmailTemplate 邮件模板，name 为 templates/email 目录下的模板名称
+func (t *TemplateService) EmailTemplate(name string, data any) (string, error) {
+
+	fileContent, err := resource.Template().ReadFile(fmt.Sprintf("templates/email/%s.tmpl", name))
+	if err != nil {
+		return "", err
+	}
+
+	return utils.RenderTemplate(fileContent, data)
+}
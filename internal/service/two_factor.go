@@ -0,0 +1,437 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go-chat/config"
+	"go-chat/internal/entity"
+	"go-chat/internal/pkg/core/errorx"
+	"go-chat/internal/pkg/jsonutil"
+	"go-chat/internal/pkg/strutil"
+	"go-chat/internal/pkg/totp"
+	"go-chat/internal/pkg/utils"
+	"go-chat/internal/repository/cache"
+	"go-chat/internal/repository/model"
+	"go-chat/internal/repository/repo"
+)
+
+const (
+	TwoFactorIssuer        = "LumenIM"       // 身份验证器中显示的发行方
+	TwoFactorRecoveryCodes = 10              // 恢复码数量
+	TwoFactorPreAuthExpire = 5 * time.Minute // 登录临时凭证有效期
+	TwoFactorPreAuthLimit  = 5               // 登录临时凭证允许的校验失败次数
+
+	TwoFactorActionVerify = "verify" // 校验动态码
+	TwoFactorActionSetup  = "setup"  // 强制绑定身份验证器
+)
+
+var _ ITwoFactorService = (*TwoFactorService)(nil)
+
+type ITwoFactorService interface {
+	// Status 查询两步验证状态
+	Status(ctx context.Context, ownerType int, ownerId int) (*TwoFactorStatus, error)
+	// IsEnabled 是否已开启两步验证
+	IsEnabled(ctx context.Context, ownerType int, ownerId int) (bool, error)
+	// EnabledOwners 查询已开启两步验证的用户或管理员ID
+	EnabledOwners(ctx context.Context, ownerType int) (map[int]bool, error)
+	// Setup 生成待确认的 TOTP 密钥
+	Setup(ctx context.Context, ownerType int, ownerId int, account string) (*TwoFactorSetup, error)
+	// Enable 校验动态码并开启两步验证，返回恢复码
+	Enable(ctx context.Context, ownerType int, ownerId int, code string) ([]string, error)
+	// Verify 校验动态码或恢复码
+	Verify(ctx context.Context, ownerType int, ownerId int, code string) error
+	// Disable 关闭两步验证
+	Disable(ctx context.Context, ownerType int, ownerId int) error
+	// RecoveryCodes 重新生成恢复码
+	RecoveryCodes(ctx context.Context, ownerType int, ownerId int) ([]string, error)
+	// CreatePreAuth 密码校验通过后签发登录临时凭证
+	CreatePreAuth(ctx context.Context, value *cache.PreAuth) (string, error)
+	// GetPreAuth 获取登录临时凭证
+	GetPreAuth(ctx context.Context, token string, guard string, action string) (*cache.PreAuth, error)
+	// CompletePreAuth 校验登录临时凭证对应的动态码，校验通过后凭证作废
+	CompletePreAuth(ctx context.Context, token string, guard string, code string) (*cache.PreAuth, error)
+	// CompleteSetup 强制绑定时校验动态码并开启两步验证，校验通过后凭证作废
+	CompleteSetup(ctx context.Context, token string, guard string, code string) (*cache.PreAuth, []string, error)
+}
+
+type TwoFactorService struct {
+	Config            *config.Config
+	TwoFactorRepo     *repo.TwoFactor
+	PreAuthStorage    *cache.PreAuthStorage
+	LoginLimitStorage *cache.LoginLimitStorage
+}
+
+type TwoFactorStatus struct {
+	Enabled       bool   `json:"enabled"`        // 是否已开启
+	EnabledAt     string `json:"enabled_at"`     // 开启时间
+	RecoveryCodes int    `json:"recovery_codes"` // 剩余可用恢复码数量
+}
+
+type TwoFactorSetup struct {
+	Secret string `json:"secret"` // Base32 密钥，用于手动输入
+	Uri    string `json:"uri"`    // otpauth 地址，用于生成二维码
+}
+
+func (s *TwoFactorService) Status(ctx context.Context, ownerType int, ownerId int) (*TwoFactorStatus, error) {
+	info, err := s.find(ctx, ownerType, ownerId)
+	if err != nil {
+		if errors.Is(err, entity.ErrTwoFactorNotEnabled) {
+			return &TwoFactorStatus{}, nil
+		}
+
+		return nil, err
+	}
+
+	if info.IsEnabled != model.Yes {
+		return &TwoFactorStatus{}, nil
+	}
+
+	status := &TwoFactorStatus{
+		Enabled:       true,
+		RecoveryCodes: len(s.decode(info.RecoveryCodes)),
+	}
+
+	if info.EnabledAt != nil {
+		status.EnabledAt = info.EnabledAt.Format(time.DateTime)
+	}
+
+	return status, nil
+}
+
+func (s *TwoFactorService) IsEnabled(ctx context.Context, ownerType int, ownerId int) (bool, error) {
+	return s.TwoFactorRepo.IsEnabled(ctx, ownerType, ownerId)
+}
+
+func (s *TwoFactorService) EnabledOwners(ctx context.Context, ownerType int) (map[int]bool, error) {
+	var ids []int
+	err := s.TwoFactorRepo.Model(ctx).Where("owner_type = ? and is_enabled = ?", ownerType, model.Yes).Pluck("owner_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	items := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		items[id] = true
+	}
+
+	return items, nil
+}
+
+func (s *TwoFactorService) Setup(ctx context.Context, ownerType int, ownerId int, account string) (*TwoFactorSetup, error) {
+	info, err := s.find(ctx, ownerType, ownerId)
+	if err != nil && !errors.Is(err, entity.ErrTwoFactorNotEnabled) {
+		return nil, err
+	}
+
+	if info != nil && info.IsEnabled == model.Yes {
+		return nil, entity.ErrTwoFactorEnabled
+	}
+
+	secret := totp.GenerateSecret()
+
+	// 未确认的密钥每次重新生成，覆盖上一次的绑定请求
+	if info == nil {
+		err = s.TwoFactorRepo.Create(ctx, &model.TwoFactor{
+			OwnerType: ownerType,
+			OwnerId:   ownerId,
+			Secret:    secret,
+			IsEnabled: model.No,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		})
+	} else {
+		_, err = s.TwoFactorRepo.UpdateById(ctx, info.Id, map[string]any{
+			"secret":     secret,
+			"last_step":  0,
+			"updated_at": time.Now(),
+		})
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	issuer := TwoFactorIssuer
+	if ownerType == model.TwoFactorOwnerAdmin {
+		issuer = TwoFactorIssuer + " Admin"
+	}
+
+	return &TwoFactorSetup{
+		Secret: secret,
+		Uri:    totp.ProvisioningURI(issuer, account, secret),
+	}, nil
+}
+
+func (s *TwoFactorService) Enable(ctx context.Context, ownerType int, ownerId int, code string) ([]string, error) {
+	info, err := s.find(ctx, ownerType, ownerId)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsEnabled == model.Yes {
+		return nil, entity.ErrTwoFactorEnabled
+	}
+
+	step, ok := totp.Validate(info.Secret, code, time.Now())
+	if !ok {
+		return nil, entity.ErrTwoFactorCodeError
+	}
+
+	codes, hashes := s.generate()
+
+	_, err = s.TwoFactorRepo.UpdateById(ctx, info.Id, map[string]any{
+		"is_enabled":     model.Yes,
+		"last_step":      step,
+		"recovery_codes": jsonutil.Encode(hashes),
+		"enabled_at":     time.Now(),
+		"updated_at":     time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func (s *TwoFactorService) Verify(ctx context.Context, ownerType int, ownerId int, code string) error {
+	info, err := s.find(ctx, ownerType, ownerId)
+	if err != nil {
+		return err
+	}
+
+	if info.IsEnabled != model.Yes {
+		return entity.ErrTwoFactorNotEnabled
+	}
+
+	if step, ok := totp.Validate(info.Secret, code, time.Now()); ok {
+		// 同一时间步的动态码只允许使用一次，防止重放
+		affected, err := s.TwoFactorRepo.UpdateByWhere(ctx, map[string]any{
+			"last_step":  step,
+			"updated_at": time.Now(),
+		}, "id = ? and last_step < ?", info.Id, step)
+		if err != nil {
+			return err
+		}
+
+		if affected == 0 {
+			return entity.ErrTwoFactorCodeError
+		}
+
+		return nil
+	}
+
+	hashes := s.decode(info.RecoveryCodes)
+
+	index := slices.Index(hashes, totp.HashRecoveryCode(code))
+	if index < 0 {
+		return entity.ErrTwoFactorCodeError
+	}
+
+	// 恢复码使用后立即作废，以原值作为条件避免并发重复使用
+	affected, err := s.TwoFactorRepo.UpdateByWhere(ctx, map[string]any{
+		"recovery_codes": jsonutil.Encode(slices.Delete(slices.Clone(hashes), index, index+1)),
+		"updated_at":     time.Now(),
+	}, "id = ? and recovery_codes = ?", info.Id, info.RecoveryCodes)
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return entity.ErrTwoFactorCodeError
+	}
+
+	return nil
+}
+
+func (s *TwoFactorService) Disable(ctx context.Context, ownerType int, ownerId int) error {
+	info, err := s.find(ctx, ownerType, ownerId)
+	if err != nil {
+		return err
+	}
+
+	return s.TwoFactorRepo.Delete(ctx, info.Id)
+}
+
+func (s *TwoFactorService) RecoveryCodes(ctx context.Context, ownerType int, ownerId int) ([]string, error) {
+	info, err := s.find(ctx, ownerType, ownerId)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsEnabled != model.Yes {
+		return nil, entity.ErrTwoFactorNotEnabled
+	}
+
+	codes, hashes := s.generate()
+
+	_, err = s.TwoFactorRepo.UpdateById(ctx, info.Id, map[string]any{
+		"recovery_codes": jsonutil.Encode(hashes),
+		"updated_at":     time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func (s *TwoFactorService) CreatePreAuth(ctx context.Context, value *cache.PreAuth) (string, error) {
+	token := strutil.NewMsgId()
+
+	if err := s.PreAuthStorage.Set(ctx, token, value, TwoFactorPreAuthExpire); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+func (s *TwoFactorService) GetPreAuth(ctx context.Context, token string, guard string, action string) (*cache.PreAuth, error) {
+	value, err := s.PreAuthStorage.Get(ctx, token)
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, entity.ErrTwoFactorExpired
+		}
+
+		return nil, err
+	}
+
+	if value.Guard != guard || value.Action != action {
+		return nil, entity.ErrTwoFactorExpired
+	}
+
+	return value, nil
+}
+
+func (s *TwoFactorService) CompletePreAuth(ctx context.Context, token string, guard string, code string) (*cache.PreAuth, error) {
+	value, err := s.GetPreAuth(ctx, token, guard, TwoFactorActionVerify)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerType := model.TwoFactorOwnerUser
+	if guard == "admin" {
+		ownerType = model.TwoFactorOwnerAdmin
+	}
+
+	// 每次登录都会生成新的临时凭证，动态码失败次数需按账号累计
+	limitKey := fmt.Sprintf("%d:%d", ownerType, value.UserId)
+
+	state, err := s.LoginLimitStorage.State(ctx, cache.LoginLimitTwoFactor, limitKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if state.Locked > 0 {
+		_ = s.PreAuthStorage.Del(ctx, token)
+		return nil, s.lockedError(state.Locked)
+	}
+
+	if err := s.Verify(ctx, ownerType, value.UserId, code); err != nil {
+		if !errors.Is(err, entity.ErrTwoFactorCodeError) {
+			return nil, err
+		}
+
+		if locked := s.fail(ctx, limitKey); locked > 0 {
+			_ = s.PreAuthStorage.Del(ctx, token)
+			return nil, s.lockedError(locked)
+		}
+
+		if s.PreAuthStorage.Fail(ctx, token, TwoFactorPreAuthLimit) {
+			return nil, entity.ErrTwoFactorExpired
+		}
+
+		return nil, err
+	}
+
+	_ = s.PreAuthStorage.Del(ctx, token)
+	_ = s.LoginLimitStorage.Clear(ctx, cache.LoginLimitTwoFactor, limitKey)
+
+	return value, nil
+}
+
+// fail 记录一次动态码校验失败，失败次数达到阈值时锁定账号的两步验证，返回锁定时长
+func (s *TwoFactorService) fail(ctx context.Context, key string) time.Duration {
+	conf := s.Config.Login.WithDefault()
+
+	count, err := s.LoginLimitStorage.Fail(ctx, cache.LoginLimitTwoFactor, key, time.Duration(conf.Window)*time.Second)
+	if err != nil || count < int64(conf.LockThreshold) {
+		return 0
+	}
+
+	duration := time.Duration(conf.LockDuration) * time.Second
+	if err := s.LoginLimitStorage.Lock(ctx, cache.LoginLimitTwoFactor, key, duration); err != nil {
+		return 0
+	}
+
+	return duration
+}
+
+func (s *TwoFactorService) lockedError(locked time.Duration) error {
+	minutes := int(math.Ceil(locked.Minutes()))
+	return errorx.New(entity.ErrLoginLocked.Code, fmt.Sprintf("动态码错误次数过多，请 %d 分钟后再试", minutes))
+}
+
+func (s *TwoFactorService) CompleteSetup(ctx context.Context, token string, guard string, code string) (*cache.PreAuth, []string, error) {
+	value, err := s.GetPreAuth(ctx, token, guard, TwoFactorActionSetup)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ownerType := model.TwoFactorOwnerUser
+	if guard == "admin" {
+		ownerType = model.TwoFactorOwnerAdmin
+	}
+
+	codes, err := s.Enable(ctx, ownerType, value.UserId, code)
+	if err != nil {
+		if errors.Is(err, entity.ErrTwoFactorCodeError) && s.PreAuthStorage.Fail(ctx, token, TwoFactorPreAuthLimit) {
+			return nil, nil, entity.ErrTwoFactorExpired
+		}
+
+		return nil, nil, err
+	}
+
+	_ = s.PreAuthStorage.Del(ctx, token)
+
+	return value, codes, nil
+}
+
+func (s *TwoFactorService) find(ctx context.Context, ownerType int, ownerId int) (*model.TwoFactor, error) {
+	info, err := s.TwoFactorRepo.FindByOwner(ctx, ownerType, ownerId)
+	if err != nil {
+		if utils.IsSqlNoRows(err) {
+			return nil, entity.ErrTwoFactorNotEnabled
+		}
+
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// 生成恢复码，返回明文及对应的摘要
+func (s *TwoFactorService) generate() ([]string, []string) {
+	codes := totp.GenerateRecoveryCodes(TwoFactorRecoveryCodes)
+
+	hashes := make([]string, 0, len(codes))
+	for _, code := range codes {
+		hashes = append(hashes, totp.HashRecoveryCode(code))
+	}
+
+	return codes, hashes
+}
+
+func (s *TwoFactorService) decode(value string) []string {
+	var hashes []string
+	if value != "" {
+		_ = jsonutil.Decode(value, &hashes)
+	}
+
+	return hashes
+}
@@ -7,6 +7,7 @@ import (
 
 	"go-chat/internal/entity"
 	"go-chat/internal/pkg/encrypt"
+	"go-chat/internal/pkg/logger"
 	"go-chat/internal/pkg/utils"
 	"go-chat/internal/repository/model"
 	"go-chat/internal/repository/repo"
@@ -22,7 +23,8 @@ type IUserService interface {
 }
 
 type UserService struct {
-	UsersRepo *repo.Users
+	UsersRepo    *repo.Users
+	EmailService IEmailService
 }
 
 type UserRegisterOpt struct {
@@ -48,6 +50,7 @@ func (s *UserService) Register(ctx context.Context, opt *UserRegisterOpt) (*mode
 		Email:     "",
 		Birthday:  "",
 		IsRobot:   model.No,
+		Status:    model.UsersStatusNormal,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
@@ -74,6 +77,10 @@ func (s *UserService) Login(ctx context.Context, mobile string, password string)
 		return nil, entity.ErrAccountOrPassword
 	}
 
+	if user.Status == model.UsersStatusDisabled {
+		return nil, entity.ErrAccountDisabled
+	}
+
 	return user, nil
 }
 
@@ -95,6 +102,10 @@ func (s *UserService) Forget(ctx context.Context, opt *UserForgetOpt) (bool, err
 		"password": encrypt.HashPassword(opt.Password),
 	})
 
+	if err == nil && affected > 0 {
+		s.notifyPasswordReset(ctx, user, "短信验证找回密码")
+	}
+
 	return affected > 0, err
 }
 
@@ -113,5 +124,30 @@ func (s *UserService) UpdatePassword(ctx context.Context, uid int, oldPassword s
 		"password": encrypt.HashPassword(password),
 	})
 
+	if err == nil {
+		s.notifyPasswordReset(ctx, user, "账号设置修改密码")
+	}
+
 	return err
 }
+
+// 密码重置后向绑定的邮箱发送通知，发送失败不影响密码修改
+func (s *UserService) notifyPasswordReset(ctx context.Context, user *model.Users, method string) {
+	if user.Email == "" {
+		return
+	}
+
+	err := s.EmailService.Send(ctx, &EmailSendOpt{
+		UserId:   user.Id,
+		Email:    user.Email,
+		Template: EmailTemplateResetPassword,
+		Data: map[string]string{
+			"nickname": user.Nickname,
+			"reset_at": time.Now().Format(time.DateTime),
+			"method":   method,
+		},
+	})
+	if err != nil {
+		logger.Errorf("密码重置通知邮件发送失败 uid:%d err:%s", user.Id, err.Error())
+	}
+}
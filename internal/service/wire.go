@@ -9,6 +9,27 @@ var ProviderSet = wire.NewSet(
 	wire.Struct(new(UserService), "*"),
 	wire.Bind(new(IUserService), new(*UserService)),
 
+	wire.Struct(new(AdminUserService), "*"),
+	wire.Bind(new(IAdminUserService), new(*AdminUserService)),
+
+	wire.Struct(new(AdminGroupService), "*"),
+	wire.Bind(new(IAdminGroupService), new(*AdminGroupService)),
+
+	wire.Struct(new(AdminAuditService), "*"),
+	wire.Bind(new(IAdminAuditService), new(*AdminAuditService)),
+
+	wire.Struct(new(AdminRoleService), "*"),
+	wire.Bind(new(IAdminRoleService), new(*AdminRoleService)),
+
+	wire.Struct(new(AdminAccountService), "*"),
+	wire.Bind(new(IAdminAccountService), new(*AdminAccountService)),
+
+	wire.Struct(new(StorageQuotaService), "*"),
+	wire.Bind(new(IStorageQuotaService), new(*StorageQuotaService)),
+
+	wire.Struct(new(FileScanService), "*"),
+	wire.Bind(new(IFileScanService), new(*FileScanService)),
+
 	wire.Struct(new(SmsService), "*"),
 	wire.Bind(new(ISmsService), new(*SmsService)),
 
@@ -63,6 +84,12 @@ var ProviderSet = wire.NewSet(
 	wire.Struct(new(ArticleAnnexService), "*"),
 	wire.Bind(new(IArticleAnnexService), new(*ArticleAnnexService)),
 
+	wire.Struct(new(ArticleTransferService), "*"),
+	wire.Bind(new(IArticleTransferService), new(*ArticleTransferService)),
+
+	wire.Struct(new(TalkExportService), "*"),
+	wire.Bind(new(ITalkExportService), new(*TalkExportService)),
+
 	wire.Struct(new(TemplateService), "*"),
 	wire.Bind(new(ITemplateService), new(*TemplateService)),
 
@@ -75,6 +102,45 @@ var ProviderSet = wire.NewSet(
 	wire.Struct(new(RoomService), "*"),
 	wire.Bind(new(IRoomService), new(*RoomService)),
 
+	wire.Struct(new(RobotService), "*"),
+	wire.Bind(new(IRobotService), new(*RobotService)),
+
+	wire.Struct(new(OpenAppService), "*"),
+	wire.Bind(new(IOpenAppService), new(*OpenAppService)),
+
+	wire.Struct(new(CommandService), "*"),
+	wire.Bind(new(ICommandService), new(*CommandService)),
+
+	wire.Struct(new(TwoFactorService), "*"),
+	wire.Bind(new(ITwoFactorService), new(*TwoFactorService)),
+
+	wire.Struct(new(AuthSessionService), "*"),
+	wire.Bind(new(IAuthSessionService), new(*AuthSessionService)),
+
+	wire.Struct(new(OrganizeSyncService), "*"),
+	wire.Bind(new(IOrganizeSyncService), new(*OrganizeSyncService)),
+
+	wire.Struct(new(LdapService), "*"),
+	wire.Bind(new(ILdapService), new(*LdapService)),
+
+	wire.Struct(new(OidcService), "*"),
+	wire.Bind(new(IOidcService), new(*OidcService)),
+
+	wire.Struct(new(LoginLimitService), "*"),
+	wire.Bind(new(ILoginLimitService), new(*LoginLimitService)),
+
+	wire.Struct(new(EmailService), "*"),
+	wire.Bind(new(IEmailService), new(*EmailService)),
+
+	wire.Struct(new(OfflineDigestService), "*"),
+	wire.Bind(new(IOfflineDigestService), new(*OfflineDigestService)),
+
+	wire.Struct(new(PushService), "*"),
+	wire.Bind(new(IPushService), new(*PushService)),
+
+	wire.Struct(new(TalkMentionService), "*"),
+	wire.Bind(new(ITalkMentionService), new(*TalkMentionService)),
+
 	wire.Struct(new(message.Service), "*"),
 	wire.Bind(new(message.IService), new(*message.Service)),
 )